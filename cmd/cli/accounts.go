@@ -0,0 +1,141 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"github.com/devsheke/scrapollo/internal/io"
+	"github.com/devsheke/scrapollo/internal/logging"
+	"github.com/devsheke/scrapollo/internal/runner"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+var healthReport string
+
+var accountsCmd = &cobra.Command{
+	Use:   "accounts",
+	Short: "Manage apollo.io accounts",
+}
+
+var accountsCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Check the health of apollo.io accounts and write a report",
+	Long: `Check the health of apollo.io accounts and write a report.
+
+For every account, the report records whether it has login cookies (and when they expire),
+whether its password can be used to log in, its credit balance, whether it is timed out and
+whether its list exists. Refreshed login cookies are saved to the output directory.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		logging.Init(debug)
+
+		accounts := readAccounts()
+
+		runnerOpts := []runner.RunnerOpt{
+			runner.Annoyances(annoyances),
+			runner.AnnoyanceTimeout(seconds(annoyanceTimeout)),
+			runner.CreditHistory(useCreditHistory),
+			runner.Debug(debug),
+			runner.Headless(headless),
+			runner.VirtualDisplay(headfulVirtual),
+			runner.OutputDir(outputDir),
+			runner.Stealth(stealth),
+			runner.DeviceProfiles(deviceProfiles),
+			runner.MobileView(mobileView),
+			runner.Timeout(seconds(timeout)),
+		}
+
+		runnerOpts = append(runnerOpts, sharedRunnerOpts()...)
+		defer closePlugins()
+
+		r, err := runner.New(accounts, runnerOpts...)
+		if err != nil {
+			exitOnError(err, 1)
+		}
+		logging.SetRunID(r.RunID())
+
+		report := r.CheckAccounts()
+		if err := io.SaveRecords(healthReport, report); err != nil {
+			exitOnError(err, 1)
+		}
+
+		log.Info().Str("file", healthReport).Msg("saved account health report")
+	},
+}
+
+func init() {
+	flags := accountsCheckCmd.Flags()
+
+	flags.StringVarP(&input, "input", "i", "", "path to file containing apollo accounts ('-' for stdin)")
+
+	flags.StringVar(&inputFormat, "input-format", "csv", "format of the accounts read from stdin ('csv', 'json' or 'xlsx')")
+
+	flags.StringVarP(&healthReport, "report", "r", "./account-health.csv", "path to the health report file (CSV or JSON)")
+
+	flags.StringVarP(&outputDir, "output-dir", "o", "./scrape-results", "specify path to output directory")
+
+	flags.StringVar(&configFile, "config", "", "path to a JSON configuration file (e.g. for per-action timeouts)")
+
+	flags.StringVarP(&cookieFile, "cookie-file", "c", "", "specify path to file containing cookies for your Apollo accounts")
+
+	flags.BoolVar(&useCreditHistory, "credit-history", true, "keep a history of every credit usage fetch in the output directory")
+
+	flags.IntVarP(&timeout, "timeout", "T", 60, "max time allowed for an operation (in seconds)")
+
+	flags.StringSliceVar(&pluginPaths, "plugin", nil, "path to a plugin executable implementing one or more extension points (can be repeated)")
+
+	flags.BoolVar(&debug, "debug", false, "print debugging information")
+
+	flags.BoolVarP(&headless, "headless", "H", true, "run browser in headless mode")
+
+	flags.BoolVar(&headfulVirtual, "headful-virtual", false, "run a headful browser, on a virtual display (Xvfb) on Linux servers without one")
+
+	flags.BoolVar(&stealth, "stealth", false, "specify whether or not to inject stealth script at every page load")
+
+	flags.BoolVar(&deviceProfiles, "device-profiles", false, "make each account's browser look like the same device across runs")
+
+	flags.BoolVar(&mobileView, "mobile-view", false, "emulate phones, so that Apollo serves its mobile web layout")
+
+	flags.StringSliceVar(&annoyances, "annoyances", nil, "specify the apollo.io annoyances to look out for ('banner', 'new-ui', 'pop-up' or 'sidenav')")
+
+	flags.IntVar(&annoyanceTimeout, "annoyance-timeout", 5, "max time allowed for checking all annoyances at once (in seconds)")
+
+	vpnProviderFlags(flags)
+
+	limitFlags(flags)
+
+	networkFlags(flags)
+
+	flags.StringVar(&vpnConfigs, "vpn-configs-dir", "", "path to directory containing OpenVPN configuration files")
+
+	flags.StringVar(&vpnCredentialsFile, "vpn-credentials", "", "path to file containing OpenVPN credentials")
+
+	flags.StringVar(&vpnArgs, "vpn-args", "", "specify arguments to use with OpenVPN")
+
+	flags.IntVar(&vpnCooldown, "vpn-cooldown", 0, "time for which a used OpenVPN config isn't reused, even across runs (in hours, 0 only avoids reuse within a run)")
+
+	flags.StringVar(&vpnFailover, "vpn-failover", "none", "what to do when no OpenVPN config connects ('none' fails the job, 'proxy' falls back to a proxy and 'direct' to a proxy or a direct connection)")
+
+	flags.StringSliceVar(&proxies, "proxy", nil, "proxy to fall back to when no OpenVPN config connects, e.g. 'socks5://127.0.0.1:1080' (can be repeated)")
+
+	flags.BoolVar(&vpnSplitTunnel, "vpn-split-tunnel", false, "only route the browser's traffic through OpenVPN, leaving other traffic (e.g. webhooks) on the host's network (Linux only)")
+
+	flags.StringVar(&vpnStateFile, "vpn-state", "", "path to the file recording when each OpenVPN config was last used (defaults to 'vpn-state.json' in the output directory)")
+
+	_ = accountsCheckCmd.MarkFlagRequired("input")
+	accountsCheckCmd.MarkFlagsRequiredTogether("vpn-configs-dir", "vpn-credentials")
+
+	accountsCmd.AddCommand(accountsCheckCmd)
+	rootCmd.AddCommand(accountsCmd)
+}
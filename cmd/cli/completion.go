@@ -0,0 +1,53 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// flagValues are the values suggested by shell completions for flags taking one of a fixed set of
+// values. Completions for scrapollo are generated with 'scrapollo completion <shell>'.
+var flagValues = map[string][]string{
+	"annoyances":      {"banner", "new-ui", "pop-up", "sidenav"},
+	"input-format":    {"csv", "json", "xlsx"},
+	"output-format":   {"csv", "json", "xlsx"},
+	"resolve":         {"email", "recent"},
+	"snapshot-format": {"png", "jpeg", "webp"},
+	"tab":             {"new", "saved", "total"},
+	"vpn-failover":    {"none", "proxy", "direct"},
+	"vpn-provider":    {"openvpn", "gluetun", "tailscale"},
+}
+
+// registerCompletions registers the completions of the flags of cmd and its subcommands which
+// take one of a fixed set of values.
+func registerCompletions(cmd *cobra.Command) {
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		values, ok := flagValues[f.Name]
+		if !ok {
+			return
+		}
+
+		err := cmd.RegisterFlagCompletionFunc(f.Name, cobra.FixedCompletions(values, cobra.ShellCompDirectiveNoFileComp))
+		if err != nil {
+			exitOnError(err, 1)
+		}
+	})
+
+	for _, sub := range cmd.Commands() {
+		registerCompletions(sub)
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/credits"
+	"github.com/devsheke/scrapollo/internal/runner"
+	"github.com/spf13/cobra"
+)
+
+var historyAccounts []string
+
+var creditsCmd = &cobra.Command{
+	Use:   "credits",
+	Short: "Inspect the credit usage of apollo.io accounts",
+}
+
+var creditsHistoryCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show the credit burn rate of apollo.io accounts and when they will run out",
+	Long: `Show the credit burn rate of apollo.io accounts and when they will run out.
+
+Every time the credit usage of an account is fetched, it is recorded in the output directory. This
+command summarises those records: the credits remaining, the average amount of credits used per day
+since they were last renewed and, at that rate, when each account (and the whole pool) runs out.`,
+	Run: func(cmd *cobra.Command, args []string) {
+		history, err := credits.Open(filepath.Join(outputDir, runner.CreditHistoryDir))
+		if err != nil {
+			exitOnError(fmt.Errorf("failed to open credit history: %w", err), 1)
+		}
+
+		accounts := historyAccounts
+		if len(accounts) == 0 {
+			if accounts, err = history.Accounts(); err != nil {
+				exitOnError(err, 1)
+			}
+		}
+
+		var forecasts []*credits.Forecast
+		for _, account := range accounts {
+			records, err := history.Read(account)
+			if err != nil {
+				exitOnError(fmt.Errorf("failed to read credit history of %q: %w", account, err), 1)
+			}
+
+			if forecast := credits.NewForecast(records); forecast != nil {
+				forecasts = append(forecasts, forecast)
+			}
+		}
+
+		slices.SortFunc(forecasts, func(a, b *credits.Forecast) int {
+			return compareExhaustion(a.ExhaustedAt, b.ExhaustedAt)
+		})
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ACCOUNT\tREMAINING\tMAX\tBURN/DAY\tRUNS OUT\tRENEWS\tUPDATED")
+
+		for _, f := range append(forecasts, credits.PoolForecast(forecasts)) {
+			fmt.Fprintf(
+				w,
+				"%s\t%d\t%d\t%.1f\t%s\t%s\t%s\n",
+				f.Account, f.Remaining, f.Max, f.BurnRate,
+				formatTime(f.ExhaustedAt), formatTime(f.Renewal), formatTime(f.Updated),
+			)
+		}
+
+		if err := w.Flush(); err != nil {
+			exitOnError(err, 1)
+		}
+	},
+}
+
+// compareExhaustion orders accounts that run out sooner first, and accounts that never run out last.
+func compareExhaustion(a, b time.Time) int {
+	switch {
+	case a.IsZero() && b.IsZero():
+		return 0
+	case a.IsZero():
+		return 1
+	case b.IsZero():
+		return -1
+	default:
+		return a.Compare(b)
+	}
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+
+	return t.Local().Format("2006-01-02 15:04")
+}
+
+func init() {
+	flags := creditsHistoryCmd.Flags()
+
+	flags.StringVarP(&outputDir, "output-dir", "o", "./scrape-results", "specify path to output directory")
+
+	flags.StringSliceVarP(&historyAccounts, "account", "a", nil, "only show the provided accounts (can be repeated)")
+
+	creditsCmd.AddCommand(creditsHistoryCmd)
+	rootCmd.AddCommand(creditsCmd)
+}
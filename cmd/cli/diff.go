@@ -0,0 +1,85 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/devsheke/scrapollo/internal/io"
+	"github.com/devsheke/scrapollo/internal/logging"
+	"github.com/devsheke/scrapollo/pkg/leaddiff"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+var diffOutput string
+
+var diffCmd = &cobra.Command{
+	Use:   "diff old-file new-file",
+	Short: "Show the leads added, removed and changed between two scrapes of the same search",
+	Long: `Show the leads added, removed and changed between two scrapes of the same search.
+
+The files are output files of scrapollo (CSV or JSON). Leads are matched by their LinkedIn profile,
+or by their name and company if they don't have one, and the details of matching leads are compared
+(their provenance, e.g. when and by which account they were scraped, is ignored).`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		logging.Init(false)
+
+		result, err := leaddiff.DiffFiles(args[0], args[1])
+		if err != nil {
+			exitOnError(err, 1)
+		}
+
+		records := result.Records()
+		if diffOutput != "" {
+			if err := io.SaveRecords(diffOutput, records); err != nil {
+				exitOnError(err, 1)
+			}
+
+			log.Info().Str("file", diffOutput).Msg("saved lead diff")
+		} else {
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "CHANGE\tNAME\tTITLE\tCOMPANY\tCHANGED FIELDS")
+
+			for _, r := range records {
+				fields := r.Fields
+				if fields == "" {
+					fields = "-"
+				}
+
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", r.Change, r.Name, r.Title, r.Company, fields)
+			}
+
+			if err := w.Flush(); err != nil {
+				exitOnError(err, 1)
+			}
+		}
+
+		fmt.Printf(
+			"\n%d added, %d removed, %d changed\n",
+			len(result.Added), len(result.Removed), len(result.Changed),
+		)
+	},
+}
+
+func init() {
+	diffCmd.Flags().
+		StringVarP(&diffOutput, "output", "o", "", "save the diff to this file (CSV or JSON) instead of printing it")
+
+	rootCmd.AddCommand(diffCmd)
+}
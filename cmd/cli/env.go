@@ -0,0 +1,68 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// envPrefix is the prefix of the environment variables overriding the flags' defaults.
+const envPrefix = "SCRAPOLLO_"
+
+func init() {
+	cobra.OnInitialize(applyEnv)
+}
+
+// envName returns the name of the environment variable setting the flag with the given name,
+// e.g. 'SCRAPOLLO_OUTPUT_DIR' for '--output-dir'.
+func envName(flag string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(flag, "-", "_"))
+}
+
+// applyEnv sets the flags of the command being run which weren't given on the command line
+// from their environment variables, if set. It runs before required flags are checked, so a
+// required flag may be given through its environment variable instead.
+func applyEnv() {
+	cmd, _, err := rootCmd.Find(os.Args[1:])
+	if err != nil {
+		return
+	}
+
+	var errs []string
+	cmd.Flags().VisitAll(func(f *pflag.Flag) {
+		if f.Changed || f.Name == "help" || f.Name == "version" {
+			return
+		}
+
+		name := envName(f.Name)
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			return
+		}
+
+		if err := cmd.Flags().Set(f.Name, value); err != nil {
+			errs = append(errs, fmt.Sprintf("invalid value %q for %s: %v", value, name, err))
+		}
+	})
+
+	if len(errs) > 0 {
+		exitOnError(fmt.Errorf("%s", strings.Join(errs, "; ")), 1)
+	}
+}
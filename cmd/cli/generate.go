@@ -0,0 +1,139 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/devsheke/scrapollo/internal/generator"
+	"github.com/devsheke/scrapollo/internal/io"
+	"github.com/devsheke/scrapollo/internal/logging"
+	"github.com/devsheke/scrapollo/internal/models"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+var (
+	generateAccounts, generateOutput, generateSearches, generateUnassigned string
+	generateFormat                                                         string
+	generateDays                                                           int
+)
+
+var generateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate the input file of a scrape from a list of searches and a pool of accounts",
+	Long: `Generate the input file of a scrape from a list of searches and a pool of accounts.
+
+The searches file (CSV, JSON or XLSX) has a row per search with its 'url', the 'list' to save its
+leads to and the number of 'leads' to save. The accounts file is an input file of scrapollo, whose
+searches (if any) are replaced. Searches are given to the accounts in order, each account saving
+leads from a single search, and no more leads than its daily limit allows over the number of days
+(or than its credits, if they're known). Searches with more leads than one account can save are
+split across accounts, in which case the accounts are shown the same leads, so they should be
+deduplicated with --dedupe-store when scraping.`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		// the generated file may be piped into another scrapollo process.
+		logConfig := logging.Config{Level: zerolog.InfoLevel}
+		if debug {
+			logConfig.Level = zerolog.DebugLevel
+		}
+		if generateOutput == io.Stdio {
+			logConfig.Out = os.Stderr
+		}
+		logging.Configure(logConfig)
+
+		var searches []*generator.Search
+		if err := io.ReadRecords(generateSearches, &searches); err != nil {
+			exitOnError(err, 1)
+		}
+
+		var pool []*models.Account
+		if err := io.ReadRecords(generateAccounts, &pool); err != nil {
+			exitOnError(err, 1)
+		}
+
+		plan, err := generator.Generate(searches, pool, generator.Options{DailyLimit: dailyLimit, Days: generateDays})
+		if err != nil {
+			exitOnError(err, 1)
+		}
+
+		if generateOutput == io.Stdio {
+			format, err := io.ParseFileFormat(generateFormat)
+			if err != nil {
+				exitOnError(err, 1)
+			}
+
+			err = io.SaveRecordsTo(os.Stdout, format, plan.Accounts)
+			if err != nil {
+				exitOnError(err, 1)
+			}
+		} else if err := io.SaveRecords(generateOutput, plan.Accounts); err != nil {
+			exitOnError(err, 1)
+		}
+
+		for _, search := range plan.Shared {
+			log.Warn().Str("list", search.List).Msg("search split across accounts, use --dedupe-store when scraping")
+		}
+
+		for _, search := range plan.Unassigned {
+			log.Warn().Str("list", search.List).Int("leads", search.Leads).Msg("not enough accounts for search")
+		}
+
+		if generateUnassigned != "" && len(plan.Unassigned) > 0 {
+			if err := io.SaveRecords(generateUnassigned, plan.Unassigned); err != nil {
+				exitOnError(err, 1)
+			}
+			log.Info().Str("file", generateUnassigned).Msg("saved unassigned searches")
+		}
+
+		log.Info().
+			Str("file", generateOutput).
+			Int("accounts", len(plan.Accounts)).
+			Int("unused", len(pool)-len(plan.Accounts)).
+			Msg("saved generated accounts")
+	},
+}
+
+func init() {
+	flags := generateCmd.Flags()
+
+	flags.StringVarP(&generateSearches, "searches", "s", "", "path to the file listing the searches (CSV, JSON or XLSX)")
+
+	flags.StringVarP(&generateAccounts, "accounts", "a", "", "path to the file listing the pool of apollo accounts")
+
+	flags.StringVarP(&generateOutput, "output", "o", "./accounts.csv", "path to the generated input file (CSV, JSON or XLSX), or '-' for stdout")
+
+	flags.StringVar(&generateFormat, "output-format", "csv", "format of the input file written to stdout ('csv', 'json' or 'xlsx')")
+
+	flags.StringVar(&generateUnassigned, "unassigned", "", "save the searches that no account could be given to this file, for a later run")
+
+	flags.IntVarP(&dailyLimit, "daily-limit", "d", 500, "daily limit for saving leads")
+
+	flags.IntVar(&generateDays, "days", 1, "number of days the accounts have to save their leads in")
+
+	flags.BoolVar(&debug, "debug", false, "print debugging information")
+
+	for _, name := range []string{"searches", "accounts"} {
+		if err := generateCmd.MarkFlagRequired(name); err != nil {
+			fmt.Fprintln(os.Stderr, "Error:", err)
+			os.Exit(1)
+		}
+	}
+
+	rootCmd.AddCommand(generateCmd)
+}
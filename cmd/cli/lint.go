@@ -0,0 +1,71 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/devsheke/scrapollo/internal/io"
+	"github.com/devsheke/scrapollo/internal/lint"
+	"github.com/devsheke/scrapollo/internal/models"
+	"github.com/spf13/cobra"
+)
+
+var lintCmd = &cobra.Command{
+	Use:   "lint input-file",
+	Short: "Check an input file for mistakes before a run",
+	Long: `Check an input file (CSV, JSON or XLSX) for mistakes before a run.
+
+Missing and unknown columns, accounts without an email, search URL or list, duplicate emails,
+invalid activity windows and searches or lists shared by several accounts are reported. The
+command exits with code 1 if any errors (as opposed to warnings) are found.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		file := args[0]
+
+		columns, err := io.ReadColumns(file)
+		if err != nil {
+			exitOnError(err, 1)
+		}
+
+		var accounts []*models.Account
+		if err := io.ReadRecords(file, &accounts); err != nil {
+			exitOnError(err, 1)
+		}
+
+		issues := append(lint.Columns(columns), lint.Accounts(accounts)...)
+
+		var errorCount, warningCount int
+		for _, issue := range issues {
+			fmt.Println(issue)
+
+			if issue.Severity == lint.SeverityError {
+				errorCount++
+			} else {
+				warningCount++
+			}
+		}
+
+		fmt.Printf("%d accounts checked: %d errors, %d warnings\n", len(accounts), errorCount, warningCount)
+
+		if errorCount > 0 {
+			exitOnError(fmt.Errorf("%s has %d errors", file, errorCount), 1)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(lintCmd)
+}
@@ -15,16 +15,40 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"time"
 
+	"github.com/devsheke/scrapollo/internal/actions"
+	"github.com/devsheke/scrapollo/internal/bus"
+	"github.com/devsheke/scrapollo/internal/classify"
+	"github.com/devsheke/scrapollo/internal/config"
+	"github.com/devsheke/scrapollo/internal/dedupe"
+	"github.com/devsheke/scrapollo/internal/events"
+	"github.com/devsheke/scrapollo/internal/exitnode"
+	"github.com/devsheke/scrapollo/internal/extract"
+	"github.com/devsheke/scrapollo/internal/feed"
+	"github.com/devsheke/scrapollo/internal/health"
 	"github.com/devsheke/scrapollo/internal/io"
+	"github.com/devsheke/scrapollo/internal/limiter"
 	"github.com/devsheke/scrapollo/internal/logging"
 	"github.com/devsheke/scrapollo/internal/models"
 	"github.com/devsheke/scrapollo/internal/openvpn"
+	"github.com/devsheke/scrapollo/internal/privacy"
 	"github.com/devsheke/scrapollo/internal/runner"
+	"github.com/devsheke/scrapollo/internal/scoring"
+	"github.com/devsheke/scrapollo/internal/splitter"
+	"github.com/devsheke/scrapollo/internal/tagging"
+	"github.com/devsheke/scrapollo/internal/tracing"
+	"github.com/devsheke/scrapollo/pkg/plugin"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 const (
@@ -32,39 +56,159 @@ const (
 	VERSION string = "0.1.1"
 )
 
+// exitTimeBudget is the exit code used when a run is stopped for exceeding its time budget, so
+// that wrapper schedulers can tell it apart from a failure and rerun it to resume the scrape.
+const exitTimeBudget = 3
+
 var (
-	dailyLimit, timeout                    int
-	csvOut, jsonOut                        bool
+	annoyanceTimeout, dailyLimit, timeout  int
+	healthStall, stallTimeout              int
+	maxBrowserMemory, recyclePages         int
+	maxJobDuration, maxRuntime             int
+	progressHistory, asyncWrites           int
+	retentionDays                          int
+	simulateDays, simulateLatency          int
+	simulateErrorRate                      float64
+	simulateSeed                           uint64
+	partitionSize                          int
+	snapshotQuality, logSample             int
+	warmUpContacts, warmUpDuration         int
+	windowJitter                           int
+	prefetchLogins, prefetchSpread         int
+	errorBackoff, maxErrorBackoff          int
+	maxFailures                            int
+	csvOut, jsonOut, gzipOut               bool
+	partitionByDate                        bool
 	debug, fetchCredits, headless, stealth bool
-	cookieFile, input, outputDir, tab      string
+	overlapScrape, pipelineScrape          bool
+	bulkSave, splitSearches                bool
+	useJournal, ignoreTimeouts             bool
+	deviceProfiles, headfulVirtual         bool
+	mobileView, verifySaves, apiExport     bool
+	leadFeed                               bool
+	useCreditHistory                       bool
+	snapshotFullPage, snapshotMHTML        bool
+	watchAnnoyances, watchInput            bool
+	logCaller, quiet                       bool
+	blockResources, simulate               bool
+	configFile, cookieFile, dedupeStore    string
+	blacklistFile, healthAddr, pauseFile   string
+	eventsSocket, healthToken              string
+	input, inputFormat, otlpEndpoint       string
+	browserCacheDir                        string
+	fixtureDir, outputDir, outputTemplate  string
+	progressHistoryTemplate, scrubPII      string
+	snapshotFormat, tab                    string
+	annoyances, logModules, pluginPaths    []string
+	allowURLs, blockURLs                   []string
+	webhooks, buses, busHeaders            []string
+	busKey                                 string
+	browserFlags, extensions               []string
 )
 
-var vpnConfigs, vpnCredentialsFile, vpnArgs string
+// plugins holds the plugins loaded for the current run so that they can be stopped on exit.
+var plugins []*plugin.Plugin
+
+var (
+	gluetunAPIKey, gluetunProxy, gluetunURL               string
+	vpnProviderName                                       string
+	vpnConfigs, vpnCredentialsFile, vpnArgs, vpnStateFile string
+	vpnFailover                                           string
+	vpnCooldown                                           int
+	vpnSplitTunnel                                        bool
+	proxies                                               []string
+)
+
+var (
+	llmURL, llmAPIKey, llmModel            string
+	llmMaxCalls, llmMaxTokens, llmMaxInput int
+)
+
+var (
+	classifyFailures bool
+	classifierURL    string
+	maintenanceWait  int
+)
+
+var (
+	maxConcurrentLogins, maxSavesPerHour int
+	limitsFile                           string
+)
 
 var rootCmd = &cobra.Command{
 	Use:   APPNAME,
 	Short: "Save and extract leads from apollo.io",
 	Run: func(cmd *cobra.Command, args []string) {
-		logging.Init(debug)
+		initLogging()
 
-		var accounts []*models.Account
-		if err := io.ReadRecords(input, &accounts); err != nil {
+		shutdownTracing := initTracing()
+		defer shutdownTracing()
+
+		accounts := readAccounts()
+
+		switch proto.PageCaptureScreenshotFormat(snapshotFormat) {
+		case proto.PageCaptureScreenshotFormatPng,
+			proto.PageCaptureScreenshotFormatJpeg,
+			proto.PageCaptureScreenshotFormatWebp:
+		default:
+			exitOnError(fmt.Errorf("unsupported snapshot format: %q", snapshotFormat), 1)
+		}
+
+		scrub, err := privacy.ParseScrubMode(scrubPII)
+		if err != nil {
 			exitOnError(err, 1)
 		}
 
 		runnerOpts := []runner.RunnerOpt{
+			runner.Annoyances(annoyances),
+			runner.AsyncWrites(asyncWrites),
+			runner.AnnoyanceTimeout(seconds(annoyanceTimeout)),
+			runner.BlacklistFile(blacklistFile),
+			runner.BulkSave(bulkSave),
+			runner.VerifySaves(verifySaves),
+			runner.APIExport(apiExport),
+			runner.CreditHistory(useCreditHistory),
 			runner.Dailyimit(dailyLimit),
 			runner.Debug(debug),
 			runner.FetchCredits(fetchCredits),
 			runner.Headless(headless),
+			runner.VirtualDisplay(headfulVirtual),
+			runner.Journal(useJournal),
+			runner.RecordFixtures(fixtureDir),
+			runner.MaxBrowserMemory(uint64(maxBrowserMemory) << 20),
+			runner.MaxJobDuration(seconds(maxJobDuration)),
+			runner.MaxRuntime(seconds(maxRuntime)),
 			runner.OutputDir(outputDir),
+			runner.OutputTemplate(outputTemplate),
+			runner.OverlapScrape(overlapScrape),
+			runner.PauseFile(pauseFile),
+			runner.PipelineScrape(pipelineScrape),
+			runner.PrefetchLogins(prefetchLogins, seconds(prefetchSpread)),
+			runner.ProgressHistory(seconds(progressHistory), progressHistoryTemplate),
+			runner.RecyclePages(recyclePages),
+			runner.Retention(time.Duration(retentionDays) * 24 * time.Hour),
+			runner.ScrubPII(scrub),
+			runner.Snapshots(actions.SnapshotOptions{
+				Format:   proto.PageCaptureScreenshotFormat(snapshotFormat),
+				Quality:  snapshotQuality,
+				FullPage: snapshotFullPage,
+				MHTML:    snapshotMHTML,
+			}),
+			runner.StallTimeout(seconds(stallTimeout)),
 			runner.Stealth(stealth),
+			runner.DeviceProfiles(deviceProfiles),
+			runner.ErrorBackoff(seconds(errorBackoff), seconds(maxErrorBackoff)),
+			runner.MaxFailures(maxFailures),
+			runner.MobileView(mobileView),
 			runner.Tab(tab),
-			runner.Timeout(time.Duration(timeout) * time.Second),
-		}
-
-		if cookieFile != "" {
-			runnerOpts = append(runnerOpts, runner.CookieFile(cookieFile))
+			runner.Timeout(seconds(timeout)),
+			runner.IgnoreTimeouts(ignoreTimeouts),
+			runner.WarmUp(actions.WarmUpOptions{
+				Duration: time.Duration(warmUpDuration) * time.Minute,
+				Contacts: warmUpContacts,
+			}),
+			runner.WatchAnnoyances(watchAnnoyances),
+			runner.WindowJitter(time.Duration(windowJitter) * time.Minute),
 		}
 
 		if csvOut {
@@ -73,27 +217,148 @@ var rootCmd = &cobra.Command{
 			runnerOpts = append(runnerOpts, runner.JsonOutput())
 		}
 
-		if vpnConfigs != "" {
-			vpn, err := openvpn.NewManager(vpnConfigs, vpnCredentialsFile, vpnArgs)
+		if gzipOut {
+			runnerOpts = append(runnerOpts, runner.OutputLayout(io.Gzip()))
+		}
+
+		if partitionSize > 0 {
+			runnerOpts = append(runnerOpts, runner.OutputLayout(io.PartitionBySize(int64(partitionSize)<<20)))
+		}
+
+		if partitionByDate {
+			runnerOpts = append(runnerOpts, runner.OutputLayout(io.PartitionByDate()))
+		}
+
+		for _, url := range webhooks {
+			runnerOpts = append(runnerOpts, runner.Sinks(io.Sink{Name: "webhook", Writer: io.NewWebhookLeadWriter(url)}))
+		}
+
+		for _, url := range buses {
+			pub, err := bus.Open(url)
+			if err != nil {
+				exitOnError(fmt.Errorf("failed to connect to message bus: %w", err), 1)
+			}
+			defer pub.Close()
+
+			runnerOpts = append(runnerOpts, runner.Sinks(io.Sink{Name: "bus", Writer: bus.NewLeadWriter(pub, busKey, busHeaders...)}))
+		}
+
+		var leads *feed.Feed
+		if leadFeed {
+			if healthAddr == "" {
+				exitOnError(errors.New("--lead-feed is served by the health server, which needs --health-addr"), 1)
+			}
+
+			// the feed streams the leads' personal data, so it isn't served to other hosts without a token.
+			if healthToken == "" && !health.IsLoopback(healthAddr) {
+				exitOnError(errors.New("--lead-feed needs --health-token unless --health-addr is a loopback address"), 1)
+			}
+
+			leads = feed.New(feed.DefaultBacklog)
+			runnerOpts = append(runnerOpts, runner.Sinks(io.Sink{Name: "feed", Writer: leads}))
+		}
+
+		if simulate {
+			runnerOpts = append(runnerOpts, runner.Simulate(runner.Simulation{
+				PageLatency: seconds(simulateLatency),
+				ErrorRate:   simulateErrorRate,
+				Seed:        simulateSeed,
+				Horizon:     time.Duration(simulateDays) * 24 * time.Hour,
+			}))
+		}
+
+		if watchInput {
+			if input == io.Stdio {
+				exitOnError(errors.New("--watch-input can't be used when reading accounts from stdin"), 1)
+			}
+			runnerOpts = append(runnerOpts, runner.WatchAccounts(input))
+		}
+
+		if splitSearches {
+			runnerOpts = append(runnerOpts, runner.SplitSearches(splitter.New()))
+		}
+
+		if llmURL != "" {
+			extractor := extract.New(llmURL, llmAPIKey, llmModel, extract.Limits{
+				MaxCalls:  llmMaxCalls,
+				MaxTokens: llmMaxTokens,
+				MaxInput:  llmMaxInput,
+			})
+			runnerOpts = append(runnerOpts, runner.LLMExtraction(extractor))
+		}
+
+		if classifyFailures || classifierURL != "" {
+			// the model is asked first, and the heuristics recognize what it doesn't.
+			var classifier classify.Classifier = classify.Heuristics{}
+			if classifierURL != "" {
+				classifier = classify.Chain(classify.NewEndpoint(classifierURL), classifier)
+			}
+			runnerOpts = append(runnerOpts, runner.ClassifyFailures(classifier, seconds(maintenanceWait)))
+		}
+
+		if dedupeStore != "" {
+			store, err := dedupe.Open(dedupeStore)
+			if err != nil {
+				exitOnError(err, 1)
+			}
+			defer store.Close()
+
+			runnerOpts = append(runnerOpts, runner.Dedupe(store))
+		}
+
+		if eventsSocket != "" {
+			stream, err := events.Listen(eventsSocket)
 			if err != nil {
 				exitOnError(err, 1)
 			}
+			defer stream.Close()
 
-			runnerOpts = append(runnerOpts, runner.VpnManager(vpn))
+			runnerOpts = append(runnerOpts, runner.ProgressEvents(stream))
 		}
 
+		runnerOpts = append(runnerOpts, sharedRunnerOpts()...)
+		defer closePlugins()
+
 		r, err := runner.New(accounts, runnerOpts...)
 		if err != nil {
 			exitOnError(err, 1)
 		}
+		logging.SetRunID(r.RunID())
+
+		if healthAddr != "" {
+			server := health.NewServer(healthAddr, r, seconds(healthStall), health.Token(healthToken))
+			if leads != nil {
+				server.HandleProtected("GET /leads", leads)
+			}
+			server.Start()
+
+			defer func() {
+				// the feed's streams never end on their own, so they're closed first.
+				if leads != nil {
+					leads.Close()
+				}
+
+				ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+				defer cancel()
+				_ = server.Stop(ctx)
+			}()
+		}
 
-		if err := r.Start(); err != nil {
+		err = r.Start()
+		logResults(r.Results())
+		logCampaignResults(r.CampaignResults())
+
+		if errors.Is(err, runner.ErrorTimeBudgetExceeded) {
+			exitOnError(err, exitTimeBudget)
+		} else if err != nil {
 			exitOnError(err, 1)
 		}
 	},
 }
 
 func main() {
+	registerCompletions(rootCmd)
+
 	if err := rootCmd.Execute(); err != nil {
 		os.Exit(1)
 	}
@@ -103,11 +368,35 @@ func init() {
 	rootCmd.Version = VERSION
 
 	rootCmd.Flags().
-		StringVarP(&input, "input", "i", "", "path to file containing apollo accounts and scraping instructions")
+		StringVarP(&input, "input", "i", "", "path to file containing apollo accounts and scraping instructions ('-' for stdin)")
+
+	rootCmd.Flags().
+		StringVar(&inputFormat, "input-format", "csv", "format of the accounts read from stdin ('csv', 'json' or 'xlsx')")
+
+	rootCmd.Flags().
+		StringVar(&blacklistFile, "blacklist-file", "", "path to a file listing accounts (one email per line, optionally followed by a reason) whose jobs are dropped")
+
+	rootCmd.Flags().
+		BoolVar(&watchInput, "watch-input", false, "add the accounts appended to the input file to the running queue when it changes or on SIGHUP")
+
+	rootCmd.Flags().
+		BoolVar(&ignoreTimeouts, "ignore-timeouts", false, "discard the accounts' timeouts carried over from previous runs, so that every account is eligible right away")
 
 	rootCmd.Flags().
 		StringVarP(&outputDir, "output-dir", "o", "./scrape-results", "specify path to output directory")
 
+	rootCmd.Flags().
+		StringVar(&outputTemplate, "output-template", "{list}", "name of the output files; '{list}', '{account}', '{run-id}', '{job-id}' and '{date}' are replaced")
+
+	rootCmd.Flags().
+		IntVar(&progressHistory, "progress-history", 0, "also save a timestamped snapshot of the progress file at most this often (in seconds, 0 disables)")
+
+	rootCmd.Flags().
+		StringVar(&progressHistoryTemplate, "progress-history-template", runner.DefaultProgressHistoryTemplate, "name of the progress snapshots; '{run-id}', '{date}', '{hour}' and '{time}' are replaced and '/' makes directories")
+
+	rootCmd.Flags().
+		StringVar(&configFile, "config", "", "path to a JSON configuration file (e.g. for per-action timeouts)")
+
 	rootCmd.Flags().
 		StringVarP(&cookieFile, "cookie-file", "c", "", "specify path to file containing cookies for your Apollo accounts")
 
@@ -117,22 +406,234 @@ func init() {
 	rootCmd.Flags().
 		IntVarP(&timeout, "timeout", "T", 60, "max time allowed for an operation (in seconds)")
 
+	rootCmd.Flags().
+		StringVar(&healthAddr, "health-addr", "", "address on which to serve the health and status endpoints (e.g. ':8080')")
+
+	rootCmd.Flags().
+		StringVar(&healthToken, "health-token", "", "bearer token required to blacklist accounts and stream leads through the health server (required for them unless --health-addr is a loopback address)")
+
+	rootCmd.Flags().
+		BoolVar(&leadFeed, "lead-feed", false, "stream scraped leads as server-sent events at /leads on the health server")
+
+	rootCmd.Flags().
+		StringVar(&eventsSocket, "events-socket", "", "path of a Unix socket (or an existing named pipe) on which to stream JSON progress events")
+
+	rootCmd.Flags().
+		IntVar(&healthStall, "health-stall-timeout", 600, "time without progress after which the scraper is reported as unhealthy (in seconds)")
+
+	rootCmd.Flags().
+		IntVar(&stallTimeout, "stall-timeout", 900, "time without progress after which a job is aborted and requeued (in seconds, 0 disables)")
+
+	rootCmd.Flags().
+		IntVar(&errorBackoff, "error-backoff", 60, "time before a job that failed with an error is retried, doubling with each consecutive failure (in seconds, 0 disables)")
+
+	rootCmd.Flags().
+		IntVar(&maxErrorBackoff, "max-error-backoff", 1800, "longest time before a failed job is retried (in seconds)")
+
+	rootCmd.Flags().
+		IntVar(&maxFailures, "max-failures", 10, "number of errors after which a job is given up on and written to the dead-letter list (0 for no limit)")
+
+	rootCmd.Flags().
+		BoolVar(&classifyFailures, "classify-failures", false, "classify the page a job failed on (captcha, login page, empty results, maintenance or banner overlap) to recover from the failure accordingly")
+
+	rootCmd.Flags().
+		StringVar(&classifierURL, "classifier-url", "", "endpoint of a model that classifies the screenshots of pages jobs failed on, before the built-in heuristics (implies --classify-failures)")
+
+	rootCmd.Flags().
+		IntVar(&maintenanceWait, "maintenance-wait", 900, "time that all jobs wait for once apollo is found down for maintenance by --classify-failures (in seconds)")
+
+	rootCmd.Flags().
+		StringVar(&pauseFile, "pause-file", "", "pause the run after the current page for as long as this file exists (SIGUSR1 and SIGUSR2 also pause and resume it)")
+
+	rootCmd.Flags().
+		IntVar(&maxRuntime, "max-runtime", 0, "save progress and exit with code 3 once the run exceeds this duration (in seconds, 0 disables)")
+
+	rootCmd.Flags().
+		IntVar(&maxJobDuration, "max-job-duration", 0, "save progress and exit with code 3 once a job exceeds this duration (in seconds, 0 disables)")
+
+	rootCmd.Flags().
+		IntVar(&recyclePages, "recycle-pages", 10, "replace the scraping page with a new one after this many pages (0 disables)")
+
+	rootCmd.Flags().
+		BoolVar(&overlapScrape, "overlap-scrape", false, "scrape saved pages of a list in a second tab while the rest are still being saved")
+
+	rootCmd.Flags().
+		BoolVar(&bulkSave, "bulk-save", false, "save all of an account's leads at once when Apollo offers to 'Select all' of a search's leads")
+
+	rootCmd.Flags().
+		BoolVar(&verifySaves, "verify-saves", false, "check that saved leads landed in the account's list before counting them as saved")
+
+	rootCmd.Flags().
+		BoolVar(&apiExport, "api-export", false, "save the leads of accounts with an api-key with the browser and only export their lists through apollo's api")
+
+	rootCmd.Flags().
+		BoolVar(&splitSearches, "split-searches", false, "split lists with more leads than apollo shows (2500) by company size and seniority to scrape all of their leads")
+
+	rootCmd.Flags().
+		BoolVar(&pipelineScrape, "pipeline-scrape", false, "scrape each page of a list while the next one loads")
+
+	rootCmd.Flags().
+		StringVar(&llmURL, "llm-url", "", "chat completions endpoint of an LLM to extract leads with when the scrape script finds none on a page that shows results, e.g. 'https://api.openai.com/v1/chat/completions'")
+
+	rootCmd.Flags().
+		StringVar(&llmAPIKey, "llm-api-key", "", "API key for --llm-url")
+
+	rootCmd.Flags().
+		StringVar(&llmModel, "llm-model", "gpt-4o-mini", "model used by --llm-url")
+
+	rootCmd.Flags().
+		IntVar(&llmMaxCalls, "llm-max-calls", 20, "max number of requests sent to --llm-url per run (0 for no limit)")
+
+	rootCmd.Flags().
+		IntVar(&llmMaxTokens, "llm-max-tokens", 200000, "max number of tokens used by requests to --llm-url per run (0 for no limit)")
+
+	rootCmd.Flags().
+		IntVar(&llmMaxInput, "llm-max-input", 60000, "max number of characters of a page's sanitized html sent to --llm-url, beyond which it's truncated (0 for no limit)")
+
+	rootCmd.Flags().
+		IntVar(&maxBrowserMemory, "max-browser-memory", 0, "restart the browser when its memory usage exceeds this limit (in MiB, 0 disables)")
+
+	rootCmd.Flags().
+		StringVar(&snapshotFormat, "snapshot-format", "png", "image format of error screenshots ('png', 'jpeg' or 'webp')")
+
+	rootCmd.Flags().
+		IntVar(&snapshotQuality, "snapshot-quality", 80, "compression quality of 'jpeg' and 'webp' error screenshots (0-100)")
+
+	rootCmd.Flags().
+		BoolVar(&snapshotFullPage, "snapshot-full-page", false, "capture the whole page in error screenshots instead of just the viewport")
+
+	rootCmd.Flags().
+		BoolVar(&snapshotMHTML, "snapshot-mhtml", false, "additionally capture the complete page as an MHTML archive on errors")
+
+	rootCmd.Flags().
+		BoolVar(&useJournal, "journal", true, "keep a journal of every action taken by each account in the output directory")
+
+	rootCmd.Flags().
+		IntVar(&windowJitter, "window-jitter", 15, "maximum amount by which the activity windows of accounts are randomly shifted each day (in minutes)")
+
+	rootCmd.Flags().
+		IntVar(&warmUpDuration, "warm-up-duration", 20, "duration of the daily warm-up sessions of accounts with a 'warm-up' value (in minutes)")
+
+	rootCmd.Flags().
+		IntVar(&warmUpContacts, "warm-up-contacts", 5, "maximum number of contacts viewed during a warm-up session")
+
+	rootCmd.Flags().
+		StringVar(&fixtureDir, "record-fixtures", "", "save snapshots of the 'People' pages visited to this directory as test fixtures")
+
+	rootCmd.Flags().
+		BoolVar(&useCreditHistory, "credit-history", true, "keep a history of every credit usage fetch in the output directory")
+
+	rootCmd.Flags().
+		StringSliceVar(&pluginPaths, "plugin", nil, "path to a plugin executable implementing one or more extension points (can be repeated)")
+
 	rootCmd.Flags().BoolVar(&debug, "debug", false, "print debugging information")
 
+	rootCmd.Flags().
+		BoolVarP(&quiet, "quiet", "q", false, "only log warnings, errors and per-page summaries")
+
+	rootCmd.Flags().
+		BoolVar(&logCaller, "log-caller", false, "add the file and line each message was logged from")
+
+	rootCmd.Flags().
+		StringSliceVar(&logModules, "log-module", nil, "log level of a module, e.g. 'runner=debug' or 'actions=warn' (can be repeated)")
+
+	rootCmd.Flags().
+		IntVar(&logSample, "log-sample", 0, "max number of identical debug and info messages logged per minute (0 disables)")
+
+	rootCmd.Flags().
+		StringVar(&otlpEndpoint, "otlp-endpoint", "", "export traces with OTLP over HTTP to this URL, e.g. 'http://localhost:4318'")
+
 	rootCmd.Flags().
 		BoolVarP(&fetchCredits, "fetch-credits", "f", false, "fetch credit usage for apollo accounts")
 
+	rootCmd.Flags().
+		IntVar(&prefetchLogins, "prefetch-logins", 0, "log all accounts in before scraping, this many at a time, to refresh their cookies and credits up front (0 to disable)")
+
+	rootCmd.Flags().
+		IntVar(&prefetchSpread, "prefetch-spread", 60, "time over which the logins of --prefetch-logins are spread (in seconds)")
+
 	rootCmd.Flags().BoolVarP(&headless, "headless", "H", true, "run browser in headless mode")
 
+	rootCmd.Flags().
+		BoolVar(&headfulVirtual, "headful-virtual", false, "run a headful browser, on a virtual display (Xvfb) on Linux servers without one")
+
 	rootCmd.Flags().
 		BoolVar(&stealth, "stealth", false, "specify whether or not to inject stealth script at every page load")
 
+	rootCmd.Flags().
+		BoolVar(&deviceProfiles, "device-profiles", false, "make each account's browser look like the same device across runs")
+
+	rootCmd.Flags().
+		BoolVar(&mobileView, "mobile-view", false, "emulate phones, so that Apollo serves its mobile web layout")
+
+	rootCmd.Flags().
+		StringSliceVar(&annoyances, "annoyances", nil, "specify the apollo.io annoyances to look out for ('banner', 'new-ui', 'pop-up' or 'sidenav')")
+
+	rootCmd.Flags().
+		IntVar(&annoyanceTimeout, "annoyance-timeout", 5, "max time allowed for checking all annoyances at once (in seconds)")
+
+	rootCmd.Flags().
+		BoolVar(&watchAnnoyances, "watch-annoyances", true, "remove annoyances in the background as soon as they appear")
+
 	rootCmd.Flags().BoolVar(&csvOut, "csv", false, "save output files in CSV format")
 
 	rootCmd.Flags().BoolVar(&jsonOut, "json", false, "save output files in JSON format")
 
+	rootCmd.Flags().BoolVar(&gzipOut, "gzip", false, "gzip-compress output files")
+
+	rootCmd.Flags().
+		StringSliceVar(&webhooks, "webhook", nil, "URL to POST scraped leads to as JSON, alongside the output files (can be repeated)")
+
+	rootCmd.Flags().
+		StringSliceVar(&buses, "bus", nil, "message bus to publish scraped leads to, i.e. a NATS subject ('nats://host:4222/subject'), a Kafka topic ('kafka://broker:9092/topic') or a Kafka topic through the REST Proxy ('kafka-rest+https://proxy:8082/v3/clusters/ID/topics/TOPIC') (can be repeated)")
+
 	rootCmd.Flags().
-		StringVarP(&tab, "tab", "t", "new", "specify the apollo.io tab from which leads will be scraped ('new', 'saved' or 'total')")
+		StringVar(&busKey, "bus-key", bus.DefaultKey, "lead field to key messages published to --bus by")
+
+	rootCmd.Flags().
+		StringSliceVar(&busHeaders, "bus-header", bus.DefaultHeaders, "lead fields to send as the headers of messages published to --bus")
+
+	rootCmd.Flags().
+		StringVar(&scrubPII, "scrub-pii", "", "scrub the emails and phone numbers of leads before they're written ('hash' or 'remove')")
+
+	rootCmd.Flags().
+		IntVar(&retentionDays, "retention-days", 0, "purge error snapshots, journal entries, downloads, progress snapshots and output files older than this many days on start (0 keeps everything)")
+
+	rootCmd.Flags().
+		IntVar(&asyncWrites, "async-writes", 0, "write leads in the background, queueing up to this many pages per job before scraping waits (0 writes synchronously)")
+
+	rootCmd.Flags().
+		BoolVar(&simulate, "simulate", false, "simulate the run against a fake Apollo, without a browser, to check how its scheduling and limits play out")
+
+	rootCmd.Flags().
+		IntVar(&simulateDays, "simulate-days", 90, "the longest time (in days) that --simulate simulates the run for")
+
+	rootCmd.Flags().
+		IntVar(&simulateLatency, "simulate-latency", 20, "the average time (in seconds) that saving a page takes with --simulate")
+
+	rootCmd.Flags().
+		Float64Var(&simulateErrorRate, "simulate-error-rate", 0, "the probability (from 0 to 1) that an action fails with --simulate")
+
+	rootCmd.Flags().
+		Uint64Var(&simulateSeed, "simulate-seed", 0, "seed of the random latencies and failures of --simulate, to repeat a simulation")
+
+	rootCmd.Flags().
+		IntVar(&partitionSize, "partition-size", 0, "start a new output file once the current one reaches this size (in MiB, 0 disables)")
+
+	rootCmd.Flags().
+		BoolVar(&partitionByDate, "partition-by-date", false, "write leads to a separate output file per day, e.g. '<list>-2025-06-01.csv'")
+
+	rootCmd.Flags().
+		StringVarP(&tab, "tab", "t", "new", "specify the apollo.io tab from which leads will be scraped ('new', 'saved' or 'total'); leads are only saved to the account's list from 'new'")
+
+	vpnProviderFlags(rootCmd.Flags())
+
+	limitFlags(rootCmd.Flags())
+
+	networkFlags(rootCmd.Flags())
+
+	rootCmd.Flags().
+		StringVar(&dedupeStore, "dedupe-store", "", "path to a file indexing the leads captured by every account across runs, so that they aren't saved again")
 
 	rootCmd.Flags().
 		StringVar(&vpnConfigs, "vpn-configs-dir", "", "path to directory containing OpenVPN configuration files")
@@ -143,6 +644,21 @@ func init() {
 	rootCmd.Flags().
 		StringVar(&vpnArgs, "vpn-args", "", "specify arguments to use with OpenVPN")
 
+	rootCmd.Flags().
+		IntVar(&vpnCooldown, "vpn-cooldown", 0, "time for which a used OpenVPN config isn't reused, even across runs (in hours, 0 only avoids reuse within a run)")
+
+	rootCmd.Flags().
+		StringVar(&vpnFailover, "vpn-failover", "none", "what to do when no OpenVPN config connects ('none' fails the job, 'proxy' falls back to a proxy and 'direct' to a proxy or a direct connection)")
+
+	rootCmd.Flags().
+		StringSliceVar(&proxies, "proxy", nil, "proxy to fall back to when no OpenVPN config connects, e.g. 'socks5://127.0.0.1:1080' (can be repeated)")
+
+	rootCmd.Flags().
+		BoolVar(&vpnSplitTunnel, "vpn-split-tunnel", false, "only route the browser's traffic through OpenVPN, leaving other traffic (e.g. webhooks) on the host's network (Linux only)")
+
+	rootCmd.Flags().
+		StringVar(&vpnStateFile, "vpn-state", "", "path to the file recording when each OpenVPN config was last used (defaults to 'vpn-state.json' in the output directory)")
+
 	if err := rootCmd.MarkFlagRequired("input"); err != nil {
 		fmt.Fprintln(os.Stderr, "Error:", err)
 		os.Exit(1)
@@ -154,7 +670,378 @@ func init() {
 	rootCmd.MarkFlagsOneRequired("csv", "json")
 }
 
+// initTracing starts exporting traces to the OTLP endpoint (if any), returning a function that
+// flushes the remaining spans before exiting.
+func initTracing() func() {
+	shutdown, enabled, err := tracing.Init(context.Background(), otlpEndpoint, VERSION)
+	if err != nil {
+		exitOnError(err, 1)
+	}
+
+	if enabled {
+		log.Info().Msg("exporting traces with otlp")
+	}
+
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		if err := shutdown(ctx); err != nil {
+			log.Warn().Err(err).Msg("failed to flush traces")
+		}
+	}
+}
+
+// initLogging configures the global logger from the logging flags.
+func initLogging() {
+	config := logging.Config{
+		Level:        zerolog.InfoLevel,
+		SampleBurst:  logSample,
+		SamplePeriod: time.Minute,
+		Caller:       logCaller,
+		Quiet:        quiet,
+	}
+	if debug {
+		config.Level = zerolog.DebugLevel
+	}
+
+	var err error
+	if config.Modules, err = logging.ParseModuleLevels(logModules); err != nil {
+		exitOnError(err, 1)
+	}
+
+	logging.Configure(config)
+}
+
+// logResults logs a summary of the outcome of every account's job.
+func logResults(results []runner.JobResult) {
+	for _, result := range results {
+		logging.Summary(log.Info()).
+			Str("account", result.Account).
+			Str("status", string(result.Status)).
+			Int("saved", result.Saved).
+			Int("scraped", result.Scraped).
+			Int("team-owned", result.TeamOwned).
+			Int64("bytes-received", result.Traffic.Received).
+			Int64("bytes-sent", result.Traffic.Sent).
+			Dur("duration", result.Duration).
+			Strs("outputs", result.Outputs).
+			AnErr("error", result.Err).
+			Msg("job result")
+	}
+}
+
+// logCampaignResults logs a summary of the outcome of every campaign.
+func logCampaignResults(results []runner.CampaignResult) {
+	for _, result := range results {
+		logging.Summary(log.Info()).
+			Str("campaign", result.Campaign).
+			Int("accounts", result.Accounts).
+			Int("finished", result.Finished).
+			Int("pending", result.Pending).
+			Int("failed", result.Failed).
+			Int("saved", result.Saved).
+			Int("scraped", result.Scraped).
+			Int64("bytes-received", result.BytesReceived).
+			Int64("bytes-sent", result.BytesSent).
+			Msg("campaign result")
+	}
+}
+
+// readAccounts reads the accounts from the input file, or from the standard input in the input
+// format if the file is '-'.
+func readAccounts() []*models.Account {
+	var accounts []*models.Account
+	if input != io.Stdio {
+		if err := io.ReadRecords(input, &accounts); err != nil {
+			exitOnError(err, 1)
+		}
+		return accounts
+	}
+
+	format, err := io.ParseFileFormat(inputFormat)
+	if err != nil {
+		exitOnError(err, 1)
+	}
+
+	if err := io.ReadRecordsFrom(os.Stdin, format, &accounts); err != nil {
+		exitOnError(err, 1)
+	}
+
+	return accounts
+}
+
+// configTagRules returns the tagging rules of the config file.
+func configTagRules(cfg *config.Config) []*tagging.Rule {
+	var rules []*tagging.Rule
+	for _, t := range cfg.Tags {
+		rule, err := tagging.NewRule(t.Tag, t.Pattern, t.Fields...)
+		if err != nil {
+			exitOnError(err, 1)
+		}
+		rules = append(rules, rule)
+	}
+
+	return rules
+}
+
+// configScorer returns the score expression of the config file, if it has one.
+func configScorer(cfg *config.Config) *scoring.Expression {
+	if cfg.Score == "" {
+		return nil
+	}
+
+	expr, err := scoring.Compile(cfg.Score)
+	if err != nil {
+		exitOnError(fmt.Errorf("invalid score expression: %w", err), 1)
+	}
+
+	return expr
+}
+
+// costRates converts the costs of the config file to [runner.CostRates].
+func costRates(c *config.Costs) *runner.CostRates {
+	return &runner.CostRates{Currency: c.Currency, Credit: c.Credit, VpnHour: c.VpnHour}
+}
+
+// sharedRunnerOpts returns the [runner.RunnerOpt]s for the config file, cookie file, VPN and
+// plugin flags shared by commands.
+func sharedRunnerOpts() []runner.RunnerOpt {
+	var runnerOpts []runner.RunnerOpt
+	if configFile != "" {
+		cfg, err := config.Load(configFile)
+		if err != nil {
+			exitOnError(err, 1)
+		}
+
+		runnerOpts = append(runnerOpts, runner.Timeouts(runner.ActionTimeouts{
+			Login:      seconds(cfg.Timeouts.Login),
+			TabSelect:  seconds(cfg.Timeouts.TabSelect),
+			SaveDialog: seconds(cfg.Timeouts.SaveDialog),
+			TableLoad:  seconds(cfg.Timeouts.TableLoad),
+			Credits:    seconds(cfg.Timeouts.Credits),
+		}))
+
+		for _, l := range cfg.Credits.Locales {
+			locale, err := actions.NewCreditLocale(l.Name, l.Usage, l.Renewal, l.Plan, l.Layouts)
+			if err != nil {
+				exitOnError(fmt.Errorf("invalid credit locale %q: %w", l.Name, err), 1)
+			}
+
+			runnerOpts = append(runnerOpts, runner.CreditLocales(locale))
+		}
+
+		runnerOpts = append(runnerOpts, runner.TagRules(configTagRules(cfg)...))
+
+		if cfg.ApolloURL != "" {
+			url, err := actions.NormalizeApolloURL(cfg.ApolloURL)
+			if err != nil {
+				exitOnError(err, 1)
+			}
+
+			runnerOpts = append(runnerOpts, runner.ApolloURL(url))
+		}
+		runnerOpts = append(runnerOpts, runner.Regions(cfg.Regions), runner.APIURL(cfg.APIURL))
+
+		// the cost report is written if any costs are set, those of the campaigns without any being
+		// estimated with the top-level ones (zero if unset).
+		costs := cfg.Costs != nil
+		for _, c := range cfg.Campaigns {
+			campaign := &runner.Campaign{
+				Name:           c.Name,
+				OutputDir:      c.OutputDir,
+				OutputTemplate: c.OutputTemplate,
+				DailyLimit:     c.DailyLimit,
+			}
+			if c.MaxSavesPerHour > 0 || c.MaxConcurrentLogins > 0 {
+				campaign.Limiter = limiter.New(c.MaxSavesPerHour, c.MaxConcurrentLogins, c.LimitsFile)
+			}
+			if c.Costs != nil {
+				campaign.CostRates = costRates(c.Costs)
+				costs = true
+			}
+
+			runnerOpts = append(runnerOpts, runner.Campaigns(campaign))
+		}
+
+		if costs {
+			rates := runner.CostRates{}
+			if cfg.Costs != nil {
+				rates = *costRates(cfg.Costs)
+			}
+
+			runnerOpts = append(runnerOpts, runner.CostReport(rates))
+		}
+
+		if expr := configScorer(cfg); expr != nil {
+			runnerOpts = append(runnerOpts, runner.Scorer(expr))
+		}
+	}
+
+	if cookieFile != "" {
+		runnerOpts = append(runnerOpts, runner.CookieFile(cookieFile))
+	}
+
+	if browserCacheDir != "" {
+		runnerOpts = append(runnerOpts, runner.BrowserCacheDir(browserCacheDir))
+	}
+
+	if blockResources {
+		runnerOpts = append(runnerOpts, runner.BlockResources(actions.NewResourceBlocker(blockURLs, allowURLs)))
+	}
+
+	runnerOpts = append(runnerOpts, runner.BrowserFlags(browserFlags...), runner.Extensions(extensions...))
+
+	if vpn := vpnProvider(); vpn != nil {
+		policy, err := runner.ParseFailoverPolicy(vpnFailover)
+		if err != nil {
+			exitOnError(err, 1)
+		}
+
+		for i, proxy := range proxies {
+			if err := runner.ValidateProxy(proxy); err != nil {
+				exitOnError(fmt.Errorf("invalid --proxy %d: %w", i+1, err), 1)
+			}
+		}
+
+		runnerOpts = append(runnerOpts, runner.VpnManager(vpn), runner.Failover(policy, proxies...))
+	}
+
+	if maxSavesPerHour > 0 || maxConcurrentLogins > 0 {
+		runnerOpts = append(runnerOpts, runner.Limits(limiter.New(maxSavesPerHour, maxConcurrentLogins, limitsFile)))
+	}
+
+	for _, path := range pluginPaths {
+		p, err := plugin.Load(path)
+		if err != nil {
+			exitOnError(fmt.Errorf("failed to load plugin %q: %w", path, err), 1)
+		}
+		plugins = append(plugins, p)
+
+		runnerOpts = append(runnerOpts, pluginOpts(p, filepath.Base(path))...)
+	}
+
+	return runnerOpts
+}
+
+// vpnProviderFlags adds the flags selecting and configuring the VPN provider to the given flag set.
+func vpnProviderFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&vpnProviderName, "vpn-provider", "openvpn", "VPN to connect through: 'openvpn' spawns OpenVPN, 'gluetun' and 'tailscale' switch the exit node of a Gluetun container or the tailnet")
+
+	flags.StringVar(&gluetunURL, "gluetun-url", "http://127.0.0.1:8000", "URL of Gluetun's control server")
+
+	flags.StringVar(&gluetunAPIKey, "gluetun-api-key", "", "API key for Gluetun's control server")
+
+	flags.StringVar(&gluetunProxy, "gluetun-proxy", "http://127.0.0.1:8888", "URL of Gluetun's HTTP proxy, through which the browser connects")
+}
+
+// limitFlags adds the flags capping the activity of all accounts to the given flag set.
+func limitFlags(flags *pflag.FlagSet) {
+	flags.IntVar(&maxSavesPerHour, "max-saves-per-hour", 0, "max number of leads saved per hour across all accounts (0 for no limit)")
+
+	flags.IntVar(&maxConcurrentLogins, "max-concurrent-logins", 0, "max number of accounts logging in at the same time (0 for no limit)")
+
+	flags.StringVar(&limitsFile, "limits-file", "", "path to a file in which the caps' state is kept, so that they're shared by every scrapollo process using it")
+}
+
+// networkFlags adds the flags configuring the browser's requests to the given flag set.
+func networkFlags(flags *pflag.FlagSet) {
+	flags.StringVar(&browserCacheDir, "browser-cache-dir", "", "directory in which the browsers keep their HTTP cache, so that Apollo's assets are downloaded once across browsers and runs")
+
+	flags.BoolVar(&blockResources, "block-resources", false, "block images, fonts, analytics beacons and third-party trackers to speed up page loads")
+
+	flags.StringSliceVar(&blockURLs, "block-url", nil, "URL pattern to block along with the defaults of --block-resources, e.g. '*://*.example.com/*' (can be repeated)")
+
+	flags.StringSliceVar(&allowURLs, "allow-url", nil, "URL pattern that --block-resources must never block (can be repeated)")
+
+	flags.StringArrayVar(&browserFlags, "browser-flag", nil, "additional Chrome flag to launch the browsers with, e.g. '--lang=de-DE', or '!name' to remove one of the default flags (can be repeated)")
+
+	flags.StringArrayVar(&extensions, "extension", nil, "directory of an unpacked Chrome extension to load into the browsers (can be repeated)")
+}
+
+// vpnProvider returns the [runner.VpnProvider] selected by the VPN flags, or nil if no VPN is used.
+func vpnProvider() runner.VpnProvider {
+	switch vpnProviderName {
+	case "openvpn":
+		if vpnConfigs == "" {
+			return nil
+		}
+
+		stateFile := vpnStateFile
+		if stateFile == "" {
+			stateFile = filepath.Join(outputDir, "vpn-state.json")
+		}
+
+		vpn, err := openvpn.NewManager(
+			vpnConfigs,
+			vpnCredentialsFile,
+			vpnArgs,
+			openvpn.Cooldown(time.Duration(vpnCooldown)*time.Hour),
+			openvpn.StateFile(stateFile),
+			openvpn.SplitTunnel(vpnSplitTunnel),
+		)
+		if err != nil {
+			exitOnError(err, 1)
+		}
+		return vpn
+
+	case "gluetun":
+		return exitnode.NewGluetun(gluetunURL, gluetunAPIKey, gluetunProxy)
+
+	case "tailscale":
+		vpn, err := exitnode.NewTailscale()
+		if err != nil {
+			exitOnError(err, 1)
+		}
+		return vpn
+
+	default:
+		exitOnError(fmt.Errorf("unsupported vpn provider: %q", vpnProviderName), 1)
+		return nil
+	}
+}
+
+func pluginOpts(p *plugin.Plugin, name string) []runner.RunnerOpt {
+	var opts []runner.RunnerOpt
+	if p.LeadWriter != nil {
+		opts = append(opts, runner.Sinks(io.Sink{Name: "plugin:" + name, Writer: p.LeadWriter}))
+	}
+
+	if p.Scheduler != nil {
+		opts = append(opts, runner.Scheduler(p.Scheduler))
+	}
+
+	if p.CaptchaSolver != nil {
+		opts = append(opts, runner.CaptchaSolver(p.CaptchaSolver))
+	}
+
+	if p.Notifier != nil {
+		opts = append(opts, runner.Notifiers(p.Notifier))
+	}
+
+	if p.LeadScorer != nil {
+		opts = append(opts, runner.Scorer(p.LeadScorer))
+	}
+
+	if p.PageHook != nil {
+		opts = append(opts, runner.PageHooks(p.PageHook))
+	}
+
+	return opts
+}
+
+func closePlugins() {
+	for _, p := range plugins {
+		p.Close()
+	}
+}
+
+func seconds(s int) time.Duration {
+	return time.Duration(s) * time.Second
+}
+
 func exitOnError(err error, code int) {
+	closePlugins()
 	fmt.Fprintln(os.Stderr, "Error:", err)
 	os.Exit(code)
 }
@@ -0,0 +1,90 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+
+	"github.com/devsheke/scrapollo/internal/io"
+	"github.com/devsheke/scrapollo/internal/logging"
+	"github.com/devsheke/scrapollo/internal/merge"
+	"github.com/devsheke/scrapollo/internal/models"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+var (
+	mergeOutput string
+	mergeRules  []string
+)
+
+var mergeCmd = &cobra.Command{
+	Use:   "merge file...",
+	Short: "Combine output files into a single deduplicated file",
+	Long: `Combine output files (e.g. one per list) into a single deduplicated file.
+
+The input and output files may be CSV, JSON or XLSX files. Leads are matched by their LinkedIn
+profile, or by their name and company if they don't have one. When a lead appears more than once,
+the conflict resolution rules are applied in order until one of them prefers a row: 'email' keeps
+rows with an email and 'recent' keeps the most recently scraped row. If none does, the first row
+is kept.`,
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		logging.Init(debug)
+
+		var rules []merge.Rule
+		for _, s := range mergeRules {
+			rule, err := merge.ParseRule(s)
+			if err != nil {
+				exitOnError(err, 1)
+			}
+			rules = append(rules, rule)
+		}
+
+		var leads []*models.Lead
+		for _, file := range args {
+			l, err := io.ReadLeads(file)
+			if err != nil {
+				exitOnError(fmt.Errorf("failed to read leads from %q: %w", file, err), 1)
+			}
+
+			log.Debug().Str("file", file).Int("leads", len(l)).Msg("read leads")
+			leads = append(leads, l...)
+		}
+
+		merged := merge.Merge(leads, rules...)
+		if err := io.SaveRecords(mergeOutput, merged); err != nil {
+			exitOnError(err, 1)
+		}
+
+		log.Info().
+			Str("file", mergeOutput).
+			Int("leads", len(merged)).
+			Int("duplicates", len(leads)-len(merged)).
+			Msg("saved merged leads")
+	},
+}
+
+func init() {
+	flags := mergeCmd.Flags()
+
+	flags.StringVarP(&mergeOutput, "output", "o", "./leads.csv", "path to the merged file (CSV, JSON or XLSX)")
+
+	flags.StringSliceVar(&mergeRules, "resolve", []string{"email", "recent"}, "rules resolving conflicts between rows of the same lead, in order ('email' or 'recent')")
+
+	flags.BoolVar(&debug, "debug", false, "print debugging information")
+
+	rootCmd.AddCommand(mergeCmd)
+}
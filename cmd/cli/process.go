@@ -0,0 +1,141 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/devsheke/scrapollo/internal/config"
+	"github.com/devsheke/scrapollo/internal/io"
+	"github.com/devsheke/scrapollo/internal/logging"
+	"github.com/devsheke/scrapollo/internal/privacy"
+	"github.com/devsheke/scrapollo/internal/process"
+	"github.com/devsheke/scrapollo/internal/scoring"
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+var (
+	processConfig, processFilter string
+	processOutput, processScore  string
+	processScrub                 string
+)
+
+var processCmd = &cobra.Command{
+	Use:   "process file",
+	Short: "Apply the normalisation, tagging, scoring and filtering of leads to an existing output file",
+	Long: `Apply the normalisation, tagging, scoring and filtering of leads to an existing output file,
+e.g. to reprocess earlier scrapes once new tagging rules or a new score expression have been added.
+
+The input and output files may be CSV, JSON or XLSX files. The employee counts and locations of the
+leads are parsed again, the leads are tagged with the rules and scored with the expression of the
+config file (or --score), the leads for which the --filter expression is 0 or false are left out
+and the emails and phone numbers of the others are scrubbed with --scrub-pii. Steps which aren't
+configured leave the leads as they are.`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		logging.Init(debug)
+
+		var pipeline process.Pipeline
+		if processConfig != "" {
+			cfg, err := config.Load(processConfig)
+			if err != nil {
+				exitOnError(err, 1)
+			}
+
+			pipeline.Tags = configTagRules(cfg)
+			if expr := configScorer(cfg); expr != nil {
+				pipeline.Scorer = expr
+			}
+		}
+
+		if processScore != "" {
+			expr, err := scoring.Compile(processScore)
+			if err != nil {
+				exitOnError(fmt.Errorf("invalid score expression: %w", err), 1)
+			}
+			pipeline.Scorer = expr
+		}
+
+		if processFilter != "" {
+			expr, err := scoring.Compile(processFilter)
+			if err != nil {
+				exitOnError(fmt.Errorf("invalid filter expression: %w", err), 1)
+			}
+			pipeline.Filter = expr
+		}
+
+		scrub, err := privacy.ParseScrubMode(processScrub)
+		if err != nil {
+			exitOnError(err, 1)
+		}
+		pipeline.Scrub = scrub
+
+		file := args[0]
+		leads, err := io.ReadLeads(file)
+		if err != nil {
+			exitOnError(fmt.Errorf("failed to read leads from %q: %w", file, err), 1)
+		}
+
+		kept, err := pipeline.Process(leads)
+		if err != nil {
+			log.Warn().Err(err).Msg("failed to process some leads")
+		}
+
+		output := processOutput
+		if output == "" {
+			output = processedName(file)
+		}
+
+		if err := io.SaveRecords(output, kept); err != nil {
+			exitOnError(err, 1)
+		}
+
+		log.Info().
+			Str("file", output).
+			Int("leads", len(kept)).
+			Int("filtered", len(leads)-len(kept)).
+			Msg("saved processed leads")
+	},
+}
+
+// processedName returns the default name of the file that the processed leads of the given output
+// file are saved to, e.g. 'leads-processed.csv' for 'leads.csv' or 'leads.csv.gz'.
+func processedName(file string) string {
+	file = strings.TrimSuffix(file, ".gz")
+	ext := filepath.Ext(file)
+
+	return strings.TrimSuffix(file, ext) + "-processed" + ext
+}
+
+func init() {
+	flags := processCmd.Flags()
+
+	flags.StringVarP(&processOutput, "output", "o", "", "path to the processed file (CSV, JSON or XLSX; defaults to '<file>-processed')")
+
+	flags.StringVar(&processConfig, "config", "", "path to a JSON configuration file whose tagging rules and score expression are applied")
+
+	flags.StringVar(&processScore, "score", "", "expression computing the score of every lead, in place of the config file's")
+
+	flags.StringVar(&processFilter, "filter", "", "expression keeping only the leads for which it isn't 0 or false (e.g. 'country == \"Germany\"')")
+
+	flags.StringVar(&processScrub, "scrub-pii", "", "scrub the emails and phone numbers of the leads ('hash' or 'remove')")
+
+	flags.BoolVar(&debug, "debug", false, "print debugging information")
+
+	rootCmd.AddCommand(processCmd)
+}
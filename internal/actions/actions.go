@@ -16,19 +16,24 @@ package actions
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"net/url"
 	"os"
 	"path/filepath"
-	"strconv"
+	"regexp"
 	"strings"
 	"time"
 
-	"github.com/devsheke/scrapollo/internal/models"
+	"github.com/devsheke/scrapollo/internal/parse"
+	"github.com/devsheke/scrapollo/internal/tracing"
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/input"
 	"github.com/go-rod/rod/lib/proto"
-	"github.com/rs/zerolog/log"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var (
@@ -39,6 +44,13 @@ var (
 	// ErrorNavButtonsNotFound is an error returned when the page navigation buttons are not found
 	// on the current page.
 	ErrorNavButtonsNotFound = errors.New("failed to find page navigation buttons")
+
+	// ErrorPageNotAdvanced is returned when clicking the next page button doesn't load the next page.
+	ErrorPageNotAdvanced = errors.New("the next page didn't load")
+
+	// ErrorPageSkipped is returned when the page advanced past the next page (e.g. because both clicks
+	// on the next page button went through) and navigating back to the next page failed.
+	ErrorPageSkipped = errors.New("the next page was skipped")
 )
 
 // PageData represents all available data regarding the page number,
@@ -50,46 +62,123 @@ type PageData struct {
 }
 
 // NextPage is a method for navigating to the next available page for
-// the given set of filters.
-func (pd *PageData) NextPage(page *rod.Page) error {
-	log.Debug().Msg("going to next page")
+// the given set of filters. It waits up to the provided timeout for the
+// next page's results to be shown, clicking the next page button again
+// halfway through if they aren't, as clicks are sometimes lost while the
+// page is busy. If the page still hasn't advanced, [ErrorPageNotAdvanced]
+// is returned. If it advanced past the next page, it is navigated back to
+// the next page, and [ErrorPageSkipped] is returned if that fails.
+func (pd *PageData) NextPage(page *rod.Page, timeout time.Duration) (err error) {
+	page, span := startSpan(page, "NextPage")
+	defer func() { tracing.End(span, err) }()
+
+	logger(page).Debug().Msg("going to next page")
 
 	if pd.LastPage {
 		return ErrorListEnd
 	}
 
-	switch btn := pd.NavButtons.Last(); btn {
-	case nil:
+	btn := pd.NavButtons.Last()
+	if btn == nil {
 		return ErrorNavButtonsNotFound
+	}
+
+	for attempt := range 2 {
+		if attempt > 0 {
+			logger(page).Warn().Int("page", pd.Number).Msg("next page didn't load; clicking the next page button again")
+
+			// the button may have been replaced since it was located.
+			buttons, err := navButtons(page)
+			if err != nil {
+				return err
+			}
+			btn = buttons.Last()
+		}
+
+		if err := btn.Click(proto.InputMouseButtonLeft, 1); err != nil {
+			return err
+		}
+
+		start := waitForResults(page, pd.Start, timeout/2)
+		if start == 0 {
+			continue
+		}
+
+		if err := pd.checkAdvance(start); !errors.Is(err, ErrorPageSkipped) {
+			return err
+		}
+
+		logger(page).Warn().
+			Int("page", pd.Number).
+			Int("start", start).
+			Msg("skipped past the next page; navigating back to it")
+
+		if err := GoToPage(page, pd.Number+1, timeout); err != nil {
+			return errors.Join(ErrorPageSkipped, err)
+		}
+
+		if start = waitForResults(page, start, timeout/2); start == 0 {
+			return ErrorPageSkipped
+		}
+		return pd.checkAdvance(start)
+	}
+
+	return ErrorPageNotAdvanced
+}
 
+// checkAdvance checks that the results starting at the provided index are those of the page that
+// follows the one described by the [*PageData]. [ErrorPageSkipped] is returned if they are those
+// of a later page, and [ErrorPageNotAdvanced] if they aren't those of a later page at all.
+func (pd *PageData) checkAdvance(start int) error {
+	switch {
+	case start == pd.End+1:
+		return nil
+	case start > pd.End+1:
+		return ErrorPageSkipped
 	default:
-		return btn.Click(proto.InputMouseButtonLeft, 1)
+		return ErrorPageNotAdvanced
+	}
+}
+
+// waitForResults waits up to the provided timeout for the page to show results starting at another
+// index than the provided one, returning the index of the first result shown, or 0 if it doesn't.
+func waitForResults(page *rod.Page, from int, timeout time.Duration) int {
+	sel := selectors(page)
+	for deadline := time.Now().Add(timeout); time.Now().Before(deadline); {
+		if page.GetContext().Err() != nil {
+			return 0
+		}
+
+		if info, err := page.Elements(sel.PageInfo); err == nil && !info.Empty() {
+			if text, err := info.First().Text(); err == nil {
+				if start, _, _, err := parse.Range(text); err == nil && start != from {
+					return start
+				}
+			}
+		}
+
+		time.Sleep(100 * time.Millisecond)
 	}
+
+	return 0
 }
 
 // GetPageData is a page action that returns a [*PageData] value representing all available
 // data regarding the page number, size, etc,. This function only works on the 'People' page
 // on Apollo. This function assumes you're on the 'People' page on Apollo.
 func GetPageData(page *rod.Page, timeout time.Duration) (pd *PageData, err error) {
-	log.Debug().Msg("getting page data")
+	page, span := startSpan(page, "GetPageData")
+	defer func() { tracing.End(span, err) }()
 
-	err = rod.Try(func() {
-		log.Debug().Msg("parsing page size information")
+	logger(page).Debug().Msg("getting page data")
 
-		info := strings.Split(
-			page.Timeout(timeout).MustElement(".zp_xAPpZ").MustWaitVisible().MustText(),
-			" ",
-		)
-
-		if pd.Start, err = strconv.Atoi(info[0]); err != nil {
-			panic(err)
-		}
-
-		if pd.End, err = strconv.Atoi(info[2]); err != nil {
-			panic(err)
-		}
+	pd = new(PageData)
+	sel := selectors(page)
+	err = rod.Try(func() {
+		logger(page).Debug().Msg("parsing page size information")
 
-		if pd.TotalSize, err = strconv.Atoi(strings.ReplaceAll(info[4], ",", "")); err != nil {
+		info := page.Timeout(timeout).MustElement(sel.PageInfo).MustWaitVisible().MustText()
+		if pd.Start, pd.End, pd.TotalSize, err = parse.Range(info); err != nil {
 			panic(err)
 		}
 
@@ -99,7 +188,7 @@ func GetPageData(page *rod.Page, timeout time.Duration) (pd *PageData, err error
 	if errors.Is(err, context.DeadlineExceeded) {
 		err := rod.Try(func() {
 			page.Timeout(20*time.Second).
-				MustElementR(".zp_MVq1c", "No people match your criteria").
+				MustElementR(sel.NoResults, "No people match your criteria").
 				MustWaitVisible()
 		})
 
@@ -113,20 +202,20 @@ func GetPageData(page *rod.Page, timeout time.Duration) (pd *PageData, err error
 	}
 
 	err = rod.Try(func() {
-		log.Debug().Msg("getting page navigation information")
+		logger(page).Debug().Msg("getting page navigation information")
 
 		numText := page.Timeout(20 * time.Second).
-			MustElement(".zp_jzp8p").
+			MustElement(sel.PageNumber).
 			MustWaitVisible().
 			MustText()
 
-		if pd.Number, err = strconv.Atoi(numText); err != nil {
-			panic(err)
+		if pd.Number, err = parse.FindCount(numText); err != nil {
+			panic(fmt.Errorf("failed to parse page number %q: %w", numText, err))
 		}
 
-		navBtns := page.MustElements(".zp_m_JQ3 > .zp_qe0Li.zp_S5tZC")
-		if len(navBtns) < 2 {
-			panic(fmt.Errorf("not enough page buttons found"))
+		navBtns, err := navButtons(page)
+		if err != nil {
+			panic(err)
 		}
 
 		if attr, err := navBtns.Last().Attribute("disabled"); err != nil {
@@ -141,76 +230,272 @@ func GetPageData(page *rod.Page, timeout time.Duration) (pd *PageData, err error
 	return pd, err
 }
 
+// navButtons returns the page's navigation buttons, the last of which goes to the next page. If they
+// can't be found, the next page button is located by its label instead.
+func navButtons(page *rod.Page) (rod.Elements, error) {
+	sel := selectors(page)
+
+	buttons, err := page.Elements(sel.NavButtons)
+	if err != nil {
+		return nil, err
+	} else if len(buttons) >= 2 {
+		return buttons, nil
+	}
+
+	next, err := nextPageButton.find(page)
+	if err != nil {
+		return nil, err
+	} else if next == nil {
+		return nil, fmt.Errorf("not enough page buttons found")
+	}
+
+	logger(page).Warn().
+		Str("selector", sel.NavButtons).
+		Msg("page buttons not found; located next page button by its label instead")
+
+	return rod.Elements{next}, nil
+}
+
 // GoToPage is a page navigation function that navigates to the specified page
 // number on the 'People' page on Apollo. This function assumes you're on the 'People'
 // page on Apollo.
-func GoToPage(page *rod.Page, pageNumber int, timeout time.Duration) error {
-	log.Debug().Int("number", pageNumber).Msg("navigating to page")
+func GoToPage(page *rod.Page, pageNumber int, timeout time.Duration) (err error) {
+	page, span := startSpan(page, "GoToPage", attribute.Int("page", pageNumber))
+	defer func() { tracing.End(span, err) }()
 
+	logger(page).Debug().Int("number", pageNumber).Msg("navigating to page")
+
+	sel := selectors(page)
 	page = page.Timeout(timeout)
-	err := rod.Try(func() {
-		page.MustElement(".zp_VTl3h.zp_xqxgc .zp_dJ2fA").MustWaitVisible()
+	err = rod.Try(func() {
+		page.MustElement(sel.PageSwitch).MustWaitVisible()
 
-		inputs := page.MustElements(".zp_VTl3h.zp_xqxgc .zp_dJ2fA")
+		inputs := page.MustElements(sel.PageSwitch)
 		if len(inputs) < 2 {
 			panic("could not find page control switch")
 		}
 
 		inputs[1].MustClick()
 
-		listbox := page.MustElement("[role=listbox]").MustWaitVisible()
+		listbox := page.MustElement(sel.PageList).MustWaitVisible()
 		listbox.MustElement("a").MustWaitVisible()
 
-		pages := listbox.MustElements("a")
-		if len(pages) < pageNumber-1 {
-			panic("found too few page number option")
+		option, err := pageOption(listbox.MustElements("a"), pageNumber)
+		if err != nil {
+			panic(err)
 		}
 
-		pages[pageNumber-1].MustClick()
+		option.MustClick()
 	})
 
 	return err
 }
 
-// GrabErrorSnapshot is a page action which grabs a screenshot and the rendered
-// HTML of the current page and saves them in the specified directory.
-func GrabErrorSnapshot(page *rod.Page, acc *models.Account, errorDir string) error {
-	log.Debug().Str("account", acc.Email).Msg("grabbing error snapshot")
+// pageOption returns the option for the page number among the page number options, which are
+// listed from the first page onwards.
+func pageOption(pages rod.Elements, pageNumber int) (*rod.Element, error) {
+	if pageNumber < 1 || len(pages) < pageNumber {
+		return nil, fmt.Errorf("found %d page number options, which lack page %d", len(pages), pageNumber)
+	}
+
+	return pages[pageNumber-1], nil
+}
+
+// IsTimeout returns true if err is the result of an element not showing up in time, which happens
+// both when the element is absent and when the page failed to render.
+func IsTimeout(err error) bool {
+	var notFound *rod.ElementNotFoundError
+	return errors.Is(err, context.DeadlineExceeded) || errors.As(err, &notFound)
+}
+
+// ReloadPage reloads the page and waits for it to load, e.g. to give a page that failed to render
+// another chance.
+func ReloadPage(page *rod.Page, timeout time.Duration) (err error) {
+	page, span := startSpan(page, "ReloadPage")
+	defer func() { tracing.End(span, err) }()
+
+	logger(page).Debug().Msg("reloading page")
+
+	return rod.Try(func() {
+		page.Timeout(timeout).MustReload().MustWaitLoad()
+	})
+}
+
+// logger returns the logger attached to the page's context, falling back to the global logger.
+func logger(page *rod.Page) *zerolog.Logger {
+	return zerolog.Ctx(page.GetContext())
+}
+
+// startSpan starts a span for the page action with the provided name, returning a copy of the page
+// whose context carries it.
+func startSpan(page *rod.Page, name string, attrs ...attribute.KeyValue) (*rod.Page, trace.Span) {
+	ctx, span := tracing.Start(page.GetContext(), "actions."+name, attrs...)
+	return page.Context(ctx), span
+}
+
+// DefaultApolloURL is the URL of the Apollo app that page actions run against.
+const DefaultApolloURL string = "https://app.apollo.io"
+
+type apolloURLKey struct{}
+
+// WithApolloURL returns a copy of the context which makes the actions of pages using it run against
+// the Apollo app at the provided URL instead of [DefaultApolloURL], e.g. a mock server in tests.
+func WithApolloURL(ctx context.Context, url string) context.Context {
+	return context.WithValue(ctx, apolloURLKey{}, strings.TrimSuffix(url, "/"))
+}
+
+// NormalizeApolloURL returns the URL of an Apollo app given by its URL or host, e.g.
+// 'app.eu.apollo.io', without any trailing slash. URLs without a scheme are taken to use HTTPS.
+func NormalizeApolloURL(s string) (string, error) {
+	s = strings.TrimSpace(s)
+	if !strings.Contains(s, "://") {
+		s = "https://" + s
+	}
+
+	u, err := url.Parse(s)
+	if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return "", fmt.Errorf("invalid Apollo URL %q", s)
+	}
+
+	return strings.TrimSuffix(u.String(), "/"), nil
+}
+
+// apolloURL returns the URL of the provided path on the Apollo app that the page runs against.
+func apolloURL(page *rod.Page, path string) string {
+	if url, ok := page.GetContext().Value(apolloURLKey{}).(string); ok {
+		return url + path
+	}
+
+	return DefaultApolloURL + path
+}
+
+// ErrorSnapshot describes a snapshot of the page on which an error was encountered. It is saved
+// alongside the snapshot's screenshot, HTML and (optionally) MHTML files.
+type ErrorSnapshot struct {
+	Account    string    `json:"account"`
+	RunID      string    `json:"run-id,omitempty"`
+	JobID      string    `json:"job-id,omitempty"`
+	Time       time.Time `json:"time"`
+	Error      string    `json:"error"`
+	State      string    `json:"state,omitempty"`
+	URL        string    `json:"url"`
+	Screenshot string    `json:"screenshot"`
+	HTML       string    `json:"html"`
+	MHTML      string    `json:"mhtml,omitempty"`
+}
+
+// SnapshotOptions configures how error snapshots are captured. The zero value captures the
+// viewport as a PNG.
+type SnapshotOptions struct {
+	// Format is the image format of the screenshot ('png', 'jpeg' or 'webp').
+	Format proto.PageCaptureScreenshotFormat
+	// Quality is the compression quality (0-100) of 'jpeg' and 'webp' screenshots.
+	Quality int
+	// FullPage captures the whole scrollable page instead of just the viewport.
+	FullPage bool
+	// MHTML additionally captures the complete page (including its resources) as an MHTML archive.
+	MHTML bool
+}
+
+func (opts SnapshotOptions) screenshotRequest() *proto.PageCaptureScreenshot {
+	req := &proto.PageCaptureScreenshot{Format: opts.Format}
+	if req.Format == "" {
+		req.Format = proto.PageCaptureScreenshotFormatPng
+	}
+
+	if req.Format != proto.PageCaptureScreenshotFormatPng && opts.Quality > 0 {
+		req.Quality = &opts.Quality
+	}
+
+	return req
+}
+
+// GrabErrorSnapshot is a page action which grabs a screenshot and the rendered HTML of the
+// current page and saves them in the specified directory, along with the provided [ErrorSnapshot]
+// describing the error that was encountered. The snapshot's time, URL and file names are filled in.
+func GrabErrorSnapshot(
+	page *rod.Page,
+	snapshot *ErrorSnapshot,
+	errorDir string,
+	opts SnapshotOptions,
+) error {
+	logger(page).Debug().Str("account", snapshot.Account).Msg("grabbing error snapshot")
+
+	snapshot.Time = time.Now()
+
+	req := opts.screenshotRequest()
+	name := snapshot.Account + "-" + snapshot.Time.Format("20060102T150405.000")
+	snapshot.Screenshot, snapshot.HTML = name+"."+string(req.Format), name+".html"
 
 	err := rod.Try(func() {
-		ssFile := filepath.Join(errorDir, acc.Email+".png")
-		page.MustScreenshot(ssFile)
+		if info, err := page.Info(); err == nil {
+			snapshot.URL = info.URL
+		}
+
+		img, err := page.Screenshot(opts.FullPage, req)
+		if err != nil {
+			panic(err)
+		}
 
-		htmlFile := filepath.Join(errorDir, acc.Email+".html")
+		if err := os.WriteFile(filepath.Join(errorDir, snapshot.Screenshot), img, 0644); err != nil {
+			panic(err)
+		}
+
+		htmlFile := filepath.Join(errorDir, snapshot.HTML)
 		if err := os.WriteFile(htmlFile, []byte(page.MustHTML()), 0644); err != nil {
 			panic(err)
 		}
 	})
 
-	return err
+	if err != nil {
+		return err
+	}
+
+	if opts.MHTML {
+		if err := grabMHTML(page, filepath.Join(errorDir, name+".mhtml")); err != nil {
+			logger(page).Warn().Err(err).Str("account", snapshot.Account).Msg("failed to capture MHTML snapshot")
+		} else {
+			snapshot.MHTML = name + ".mhtml"
+		}
+	}
+
+	b, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(filepath.Join(errorDir, name+".json"), b, 0644)
 }
 
-const (
-	accordianOpenState     string = ".zp_YkfVU"
-	accordianToggleElement string = ".zp-accordion.zp_UeG9f.zp_p8DhX"
-	filterAccordionElement string = ".zp-accordion-header.zp_r3aQ1"
-	peoplePageURL          string = "https://app.apollo.io/#/people"
-)
+func grabMHTML(page *rod.Page, file string) error {
+	res, err := proto.PageCaptureSnapshot{Format: proto.PageCaptureSnapshotFormatMhtml}.Call(page)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(file, []byte(res.Data), 0644)
+}
+
+const peoplePagePath string = "/#/people"
 
 // LocateList is a page action that navigates to the Apollo list with the provided listName.
-func LocateList(page *rod.Page, listName string, timeout time.Duration) error {
-	log.Debug().Str("list", listName).Msg("locating list")
+func LocateList(page *rod.Page, listName string, timeout time.Duration) (err error) {
+	page, span := startSpan(page, "LocateList", attribute.String("list", listName))
+	defer func() { tracing.End(span, err) }()
 
-	err := rod.Try(func() {
-		if !strings.HasPrefix(page.MustInfo().URL, peoplePageURL) {
-			page.MustNavigate(peoplePageURL).MustWaitDOMStable()
+	logger(page).Debug().Str("list", listName).Msg("locating list")
+
+	sel := selectors(page)
+	err = rod.Try(func() {
+		if url := apolloURL(page, peoplePagePath); !strings.HasPrefix(page.MustInfo().URL, url) {
+			page.MustNavigate(url).MustWaitDOMStable()
 		}
 
 		page := page.Timeout(timeout)
-		page.MustElement(filterAccordionElement).
+		page.MustElement(sel.FilterAccordion).
 			MustWaitVisible()
 
-		accordians := page.MustElements(filterAccordionElement)
+		accordians := page.MustElements(sel.FilterAccordion)
 		if len(accordians) < 11 {
 			panic(fmt.Errorf("unexpected number of filter accordians: %d", len(accordians)))
 		}
@@ -218,13 +503,94 @@ func LocateList(page *rod.Page, listName string, timeout time.Duration) error {
 		listAccordian := accordians[0]
 		class := listAccordian.MustAttribute("class")
 
-		if !strings.Contains(*class, accordianOpenState) {
-			listAccordian.MustElement(accordianToggleElement).MustClick()
+		if !strings.Contains(*class, sel.AccordionOpen) {
+			listAccordian.MustElement(sel.AccordionToggle).MustClick()
 		}
 
-		listAccordian.MustElement(".Select-input").MustInput(listName)
+		listAccordian.MustElement(sel.SelectInput).MustInput(listName)
 		page.Keyboard.MustType(input.Enter)
 	})
 
 	return err
 }
+
+// ListExists is a page action that reports whether or not an Apollo list with the provided
+// listName exists, by searching for it in the list filter on the 'People' page.
+func ListExists(page *rod.Page, listName string, timeout time.Duration) (exists bool, err error) {
+	page, span := startSpan(page, "ListExists", attribute.String("list", listName))
+	defer func() { tracing.End(span, err) }()
+
+	logger(page).Debug().Str("list", listName).Msg("checking if list exists")
+
+	sel := selectors(page)
+	err = rod.Try(func() {
+		if url := apolloURL(page, peoplePagePath); !strings.HasPrefix(page.MustInfo().URL, url) {
+			page.MustNavigate(url).MustWaitDOMStable()
+		}
+
+		page := page.Timeout(timeout)
+		page.MustElement(sel.FilterAccordion).MustWaitVisible()
+
+		listAccordian := page.MustElements(sel.FilterAccordion)[0]
+		if class := listAccordian.MustAttribute("class"); !strings.Contains(*class, sel.AccordionOpen) {
+			listAccordian.MustElement(sel.AccordionToggle).MustClick()
+		}
+
+		listAccordian.MustElement(sel.SelectInput).MustInput(listName)
+
+		menu := page.MustElement(sel.SelectMenu).MustWaitVisible()
+		_, err := menu.Sleeper(rod.NotFoundSleeper).
+			ElementR(sel.SelectOption, fmt.Sprintf("^%s$", regexp.QuoteMeta(listName)))
+
+		var notFound *rod.ElementNotFoundError
+		if errors.As(err, &notFound) {
+			return
+		} else if err != nil {
+			panic(err)
+		}
+
+		exists = true
+	})
+
+	return
+}
+
+// ListSize is a page action that returns the number of leads in the Apollo list with the provided
+// listName, which is 0 if there's no such list. The list is looked up in the list filter on the
+// 'People' page, which is left filtered by it.
+func ListSize(page *rod.Page, listName string, timeout time.Duration) (size int, err error) {
+	exists, err := ListExists(page, listName, timeout)
+	if err != nil || !exists {
+		return 0, err
+	}
+
+	page, span := startSpan(page, "ListSize", attribute.String("list", listName))
+	defer func() { tracing.End(span, err) }()
+
+	// the list's name is still typed in the list filter, so picking it is enough to apply the filter,
+	// which is kept in the page's URL.
+	err = rod.Try(func() {
+		url := page.MustInfo().URL
+		page.Keyboard.MustType(input.Enter)
+
+		err := page.Timeout(timeout).Wait(&rod.EvalOptions{
+			JS:     `(url) => location.href !== url`,
+			JSArgs: []interface{}{url},
+		})
+		if err != nil {
+			panic(err)
+		}
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	pd, err := GetPageData(page, timeout)
+	if errors.Is(err, ErrorListEnd) {
+		return 0, nil
+	} else if err != nil {
+		return 0, err
+	}
+
+	return pd.TotalSize, nil
+}
@@ -0,0 +1,70 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-rod/rod"
+)
+
+func TestIsTimeout(t *testing.T) {
+	for _, tc := range []struct {
+		err  error
+		want bool
+	}{
+		{rod.Try(func() { panic(context.DeadlineExceeded) }), true},
+		{fmt.Errorf("failed to save leads: %w", &rod.ElementNotFoundError{}), true},
+		{ErrorListEnd, false},
+		{errors.New("something else"), false},
+		{nil, false},
+	} {
+		if got := IsTimeout(tc.err); got != tc.want {
+			t.Errorf("IsTimeout(%v) = %v, want %v", tc.err, got, tc.want)
+		}
+	}
+}
+
+func TestCheckAdvance(t *testing.T) {
+	pd := &PageData{Number: 2, Start: 26, End: 50, Size: 25, TotalSize: 1234}
+
+	for start, want := range map[int]error{
+		51:  nil,
+		76:  ErrorPageSkipped, // both clicks on the next page button went through.
+		101: ErrorPageSkipped,
+		1:   ErrorPageNotAdvanced,
+		0:   ErrorPageNotAdvanced,
+	} {
+		if err := pd.checkAdvance(start); !errors.Is(err, want) {
+			t.Errorf("checkAdvance(%d) = %v, want %v", start, err, want)
+		}
+	}
+}
+
+func TestPageOption(t *testing.T) {
+	pages := rod.Elements{{}, {}, {}}
+
+	for number, ok := range map[int]bool{1: true, 3: true, 4: false, 0: false} {
+		option, err := pageOption(pages, number)
+		if ok && (err != nil || option != pages[number-1]) {
+			t.Errorf("pageOption(%d) = %p, %v, want option %d", number, option, err, number)
+		} else if !ok && err == nil {
+			t.Errorf("pageOption(%d) = %p, want an error", number, option)
+		}
+	}
+}
@@ -16,12 +16,18 @@ package actions
 
 import (
 	"context"
+	_ "embed"
+	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/devsheke/scrapollo/internal/tracing"
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/proto"
-	"github.com/rs/zerolog/log"
+	"github.com/ysmood/gson"
 )
 
 // Annoyance is represents any sort of annoyance that affects the
@@ -71,16 +77,50 @@ var (
 	}
 )
 
-// RemoveAnnoyanceis a page action which searches for all available instances of the specified
+// KnownAnnoyances are all of the annoyances known to affect the scraping flow.
+var KnownAnnoyances = []*Annoyance{TopBannerAnnoyance, NewUIAnnoyance, PopupDialogAnnoyance, SidenavAnnoyance}
+
+// RemoveAnnoyance is a page action which searches for all available instances of the specified
 // [*Annoyance] on the current page and performs the action specified by [*Annoyance.ActionFunc]
 // for each of them.
 func RemoveAnnoyance(page *rod.Page, annoyance *Annoyance, timeout time.Duration) error {
-	log.Debug().Str("annoyance", annoyance.Name).Msg("attempting to remove annoyance")
+	return RemoveAnnoyances(page, []*Annoyance{annoyance}, timeout)
+}
+
+// RemoveAnnoyances is a page action which concurrently searches for all of the specified annoyances
+// on the current page and performs the action specified by [*Annoyance.ActionFunc] for each instance
+// found. All of the annoyances share a single deadline, so the time spent looking for annoyances that
+// aren't present is bounded by timeout rather than growing with the number of annoyances.
+func RemoveAnnoyances(page *rod.Page, annoyances []*Annoyance, timeout time.Duration) (err error) {
+	page, span := startSpan(page, "RemoveAnnoyances")
+	defer func() { tracing.End(span, err) }()
+
+	ctx, cancel := context.WithTimeout(page.GetContext(), timeout)
+	defer cancel()
+	page = page.Context(ctx)
+
+	errs := make([]error, len(annoyances))
+
+	var wg sync.WaitGroup
+	for i, annoyance := range annoyances {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs[i] = removeAnnoyance(page, annoyance)
+		}()
+	}
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// removeAnnoyance removes instances of the specified [*Annoyance] until the page's context expires.
+func removeAnnoyance(page *rod.Page, annoyance *Annoyance) error {
+	logger(page).Debug().Str("annoyance", annoyance.Name).Msg("attempting to remove annoyance")
 
 	for {
 		var element *rod.Element
 		err := rod.Try(func() {
-			page = page.Timeout(timeout)
 			if annoyance.Regex != "" {
 				element = page.MustElementR(annoyance.Selector, annoyance.Regex).MustWaitVisible()
 			} else {
@@ -89,7 +129,7 @@ func RemoveAnnoyance(page *rod.Page, annoyance *Annoyance, timeout time.Duration
 		})
 
 		if errors.Is(err, context.DeadlineExceeded) {
-			log.Debug().Str("annoyance", annoyance.Name).Msg("annoyance not found")
+			logger(page).Debug().Str("annoyance", annoyance.Name).Msg("annoyance not found")
 			return nil
 		} else if err != nil {
 			return err
@@ -99,8 +139,156 @@ func RemoveAnnoyance(page *rod.Page, annoyance *Annoyance, timeout time.Duration
 			return err
 		}
 
-		log.Debug().Str("annoyance", annoyance.Name).Msg("removed annoyance")
+		logger(page).Debug().Str("annoyance", annoyance.Name).Msg("removed annoyance")
 
-		time.Sleep(2 * time.Second)
+		select {
+		case <-page.GetContext().Done():
+			return nil
+		case <-time.After(2 * time.Second):
+		}
 	}
 }
+
+//go:embed scripts/annoyances.js
+var annoyanceObserverScript string
+
+const (
+	annoyanceBinding       string        = "scrapolloAnnoyance"
+	annoyanceMaxRemovals   int           = 5
+	annoyanceRecheckPeriod time.Duration = 10 * time.Second
+)
+
+// removeVisibleAnnoyance removes the instances of the specified [*Annoyance] that are
+// currently present on the page without waiting for any of them to appear.
+func removeVisibleAnnoyance(page *rod.Page, annoyance *Annoyance) error {
+	page = page.Sleeper(rod.NotFoundSleeper)
+
+	for range annoyanceMaxRemovals {
+		var element *rod.Element
+		var err error
+		if annoyance.Regex != "" {
+			element, err = page.ElementR(annoyance.Selector, annoyance.Regex)
+		} else {
+			element, err = page.Element(annoyance.Selector)
+		}
+
+		var notFound *rod.ElementNotFoundError
+		if errors.As(err, &notFound) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if visible, err := element.Visible(); err != nil {
+			return err
+		} else if !visible {
+			return nil
+		}
+
+		if err := annoyance.ActionFunc(element); err != nil {
+			return err
+		}
+
+		logger(page).Debug().Str("annoyance", annoyance.Name).Msg("removed annoyance")
+	}
+
+	return nil
+}
+
+// WatchAnnoyances is a page action that watches the current page for the specified annoyances
+// in the background and removes them as soon as they appear. The page is observed using a
+// MutationObserver which notifies the watcher whenever one of the annoyances' selectors matches,
+// with a periodic re-check as a fallback. The returned function stops the watcher and waits
+// for it to exit.
+func WatchAnnoyances(page *rod.Page, annoyances []*Annoyance) (stop func(), err error) {
+	logger(page).Debug().Int("annoyances", len(annoyances)).Msg("starting annoyance watcher")
+
+	// work with a copy of the page so that the watcher isn't affected if the caller re-assigns it.
+	page = page.Context(page.GetContext())
+
+	signal := make(chan struct{}, 1)
+	unexpose, err := page.Expose(annoyanceBinding, func(gson.JSON) (interface{}, error) {
+		select {
+		case signal <- struct{}{}:
+		default:
+		}
+		return nil, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	selectors := make([]string, 0, len(annoyances))
+	for _, annoyance := range annoyances {
+		selectors = append(selectors, annoyance.Selector)
+	}
+
+	args, err := json.Marshal(selectors)
+	if err != nil {
+		return nil, errors.Join(err, unexpose())
+	}
+
+	observer := fmt.Sprintf(
+		"(%s)(%s, %q)",
+		strings.TrimSuffix(strings.TrimSpace(annoyanceObserverScript), ";"),
+		args,
+		annoyanceBinding,
+	)
+
+	removeObserver, err := page.EvalOnNewDocument(observer)
+	if err != nil {
+		return nil, errors.Join(err, unexpose())
+	}
+
+	if _, err := page.Eval(annoyanceObserverScript, selectors, annoyanceBinding); err != nil {
+		return nil, errors.Join(err, removeObserver(), unexpose())
+	}
+
+	ctx, cancel := context.WithCancel(page.GetContext())
+	watched := page.Context(ctx)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(annoyanceRecheckPeriod)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-signal:
+			case <-ticker.C:
+			}
+
+			for _, annoyance := range annoyances {
+				if err := removeVisibleAnnoyance(watched, annoyance); err != nil {
+					if ctx.Err() != nil {
+						return
+					}
+
+					logger(page).Debug().
+						Err(err).
+						Str("annoyance", annoyance.Name).
+						Msg("failed to remove annoyance")
+				}
+			}
+		}
+	}()
+
+	stop = func() {
+		cancel()
+		wg.Wait()
+
+		// the page may already be closed at this point, so these errors aren't meaningful.
+		_, _ = page.Eval(`() => window.__scrapolloAnnoyanceObserver?.disconnect()`)
+		_ = removeObserver()
+		_ = unexpose()
+
+		logger(page).Debug().Msg("stopped annoyance watcher")
+	}
+
+	return stop, nil
+}
@@ -0,0 +1,45 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actions
+
+import (
+	"testing"
+
+	"github.com/go-rod/rod/lib/proto"
+)
+
+func TestResourceBlockerBlocks(t *testing.T) {
+	blocker := NewResourceBlocker(
+		[]string{"*://cdn.example.com/*"},
+		[]string{"*://app.apollo.io/*.svg"},
+	)
+
+	for _, tc := range []struct {
+		url  string
+		typ  proto.NetworkResourceType
+		want bool
+	}{
+		{"https://app.apollo.io/api/v1/mixed_people/search", proto.NetworkResourceTypeXHR, false},
+		{"https://app.apollo.io/logo.png", proto.NetworkResourceTypeImage, true},
+		{"https://app.apollo.io/icons/check.svg", proto.NetworkResourceTypeImage, false},
+		{"https://www.google-analytics.com/collect?v=2", proto.NetworkResourceTypePing, true},
+		{"https://cdn.example.com/app.js", proto.NetworkResourceTypeScript, true},
+		{"https://example.com/app.js", proto.NetworkResourceTypeScript, false},
+	} {
+		if got := blocker.Blocks(tc.url, tc.typ); got != tc.want {
+			t.Errorf("%s (%s): got blocked %t, want %t", tc.url, tc.typ, got, tc.want)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actions
+
+import (
+	"errors"
+
+	"github.com/go-rod/rod"
+)
+
+// The errors returned when an account can no longer be used. Unlike a security challenge, they don't
+// go away by retrying.
+var (
+	ErrorAccountSuspended = errors.New("the account has been suspended")
+	ErrorPaymentRequired  = errors.New("the account's subscription requires payment")
+)
+
+// Interstitial represents a page that Apollo shows instead of the app when an account can no longer
+// be used. It's found by the text (Regex, a JS regex) of an element matching Selector.
+type Interstitial struct {
+	Name, Selector, Regex string
+	Err                   error
+}
+
+// interstitialSelector matches the elements holding the messages of interstitials.
+const interstitialSelector = "h1, h2, h3, [role=alert], [role=alertdialog], [role=dialog]"
+
+var (
+	// SuspensionInterstitial represents the page shown to suspended or deactivated accounts.
+	SuspensionInterstitial = &Interstitial{
+		Name:     "Account suspended",
+		Selector: interstitialSelector,
+		Regex:    `/account (has been |was |is )?(suspended|deactivated|disabled|banned)/i`,
+		Err:      ErrorAccountSuspended,
+	}
+
+	// PaymentInterstitial represents the page shown to accounts whose subscription has lapsed.
+	PaymentInterstitial = &Interstitial{
+		Name:     "Payment required",
+		Selector: interstitialSelector,
+		Regex:    `/payment (is )?required|payment (has )?failed|update your (payment|billing)|(subscription|plan) (has )?(expired|lapsed)/i`,
+		Err:      ErrorPaymentRequired,
+	}
+
+	interstitials = []*Interstitial{SuspensionInterstitial, PaymentInterstitial}
+)
+
+// IsAccountBlocked reports whether the error means that the account can no longer be used.
+func IsAccountBlocked(err error) bool {
+	return errors.Is(err, ErrorAccountSuspended) || errors.Is(err, ErrorPaymentRequired)
+}
+
+// CheckAccountStatus is a page action which looks for the interstitials shown to accounts that can no
+// longer be used, returning the error of the first one found on the current page. It doesn't wait for
+// them to appear, and returns nil if none is shown or the page can't be queried.
+func CheckAccountStatus(page *rod.Page) error {
+	for _, i := range interstitials {
+		if has, _, err := page.HasR(i.Selector, i.Regex); err == nil && has {
+			logger(page).Warn().Str("interstitial", i.Name).Msg("account can no longer be used")
+			return i.Err
+		}
+	}
+
+	return nil
+}
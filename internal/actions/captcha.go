@@ -0,0 +1,97 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actions
+
+import (
+	"errors"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/models"
+	"github.com/devsheke/scrapollo/internal/tracing"
+	"github.com/go-rod/rod"
+)
+
+// CaptchaChallenge represents a security challenge encountered while logging into Apollo.
+type CaptchaChallenge struct {
+	URL, SiteKey string
+	Screenshot   []byte
+}
+
+// CaptchaSolver is an interface for solving security challenges encountered while logging
+// into Apollo.
+type CaptchaSolver interface {
+	// Solve returns the response token for the provided challenge.
+	Solve(*CaptchaChallenge) (string, error)
+}
+
+// SolveSecurityChallenge is a page action that attempts to get past the security challenge on the
+// current page using the provided [CaptchaSolver]. It assumes the account's credentials have already
+// been submitted on the login page.
+func SolveSecurityChallenge(
+	page *rod.Page,
+	acc *models.Account,
+	solver CaptchaSolver,
+	timeout time.Duration,
+) (err error) {
+	page, span := startSpan(page, "SolveSecurityChallenge")
+	defer func() { tracing.End(span, err) }()
+
+	logger(page).Info().Str("account", acc.Email).Msg("attempting to solve security challenge")
+
+	challenge := new(CaptchaChallenge)
+	err = rod.Try(func() {
+		page := page.Timeout(timeout)
+		challenge.URL = page.MustInfo().URL
+
+		if el, err := page.Sleeper(rod.NotFoundSleeper).Element("[data-sitekey]"); err == nil {
+			if key, err := el.Attribute("data-sitekey"); err == nil && key != nil {
+				challenge.SiteKey = *key
+			}
+		}
+
+		challenge.Screenshot = page.MustScreenshot()
+	})
+
+	if err != nil {
+		return err
+	}
+
+	token, err := solver.Solve(challenge)
+	if err != nil {
+		return errors.Join(ErrorSecurityChallenge, err)
+	}
+
+	err = rod.Try(func() {
+		page := page.Timeout(timeout)
+		page.MustEval(`(token) => {
+			for (const input of document.querySelectorAll('[name="cf-turnstile-response"]')) {
+				input.value = token;
+			}
+		}`, token)
+		page.MustElement("button[data-cy=login-button]").MustClick()
+	})
+
+	if err != nil {
+		return err
+	}
+
+	if ok, err := isLoggedIn(page, acc, timeout); err != nil || !ok {
+		return errors.Join(ErrorSecurityChallenge, err)
+	}
+
+	logger(page).Info().Str("account", acc.Email).Msg("solved security challenge")
+
+	return nil
+}
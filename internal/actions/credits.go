@@ -15,86 +15,218 @@
 package actions
 
 import (
+	"errors"
 	"fmt"
-	"strconv"
+	"regexp"
+	"slices"
 	"strings"
 	"time"
 
 	"github.com/devsheke/scrapollo/internal/models"
+	"github.com/devsheke/scrapollo/internal/parse"
+	"github.com/devsheke/scrapollo/internal/tracing"
 	"github.com/go-rod/rod"
-	"github.com/rs/zerolog/log"
 )
 
-// FetchCreditUsage is a page action that fetches credit usage information for the provided
-// [*models.Account] from Apollo. This function returns the amount of credits remaining and
-// a [*models.Time] value indicating when credits will be renewed.
-func FetchCreditUsage(
-	page *rod.Page,
-	acc *models.Account,
-	timeout time.Duration,
-) (credits int, refreshTime *models.Time, err error) {
-	log.Info().Str("account", acc.Email).Msg("fetching credit usage")
+// ErrorCreditsNotFound is returned when the credit usage can't be found on Apollo's credits page.
+var ErrorCreditsNotFound = errors.New("failed to find credit usage")
 
-	var creditsText []string
-	err = rod.Try(func() {
-		log.Info().Msg("fetching credit data")
+const creditsPagePath string = "/#/settings/credits/current"
 
-		page := page.Timeout(timeout)
-		creditElem := ".zp_ZlMia"
-		page.MustNavigate("https://app.apollo.io/#/settings/credits/current").
-			MustElement(creditElem).
-			MustWaitVisible()
-
-		elems := page.MustElements(creditElem)
-		if len(elems) != 4 {
-			panic("unexpected number of credit elements found")
-		}
+// creditSelectors are the elements on Apollo's credits page that usually hold the credit usage, renewal
+// and plan. The whole page's text is searched if none of them match.
+var creditSelectors = []string{".zp_ZlMia", ".zp_jtf9O"}
+
+// CreditInfo represents the credit usage of an Apollo account.
+type CreditInfo struct {
+	Used, Max int
+	Renewal   *models.Time
+	Plan      string
+}
+
+// Remaining returns the amount of credits left.
+func (c *CreditInfo) Remaining() int {
+	return c.Max - c.Used
+}
+
+// CreditLocale describes how credit usage is displayed on Apollo's credits page in a given locale.
+type CreditLocale struct {
+	Name string
+	// Usage matches the used and maximum amounts of credits (in that order).
+	Usage *regexp.Regexp
+	// Renewal matches the time at which credits are renewed, which is parsed with RenewalLayouts
+	// (see [parse.Time]).
+	Renewal        *regexp.Regexp
+	RenewalLayouts []string
+	// Plan matches the name of the account's plan (optional).
+	Plan *regexp.Regexp
+}
+
+// NewCreditLocale compiles a [CreditLocale] from the provided patterns. Each pattern must contain
+// exactly as many capture groups as its counterpart in [CreditLocale]. The plan pattern may be empty.
+func NewCreditLocale(name, usage, renewal, plan string, layouts []string) (*CreditLocale, error) {
+	locale := &CreditLocale{Name: name, RenewalLayouts: layouts}
+
+	var err error
+	if locale.Usage, err = compileGroups(usage, 2); err != nil {
+		return nil, err
+	}
+
+	if locale.Renewal, err = compileGroups(renewal, 1); err != nil {
+		return nil, err
+	}
 
-		creditsText = strings.Split(elems[1].MustText(), " ")
-		if len(creditsText) != 6 {
-			panic(fmt.Sprintf("unexpected credit string found: %q", strings.Join(creditsText, " ")))
+	if plan != "" {
+		if locale.Plan, err = compileGroups(plan, 1); err != nil {
+			return nil, err
 		}
-	})
+	}
+
+	return locale, nil
+}
 
+func compileGroups(pattern string, groups int) (*regexp.Regexp, error) {
+	re, err := regexp.Compile(pattern)
 	if err != nil {
-		return
+		return nil, err
 	}
 
-	creditsUsed, err := strconv.Atoi(strings.ReplaceAll(creditsText[0], ",", ""))
-	if err != nil {
-		err = fmt.Errorf("failed to fetch used credits amnt: %s", err)
-		return
+	if re.NumSubexp() != groups {
+		return nil, fmt.Errorf("pattern %q must have %d capture group(s)", pattern, groups)
 	}
 
-	creditsMax, err := strconv.Atoi(strings.ReplaceAll(creditsText[2], ",", ""))
-	if err != nil {
-		err = fmt.Errorf("failed to fetch max credits amnt: %s", err)
-		return
+	return re, nil
+}
+
+// DefaultCreditLocales are the locales tried by [FetchCreditUsage] after the ones it is provided with.
+var DefaultCreditLocales = []*CreditLocale{
+	{
+		Name:    "en",
+		Usage:   regexp.MustCompile(`(?i)(` + parse.CountPattern + `)\s*(?:of|/)\s*(` + parse.CountPattern + `)\s+(?:\w+\s+)?credits`),
+		Renewal: regexp.MustCompile(`(?i)renew\D*?([A-Z][a-z]{2,8}\.? \d{1,2}(?:st|nd|rd|th)?,? \d{4}(?:,? (?:at )?\d{1,2}:\d{2} ?(?:[AP]\.?M\.?)?)?)`),
+		RenewalLayouts: []string{
+			models.TimeFormat, "Jan 2, 2006 3:04 PM", "Jan 2, 2006 3:04PM", "Jan 2, 2006",
+		},
+		Plan: regexp.MustCompile(`(?i)\b([\w ]+?)\s+plan\b`),
+	},
+	{
+		Name:           "de",
+		Usage:          regexp.MustCompile(`(?i)(` + parse.CountPattern + `)\s*(?:von|/)\s*(` + parse.CountPattern + `)\s+(?:\w+\s+)?credits`),
+		Renewal:        regexp.MustCompile(`(?i)(?:erneuer|verlänger|\bam\b)\D*?(\d{1,2}\.\d{1,2}\.\d{4}(?:,? \d{1,2}:\d{2})?)`),
+		RenewalLayouts: []string{"02.01.2006 15:04", "2.1.2006 15:04", "02.01.2006, 15:04", "02.01.2006", "2.1.2006"},
+		Plan:           regexp.MustCompile(`(?i)\b([\w ]+?)[\s-]+tarif\b`),
+	},
+}
+
+// parseCreditInfo searches the provided texts for credit usage information using the first locale
+// whose usage pattern matches.
+func parseCreditInfo(texts []string, locales []*CreditLocale) (*CreditInfo, error) {
+	for _, locale := range locales {
+		used, max, ok := findCreditUsage(texts, locale.Usage)
+		if !ok {
+			continue
+		}
+
+		info := &CreditInfo{Used: used, Max: max, Renewal: models.NewTime()}
+		if renewal, ok := findCreditRenewal(texts, locale); ok {
+			info.Renewal.Set(renewal)
+		}
+
+		if locale.Plan != nil {
+			for _, text := range texts {
+				if match := locale.Plan.FindStringSubmatch(text); match != nil {
+					info.Plan = strings.TrimSpace(match[1])
+					break
+				}
+			}
+		}
+
+		return info, nil
 	}
 
-	credits = creditsMax - creditsUsed
+	return nil, ErrorCreditsNotFound
+}
+
+func findCreditUsage(texts []string, usage *regexp.Regexp) (used, max int, ok bool) {
+	for _, text := range texts {
+		match := usage.FindStringSubmatch(text)
+		if match == nil {
+			continue
+		}
 
-	var creditsRenewal string
-	err = rod.Try(func() {
-		log.Info().Msg("fetching renewal data")
+		used, errUsed := parse.Count(match[1])
+		max, errMax := parse.Count(match[2])
+		if errUsed == nil && errMax == nil {
+			return used, max, true
+		}
+	}
+
+	return 0, 0, false
+}
+
+func findCreditRenewal(texts []string, locale *CreditLocale) (time.Time, bool) {
+	for _, text := range texts {
+		match := locale.Renewal.FindStringSubmatch(text)
+		if match == nil {
+			continue
+		}
+
+		if t, err := parse.Time(match[1], locale.RenewalLayouts, time.Local); err == nil {
+			return t, true
+		}
+	}
 
+	return time.Time{}, false
+}
+
+// FetchCreditUsage is a page action that fetches credit usage information for the provided
+// [*models.Account] from Apollo. The credits page is parsed with the provided locales, falling
+// back to [DefaultCreditLocales] if none of them match.
+func FetchCreditUsage(
+	page *rod.Page,
+	acc *models.Account,
+	locales []*CreditLocale,
+	timeout time.Duration,
+) (info *CreditInfo, err error) {
+	page, span := startSpan(page, "FetchCreditUsage")
+	defer func() { tracing.End(span, err) }()
+
+	logger(page).Info().Str("account", acc.Email).Msg("fetching credit usage")
+
+	var texts []string
+	err = rod.Try(func() {
 		page := page.Timeout(timeout)
-		if text := page.MustElement(".zp_jtf9O").MustWaitVisible().MustText(); len(text) < 30 {
-			panic(fmt.Errorf("unexpected credit renewal string: %q", text))
-		} else {
-			creditsRenewal = text[29:]
+		page.MustNavigate(apolloURL(page, creditsPagePath)).MustWaitDOMStable()
+
+		// the credit elements are preferred, but their absence isn't fatal as the page's text is
+		// searched as well.
+		if el, err := page.Timeout(timeout / 3).Element(creditSelectors[0]); err == nil {
+			_ = el.WaitVisible()
 		}
+
+		for _, selector := range creditSelectors {
+			for _, el := range page.MustElements(selector) {
+				texts = append(texts, el.MustText())
+			}
+		}
+
+		texts = append(texts, page.MustElement("body").MustText())
 	})
 
 	if err != nil {
-		return
+		return nil, err
 	}
 
-	_time, err := time.Parse(models.TimeFormat, creditsRenewal)
+	info, err = parseCreditInfo(texts, slices.Concat(locales, DefaultCreditLocales))
 	if err != nil {
-		return
+		return nil, err
 	}
 
-	refreshTime = models.NewTimeValid(_time)
-	return
+	logger(page).Debug().
+		Int("used", info.Used).
+		Int("max", info.Max).
+		Str("plan", info.Plan).
+		Msg("fetched credit usage")
+
+	return info, nil
 }
@@ -0,0 +1,133 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actions
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseCreditInfo(t *testing.T) {
+	tests := []struct {
+		name      string
+		texts     []string
+		used, max int
+		renewal   time.Time
+		plan      string
+	}{
+		{
+			name: "english",
+			texts: []string{
+				"Professional Plan",
+				"1,234 of 10,000 email credits used",
+				"Your credits will renew on: Mar 04, 2025 1:45 PM",
+			},
+			used: 1234, max: 10000,
+			renewal: time.Date(2025, time.March, 4, 13, 45, 0, 0, time.Local),
+			plan:    "Professional",
+		},
+		{
+			name:  "english page text",
+			texts: []string{"Credits\n50 / 100 credits\nCredits renew on Jan 7, 2026"},
+			used:  50, max: 100,
+			renewal: time.Date(2026, time.January, 7, 0, 0, 0, 0, time.Local),
+		},
+		{
+			name:  "english long date",
+			texts: []string{"10 000 / 10 000 credits", "Credits renew on March 4th, 2025 at 1:45 p.m."},
+			used:  10000, max: 10000,
+			renewal: time.Date(2025, time.March, 4, 13, 45, 0, 0, time.Local),
+		},
+		{
+			name: "german",
+			texts: []string{
+				"Basic-Tarif",
+				"1.234 von 10.000 Credits verbraucht",
+				"Credits werden am 04.03.2025 13:45 erneuert",
+			},
+			used: 1234, max: 10000,
+			renewal: time.Date(2025, time.March, 4, 13, 45, 0, 0, time.Local),
+			plan:    "Basic",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			info, err := parseCreditInfo(test.texts, DefaultCreditLocales)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if info.Used != test.used || info.Max != test.max {
+				t.Fatalf("expected %d/%d credits, got %d/%d", test.used, test.max, info.Used, info.Max)
+			}
+
+			if info.Remaining() != test.max-test.used {
+				t.Fatalf("expected %d remaining credits, got %d", test.max-test.used, info.Remaining())
+			}
+
+			if renewal, ok := info.Renewal.Get(); !ok || !renewal.Equal(test.renewal) {
+				t.Fatalf("expected renewal at %v, got %v (valid: %t)", test.renewal, renewal, ok)
+			}
+
+			if info.Plan != test.plan {
+				t.Fatalf("expected plan %q, got %q", test.plan, info.Plan)
+			}
+		})
+	}
+}
+
+func TestParseCreditInfoCustomLocale(t *testing.T) {
+	locale, err := NewCreditLocale(
+		"fr",
+		`(\d[\d\s]*?)\s+sur\s+(\d[\d\s]*?)\s+crédits`,
+		`renouvel\D*?(\d{2}/\d{2}/\d{4})`,
+		"",
+		[]string{"02/01/2006"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := parseCreditInfo(
+		[]string{"1 500 sur 2 000 crédits utilisés", "Crédits renouvelés le 04/03/2025"},
+		[]*CreditLocale{locale},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if info.Used != 1500 || info.Max != 2000 {
+		t.Fatalf("expected 1500/2000 credits, got %d/%d", info.Used, info.Max)
+	}
+
+	if _, ok := info.Renewal.Get(); !ok {
+		t.Fatal("expected a renewal time")
+	}
+}
+
+func TestParseCreditInfoNotFound(t *testing.T) {
+	_, err := parseCreditInfo([]string{"nothing to see here"}, DefaultCreditLocales)
+	if !errors.Is(err, ErrorCreditsNotFound) {
+		t.Fatalf("expected %v, got %v", ErrorCreditsNotFound, err)
+	}
+}
+
+func TestNewCreditLocaleGroups(t *testing.T) {
+	if _, err := NewCreditLocale("bad", `(\d+)`, `(.+)`, "", nil); err == nil {
+		t.Fatal("expected an error for a usage pattern with a single capture group")
+	}
+}
@@ -0,0 +1,146 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actions
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// textLocator locates an element by its accessible name, or by the visible text of one of a set
+// of candidate elements. It's used as a fallback when a selector no longer matches, since
+// Apollo's generated class names change far more often than the labels shown to users.
+type textLocator struct {
+	// role is the ARIA role of the element, e.g. 'button' or 'tab'.
+	role string
+	// labels are the accessible names or visible texts that the element may have.
+	labels []string
+}
+
+// candidates are the elements whose visible text is matched for each role when the element
+// can't be found in the accessibility tree.
+var candidates = map[string]string{
+	"button": "button, [role=button], a",
+	"tab":    "[role=tab], a, button",
+}
+
+var (
+	nextPageButton   = textLocator{"button", []string{"Next", "Next page", "Go to next page"}}
+	selectPageButton = textLocator{"button", []string{"Select this page"}}
+	saveButton       = textLocator{"button", []string{"Save", "Save to list"}}
+)
+
+func tabLocator(tab ApolloTab) textLocator {
+	return textLocator{"tab", []string{string(tab)}}
+}
+
+// regex returns a regular expression that matches any of the locator's labels.
+func (l textLocator) regex() string {
+	labels := make([]string, len(l.labels))
+	for i, label := range l.labels {
+		labels[i] = regexp.QuoteMeta(label)
+	}
+
+	return fmt.Sprintf(`^\s*(%s)\s*$`, strings.Join(labels, "|"))
+}
+
+// find returns the first element that matches the locator on the current page, or nil if
+// there is none.
+func (l textLocator) find(page *rod.Page) (*rod.Element, error) {
+	page = page.Sleeper(rod.NotFoundSleeper)
+
+	if root, err := page.Element("body"); err == nil {
+		for _, label := range l.labels {
+			res, err := proto.AccessibilityQueryAXTree{
+				ObjectID:       root.Object.ObjectID,
+				AccessibleName: label,
+				Role:           l.role,
+			}.Call(page)
+			if err != nil {
+				break
+			}
+
+			for _, node := range res.Nodes {
+				if node.Ignored || node.BackendDOMNodeID == 0 {
+					continue
+				}
+
+				return page.ElementFromNode(&proto.DOMNode{BackendNodeID: node.BackendDOMNodeID})
+			}
+		}
+	}
+
+	selector, ok := candidates[l.role]
+	if !ok {
+		selector = "*"
+	}
+
+	el, err := page.ElementR(selector, l.regex())
+
+	var notFound *rod.ElementNotFoundError
+	if errors.As(err, &notFound) {
+		return nil, nil
+	}
+
+	return el, err
+}
+
+// locate returns the first element matching the selector (and regex, if it isn't empty) on the
+// current page. When there is none, the element is located with the provided [textLocator]
+// instead. Both are retried until the page's context expires.
+func locate(page *rod.Page, selector, regex string, fallback textLocator) (*rod.Element, error) {
+	ctx := page.GetContext()
+	immediate := page.Sleeper(rod.NotFoundSleeper)
+
+	var notFound *rod.ElementNotFoundError
+	for {
+		var el *rod.Element
+		var err error
+		if regex == "" {
+			el, err = immediate.Element(selector)
+		} else {
+			el, err = immediate.ElementR(selector, regex)
+		}
+
+		if err == nil {
+			return el, nil
+		} else if !errors.As(err, &notFound) {
+			return nil, err
+		}
+
+		if el, err = fallback.find(page); err != nil {
+			return nil, err
+		} else if el != nil {
+			logger(page).Warn().
+				Str("selector", selector).
+				Strs("labels", fallback.labels).
+				Msg("selector not found; located element by its label instead")
+			return el, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+
+		case <-time.After(250 * time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actions
+
+import (
+	"regexp"
+	"testing"
+)
+
+func TestTextLocatorRegex(t *testing.T) {
+	re := regexp.MustCompile(textLocator{"button", []string{"Save", "Save (1)"}}.regex())
+
+	for text, want := range map[string]bool{
+		"Save":         true,
+		"  Save\n":     true,
+		"Save (1)":     true,
+		"Save to list": false,
+		"Unsave":       false,
+	} {
+		if got := re.MatchString(text); got != want {
+			t.Errorf("%q: got match %t, want %t", text, got, want)
+		}
+	}
+}
@@ -19,11 +19,14 @@ import (
 	"errors"
 	"time"
 
+	"github.com/devsheke/scrapollo/internal/fingerprint"
 	"github.com/devsheke/scrapollo/internal/models"
+	"github.com/devsheke/scrapollo/internal/tracing"
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/proto"
 	rodStealth "github.com/go-rod/stealth"
-	"github.com/rs/zerolog/log"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // ErrorSecurityChallenge is returned when a Cloudflare Turnstile captcha challenge has been encountered
@@ -40,7 +43,7 @@ func isLoggedIn(
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
-	log.Debug().Str("account", acc.Email).Msg("querying session cookies")
+	logger(page).Debug().Str("account", acc.Email).Msg("querying session cookies")
 	for {
 		select {
 		case <-ctx.Done():
@@ -49,7 +52,7 @@ func isLoggedIn(
 		case <-time.After(500 * time.Millisecond):
 		}
 
-		cookies, err := page.Cookies([]string{"https://app.apollo.io"})
+		cookies, err := page.Cookies([]string{apolloURL(page, "")})
 		if err != nil {
 			return false, err
 		}
@@ -70,68 +73,171 @@ func isLoggedIn(
 	}
 }
 
-// ApolloLogin is a page action that logs into apollo.io with the provided [*models.Account]'s credentials.
-// If arg: stealth is set to true, the resulting page will be launched in stealth mode.
-func ApolloLogin(
-	browser *rod.Browser,
-	acc *models.Account,
-	timeout time.Duration,
-	stealth bool,
-) (page *rod.Page, err error) {
-	if stealth {
-		page, err = rodStealth.Page(browser)
-	} else {
-		page, err = browser.Page(proto.TargetCreateTarget{})
-	}
+// loginSession is the set of browser operations used by the login flow.
+type loginSession interface {
+	// setCookies sets the provided cookies on the session.
+	setCookies(cookies []*proto.NetworkCookie) error
+	// clearCookies removes all cookies from the session.
+	clearCookies() error
+	// resume opens Apollo and reports whether the session is already logged in.
+	resume(acc *models.Account, timeout time.Duration) (bool, error)
+	// passwordLogin submits the account's credentials on Apollo's login page.
+	passwordLogin(acc *models.Account, timeout time.Duration) error
+	// loggedIn waits for the session to be logged in, updating the account's cookies once it is.
+	loggedIn(acc *models.Account, timeout time.Duration) (bool, error)
+	// logger returns the session's logger.
+	logger() *zerolog.Logger
+}
 
-	if err != nil {
-		return
-	}
+// pageSession is the [loginSession] backed by a [*rod.Page].
+type pageSession struct {
+	page *rod.Page
+}
 
-	log.Info().Str("account", acc.Email).Msg("logging in")
+func (s pageSession) setCookies(cookies []*proto.NetworkCookie) error {
+	return s.page.SetCookies(proto.CookiesToParams(cookies))
+}
 
-	ok, err := isLoggedIn(page, acc, 30*time.Second)
-	if err != nil && !errors.Is(err, context.DeadlineExceeded) {
-		return
-	} else if ok {
-		return
-	}
+func (s pageSession) clearCookies() error {
+	return s.page.SetCookies(nil)
+}
 
-	if cookies, ok := acc.GetLoginCookies(); ok && acc.CheckCookieValidity() {
-		log.Info().Str("account", acc.Email).Msg("logged in with previously used cookies")
-		if err = page.SetCookies(proto.CookiesToParams(cookies)); err != nil {
-			return
-		}
+func (s pageSession) resume(acc *models.Account, timeout time.Duration) (bool, error) {
+	var onLoginPage bool
+	err := rod.Try(func() {
+		page := s.page.Timeout(timeout)
+		page.MustNavigate(apolloURL(page, "/")).MustWaitDOMStable()
+		onLoginPage = page.MustHas("input[name=password]")
+	})
+
+	if err != nil || onLoginPage {
+		return false, err
 	}
 
-	err = rod.Try(func() {
-		page := page.Timeout(timeout)
-		page.MustNavigate("https://app.apollo.io/#/login").MustWaitDOMStable()
+	return s.loggedIn(acc, timeout)
+}
+
+func (s pageSession) passwordLogin(acc *models.Account, timeout time.Duration) error {
+	err := rod.Try(func() {
+		page := s.page.Timeout(timeout)
+		page.MustNavigate(apolloURL(page, "/#/login")).MustWaitDOMStable()
 		page.MustElement("input[name=email]").MustInput(acc.Email)
 		page.MustElement("input[name=password]").MustInput(acc.Password)
 		page.MustElement("button[data-cy=login-button]").MustClick()
 	})
 
 	if err != nil {
-		return page, err
+		return err
 	}
 
 	err = rod.Try(func() {
-		page.Timeout(15 * time.Second).MustElement("#securityChallenge")
+		s.page.Timeout(15 * time.Second).MustElement("#securityChallenge")
 	})
 
 	// TODO: add away to bypass the cloudflare challenge.
 	if err == nil {
-		return page, ErrorSecurityChallenge
+		return ErrorSecurityChallenge
 	}
 
-	ok, err = isLoggedIn(page, acc, timeout)
+	return nil
+}
+
+func (s pageSession) logger() *zerolog.Logger {
+	return logger(s.page)
+}
+
+func (s pageSession) loggedIn(acc *models.Account, timeout time.Duration) (bool, error) {
+	return isLoggedIn(s.page, acc, timeout)
+}
+
+// login logs into Apollo using the account's cookies if they are valid, falling back to its
+// credentials if they are not. Stale cookies are cleared from both the session and the account,
+// and the account's cookies are refreshed once logged in.
+func login(s loginSession, acc *models.Account, timeout time.Duration) error {
+	if cookies, ok := acc.GetLoginCookies(); ok && acc.CheckCookieValidity() {
+		if err := s.setCookies(cookies); err != nil {
+			return err
+		}
+
+		ok, err := s.resume(acc, timeout)
+		if err != nil && !errors.Is(err, context.DeadlineExceeded) {
+			return err
+		} else if ok {
+			s.logger().Info().Str("account", acc.Email).Msg("logged in with previously used cookies")
+			return nil
+		}
+
+		s.logger().Warn().Str("account", acc.Email).Msg("previously used cookies are stale; logging in with password")
+		acc.SetLoginCookies(nil)
+		if err := s.clearCookies(); err != nil {
+			return err
+		}
+	}
+
+	if err := s.passwordLogin(acc, timeout); err != nil {
+		return err
+	}
+
+	ok, err := s.loggedIn(acc, timeout)
 	if err != nil && !errors.Is(err, context.DeadlineExceeded) {
-		return
+		return err
 	} else if ok {
-		log.Info().Str("acc", acc.Email).Msg("logged in successfully")
+		s.logger().Info().Str("acc", acc.Email).Msg("logged in successfully")
+		return nil
+	}
+
+	return errors.New("failed to login due to unknown circumstances")
+}
+
+// NewPage opens a new blank page in the browser. If arg: stealth is set to true, the page will be
+// launched in stealth mode. If arg: profile isn't nil, the page will look like it's running on the
+// profile's device.
+func NewPage(browser *rod.Browser, stealth bool, profile *fingerprint.Profile) (*rod.Page, error) {
+	var page *rod.Page
+	var err error
+	if stealth {
+		page, err = rodStealth.Page(browser)
+	} else {
+		page, err = browser.Page(proto.TargetCreateTarget{})
+	}
+	if err != nil || profile == nil {
+		return page, err
+	}
+
+	if err := profile.Apply(page); err != nil {
+		page.Close()
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// ApolloLogin is a page action that logs into apollo.io with the provided [*models.Account]'s cookies,
+// or its credentials if the cookies are missing or stale. The account's cookies are refreshed after
+// logging in. If arg: stealth is set to true, the resulting page will be launched in stealth mode,
+// and if arg: profile isn't nil, it will look like it's running on the profile's device.
+func ApolloLogin(
+	browser *rod.Browser,
+	acc *models.Account,
+	timeout time.Duration,
+	stealth bool,
+	profile *fingerprint.Profile,
+) (page *rod.Page, err error) {
+	parent := browser.GetContext()
+	ctx, span := tracing.Start(parent, "actions.ApolloLogin", attribute.String("account", acc.Email))
+	defer func() {
+		// the page outlives the login, so it mustn't carry its span.
+		if page != nil {
+			page = page.Context(parent)
+		}
+		tracing.End(span, err)
+	}()
+
+	if page, err = NewPage(browser.Context(ctx), stealth, profile); err != nil {
 		return
 	}
 
-	return page, errors.New("failed to login due to unknown circumstances")
+	logger(page).Info().Str("account", acc.Email).Msg("logging in")
+
+	return page, login(pageSession{page}, acc, timeout)
 }
@@ -0,0 +1,204 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/models"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/rs/zerolog"
+)
+
+// fakeSession is a [loginSession] that records the operations performed on it.
+type fakeSession struct {
+	calls []string
+
+	// validCookies reports whether the cookies set on the session log it in.
+	validCookies bool
+	// passwordErr is returned by passwordLogin.
+	passwordErr error
+	// freshCookies are the cookies set on the account once logged in.
+	freshCookies []*proto.NetworkCookie
+
+	cookies       []*proto.NetworkCookie
+	authenticated bool
+}
+
+func (s *fakeSession) setCookies(cookies []*proto.NetworkCookie) error {
+	s.calls = append(s.calls, "setCookies")
+	s.cookies = cookies
+	return nil
+}
+
+func (s *fakeSession) clearCookies() error {
+	s.calls = append(s.calls, "clearCookies")
+	s.cookies = nil
+	return nil
+}
+
+func (s *fakeSession) resume(acc *models.Account, _ time.Duration) (bool, error) {
+	s.calls = append(s.calls, "resume")
+	if len(s.cookies) == 0 || !s.validCookies {
+		return false, nil
+	}
+
+	s.authenticated = true
+	acc.SetLoginCookies(s.freshCookies)
+	return true, nil
+}
+
+func (s *fakeSession) passwordLogin(*models.Account, time.Duration) error {
+	s.calls = append(s.calls, "passwordLogin")
+	if s.passwordErr == nil {
+		s.authenticated = true
+	}
+
+	return s.passwordErr
+}
+
+func (s *fakeSession) loggedIn(acc *models.Account, _ time.Duration) (bool, error) {
+	s.calls = append(s.calls, "loggedIn")
+	if !s.authenticated {
+		return false, context.DeadlineExceeded
+	}
+
+	acc.SetLoginCookies(s.freshCookies)
+	return true, nil
+}
+
+func (s *fakeSession) logger() *zerolog.Logger {
+	logger := zerolog.Nop()
+	return &logger
+}
+
+func testCookies(name string, expires time.Time) []*proto.NetworkCookie {
+	return []*proto.NetworkCookie{
+		{Name: name, Expires: proto.TimeSinceEpoch(expires.Unix())},
+	}
+}
+
+func testAccountCookie(t *testing.T, acc *models.Account, want string) {
+	t.Helper()
+
+	cookies, ok := acc.GetLoginCookies()
+	if want == "" {
+		if ok {
+			t.Fatalf("expected account to have no cookies, got %q", cookies[0].Name)
+		}
+		return
+	}
+
+	if !ok || cookies[0].Name != want {
+		t.Fatalf("expected account cookie %q, got %v", want, cookies)
+	}
+}
+
+func testCalls(t *testing.T, s *fakeSession, want ...string) {
+	t.Helper()
+
+	if !slices.Equal(s.calls, want) {
+		t.Fatalf("expected calls %v, got %v", want, s.calls)
+	}
+}
+
+func TestLoginWithValidCookies(t *testing.T) {
+	acc := &models.Account{Email: "test@example.com"}
+	acc.SetLoginCookies(testCookies("old", time.Now().Add(time.Hour)))
+
+	s := &fakeSession{validCookies: true, freshCookies: testCookies("fresh", time.Now().Add(time.Hour))}
+	if err := login(s, acc, time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	testCalls(t, s, "setCookies", "resume")
+	testAccountCookie(t, acc, "fresh")
+}
+
+func TestLoginWithStaleCookies(t *testing.T) {
+	acc := &models.Account{Email: "test@example.com"}
+	acc.SetLoginCookies(testCookies("old", time.Now().Add(time.Hour)))
+
+	s := &fakeSession{freshCookies: testCookies("fresh", time.Now().Add(time.Hour))}
+	if err := login(s, acc, time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	testCalls(t, s, "setCookies", "resume", "clearCookies", "passwordLogin", "loggedIn")
+	testAccountCookie(t, acc, "fresh")
+}
+
+func TestLoginWithExpiredCookies(t *testing.T) {
+	acc := &models.Account{Email: "test@example.com"}
+	acc.SetLoginCookies(testCookies("old", time.Now().Add(-time.Hour)))
+
+	s := &fakeSession{validCookies: true, freshCookies: testCookies("fresh", time.Now().Add(time.Hour))}
+	if err := login(s, acc, time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	testCalls(t, s, "passwordLogin", "loggedIn")
+	testAccountCookie(t, acc, "fresh")
+}
+
+func TestLoginWithoutCookies(t *testing.T) {
+	acc := &models.Account{Email: "test@example.com"}
+
+	s := &fakeSession{freshCookies: testCookies("fresh", time.Now().Add(time.Hour))}
+	if err := login(s, acc, time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	testCalls(t, s, "passwordLogin", "loggedIn")
+	testAccountCookie(t, acc, "fresh")
+}
+
+func TestLoginSecurityChallenge(t *testing.T) {
+	acc := &models.Account{Email: "test@example.com"}
+	acc.SetLoginCookies(testCookies("old", time.Now().Add(time.Hour)))
+
+	s := &fakeSession{passwordErr: ErrorSecurityChallenge}
+	if err := login(s, acc, time.Second); !errors.Is(err, ErrorSecurityChallenge) {
+		t.Fatalf("expected %v, got %v", ErrorSecurityChallenge, err)
+	}
+
+	testCalls(t, s, "setCookies", "resume", "clearCookies", "passwordLogin")
+	testAccountCookie(t, acc, "")
+}
+
+func TestLoginFailure(t *testing.T) {
+	acc := &models.Account{Email: "test@example.com"}
+
+	s := &fakeSession{}
+	if err := login(&neverLoggedIn{s}, acc, time.Second); err == nil {
+		t.Fatal("expected login to fail")
+	}
+
+	testAccountCookie(t, acc, "")
+}
+
+// neverLoggedIn is a [loginSession] whose password logins never succeed.
+type neverLoggedIn struct {
+	*fakeSession
+}
+
+func (s *neverLoggedIn) passwordLogin(*models.Account, time.Duration) error {
+	s.calls = append(s.calls, "passwordLogin")
+	return nil
+}
@@ -0,0 +1,122 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/fixture"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/launcher"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+const fixtureDir = "testdata/fixtures"
+
+// replay returns a page showing the fixture with the given name. The test is skipped if no
+// browser is installed.
+func replay(t *testing.T, name string) *rod.Page {
+	t.Helper()
+
+	bin, ok := launcher.LookPath()
+	if !ok {
+		t.Skip("no browser found")
+	}
+
+	u, err := launcher.New().Bin(bin).Headless(true).Launch()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	browser := rod.New().ControlURL(u)
+	if err := browser.Connect(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { browser.Close() })
+
+	page, err := browser.Page(proto.TargetCreateTarget{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, stop, err := fixture.Replay(page, fixtureDir, name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { stop() })
+
+	return WithUIVariant(page, UIVariant(f.UI))
+}
+
+func TestReplayPageData(t *testing.T) {
+	page := replay(t, "people-001")
+
+	pd, err := GetPageData(page, 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if pd.Number != 1 || pd.Start != 1 || pd.End != 25 || pd.Size != 25 || pd.TotalSize != 1234 {
+		t.Errorf("unexpected page data: %+v", pd)
+	}
+
+	if pd.LastPage {
+		t.Error("expected more pages after the first one")
+	}
+}
+
+func TestReplayScrapeLeads(t *testing.T) {
+	page := replay(t, "people-001")
+
+	leads, report, err := ScrapeLeads(page, 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(leads) != 2 {
+		t.Fatalf("got %d leads, want 2", len(leads))
+	}
+
+	if report.Rows != 2 || report.Extracted != 2 || report.Invalid != 0 {
+		t.Errorf("unexpected scrape report: %+v", report)
+	}
+
+	lead := leads[0]
+	if lead.Name != "Jane Doe" || lead.Company != "Acme" || lead.Email != "lead@example.com" {
+		t.Errorf("unexpected lead: %+v", lead)
+	}
+
+	if lead.Industry != "Software,Sales" {
+		t.Errorf("got industry %q, want %q", lead.Industry, "Software,Sales")
+	}
+
+	if lead.Links != "https://www.linkedin.com/in/jane-doe" {
+		t.Errorf("got links %q", lead.Links)
+	}
+}
+
+func TestReplaySaveLeads(t *testing.T) {
+	page := replay(t, "people-001")
+
+	_, list, err := SaveLeads(page, "test", 5*time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if list.Created {
+		t.Error("expected the existing list to be reused")
+	}
+}
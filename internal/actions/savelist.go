@@ -0,0 +1,138 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actions
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/go-rod/rod"
+)
+
+// ListPick describes how the list that leads were saved to was picked in Apollo's save dialog.
+type ListPick struct {
+	// Created is set if no list had the given name, so that a new one was created rather than
+	// reusing an existing one.
+	Created bool
+
+	// Similar are the names of the existing lists that Apollo offered whose names are close to
+	// the created list's, which usually means that the list's name has a typo.
+	Similar []string
+}
+
+// pickList picks the list with the given name in the save dialog, creating it if Apollo has no such
+// list. The dialog's list input is left focused, so that the save can be confirmed with 'Enter'.
+func pickList(page *rod.Page, modal *rod.Element, listName string) (pick ListPick, err error) {
+	sel := selectors(page)
+	modal.MustElement(sel.SelectInput).MustInput(listName)
+
+	menu := modal.MustElement(sel.SelectMenu).MustWaitVisible().Sleeper(rod.NotFoundSleeper)
+	exact := fmt.Sprintf("^%s$", regexp.QuoteMeta(listName))
+
+	// the menu lists the matching lists along with the option to create a new one, which is only
+	// shown if none of them have the exact name.
+	option, err := page.Race().
+		ElementFunc(func(*rod.Page) (*rod.Element, error) { return menu.ElementR(sel.SelectOption, exact) }).
+		ElementFunc(func(*rod.Page) (*rod.Element, error) { return menu.Element(sel.CreateOption) }).
+		Do()
+	if err != nil {
+		return pick, err
+	}
+
+	if pick.Created = option.MustMatches(sel.CreateOption); pick.Created {
+		var names []string
+		for _, el := range menu.MustElements(sel.SelectOption) {
+			if !el.MustMatches(sel.CreateOption) {
+				names = append(names, el.MustText())
+			}
+		}
+		pick.Similar = similarListNames(listName, names)
+
+		logger(page).Info().Str("list", listName).Strs("similar", pick.Similar).Msg("creating list")
+	} else {
+		logger(page).Debug().Str("list", listName).Msg("reusing list")
+	}
+
+	option.MustClick()
+
+	// the dialog shows the picked list once it's been picked, or created.
+	value := modal.MustElement(sel.SelectValue).MustWaitVisible()
+	if text := strings.TrimSpace(value.MustText()); text != listName {
+		return pick, fmt.Errorf("picked list %q rather than %q", text, listName)
+	}
+
+	modal.MustElement(sel.SelectInput).MustFocus()
+	return pick, nil
+}
+
+// similarListNames returns the names which are close to the given list name, ignoring case,
+// spacing and punctuation and allowing for a typo or two in longer names.
+func similarListNames(listName string, names []string) (similar []string) {
+	want := foldListName(listName)
+	for _, name := range names {
+		if name == listName {
+			continue
+		}
+
+		got := foldListName(name)
+		if got == want || editDistance(got, want) <= min(2, len([]rune(want))/4) {
+			similar = append(similar, name)
+		}
+	}
+
+	return similar
+}
+
+// foldListName lower cases the name and drops everything but its letters and digits.
+func foldListName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, name)
+}
+
+// editDistance returns the number of insertions, deletions, substitutions and transpositions of
+// adjacent characters needed to turn a into b.
+func editDistance(a, b string) int {
+	s, t := []rune(a), []rune(b)
+	d := make([][]int, len(s)+1)
+	for i := range d {
+		d[i] = make([]int, len(t)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(s); i++ {
+		for j := 1; j <= len(t); j++ {
+			cost := 1
+			if s[i-1] == t[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] {
+				d[i][j] = min(d[i][j], d[i-2][j-2]+1)
+			}
+		}
+	}
+
+	return d[len(s)][len(t)]
+}
@@ -0,0 +1,40 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actions
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSimilarListNames(t *testing.T) {
+	names := []string{"Q3 Leads", "q3-leads", "Q3 Laeds", "Q4 Leads 2025", "Sales", "Q3 Leads EU"}
+
+	tests := []struct {
+		list string
+		want []string
+	}{
+		{"Q3 Leads", []string{"q3-leads", "Q3 Laeds"}},
+		{"q3 leads", []string{"Q3 Leads", "q3-leads", "Q3 Laeds"}},
+		{"Marketing", nil},
+		{"Sale", []string{"Sales"}},
+	}
+
+	for _, tt := range tests {
+		if got := similarListNames(tt.list, names); !slices.Equal(got, tt.want) {
+			t.Errorf("%q: got similar lists %q; want %q", tt.list, got, tt.want)
+		}
+	}
+}
@@ -15,15 +15,22 @@
 package actions
 
 import (
+	"context"
 	_ "embed"
+	"errors"
 	"fmt"
 	"math/rand/v2"
+	"regexp"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/devsheke/scrapollo/internal/models"
+	"github.com/devsheke/scrapollo/internal/tracing"
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/input"
-	"github.com/rs/zerolog/log"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // ApolloTab represents the tabs on the Apollo 'People' page.
@@ -37,8 +44,11 @@ const (
 )
 
 // Select selects the given [ApolloTab] on the page.
-func (tab ApolloTab) Select(page *rod.Page) (err error) {
-	log.Debug().Str("tab", string(tab)).Msg("selecting tab")
+func (tab ApolloTab) Select(page *rod.Page, timeout time.Duration) (err error) {
+	page, span := startSpan(page, "SelectTab", attribute.String("tab", string(tab)))
+	defer func() { tracing.End(span, err) }()
+
+	logger(page).Debug().Str("tab", string(tab)).Msg("selecting tab")
 
 	defer func() {
 		if err != nil {
@@ -46,9 +56,14 @@ func (tab ApolloTab) Select(page *rod.Page) (err error) {
 		}
 	}()
 
+	sel := selectors(page)
 	err = rod.Try(func() {
-		page := page.Timeout(30 * time.Second)
-		page.MustElementR(".zp_PfDqP", fmt.Sprintf(`/%s/`, tab)).MustWaitVisible().MustClick()
+		el, err := locate(page.Timeout(timeout), sel.Tab, fmt.Sprintf(`/%s/`, tab), tabLocator(tab))
+		if err != nil {
+			panic(err)
+		}
+
+		el.MustWaitVisible().MustClick()
 	})
 
 	return
@@ -62,61 +77,349 @@ func randomSleep() {
 	time.Sleep(time.Duration(sleep) * time.Millisecond)
 }
 
-// SaveLeads saves all available leads on the current page to the specified list on Apollo.
-func SaveLeads(page *rod.Page, listName string, timeout time.Duration) error {
-	log.Info().Str("list", listName).Msg("saving leads")
-	err := rod.Try(func() {
+// SaveLeads saves all available leads on the current page to the specified list on Apollo. If
+// rows are provided, only the leads in those rows (counting from 0) are saved. Apollo doesn't save
+// the leads which are already in the contacts of another seat of the account's team, whose number
+// is returned along with whether the list was created or reused.
+func SaveLeads(
+	page *rod.Page,
+	listName string,
+	timeout time.Duration,
+	rows ...int,
+) (teamOwned int, list ListPick, err error) {
+	page, span := startSpan(page, "SaveLeads", attribute.String("list", listName))
+	defer func() { tracing.End(span, err) }()
+
+	logger(page).Info().Str("list", listName).Int("rows", len(rows)).Msg("saving leads")
+
+	if err = WaitTableLoaded(page, timeout); err != nil {
+		return 0, list, err
+	}
+
+	sel := selectors(page)
+	err = rod.Try(func() {
 		page := page.Timeout(timeout)
-		page.MustElement(".zp_wMhzv").MustWaitVisible().MustClick()
-		page.MustElement("button[type=submit].zp_qe0Li.zp_FG3Vz.zp_rsjqe.zp_h2EIO").
-			MustWaitVisible().
-			MustClick()
+		selected := len(rows)
+
+		buttons := []struct {
+			selector string
+			fallback textLocator
+		}{
+			{sel.SelectPage, selectPageButton},
+			{sel.SaveToList, saveButton},
+		}
+
+		if len(rows) == 0 {
+			selected = len(page.MustElements(sel.LeadRow))
+			page.MustElement(sel.SelectAll).MustWaitVisible().MustClick()
+		} else {
+			leadRows := page.MustElements(sel.LeadRow)
+			for _, row := range rows {
+				if row >= len(leadRows) {
+					panic(fmt.Errorf("row %d not found, the page only has %d rows", row, len(leadRows)))
+				}
+
+				leadRows[row].MustElement(sel.LeadCheckbox).MustClick()
+			}
+			buttons = buttons[1:]
+		}
 
-		page.MustElement("button.zp_qe0Li.zp_FG3Vz.zp_rsjqe.zp_h2EIO").
-			MustWaitVisible().
-			MustClick()
+		for _, button := range buttons {
+			el, err := locate(page, button.selector, "", button.fallback)
+			if err != nil {
+				panic(err)
+			}
 
-		page.MustElement(".zp-modal-content.zp_AX8K7.zp_qTumF.zp_esFCS").
-			MustWaitVisible().
-			MustElement(".Select-input").
-			MustInput(listName)
+			el.MustWaitVisible().MustClick()
+		}
 
-		for range 2 {
-			page.Keyboard.MustType(input.Enter)
-			randomSleep()
+		modal := page.MustElement(sel.SaveModal).MustWaitVisible()
+		if list, err = pickList(page, modal, listName); err != nil {
+			panic(err)
 		}
 
-		page.MustElement(".zp_VfG2H.zp_cUvBN").MustWaitVisible()
+		randomSleep()
+		page.Keyboard.MustType(input.Enter)
+
+		if teamOwned, err = waitForSave(page, selected, timeout); err != nil {
+			panic(err)
+		}
 		page.MustReload()
 	})
 
-	return err
+	if teamOwned > 0 {
+		logger(page).Info().Int("leads", teamOwned).Msg("leads are already in the team's contacts")
+	}
+
+	return teamOwned, list, err
+}
+
+// ErrorBulkSaveUnavailable is returned by [BulkSaveLeads] when Apollo doesn't offer to select all of
+// the search's leads, or when doing so would save too many of them.
+var ErrorBulkSaveUnavailable = errors.New("bulk saving is unavailable")
+
+// bulkOfferTimeout is how long the 'Select all N people' button is looked for once the selection menu
+// has been opened. It's short, since the button is shown along with the menu if it's offered at all.
+const bulkOfferTimeout = 3 * time.Second
+
+var bulkOfferRegex = regexp.MustCompile(`(?i)select all ([\d,.]+)`)
+
+// BulkSaveLeads saves all of the leads of the current search to the specified list at once with
+// Apollo's 'Select all N people' bulk action (which only some plans offer), returning the number of
+// leads saved and the number of leads skipped because they're already in the contacts of another seat
+// of the account's team, as well as whether the list was created or reused. [ErrorBulkSaveUnavailable]
+// is returned if the bulk action isn't offered or would save more than limit leads, in which case the
+// page is reloaded so that the leads can be saved page by page with [SaveLeads] instead.
+func BulkSaveLeads(
+	page *rod.Page,
+	listName string,
+	limit int,
+	timeout time.Duration,
+) (saved, teamOwned int, list ListPick, err error) {
+	page, span := startSpan(page, "BulkSaveLeads", attribute.String("list", listName))
+	defer func() { tracing.End(span, err) }()
+
+	sel := selectors(page)
+	unavailable := false
+	err = rod.Try(func() {
+		page := page.Timeout(timeout)
+		page.MustElement(sel.SelectAll).MustWaitVisible().MustClick()
+
+		offer, err := page.Timeout(bulkOfferTimeout).ElementR(sel.SelectAllResults, `/^\s*select all [\d,.]+/i`)
+		if errors.Is(err, context.DeadlineExceeded) {
+			logger(page).Debug().Msg("bulk saving isn't offered")
+			unavailable = true
+			return
+		} else if err != nil {
+			panic(err)
+		}
+
+		match := bulkOfferRegex.FindStringSubmatch(offer.MustText())
+		if match == nil {
+			panic(fmt.Errorf("unexpected bulk selection: %q", offer.MustText()))
+		}
+
+		saved, err = strconv.Atoi(strings.NewReplacer(",", "", ".", "").Replace(match[1]))
+		if err != nil {
+			panic(err)
+		}
+
+		if saved > limit {
+			logger(page).Debug().Int("leads", saved).Int("limit", limit).Msg("bulk saving would save too many leads")
+			unavailable = true
+			return
+		}
+
+		logger(page).Info().Str("list", listName).Int("leads", saved).Msg("bulk saving leads")
+		offer.MustClick()
+
+		el, err := locate(page, sel.SaveToList, "", saveButton)
+		if err != nil {
+			panic(err)
+		}
+		el.MustWaitVisible().MustClick()
+
+		modal := page.MustElement(sel.SaveModal).MustWaitVisible()
+		if list, err = pickList(page, modal, listName); err != nil {
+			panic(err)
+		}
+
+		randomSleep()
+		page.Keyboard.MustType(input.Enter)
+
+		if teamOwned, err = waitForSave(page, saved, timeout); err != nil {
+			panic(err)
+		}
+		page.MustReload()
+
+		if teamOwned > 0 {
+			logger(page).Info().Int("leads", teamOwned).Msg("leads are already in the team's contacts")
+			saved -= teamOwned
+		}
+	})
+
+	if err != nil {
+		return 0, 0, list, err
+	}
+
+	if unavailable {
+		if err := page.Reload(); err != nil {
+			return 0, 0, list, err
+		}
+		return 0, 0, list, ErrorBulkSaveUnavailable
+	}
+
+	return saved, teamOwned, list, nil
 }
 
 //go:embed scripts/scrape.js
 var scrapeScript string
 
-// ScrapeLeads returns all available leads on the current page (if they are found).
-func ScrapeLeads(page *rod.Page, timeout time.Duration) ([]*models.Lead, error) {
-	log.Debug().Msg("scraping leads")
+// ScrapeReport describes how well the rows of a page were scraped, so that leads lost to changes in
+// Apollo's markup don't go unnoticed.
+type ScrapeReport struct {
+	// Rows is the number of rows in the page's table.
+	Rows int
+
+	// Extracted is the number of leads returned.
+	Extracted int
+
+	// Recovered is the number of leads which were extracted from their row's text, as the row
+	// didn't match the table's layout, or by other means altogether (see [ValidateLeads]).
+	Recovered int
+
+	// Invalid is the number of rows which didn't hold a valid lead, even once recovered. They are
+	// dropped, except by [ListLeads].
+	Invalid int
+
+	// NoEmail is the number of rows skipped as their email can't be revealed.
+	NoEmail int
+}
+
+// scrapeOutput is the output of the scrape script.
+type scrapeOutput struct {
+	Rows    int          `json:"rows"`
+	Skipped int          `json:"skipped"`
+	Leads   []scrapedRow `json:"leads"`
+}
+
+// scrapedRow is a lead scraped from a row of the leads table.
+type scrapedRow struct {
+	models.Lead
+	Row       int  `json:"row"`
+	Recovered bool `json:"recovered"`
+}
+
+var emailPattern = regexp.MustCompile(`^[^\s@]+@[^\s@]+\.[^\s@]+$`)
+
+// validateLead returns an error if the lead has no name or an implausible email.
+func validateLead(lead *models.Lead) error {
+	if strings.TrimSpace(lead.Name) == "" {
+		return errors.New("lead has no name")
+	}
+
+	if lead.Email != "" && !emailPattern.MatchString(lead.Email) {
+		return fmt.Errorf("lead has an invalid email %q", lead.Email)
+	}
+
+	return nil
+}
+
+// leads returns the valid leads of the output, which are normalized (see [models.Lead.Normalize]),
+// and a report of the scrape. Invalid leads are kept if keepInvalid is true, so that the leads line up
+// with the table's rows.
+func (o *scrapeOutput) leads(log *zerolog.Logger, keepInvalid bool) ([]*models.Lead, *ScrapeReport) {
+	report := &ScrapeReport{Rows: o.Rows, NoEmail: o.Skipped}
+
+	leads := make([]*models.Lead, 0, len(o.Leads))
+	for _, row := range o.Leads {
+		lead := &row.Lead
+		lead.Normalize()
+
+		if err := validateLead(lead); err != nil {
+			log.Warn().Err(err).Int("row", row.Row).Msg("failed to scrape row")
+
+			report.Invalid++
+			if !keepInvalid {
+				continue
+			}
+		} else if row.Recovered {
+			log.Warn().Int("row", row.Row).Msg("row didn't match the table's layout; recovered its lead from its text")
+			report.Recovered++
+		}
+
+		leads = append(leads, lead)
+	}
+
+	report.Extracted = len(leads)
+	return leads, report
+}
+
+// ValidateLeads normalizes the leads extracted from a page by other means than the scrape script (see
+// [models.Lead.Normalize]) and returns the valid ones, along with a report in which they're counted as
+// recovered.
+func ValidateLeads(page *rod.Page, leads []*models.Lead) ([]*models.Lead, *ScrapeReport) {
+	report := &ScrapeReport{Rows: len(leads)}
+
+	valid := make([]*models.Lead, 0, len(leads))
+	for i, lead := range leads {
+		lead.Normalize()
 
+		if err := validateLead(lead); err != nil {
+			logger(page).Warn().Err(err).Int("row", i).Msg("failed to extract row")
+			report.Invalid++
+			continue
+		}
+
+		valid = append(valid, lead)
+	}
+
+	report.Extracted, report.Recovered = len(valid), len(valid)
+	return valid, report
+}
+
+// ScrapeLeads returns all available leads on the current page (if they are found), along with a
+// report of how well its rows were scraped.
+func ScrapeLeads(page *rod.Page, timeout time.Duration) (leads []*models.Lead, report *ScrapeReport, err error) {
+	page, span := startSpan(page, "ScrapeLeads")
+	defer func() { tracing.End(span, err) }()
+
+	logger(page).Debug().Msg("scraping leads")
+
+	output, err := scrapeRows(page, timeout, true)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	leads, report = output.leads(logger(page), false)
+	return leads, report, nil
+}
+
+// ListLeads returns the leads in every row of the current page without revealing their emails, so
+// that no credits are spent. Only the emails of leads which have already been saved are returned.
+// Invalid leads are kept, so that the leads line up with the page's rows.
+func ListLeads(page *rod.Page, timeout time.Duration) (leads []*models.Lead, err error) {
+	page, span := startSpan(page, "ListLeads")
+	defer func() { tracing.End(span, err) }()
+
+	logger(page).Debug().Msg("listing leads")
+
+	output, err := scrapeRows(page, timeout, false)
+	if err != nil {
+		return nil, err
+	}
+
+	leads, _ = output.leads(logger(page), true)
+	return leads, nil
+}
+
+// scrapeRows runs the scrape script on the rows of the current page.
+func scrapeRows(page *rod.Page, timeout time.Duration, reveal bool) (*scrapeOutput, error) {
+	sel := selectors(page)
 	err := rod.Try(func() {
-		page.Timeout(timeout).MustElement(".zp_tFLCQ .zp_hWv1I").MustWaitVisible()
+		page.Timeout(timeout).MustElement(sel.LeadRow).MustWaitVisible()
 	})
 
 	if err != nil {
 		return nil, err
 	}
 
-	var leads []*models.Lead
+	if err := WaitTableLoaded(page, timeout); err != nil {
+		return nil, err
+	}
 
-	log.Debug().Msg("running scrape script")
-	result, err := page.Timeout(30 * time.Second).Eval(scrapeScript)
+	logger(page).Debug().Msg("running scrape script")
+	result, err := page.Timeout(30*time.Second).Eval(scrapeScript, sel.LeadRow, sel.LeadColumn, sel.LeadEmail, reveal)
 	if err != nil {
 		return nil, err
 	}
 
-	log.Debug().Msg("unmarshaling scraped values")
-	err = result.Value.Unmarshal(&leads)
-	return leads, err
+	logger(page).Debug().Msg("unmarshaling scraped values")
+
+	output := new(scrapeOutput)
+	if err := result.Value.Unmarshal(output); err != nil {
+		return nil, err
+	}
+
+	return output, nil
 }
@@ -0,0 +1,52 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actions
+
+import (
+	"testing"
+
+	"github.com/devsheke/scrapollo/internal/models"
+	"github.com/rs/zerolog"
+)
+
+func TestScrapeOutputLeads(t *testing.T) {
+	output := &scrapeOutput{
+		Rows:    5,
+		Skipped: 1,
+		Leads: []scrapedRow{
+			{Row: 0, Lead: models.Lead{Name: "Jane Doe", Email: "jane@example.com"}},
+			{Row: 1, Lead: models.Lead{Name: "John Doe"}, Recovered: true},
+			{Row: 2, Lead: models.Lead{Name: " ", Email: "nobody@example.com"}},
+			{Row: 3, Lead: models.Lead{Name: "Max Doe", Email: "Access email"}},
+		},
+	}
+
+	log := zerolog.Nop()
+
+	leads, report := output.leads(&log, false)
+	if len(leads) != 2 || leads[0].Name != "Jane Doe" || leads[1].Name != "John Doe" {
+		t.Errorf("got leads %+v; want the valid ones", leads)
+	}
+
+	want := ScrapeReport{Rows: 5, Extracted: 2, Recovered: 1, Invalid: 2, NoEmail: 1}
+	if *report != want {
+		t.Errorf("got report %+v; want %+v", *report, want)
+	}
+
+	// listed leads line up with the rows.
+	if leads, _ := output.leads(&log, true); len(leads) != 4 {
+		t.Errorf("got %d listed leads; want 4", len(leads))
+	}
+}
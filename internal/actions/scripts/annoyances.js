@@ -0,0 +1,28 @@
+(selectors, binding) => {
+  if (window.__scrapolloAnnoyanceObserver) {
+    window.__scrapolloAnnoyanceObserver.disconnect();
+  }
+
+  let pending = false;
+  const check = () => {
+    pending = false;
+    for (const selector of selectors) {
+      if (document.querySelector(selector) !== null) {
+        window[binding](selector);
+        return;
+      }
+    }
+  };
+
+  const observer = new MutationObserver(() => {
+    if (!pending) {
+      pending = true;
+      setTimeout(check, 250);
+    }
+  });
+
+  observer.observe(document.documentElement, { childList: true, subtree: true });
+  window.__scrapolloAnnoyanceObserver = observer;
+
+  check();
+};
@@ -1,10 +1,9 @@
-() => {
-  let leads = [];
-  const rows = document.querySelectorAll('.zp_tFLCQ .zp_hWv1I');
+(rowSelector, columnSelector, emailSelector, reveal, root = document) => {
+  const emailPattern = /^[^\s@]+@[^\s@]+\.[^\s@]+$/;
 
-  for (let i = 0; i < rows.length; i++) {
-    const columns = rows[i].querySelectorAll('.zp_KtrQp');
-    let lead = {
+  // a row is extracted from its columns, by their position in the table's layout.
+  const extract = (columns) => {
+    const lead = {
       name: columns[1].innerText.replaceAll('\n------', ''),
       title: columns[2].innerText,
       company: columns[3].innerText,
@@ -21,35 +20,79 @@
     }
     lead.links = links.join(',');
 
-    const emailSpan = columns[4].querySelector('.zp_xvo3G');
+    return lead;
+  };
+
+  // recover extracts what it can from a row which doesn't match the table's layout (e.g. when a
+  // column is missing): the name from its first line of text, and its email and links by their
+  // patterns.
+  const recover = (row) => {
+    const text = row.innerText;
+    const lines = text.split('\n').map((line) => line.trim()).filter((line) => line !== '');
+    const email = text.split(/\s+/).find((word) => emailPattern.test(word));
+    const links = [...row.querySelectorAll('a')]
+      .map((link) => link.href)
+      .filter((href) => href.startsWith('http'));
+
+    return { name: lines[0] ?? '', email: email ?? '', links: links.join(',') };
+  };
+
+  const valid = (lead) => lead.name.trim() !== '' && (!lead.email || emailPattern.test(lead.email));
+
+  let leads = [];
+  let skipped = 0;
+  const rows = root.querySelectorAll(rowSelector);
+
+  for (let i = 0; i < rows.length; i++) {
+    const columns = rows[i].querySelectorAll(columnSelector);
+
+    let lead;
+    try {
+      lead = extract(columns);
+    } catch {
+      lead = { ...recover(rows[i]), recovered: true };
+    }
+    lead.row = i;
+
+    const emailColumn = lead.recovered ? null : columns[4];
+    const emailSpan = emailColumn?.querySelector(emailSelector) ?? null;
     if (emailSpan !== null) {
-      lead.email = columns[4].querySelector('.zp_xvo3G').innerText;
+      lead.email = emailSpan.innerText;
       lead.phone = columns[5].innerText;
-      leads.push(lead);
-      continue;
-    }
+    } else if (reveal && emailColumn !== null) {
+      const emailButton = emailColumn.querySelector('button');
+      if (emailButton === null) {
+        skipped++;
+        continue;
+      }
 
-    const emailButton = columns[4].querySelector('button');
-    if (emailButton === null) {
-      continue;
+      emailButton.click();
+      let retries = 0;
+      while (retries < 30) {
+        const emailSpan = emailColumn.querySelector(emailSelector);
+        if (emailSpan === null) {
+          new Promise((resolve) => setTimeout(resolve, 2000)).then((_) => { });
+          retries++;
+        } else {
+          lead.email = emailSpan.innerText;
+          lead.phone = columns[5].innerText;
+          break;
+        }
+      }
     }
 
-    emailButton.click();
-    let retries = 0;
-    while (retries < 30) {
-      const emailSpan = columns[4].querySelector('.zp_xvo3G');
-      if (emailSpan === null) {
-        new Promise((resolve) => setTimeout(resolve, 2000)).then((_) => { });
-        retries++;
-      } else {
-        lead.email = columns[4].querySelector('.zp_xvo3G').innerText;
-        lead.phone = columns[5].innerText;
-        break;
-      }
+    // rows whose columns were extracted but don't hold a valid lead, e.g. because a column moved,
+    // are recovered too.
+    if (!valid(lead)) {
+      const recovered = recover(rows[i]);
+      if (lead.name.trim() === '') lead.name = recovered.name;
+      if (lead.email && !emailPattern.test(lead.email)) lead.email = recovered.email;
+      lead.recovered = true;
     }
 
+    // without revealing emails, every row is returned so that leads line up with the rows.
     leads.push(lead);
   }
 
-  return leads;
+  return { rows: rows.length, skipped, leads };
 };
@@ -0,0 +1,16 @@
+(id) => {
+  // the page is copied into an off-screen frame rather than kept as detached elements, as the
+  // innerText of elements that aren't rendered loses the line breaks which the scrape script relies on.
+  const frame = document.createElement('iframe');
+  frame.id = id;
+  frame.setAttribute('aria-hidden', 'true');
+  frame.style.cssText = 'position: fixed; left: -10000px; top: 0; width: 1920px; height: 1080px; border: 0;';
+  document.documentElement.appendChild(frame);
+
+  const doc = frame.contentDocument;
+  for (const style of document.querySelectorAll('style, link[rel=stylesheet]')) {
+    doc.head.appendChild(doc.importNode(style, true));
+  }
+  // cloned scripts are never run.
+  doc.body.replaceWith(doc.importNode(document.body, true));
+};
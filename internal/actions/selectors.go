@@ -0,0 +1,242 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-rod/rod"
+)
+
+// UIVariant is a variant of the Apollo UI. Apollo A/B tests redesigns of its app, so accounts
+// in the same pool may see different markup.
+type UIVariant string
+
+// The known variants of the Apollo UI.
+const (
+	ClassicUI  UIVariant = "classic"
+	RedesignUI UIVariant = "redesign"
+
+	// MobileUI is the mobile web layout, which Apollo serves to phones (see
+	// [fingerprint.MobileProfiles]).
+	MobileUI UIVariant = "mobile"
+)
+
+// Selectors is the set of CSS selectors that page actions use to find elements on a variant of
+// the Apollo UI.
+type Selectors struct {
+	// Marker is an element which is only present on this variant of the UI. An empty marker
+	// matches any page.
+	Marker string
+
+	// 'People' page filters.
+	FilterAccordion, AccordionToggle, AccordionOpen, SelectInput, SelectMenu, SelectOption string
+
+	// 'People' page pagination.
+	PageInfo, NoResults, PageNumber, NavButtons, PageSwitch, PageList string
+
+	// 'People' page tabs.
+	Tab string
+
+	// Saving leads.
+	SelectAll, SelectPage, SaveToList, SaveModal, SaveConfirmation, LeadCheckbox string
+
+	// Picking the list in the save dialog. CreateOption is the menu's option to create a new list,
+	// and SelectValue shows the list once it's been picked.
+	CreateOption, SelectValue string
+
+	// Saving all of a search's leads at once. The 'Select all N people' button is found by its
+	// text among the elements matching SelectAllResults.
+	SelectAllResults string
+
+	// Scraping leads.
+	LeadRow, LeadColumn, LeadEmail string
+}
+
+var (
+	classicSelectors = &Selectors{
+		FilterAccordion:  ".zp-accordion-header.zp_r3aQ1",
+		AccordionToggle:  ".zp-accordion.zp_UeG9f.zp_p8DhX",
+		AccordionOpen:    ".zp_YkfVU",
+		SelectInput:      ".Select-input",
+		SelectMenu:       ".Select-menu-outer",
+		SelectOption:     ".Select-option",
+		PageInfo:         ".zp_xAPpZ",
+		NoResults:        ".zp_MVq1c",
+		PageNumber:       ".zp_jzp8p",
+		NavButtons:       ".zp_m_JQ3 > .zp_qe0Li.zp_S5tZC",
+		PageSwitch:       ".zp_VTl3h.zp_xqxgc .zp_dJ2fA",
+		PageList:         "[role=listbox]",
+		Tab:              ".zp_PfDqP",
+		SelectAll:        ".zp_wMhzv",
+		SelectPage:       "button[type=submit].zp_qe0Li.zp_FG3Vz.zp_rsjqe.zp_h2EIO",
+		SaveToList:       "button.zp_qe0Li.zp_FG3Vz.zp_rsjqe.zp_h2EIO",
+		SaveModal:        ".zp-modal-content.zp_AX8K7.zp_qTumF.zp_esFCS",
+		SaveConfirmation: ".zp_VfG2H.zp_cUvBN",
+		LeadCheckbox:     "input[type=checkbox]",
+		CreateOption:     ".Select-create-option-placeholder",
+		SelectValue:      ".Select-value-label",
+		SelectAllResults: "button",
+		LeadRow:          ".zp_tFLCQ .zp_hWv1I",
+		LeadColumn:       ".zp_KtrQp",
+		LeadEmail:        ".zp_xvo3G",
+	}
+
+	// redesignSelectors relies on roles and attributes rather than generated class names where
+	// possible, since the redesign's class names are still changing between releases.
+	redesignSelectors = &Selectors{
+		Marker:           "#side-nav",
+		FilterAccordion:  "[data-cy=filter-accordion]",
+		AccordionToggle:  "[data-cy=filter-accordion] [role=button]",
+		AccordionOpen:    "[aria-expanded=true]",
+		SelectInput:      ".Select-input",
+		SelectMenu:       ".Select-menu-outer",
+		SelectOption:     ".Select-option",
+		PageInfo:         "[data-cy=pagination-info]",
+		NoResults:        "[data-cy=empty-state]",
+		PageNumber:       "[data-cy=pagination-current-page]",
+		NavButtons:       "[data-cy=pagination] button[aria-label]",
+		PageSwitch:       "[data-cy=pagination] [role=combobox]",
+		PageList:         "[role=listbox]",
+		Tab:              "[role=tab]",
+		SelectAll:        "[data-cy=select-all-checkbox]",
+		SelectPage:       "[role=menu] button[type=submit]",
+		SaveToList:       "button[data-cy=save-to-list]",
+		SaveModal:        "[role=dialog]",
+		SaveConfirmation: "[role=status]",
+		LeadCheckbox:     "[role=checkbox], input[type=checkbox]",
+		CreateOption:     ".Select-create-option-placeholder",
+		SelectValue:      ".Select-value-label",
+		SelectAllResults: "[role=menu] button, [role=menuitem]",
+		LeadRow:          "[role=table] [role=row]",
+		LeadColumn:       "[role=cell]",
+		LeadEmail:        "[data-cy=email]",
+	}
+
+	// mobileSelectors are those of the mobile web layout, which shows leads as cards rather than
+	// as the rows of a table, with the fields of each card in the order of the table's columns. The
+	// filters and pagination live in drawers, which share the redesign's attributes.
+	mobileSelectors = &Selectors{
+		Marker:           "[data-cy=mobile-nav]",
+		FilterAccordion:  "[data-cy=filter-drawer] [data-cy=filter-accordion]",
+		AccordionToggle:  "[data-cy=filter-accordion] [role=button]",
+		AccordionOpen:    "[aria-expanded=true]",
+		SelectInput:      ".Select-input",
+		SelectMenu:       ".Select-menu-outer",
+		SelectOption:     ".Select-option",
+		PageInfo:         "[data-cy=pagination-info]",
+		NoResults:        "[data-cy=empty-state]",
+		PageNumber:       "[data-cy=pagination-current-page]",
+		NavButtons:       "[data-cy=pagination] button[aria-label]",
+		PageSwitch:       "[data-cy=pagination] [role=combobox]",
+		PageList:         "[role=listbox]",
+		Tab:              "[role=tab]",
+		SelectAll:        "[data-cy=select-all-checkbox]",
+		SelectPage:       "[role=menu] button[type=submit]",
+		SaveToList:       "button[data-cy=save-to-list]",
+		SaveModal:        "[role=dialog]",
+		SaveConfirmation: "[role=status]",
+		LeadCheckbox:     "[role=checkbox], input[type=checkbox]",
+		CreateOption:     ".Select-create-option-placeholder",
+		SelectValue:      ".Select-value-label",
+		SelectAllResults: "[role=menu] button, [role=menuitem]",
+		LeadRow:          "[data-cy=contact-card]",
+		LeadColumn:       "[data-cy=contact-card-field]",
+		LeadEmail:        "[data-cy=email]",
+	}
+
+	// uiVariants are the selector sets of each variant, in the order they're detected in. The
+	// classic UI comes last since it has no marker.
+	uiVariants = []struct {
+		variant   UIVariant
+		selectors *Selectors
+	}{
+		{MobileUI, mobileSelectors},
+		{RedesignUI, redesignSelectors},
+		{ClassicUI, classicSelectors},
+	}
+)
+
+// SelectorsFor returns the [*Selectors] of the provided [UIVariant], falling back to those of
+// the classic UI for unknown variants.
+func SelectorsFor(variant UIVariant) *Selectors {
+	for _, v := range uiVariants {
+		if v.variant == variant {
+			return v.selectors
+		}
+	}
+
+	return classicSelectors
+}
+
+type uiVariantKey struct{}
+
+// WithUIVariant returns a copy of the page whose actions use the selectors of the provided
+// [UIVariant].
+func WithUIVariant(page *rod.Page, variant UIVariant) *rod.Page {
+	return page.Context(context.WithValue(page.GetContext(), uiVariantKey{}, variant))
+}
+
+// UIVariantOf returns the [UIVariant] attached to the page, which defaults to the classic UI.
+func UIVariantOf(page *rod.Page) UIVariant {
+	if variant, ok := page.GetContext().Value(uiVariantKey{}).(UIVariant); ok {
+		return variant
+	}
+
+	return ClassicUI
+}
+
+// selectors returns the selectors of the [UIVariant] attached to the page.
+func selectors(page *rod.Page) *Selectors {
+	return SelectorsFor(UIVariantOf(page))
+}
+
+// DetectUIVariant is a page action that detects which variant of the Apollo UI is shown on the
+// current page by looking for each variant's marker element until the timeout expires. It should
+// be called right after logging in. The classic UI is assumed if no other marker is found.
+func DetectUIVariant(page *rod.Page, timeout time.Duration) (UIVariant, error) {
+	logger(page).Debug().Msg("detecting ui variant")
+
+	ctx, cancel := context.WithTimeout(page.GetContext(), timeout)
+	defer cancel()
+
+	for {
+		for _, v := range uiVariants {
+			if v.selectors.Marker == "" {
+				continue
+			}
+
+			has, _, err := page.Context(ctx).Has(v.selectors.Marker)
+			if err != nil && !errors.Is(err, context.DeadlineExceeded) {
+				return ClassicUI, err
+			} else if has {
+				return v.variant, nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			if err := page.GetContext().Err(); err != nil {
+				return ClassicUI, err
+			}
+
+			return ClassicUI, nil
+
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actions
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSelectors(t *testing.T) {
+	for _, v := range uiVariants {
+		if SelectorsFor(v.variant) != v.selectors {
+			t.Errorf("%s: got selectors of another variant", v.variant)
+		}
+
+		value := reflect.ValueOf(v.selectors).Elem()
+		for i := range value.NumField() {
+			name := value.Type().Field(i).Name
+			if name != "Marker" && value.Field(i).String() == "" {
+				t.Errorf("%s: missing selector %s", v.variant, name)
+			}
+		}
+	}
+
+	if SelectorsFor("unknown") != classicSelectors {
+		t.Error("expected unknown variants to fall back to the classic selectors")
+	}
+
+	if last := uiVariants[len(uiVariants)-1]; last.selectors.Marker != "" {
+		t.Errorf("expected the last variant (%s) to have no marker", last.variant)
+	}
+}
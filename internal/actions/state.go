@@ -0,0 +1,96 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actions
+
+import (
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/classify"
+	"github.com/devsheke/scrapollo/internal/tracing"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// observationTextLimit is the number of characters of a page's text kept in an observation.
+const observationTextLimit = 4000
+
+// observeScript returns the signals that the state of the current page is classified by (see
+// [classify.Observation]).
+const observeScript = `(rowSelector, textLimit) => {
+  const rows = document.querySelectorAll(rowSelector);
+  const results =
+    rows[0]?.closest('table, [role=table], [role=grid]') ??
+    document.querySelector('main, [role=main]') ??
+    document.body;
+
+  // the results are obstructed if the element on top of their centre is part of an overlay.
+  const rect = results.getBoundingClientRect();
+  const x = Math.min(rect.left + rect.width / 2, innerWidth - 1);
+  const y = Math.min(rect.top + Math.min(rect.height, innerHeight) / 2, innerHeight - 1);
+  const top = document.elementFromPoint(Math.max(x, 0), Math.max(y, 0));
+
+  let obstructed = false;
+  for (let el = top; el && el !== document.body && !results.contains(el) && !el.contains(results); el = el.parentElement) {
+    const position = getComputedStyle(el).position;
+    if (position === 'fixed' || position === 'sticky' || el.matches('[role=dialog], [role=alertdialog], [aria-modal=true]')) {
+      obstructed = true;
+      break;
+    }
+  }
+
+  return {
+    url: location.href,
+    title: document.title,
+    text: (document.body?.innerText ?? '').slice(0, textLimit),
+    rows: rows.length,
+    password: !!document.querySelector('input[type=password]'),
+    captcha: !!document.querySelector(
+      'iframe[src*="challenges.cloudflare.com"], iframe[src*="hcaptcha.com"], iframe[src*="recaptcha"], .cf-turnstile, [data-sitekey], #challenge-form',
+    ),
+    obstructed,
+  };
+}`
+
+// ObservePage is a page action which observes the current page for [classify.Classifier]s, e.g. after
+// an action failed on it, taking a screenshot of its viewport along with the signals its state is
+// classified by.
+func ObservePage(page *rod.Page, timeout time.Duration) (obs *classify.Observation, err error) {
+	page, span := startSpan(page, "ObservePage")
+	defer func() { tracing.End(span, err) }()
+
+	page = page.Timeout(timeout)
+
+	sel := selectors(page)
+	result, err := page.Eval(observeScript, sel.LeadRow, observationTextLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	obs = new(classify.Observation)
+	if err := result.Value.Unmarshal(obs); err != nil {
+		return nil, err
+	}
+
+	quality := 70
+	obs.Screenshot, err = page.Screenshot(false, &proto.PageCaptureScreenshot{
+		Format:  proto.PageCaptureScreenshotFormatJpeg,
+		Quality: &quality,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return obs, nil
+}
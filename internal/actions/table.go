@@ -0,0 +1,256 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actions
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/models"
+	"github.com/devsheke/scrapollo/internal/tracing"
+	"github.com/go-rod/rod"
+)
+
+//go:embed scripts/snapshot.js
+var snapshotScript string
+
+// snapshotScrapeScript runs the scrape script on the snapshot with the given ID.
+var snapshotScrapeScript = fmt.Sprintf(`(id, rowSelector, columnSelector, emailSelector) => {
+  const frame = document.getElementById(id);
+  if (frame === null) throw new Error('table snapshot not found');
+  return (%s)(rowSelector, columnSelector, emailSelector, false, frame.contentDocument);
+}`, strings.TrimSuffix(strings.TrimSpace(scrapeScript), ";"))
+
+// snapshotResultsHTMLScript returns the outer HTML of the results in the snapshot with the given ID.
+var snapshotResultsHTMLScript = fmt.Sprintf(`(id, rowSelector) => {
+  const frame = document.getElementById(id);
+  if (frame === null) throw new Error('table snapshot not found');
+  return (%s)(rowSelector, frame.contentDocument);
+}`, resultsHTMLScript)
+
+// snapshotDiscardScript removes the snapshot with the given ID.
+const snapshotDiscardScript = `(id) => document.getElementById(id)?.remove()`
+
+var snapshotID atomic.Int64
+
+// ErrorTableNotLoaded is returned when the rows of the leads table are still loading once the
+// timeout has passed.
+var ErrorTableNotLoaded = errors.New("the leads table didn't finish loading")
+
+// tableStateScript returns whether Apollo has flagged the leads table as loaded, by setting its
+// 'data-cy-loaded' attribute to 'true', and the number of rows in the table.
+const tableStateScript = `(rowSelector) => {
+  const table = document.querySelector('[data-cy-loaded]');
+  return {
+    flagged: table !== null,
+    loaded: table?.dataset.cyLoaded === 'true',
+    rows: document.querySelectorAll(rowSelector).length,
+  };
+}`
+
+// tableStablePolls is the number of consecutive polls for which the number of rows of a table
+// without a 'data-cy-loaded' flag must not change for it to be considered loaded.
+const tableStablePolls = 2
+
+// WaitTableLoaded waits for the rows of the current page's leads table to finish loading, so that
+// a partial table isn't scraped or saved while rows are still streaming in. The table is loaded once
+// Apollo flags it as such with its 'data-cy-loaded' attribute or, for tables without the flag, once
+// it has rows and their number stops changing. [ErrorTableNotLoaded] is returned if the table is
+// still loading once the timeout has passed.
+func WaitTableLoaded(page *rod.Page, timeout time.Duration) (err error) {
+	page, span := startSpan(page, "WaitTableLoaded")
+	defer func() { tracing.End(span, err) }()
+
+	var state struct {
+		Flagged, Loaded bool
+		Rows            int
+	}
+
+	sel := selectors(page)
+	rows, stable := -1, 0
+	for deadline := time.Now().Add(timeout); time.Now().Before(deadline); time.Sleep(150 * time.Millisecond) {
+		result, err := page.Timeout(timeout).Eval(tableStateScript, sel.LeadRow)
+		if err != nil {
+			return err
+		}
+
+		if err := result.Value.Unmarshal(&state); err != nil {
+			return err
+		}
+
+		if state.Flagged {
+			if state.Loaded {
+				return nil
+			}
+			continue
+		}
+
+		if state.Rows > 0 && state.Rows == rows {
+			if stable++; stable >= tableStablePolls {
+				return nil
+			}
+		} else {
+			stable = 0
+		}
+		rows = state.Rows
+	}
+
+	logger(page).Warn().Int("rows", state.Rows).Msg("leads table is still loading")
+	return ErrorTableNotLoaded
+}
+
+// pageHTMLScript returns the outer HTML of the leads table and of each of its rows.
+const pageHTMLScript = `(rowSelector) => {
+  const rows = [...document.querySelectorAll(rowSelector)];
+  const table = rows[0]?.closest('table, [role=table]') ?? rows[0]?.parentElement;
+  return { table: table?.outerHTML ?? '', rows: rows.map((row) => row.outerHTML) };
+}`
+
+// PageHTML is the rendered HTML of the leads table of a page.
+type PageHTML struct {
+	// Table is the outer HTML of the table, and Rows the outer HTML of each of its rows.
+	Table string   `json:"table"`
+	Rows  []string `json:"rows"`
+}
+
+// CapturePageHTML returns the rendered HTML of the leads table of the current page, e.g. for custom
+// parsers to extract leads from alongside [ScrapeLeads]. The table must already be loaded.
+func CapturePageHTML(page *rod.Page, timeout time.Duration) (html *PageHTML, err error) {
+	page, span := startSpan(page, "CapturePageHTML")
+	defer func() { tracing.End(span, err) }()
+
+	sel := selectors(page)
+	result, err := page.Timeout(timeout).Eval(pageHTMLScript, sel.LeadRow)
+	if err != nil {
+		return nil, err
+	}
+
+	html = new(PageHTML)
+	if err := result.Value.Unmarshal(html); err != nil {
+		return nil, err
+	}
+
+	return html, nil
+}
+
+// resultsHTMLScript returns the outer HTML of the leads table, or of the page's first table or main
+// content if its rows can't be found, e.g. as Apollo's markup no longer matches the selectors.
+const resultsHTMLScript = `(rowSelector, doc = document) => {
+  const row = doc.querySelector(rowSelector);
+  const results =
+    row?.closest('table, [role=table], [role=grid]') ??
+    doc.querySelector('table, [role=table], [role=grid]') ??
+    doc.querySelector('main, [role=main]') ??
+    doc.body;
+  return results.outerHTML;
+}`
+
+// CaptureResultsHTML returns the rendered HTML of the results of the current page, i.e. of the leads
+// table, even if its rows no longer match the selectors.
+func CaptureResultsHTML(page *rod.Page, timeout time.Duration) (html string, err error) {
+	page, span := startSpan(page, "CaptureResultsHTML")
+	defer func() { tracing.End(span, err) }()
+
+	sel := selectors(page)
+	result, err := page.Timeout(timeout).Eval(resultsHTMLScript, sel.LeadRow)
+	if err != nil {
+		return "", err
+	}
+
+	return result.Value.Str(), nil
+}
+
+// TableSnapshot is a copy of the leads table of a page, which can be scraped after the page has moved
+// on (e.g. to the next page of results).
+type TableSnapshot struct {
+	id   string
+	page *rod.Page
+}
+
+// CaptureTable waits for the leads table of the current page to load and takes a snapshot of it.
+// The snapshot is kept in the page until [*TableSnapshot.Discard] is called, so the page must not be
+// reloaded in the meantime.
+func CaptureTable(page *rod.Page, timeout time.Duration) (snapshot *TableSnapshot, err error) {
+	page, span := startSpan(page, "CaptureTable")
+	defer func() { tracing.End(span, err) }()
+
+	sel := selectors(page)
+	err = rod.Try(func() {
+		page.Timeout(timeout).MustElement(sel.LeadRow).MustWaitVisible()
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if err := WaitTableLoaded(page, timeout); err != nil {
+		return nil, err
+	}
+
+	snapshot = &TableSnapshot{id: fmt.Sprintf("scrapollo-snapshot-%d", snapshotID.Add(1)), page: page}
+	if _, err := page.Timeout(timeout).Eval(snapshotScript, snapshot.id); err != nil {
+		return nil, err
+	}
+
+	logger(page).Debug().Str("snapshot", snapshot.id).Msg("captured leads table")
+	return snapshot, nil
+}
+
+// Leads returns the leads in the snapshot's rows, along with a report of how well they were scraped.
+// Like [ListLeads], no emails are revealed, so only the emails of leads which have
+// already been saved are returned, but invalid leads are dropped like by [ScrapeLeads].
+func (s *TableSnapshot) Leads() (leads []*models.Lead, report *ScrapeReport, err error) {
+	page, span := startSpan(s.page, "ScrapeSnapshot")
+	defer func() { tracing.End(span, err) }()
+
+	sel := selectors(page)
+	result, err := page.Timeout(30*time.Second).
+		Eval(snapshotScrapeScript, s.id, sel.LeadRow, sel.LeadColumn, sel.LeadEmail)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	output := new(scrapeOutput)
+	if err := result.Value.Unmarshal(output); err != nil {
+		return nil, nil, err
+	}
+
+	leads, report = output.leads(logger(page), false)
+	return leads, report, nil
+}
+
+// ResultsHTML returns the rendered HTML of the results in the snapshot, like [CaptureResultsHTML].
+func (s *TableSnapshot) ResultsHTML() (html string, err error) {
+	page, span := startSpan(s.page, "CaptureSnapshotHTML")
+	defer func() { tracing.End(span, err) }()
+
+	sel := selectors(page)
+	result, err := page.Timeout(30*time.Second).Eval(snapshotResultsHTMLScript, s.id, sel.LeadRow)
+	if err != nil {
+		return "", err
+	}
+
+	return result.Value.Str(), nil
+}
+
+// Discard removes the snapshot from the page.
+func (s *TableSnapshot) Discard() error {
+	_, err := s.page.Timeout(30*time.Second).Eval(snapshotDiscardScript, s.id)
+	return err
+}
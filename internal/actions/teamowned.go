@@ -0,0 +1,97 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actions
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/parse"
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/input"
+)
+
+// teamOwnedPattern matches Apollo's notice that contacts weren't saved because they're already in
+// the contacts of another seat of the account's team, along with their number if it's given, e.g.
+// '3 contacts are already in your team's contacts'.
+var teamOwnedPattern = regexp.MustCompile(
+	`(?i)(?:(` + parse.CountPattern + `)\s+(?:\w+\s+){0,3}?)?(?:is|are|was|were)?\s*already in your team(?:'|’)?s contacts`,
+)
+
+// saveStateScript returns the text of the save confirmation and of the save dialog, for those which
+// are visible.
+const saveStateScript = `(confirmation, modal) => {
+  const text = (selector) => {
+    const el = document.querySelector(selector);
+    return el !== null && el.getClientRects().length > 0 ? el.innerText : null;
+  };
+  return { confirmation: text(confirmation), modal: text(modal) };
+}`
+
+// parseTeamOwned returns the number of the selected leads which weren't saved because they're
+// already owned by the account's team, according to the text of Apollo's save confirmation or
+// dialog. All of them are if the text doesn't say how many.
+func parseTeamOwned(text string, selected int) int {
+	match := teamOwnedPattern.FindStringSubmatch(text)
+	if match == nil {
+		return 0
+	}
+
+	if match[1] == "" {
+		return selected
+	}
+
+	n, err := parse.Count(match[1])
+	if err != nil {
+		return selected
+	}
+
+	return min(n, selected)
+}
+
+// waitForSave waits for Apollo to confirm that the selected leads were saved, returning the number
+// of them which were skipped because they're already owned by the account's team. If Apollo refuses
+// to save any of them for that reason, its dialog is dismissed.
+func waitForSave(page *rod.Page, selected int, timeout time.Duration) (teamOwned int, err error) {
+	var state struct {
+		Confirmation, Modal *string
+	}
+
+	sel := selectors(page)
+	page = page.Timeout(timeout)
+	for {
+		result, err := page.Eval(saveStateScript, sel.SaveConfirmation, sel.SaveModal)
+		if err != nil {
+			return 0, err
+		}
+
+		if err := result.Value.Unmarshal(&state); err != nil {
+			return 0, err
+		}
+
+		switch {
+		case state.Confirmation != nil:
+			return parseTeamOwned(*state.Confirmation, selected), nil
+
+		case state.Modal != nil && teamOwnedPattern.MatchString(*state.Modal):
+			teamOwned = parseTeamOwned(*state.Modal, selected)
+			logger(page).Debug().Str("notice", strings.TrimSpace(*state.Modal)).Msg("dismissing the save dialog")
+			return teamOwned, page.Keyboard.Type(input.Escape)
+		}
+
+		time.Sleep(150 * time.Millisecond)
+	}
+}
@@ -0,0 +1,37 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actions
+
+import "testing"
+
+func TestParseTeamOwned(t *testing.T) {
+	tests := []struct {
+		text     string
+		selected int
+		want     int
+	}{
+		{"Saved to list", 25, 0},
+		{"Saved to list. 3 contacts are already in your team's contacts", 25, 3},
+		{"Saved 22 contacts. 1,200 people are already in your team’s contacts", 25, 25},
+		{"This contact is already in your team's contacts", 1, 1},
+		{"These contacts are already in your teams contacts and weren't saved", 10, 10},
+	}
+
+	for _, tt := range tests {
+		if got := parseTeamOwned(tt.text, tt.selected); got != tt.want {
+			t.Errorf("%q: got %d team-owned leads; want %d", tt.text, got, tt.want)
+		}
+	}
+}
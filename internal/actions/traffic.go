@@ -0,0 +1,151 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actions
+
+import (
+	"context"
+	"sync"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/rs/zerolog/log"
+)
+
+// Traffic represents the number of bytes that a browser transferred over the network. Received
+// counts the encoded bodies and headers of responses, as reported by the browser, and Sent is
+// estimated from the URLs, headers and bodies of requests. Responses served from the browser's cache
+// and requests that were blocked aren't counted.
+type Traffic struct {
+	Received, Sent int64
+}
+
+// Total returns the number of bytes transferred in both directions.
+func (t Traffic) Total() int64 {
+	return t.Received + t.Sent
+}
+
+// Add returns the sum of both [Traffic]s.
+func (t Traffic) Add(other Traffic) Traffic {
+	return Traffic{Received: t.Received + other.Received, Sent: t.Sent + other.Sent}
+}
+
+// blockedByClient is the error of the requests failed by a [ResourceBlocker].
+const blockedByClient = "net::ERR_BLOCKED_BY_CLIENT"
+
+// TrafficMeter counts the bytes transferred by the pages of a browser from CDP's network events.
+type TrafficMeter struct {
+	mu      sync.Mutex
+	traffic Traffic
+
+	// pending are the estimated sizes of the requests which haven't finished loading yet, which are
+	// only counted once they have, as blocked requests are never sent.
+	pending map[proto.NetworkRequestID]int64
+}
+
+// Traffic returns the bytes transferred so far.
+func (m *TrafficMeter) Traffic() Traffic {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.traffic
+}
+
+// MeterTraffic counts the bytes transferred by the pages that the browser opens from now on. The
+// returned function stops counting them.
+func MeterTraffic(browser *rod.Browser) (meter *TrafficMeter, stop func()) {
+	meter = &TrafficMeter{pending: make(map[proto.NetworkRequestID]int64)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	wait := browser.Context(ctx).EachEvent(
+		func(e *proto.TargetAttachedToTarget) {
+			if e.TargetInfo == nil || e.TargetInfo.Type != proto.TargetTargetInfoTypePage {
+				return
+			}
+
+			// network events are only emitted by the pages which enabled them, and calling the
+			// browser from its event loop would block it.
+			go func() {
+				page := browser.PageFromSession(e.SessionID)
+				if err := (proto.NetworkEnable{}).Call(page); err != nil {
+					log.Debug().Err(err).Msg("failed to meter the traffic of a page")
+				}
+			}()
+		},
+		func(e *proto.NetworkRequestWillBeSent) {
+			meter.mu.Lock()
+			defer meter.mu.Unlock()
+
+			meter.pending[e.RequestID] = requestSize(e.Request)
+		},
+		func(e *proto.NetworkLoadingFinished) {
+			meter.mu.Lock()
+			defer meter.mu.Unlock()
+
+			meter.traffic.Received += int64(e.EncodedDataLength)
+			meter.traffic.Sent += meter.pending[e.RequestID]
+			delete(meter.pending, e.RequestID)
+		},
+		func(e *proto.NetworkLoadingFailed) {
+			meter.mu.Lock()
+			defer meter.mu.Unlock()
+
+			if e.BlockedReason == "" && e.ErrorText != blockedByClient {
+				meter.traffic.Sent += meter.pending[e.RequestID]
+			}
+			delete(meter.pending, e.RequestID)
+		},
+		func(e *proto.NetworkWebSocketFrameReceived) {
+			if e.Response == nil {
+				return
+			}
+
+			meter.mu.Lock()
+			defer meter.mu.Unlock()
+
+			meter.traffic.Received += int64(len(e.Response.PayloadData))
+		},
+		func(e *proto.NetworkWebSocketFrameSent) {
+			if e.Response == nil {
+				return
+			}
+
+			meter.mu.Lock()
+			defer meter.mu.Unlock()
+
+			meter.traffic.Sent += int64(len(e.Response.PayloadData))
+		},
+	)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		wait()
+	}()
+
+	return meter, func() {
+		cancel()
+		<-done
+	}
+}
+
+// requestSize estimates the number of bytes that sending the request takes.
+func requestSize(req *proto.NetworkRequest) int64 {
+	size := len(req.Method) + len(req.URL) + len(req.PostData)
+	for name, value := range req.Headers {
+		size += len(name) + len(value.String()) + 4
+	}
+
+	return int64(size)
+}
@@ -0,0 +1,150 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package actions
+
+import (
+	"math/rand/v2"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/tracing"
+	"github.com/go-rod/rod"
+)
+
+// warmUpPaths are the Apollo pages browsed while warming up an account.
+var warmUpPaths = []string{
+	"/#/home",
+	peoplePagePath,
+	"/#/companies",
+	"/#/sequences",
+	"/#/tasks",
+	creditsPagePath,
+}
+
+// contactLinks are the links to contacts' profiles in the rows of the 'People' page.
+const contactLinks string = `a[href*="#/people/"], a[href*="#/contacts/"]`
+
+// WarmUpOptions configures a warm-up session.
+type WarmUpOptions struct {
+	// Duration is how long the session lasts.
+	Duration time.Duration
+	// Contacts is the maximum number of contacts viewed during the session.
+	Contacts int
+	// Progress, if set, is called after every page that's browsed.
+	Progress func()
+}
+
+// WarmUp is a page action which warms up a newly added account by using Apollo like a person would:
+// browsing the dashboard's pages, viewing a handful of contacts and idling in between, until the
+// session's duration has passed. The page is expected to be logged in.
+func WarmUp(page *rod.Page, opts WarmUpOptions) (err error) {
+	page, span := startSpan(page, "WarmUp")
+	defer func() { tracing.End(span, err) }()
+
+	logger(page).Info().Dur("duration", opts.Duration).Msg("warming up account")
+
+	deadline := time.Now().Add(opts.Duration)
+	contacts := opts.Contacts
+
+	for time.Now().Before(deadline) {
+		if err := page.GetContext().Err(); err != nil {
+			return err
+		}
+
+		path := warmUpPaths[rand.IntN(len(warmUpPaths))]
+		logger(page).Debug().Str("path", path).Msg("browsing page")
+
+		err := rod.Try(func() {
+			page.Timeout(time.Minute).MustNavigate(apolloURL(page, path)).MustWaitDOMStable()
+		})
+		if err != nil {
+			return err
+		}
+
+		if opts.Progress != nil {
+			opts.Progress()
+		}
+
+		idle(page, until(deadline, 20*time.Second, 90*time.Second))
+
+		if path == peoplePagePath && contacts > 0 {
+			viewed, err := viewContacts(page, min(contacts, rand.IntN(3)+1), deadline)
+			if err != nil {
+				logger(page).Debug().Err(err).Msg("failed to view contacts")
+			}
+			contacts -= viewed
+		}
+	}
+
+	return nil
+}
+
+// viewContacts opens the profiles of up to n random contacts on the 'People' page, idling on each
+// of them, and returns the number of contacts viewed.
+func viewContacts(page *rod.Page, n int, deadline time.Time) (viewed int, err error) {
+	sel := selectors(page)
+
+	err = rod.Try(func() {
+		page.Timeout(30 * time.Second).MustElement(sel.LeadRow).MustWaitVisible()
+
+		for ; viewed < n && time.Now().Before(deadline); viewed++ {
+			links := page.MustElements(sel.LeadRow + " " + contactLinks)
+			if len(links) == 0 {
+				return
+			}
+
+			logger(page).Debug().Msg("viewing contact")
+			links[rand.IntN(len(links))].MustClick()
+			page.MustWaitDOMStable()
+			idle(page, until(deadline, 10*time.Second, 40*time.Second))
+
+			page.MustNavigateBack()
+			page.MustWaitDOMStable()
+		}
+	})
+
+	return
+}
+
+// until returns a random duration between lower and upper which doesn't go past the deadline.
+func until(deadline time.Time, lower, upper time.Duration) time.Duration {
+	d := lower + rand.N(upper-lower)
+	return max(min(d, time.Until(deadline)), 0)
+}
+
+// idle moves the mouse and scrolls the page at random intervals for the given duration, like a
+// person reading the page would.
+func idle(page *rod.Page, d time.Duration) {
+	ctx := page.GetContext()
+	deadline := time.Now().Add(d)
+
+	for time.Now().Before(deadline) {
+		_ = rod.Try(func() {
+			switch rand.IntN(3) {
+			case 0:
+				page.Mouse.MustMoveTo(float64(rand.IntN(1200)), float64(rand.IntN(700)))
+			case 1:
+				page.Mouse.MustScroll(0, float64(rand.IntN(600)-200))
+			default:
+				// read the page without moving.
+			}
+		})
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(until(deadline, time.Second, 6*time.Second)):
+		}
+	}
+}
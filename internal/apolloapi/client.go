@@ -0,0 +1,103 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package apolloapi is a client of Apollo's official REST API, an alternative to the browser for
+// accounts with API access. Its people search and enrichment endpoints return the same leads that
+// the 'People' page shows.
+package apolloapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultURL is the base URL of Apollo's REST API.
+const DefaultURL = "https://api.apollo.io/api/v1"
+
+// MaxPerPage is the largest page of people that the search endpoint returns.
+const MaxPerPage = 100
+
+// ErrorUnauthorized is returned when Apollo rejects the client's API key.
+var ErrorUnauthorized = errors.New("apollo api: invalid api key")
+
+// ErrorRateLimited is returned when the client has made too many requests, or has run out of
+// credits for the current period.
+var ErrorRateLimited = errors.New("apollo api: rate limited")
+
+// Client is a client of Apollo's REST API, authenticated with an account's API key.
+type Client struct {
+	key, url string
+	client   *http.Client
+}
+
+// NewClient returns a [*Client] of the API at the given base URL (see [DefaultURL]) that
+// authenticates with the given API key.
+func NewClient(url, key string) *Client {
+	if url == "" {
+		url = DefaultURL
+	}
+
+	return &Client{
+		key:    key,
+		url:    strings.TrimSuffix(url, "/"),
+		client: &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+func (c *Client) request(ctx context.Context, method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.url+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Cache-Control", "no-cache")
+	req.Header.Set("X-Api-Key", c.key)
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	switch {
+	case res.StatusCode == http.StatusUnauthorized, res.StatusCode == http.StatusForbidden:
+		return ErrorUnauthorized
+	case res.StatusCode == http.StatusTooManyRequests:
+		return ErrorRateLimited
+	case res.StatusCode >= 300:
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("apollo api: %s %s: %s: %s", method, path, res.Status, strings.TrimSpace(string(msg)))
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(res.Body).Decode(out)
+}
@@ -0,0 +1,86 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apolloapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// Label is a list of contacts, which the app calls a list and the API a label.
+type Label struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Labels returns the account's lists.
+func (c *Client) Labels(ctx context.Context) ([]*Label, error) {
+	var labels []*Label
+	if err := c.request(ctx, http.MethodGet, "/labels", nil, &labels); err != nil {
+		return nil, err
+	}
+
+	return labels, nil
+}
+
+// LabelID returns the ID of the account's list with the given name.
+func (c *Client) LabelID(ctx context.Context, name string) (string, error) {
+	labels, err := c.Labels(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	for _, label := range labels {
+		if label.Name == name {
+			return label.ID, nil
+		}
+	}
+
+	return "", fmt.Errorf("apollo api: no list named %q", name)
+}
+
+// ContactPage is a page of the contacts in one of the account's lists.
+type ContactPage struct {
+	Contacts   []*Person `json:"contacts"`
+	Pagination struct {
+		Page       int `json:"page"`
+		PerPage    int `json:"per_page"`
+		Total      int `json:"total_entries"`
+		TotalPages int `json:"total_pages"`
+	} `json:"pagination"`
+}
+
+// LastPage reports whether the page is the list's last.
+func (p *ContactPage) LastPage() bool {
+	return p.Pagination.Page >= p.Pagination.TotalPages
+}
+
+// SearchContacts returns the given page (counting from 1) of the contacts in the list with the given
+// ID. Contacts have been saved already, so their emails are returned without using any credits.
+func (c *Client) SearchContacts(ctx context.Context, labelID string, page, perPage int) (*ContactPage, error) {
+	body := map[string]any{
+		"contact_label_ids": []string{labelID},
+		"page":              page,
+		"per_page":          min(perPage, MaxPerPage),
+	}
+
+	res := new(ContactPage)
+	if err := c.request(ctx, http.MethodPost, "/contacts/search", body, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
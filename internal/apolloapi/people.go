@@ -0,0 +1,131 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apolloapi
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/devsheke/scrapollo/internal/models"
+)
+
+// Person is a person as returned by the API. Its email is only revealed by [Client.Enrich].
+type Person struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	Title        string `json:"title"`
+	Email        string `json:"email"`
+	LinkedInURL  string `json:"linkedin_url"`
+	City         string `json:"city"`
+	State        string `json:"state"`
+	Country      string `json:"country"`
+	Organization struct {
+		Name      string   `json:"name"`
+		Employees int      `json:"estimated_num_employees"`
+		Industry  string   `json:"industry"`
+		Keywords  []string `json:"keywords"`
+	} `json:"organization"`
+	PhoneNumbers []struct {
+		Number string `json:"sanitized_number"`
+	} `json:"phone_numbers"`
+}
+
+// Lead returns the person as a [*models.Lead], in the same shape as the leads scraped from the
+// 'People' page.
+func (p *Person) Lead() *models.Lead {
+	lead := &models.Lead{
+		Name:     p.Name,
+		Title:    p.Title,
+		Company:  p.Organization.Name,
+		Industry: p.Organization.Industry,
+		Keywords: strings.Join(p.Organization.Keywords, ","),
+		Links:    p.LinkedInURL,
+		Email:    p.Email,
+	}
+
+	var location []string
+	for _, part := range []string{p.City, p.State, p.Country} {
+		if part != "" {
+			location = append(location, part)
+		}
+	}
+	lead.Location = strings.Join(location, ", ")
+
+	if p.Organization.Employees > 0 {
+		lead.Employees = strconv.Itoa(p.Organization.Employees)
+	}
+
+	if len(p.PhoneNumbers) > 0 {
+		lead.Phone = p.PhoneNumbers[0].Number
+	}
+
+	lead.Normalize()
+	return lead
+}
+
+// SearchPage is a page of the people matching a search.
+type SearchPage struct {
+	People     []*Person `json:"people"`
+	Pagination struct {
+		Page       int `json:"page"`
+		PerPage    int `json:"per_page"`
+		Total      int `json:"total_entries"`
+		TotalPages int `json:"total_pages"`
+	} `json:"pagination"`
+}
+
+// LastPage reports whether the page is the search's last.
+func (p *SearchPage) LastPage() bool {
+	return p.Pagination.Page >= p.Pagination.TotalPages
+}
+
+// SearchPeople returns the given page (counting from 1) of the people matching the search, which
+// doesn't use any credits.
+func (c *Client) SearchPeople(ctx context.Context, search Search, page, perPage int) (*SearchPage, error) {
+	body := make(map[string]any, len(search)+2)
+	for key, value := range search {
+		body[key] = value
+	}
+	body["page"], body["per_page"] = page, min(perPage, MaxPerPage)
+
+	res := new(SearchPage)
+	if err := c.request(ctx, http.MethodPost, "/mixed_people/search", body, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Enrich returns the person with the given ID along with their email, which uses one of the
+// account's credits.
+func (c *Client) Enrich(ctx context.Context, id string) (*Person, error) {
+	var res struct {
+		Person *Person `json:"person"`
+	}
+
+	body := map[string]any{"id": id, "reveal_personal_emails": false}
+	if err := c.request(ctx, http.MethodPost, "/people/match", body, &res); err != nil {
+		return nil, err
+	}
+
+	if res.Person == nil {
+		return nil, fmt.Errorf("apollo api: no person with id %q", id)
+	}
+
+	return res.Person, nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apolloapi
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"unicode"
+)
+
+// Search holds the filters of a people search, keyed by the API's parameter names.
+type Search map[string]any
+
+// uiParams are the parameters of the app's search URLs which only affect how the search is shown.
+var uiParams = map[string]bool{
+	"page":         true,
+	"finderViewId": true,
+	"uniqueUrlId":  true,
+}
+
+// ParseSearchURL returns the filters of the search at the given URL of the 'People' page (e.g.
+// 'https://app.apollo.io/#/people?personTitles[]=ceo&personLocations[]=Germany'). The app names its
+// parameters like the API does, only in camel case, with lists suffixed by '[]'.
+func ParseSearchURL(s string) (Search, error) {
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, err
+	}
+
+	// the app keeps its search in the URL's fragment.
+	_, query, ok := strings.Cut(u.Fragment, "?")
+	if !ok {
+		query = u.RawQuery
+	}
+
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return nil, fmt.Errorf("invalid search url %q: %w", s, err)
+	}
+
+	search := make(Search, len(values))
+	for key, vals := range values {
+		name, list := strings.CutSuffix(key, "[]")
+		if uiParams[name] {
+			continue
+		}
+
+		if list {
+			search[snakeCase(name)] = vals
+		} else {
+			search[snakeCase(name)] = vals[0]
+		}
+	}
+
+	return search, nil
+}
+
+// snakeCase converts a camel case name to snake case, e.g. 'personTitles' to 'person_titles'.
+func snakeCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
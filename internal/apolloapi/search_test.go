@@ -0,0 +1,39 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apolloapi
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSearchURL(t *testing.T) {
+	search, err := ParseSearchURL(
+		"https://app.apollo.io/#/people?page=3&personTitles[]=ceo&personTitles[]=cto" +
+			"&personLocations[]=Germany&qKeywords=saas&finderViewId=5b6d",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Search{
+		"person_titles":    []string{"ceo", "cto"},
+		"person_locations": []string{"Germany"},
+		"q_keywords":       "saas",
+	}
+	if !reflect.DeepEqual(search, want) {
+		t.Errorf("got search %v, want %v", search, want)
+	}
+}
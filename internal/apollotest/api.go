@@ -0,0 +1,207 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apollotest
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// APIPath is the path of the mock's REST API, which is joined to the server's URL to get the API's
+// base URL.
+const APIPath = "/api/v1"
+
+// AddAPIKey adds a key which can authenticate requests to the mock's REST API.
+func (s *Server) AddAPIKey(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.apiKeys[key] = true
+}
+
+// apiAuthenticated wraps a handler of the REST API which requires a valid API key.
+func (s *Server) apiAuthenticated(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		ok := s.apiKeys[r.Header.Get("X-Api-Key")]
+		s.mu.Unlock()
+
+		if !ok {
+			http.Error(w, `{"error":"invalid api key"}`, http.StatusUnauthorized)
+			return
+		}
+
+		h(w, r)
+	}
+}
+
+// apiPerson is a lead as returned by the REST API, whose email is only revealed by enriching it.
+type apiPerson struct {
+	ID           string `json:"id"`
+	Name         string `json:"name"`
+	Title        string `json:"title"`
+	Email        string `json:"email,omitempty"`
+	LinkedInURL  string `json:"linkedin_url"`
+	City         string `json:"city"`
+	Country      string `json:"country"`
+	Organization struct {
+		Name      string   `json:"name"`
+		Employees int      `json:"estimated_num_employees"`
+		Industry  string   `json:"industry"`
+		Keywords  []string `json:"keywords"`
+	} `json:"organization"`
+}
+
+func newAPIPerson(lead *Lead, reveal bool) *apiPerson {
+	p := &apiPerson{
+		ID:          strconv.Itoa(lead.ID),
+		Name:        lead.Name,
+		Title:       lead.Title,
+		LinkedInURL: lead.Link,
+	}
+	p.City, p.Country, _ = strings.Cut(lead.Location, ", ")
+	p.Organization.Name = lead.Company
+	p.Organization.Industry = lead.Industry
+	p.Organization.Keywords = strings.Split(lead.Keywords, ",")
+
+	if reveal {
+		p.Email = lead.Email
+	}
+
+	return p
+}
+
+// handleAPISearch returns a page of all of the mock's leads, ignoring the search's filters.
+func (s *Server) handleAPISearch(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Page    int `json:"page"`
+		PerPage int `json:"per_page"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	req.Page, req.PerPage = max(req.Page, 1), max(req.PerPage, 1)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var res struct {
+		People     []*apiPerson `json:"people"`
+		Pagination struct {
+			Page       int `json:"page"`
+			PerPage    int `json:"per_page"`
+			Total      int `json:"total_entries"`
+			TotalPages int `json:"total_pages"`
+		} `json:"pagination"`
+	}
+
+	res.People = []*apiPerson{}
+	start := (req.Page - 1) * req.PerPage
+	for i := start; i < min(start+req.PerPage, len(s.leads)); i++ {
+		res.People = append(res.People, newAPIPerson(s.leads[i], false))
+	}
+
+	res.Pagination.Page, res.Pagination.PerPage = req.Page, req.PerPage
+	res.Pagination.Total = len(s.leads)
+	res.Pagination.TotalPages = (len(s.leads) + req.PerPage - 1) / req.PerPage
+
+	writeJSON(w, res)
+}
+
+// handleAPIMatch reveals the lead with the given ID, using one of the account's credits.
+func (s *Server) handleAPIMatch(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id, err := strconv.Atoi(req.ID)
+	if err != nil || id < 0 || id >= len(s.leads) {
+		writeJSON(w, struct {
+			Person *apiPerson `json:"person"`
+		}{})
+		return
+	}
+
+	s.creditsUsed++
+	writeJSON(w, struct {
+		Person *apiPerson `json:"person"`
+	}{newAPIPerson(s.leads[id], true)})
+}
+
+// handleAPILabels returns the mock's lists, whose IDs are their names.
+func (s *Server) handleAPILabels(w http.ResponseWriter, _ *http.Request) {
+	type label struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+
+	labels := []label{}
+	for _, name := range s.Lists() {
+		labels = append(labels, label{name, name})
+	}
+
+	writeJSON(w, labels)
+}
+
+// handleAPIContacts returns a page of the leads saved to the given list, with their emails.
+func (s *Server) handleAPIContacts(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Labels  []string `json:"contact_label_ids"`
+		Page    int      `json:"page"`
+		PerPage int      `json:"per_page"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Labels) != 1 {
+		http.Error(w, "expected a single list", http.StatusBadRequest)
+		return
+	}
+	req.Page, req.PerPage = max(req.Page, 1), max(req.PerPage, 1)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := s.lists[req.Labels[0]]
+
+	var res struct {
+		Contacts   []*apiPerson `json:"contacts"`
+		Pagination struct {
+			Page       int `json:"page"`
+			PerPage    int `json:"per_page"`
+			Total      int `json:"total_entries"`
+			TotalPages int `json:"total_pages"`
+		} `json:"pagination"`
+	}
+
+	res.Contacts = []*apiPerson{}
+	start := (req.Page - 1) * req.PerPage
+	for i := start; i < min(start+req.PerPage, len(ids)); i++ {
+		res.Contacts = append(res.Contacts, newAPIPerson(s.leads[ids[i]], true))
+	}
+
+	res.Pagination.Page, res.Pagination.PerPage = req.Page, req.PerPage
+	res.Pagination.Total = len(ids)
+	res.Pagination.TotalPages = (len(ids) + req.PerPage - 1) / req.PerPage
+
+	writeJSON(w, res)
+}
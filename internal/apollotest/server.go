@@ -0,0 +1,409 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package apollotest provides a mock of the Apollo app for integration tests. It serves the pages
+// that scrapollo's page actions use (login, 'People', the save dialog and credits) from a single
+// page app backed by in-memory accounts and leads, so that the runner can be exercised without
+// burning real accounts.
+package apollotest
+
+import (
+	"crypto/rand"
+	_ "embed"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// PerPage is the number of leads shown on each page of the 'People' page.
+const PerPage = 25
+
+// The cookies set by the mock when an account logs in, which are the ones scrapollo waits for.
+const (
+	deviceCookie   = "intercom-device-id-dyws6i9m"
+	sessionCookie  = "intercom-session-dyws6i9m"
+	rememberCookie = "remember_token_leadgenie_v2"
+)
+
+//go:embed app.html
+var app []byte
+
+// Lead is a lead shown on the mock's 'People' page.
+type Lead struct {
+	ID        int    `json:"id"`
+	Name      string `json:"name"`
+	Title     string `json:"title"`
+	Company   string `json:"company"`
+	Email     string `json:"email,omitempty"`
+	Phone     string `json:"phone,omitempty"`
+	Link      string `json:"link"`
+	Location  string `json:"location"`
+	Employees string `json:"employees"`
+	Industry  string `json:"industry"`
+	Keywords  string `json:"keywords"`
+}
+
+// Server is a mock of the Apollo app running on a local HTTP server.
+type Server struct {
+	*httptest.Server
+
+	mu          sync.Mutex
+	accounts    map[string]string
+	suspended   map[string]bool
+	sessions    map[string]string
+	leads       []*Lead
+	lists       map[string][]int
+	teamOwned   map[int]bool
+	apiKeys     map[string]bool
+	creditsUsed int
+	creditsMax  int
+}
+
+// NewServer starts a mock of the Apollo app with the given number of generated leads. Accounts have
+// to be added with [Server.AddAccount] before they can log in. The server should be closed once the
+// test is done.
+func NewServer(leads int) *Server {
+	s := &Server{
+		accounts:   make(map[string]string),
+		suspended:  make(map[string]bool),
+		sessions:   make(map[string]string),
+		lists:      make(map[string][]int),
+		teamOwned:  make(map[int]bool),
+		apiKeys:    make(map[string]bool),
+		creditsMax: 10000,
+	}
+
+	for i := range leads {
+		s.leads = append(s.leads, &Lead{
+			ID:        i,
+			Name:      fmt.Sprintf("Lead %d", i+1),
+			Title:     "Head of Sales",
+			Company:   fmt.Sprintf("Company %d", i+1),
+			Email:     fmt.Sprintf("lead%d@example.com", i+1),
+			Phone:     fmt.Sprintf("+1 555 %04d", i+1),
+			Link:      fmt.Sprintf("https://www.linkedin.com/in/lead-%d", i+1),
+			Location:  "Berlin, Germany",
+			Employees: "51-200",
+			Industry:  "Software",
+			Keywords:  "saas",
+		})
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", s.handleApp)
+	mux.HandleFunc("POST /api/login", s.handleLogin)
+	mux.HandleFunc("GET /api/people", s.authenticated(s.handlePeople))
+	mux.HandleFunc("GET /api/lists", s.authenticated(s.handleLists))
+	mux.HandleFunc("POST /api/save", s.authenticated(s.handleSave))
+	mux.HandleFunc("GET /api/credits", s.authenticated(s.handleCredits))
+	mux.HandleFunc("POST /api/v1/mixed_people/search", s.apiAuthenticated(s.handleAPISearch))
+	mux.HandleFunc("POST /api/v1/people/match", s.apiAuthenticated(s.handleAPIMatch))
+	mux.HandleFunc("GET /api/v1/labels", s.apiAuthenticated(s.handleAPILabels))
+	mux.HandleFunc("POST /api/v1/contacts/search", s.apiAuthenticated(s.handleAPIContacts))
+
+	s.Server = httptest.NewServer(mux)
+	return s
+}
+
+// AddAccount adds an account which can log in with the given credentials.
+func (s *Server) AddAccount(email, password string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.accounts[email] = password
+}
+
+// Suspend suspends the account with the given email. The app shows the suspension page to suspended
+// accounts instead of any of its pages.
+func (s *Server) Suspend(email string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.suspended[email] = true
+}
+
+// OwnByTeam marks the leads with the given IDs as already being in the contacts of another seat of
+// the accounts' team, so that saving them is refused.
+func (s *Server) OwnByTeam(ids ...int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, id := range ids {
+		s.teamOwned[id] = true
+	}
+}
+
+// AddList adds an empty list with the given name, which the save dialog offers to save leads to.
+func (s *Server) AddList(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.lists[name]; !ok {
+		s.lists[name] = []int{}
+	}
+}
+
+// SaveToList saves the leads with the given IDs to the list with the given name, as if they had been
+// saved in the app.
+func (s *Server) SaveToList(name string, ids ...int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.lists[name] = append(s.lists[name], ids...)
+}
+
+// Lists returns the names of the lists, sorted.
+func (s *Server) Lists() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Sorted(maps.Keys(s.lists))
+}
+
+// Saved returns the number of leads saved to the list with the given name.
+func (s *Server) Saved(list string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.lists[list])
+}
+
+// saved reports whether the lead has been saved to any list. The caller must hold the lock.
+func (s *Server) saved(id int) bool {
+	for _, ids := range s.lists {
+		if slices.Contains(ids, id) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (s *Server) handleApp(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Write(app)
+}
+
+func (s *Server) handleLogin(w http.ResponseWriter, r *http.Request) {
+	var creds struct{ Email, Password string }
+	if err := json.NewDecoder(r.Body).Decode(&creds); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if password, ok := s.accounts[creds.Email]; !ok || password != creds.Password {
+		http.Error(w, "invalid email or password", http.StatusUnauthorized)
+		return
+	}
+
+	token := newToken()
+	s.sessions[token] = creds.Email
+
+	expires := time.Now().Add(30 * 24 * time.Hour)
+	for name, value := range map[string]string{
+		deviceCookie:   newToken(),
+		sessionCookie:  token,
+		rememberCookie: token,
+	} {
+		http.SetCookie(w, &http.Cookie{Name: name, Value: value, Path: "/", Expires: expires})
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// authenticated wraps a handler which requires the request to come from a logged in account.
+func (s *Server) authenticated(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		cookie, err := r.Cookie(rememberCookie)
+		if err != nil {
+			http.Error(w, "not logged in", http.StatusUnauthorized)
+			return
+		}
+
+		s.mu.Lock()
+		email, ok := s.sessions[cookie.Value]
+		suspended := s.suspended[email]
+		s.mu.Unlock()
+
+		if !ok {
+			http.Error(w, "not logged in", http.StatusUnauthorized)
+			return
+		} else if suspended {
+			http.Error(w, "account suspended", http.StatusForbidden)
+			return
+		}
+
+		h(w, r)
+	}
+}
+
+// peopleLead is a lead as shown on the 'People' page, whose contact details are only revealed once
+// it has been saved.
+type peopleLead struct {
+	*Lead
+	Saved bool `json:"saved"`
+}
+
+// search returns the leads shown on the given tab of the 'People' page, or those of the given list
+// if it isn't empty. The caller must hold the lock.
+func (s *Server) search(tab, list string) []*Lead {
+	var leads []*Lead
+	if list != "" {
+		for _, id := range s.lists[list] {
+			leads = append(leads, s.leads[id])
+		}
+		return leads
+	}
+
+	for _, lead := range s.leads {
+		switch saved := s.saved(lead.ID); tab {
+		case "net-new":
+			if saved {
+				continue
+			}
+		case "saved":
+			if !saved {
+				continue
+			}
+		}
+
+		leads = append(leads, lead)
+	}
+
+	return leads
+}
+
+func (s *Server) handlePeople(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	page, _ := strconv.Atoi(query.Get("page"))
+	page = max(page, 1)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	leads := s.search(query.Get("tab"), query.Get("list"))
+
+	res := struct {
+		Total   int           `json:"total"`
+		PerPage int           `json:"perPage"`
+		Leads   []*peopleLead `json:"leads"`
+	}{Total: len(leads), PerPage: PerPage, Leads: []*peopleLead{}}
+
+	start := min((page-1)*PerPage, len(leads))
+	for _, lead := range leads[start:min(start+PerPage, len(leads))] {
+		shown := &peopleLead{Lead: lead, Saved: s.saved(lead.ID)}
+		if !shown.Saved {
+			hidden := *lead
+			hidden.Email, hidden.Phone = "", ""
+			shown.Lead = &hidden
+		}
+
+		res.Leads = append(res.Leads, shown)
+	}
+
+	writeJSON(w, res)
+}
+
+func (s *Server) handleLists(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, s.Lists())
+}
+
+func (s *Server) handleSave(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		List string `json:"list"`
+		IDs  []int  `json:"ids"`
+
+		// All is the search whose leads are all saved, when they have been selected in bulk.
+		All *struct {
+			Tab  string `json:"tab"`
+			List string `json:"list"`
+		} `json:"all"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if req.All != nil {
+		req.IDs = nil
+		for _, lead := range s.search(req.All.Tab, req.All.List) {
+			req.IDs = append(req.IDs, lead.ID)
+		}
+	}
+
+	// saving to a list that doesn't exist creates it, even if none of the leads end up in it.
+	if _, ok := s.lists[req.List]; !ok {
+		s.lists[req.List] = []int{}
+	}
+
+	var saved, teamOwned int
+	for _, id := range req.IDs {
+		if id < 0 || id >= len(s.leads) || slices.Contains(s.lists[req.List], id) {
+			continue
+		}
+
+		if s.teamOwned[id] {
+			teamOwned++
+			continue
+		}
+
+		if !s.saved(id) {
+			s.creditsUsed++
+		}
+
+		s.lists[req.List] = append(s.lists[req.List], id)
+		saved++
+	}
+
+	writeJSON(w, struct {
+		Saved     int `json:"saved"`
+		TeamOwned int `json:"teamOwned"`
+	}{saved, teamOwned})
+}
+
+func (s *Server) handleCredits(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	writeJSON(w, struct {
+		Used    int    `json:"used"`
+		Max     int    `json:"max"`
+		Renewal string `json:"renewal"`
+	}{s.creditsUsed, s.creditsMax, time.Now().AddDate(0, 1, 0).Format("Jan 02, 2006 3:04 PM")})
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func newToken() string {
+	var b [16]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,115 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package apollotest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/cookiejar"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestServer(t *testing.T) {
+	s := NewServer(30)
+	defer s.Close()
+	s.AddAccount("test@example.com", "password")
+
+	jar, _ := cookiejar.New(nil)
+	client := &http.Client{Jar: jar}
+
+	post := func(path, body string) *http.Response {
+		t.Helper()
+
+		res, err := client.Post(s.URL+path, "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		return res
+	}
+
+	type people struct {
+		Total int
+		Leads []struct {
+			ID    int
+			Email string
+			Saved bool
+		}
+	}
+
+	get := func(query string) people {
+		t.Helper()
+
+		res, err := client.Get(s.URL + "/api/people?" + query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer res.Body.Close()
+
+		var p people
+		if err := json.NewDecoder(res.Body).Decode(&p); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+
+	if res := post("/api/login", `{"email":"test@example.com","password":"wrong"}`); res.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("got status %d for a wrong password", res.StatusCode)
+	}
+
+	if res := post("/api/login", `{"email":"test@example.com","password":"password"}`); res.StatusCode != http.StatusNoContent {
+		t.Fatalf("got status %d for a valid login", res.StatusCode)
+	}
+
+	if p := get("tab=net-new&page=2"); p.Total != 30 || len(p.Leads) != 5 || p.Leads[0].Email != "" {
+		t.Fatalf("unexpected second page of net new leads: %+v", p)
+	}
+
+	post("/api/save", `{"list":"test","ids":[0,1,2]}`)
+	if saved := s.Saved("test"); saved != 3 {
+		t.Errorf("got %d saved leads, want 3", saved)
+	}
+
+	if p := get("tab=net-new"); p.Total != 27 {
+		t.Errorf("got %d net new leads after saving, want 27", p.Total)
+	}
+
+	if p := get("list=test"); p.Total != 3 || !p.Leads[0].Saved || p.Leads[0].Email != "lead1@example.com" {
+		t.Errorf("unexpected list: %+v", p)
+	}
+
+	post("/api/save", `{"list":"bulk","ids":[],"all":{"tab":"net-new","list":""}}`)
+	if saved := s.Saved("bulk"); saved != 27 {
+		t.Errorf("got %d leads saved in bulk, want 27", saved)
+	}
+
+	s.AddList("empty")
+	if lists := s.Lists(); !slices.Equal(lists, []string{"bulk", "empty", "test"}) {
+		t.Errorf("got lists %q", lists)
+	}
+
+	s.Suspend("test@example.com")
+	res, err := client.Get(s.URL + "/api/credits")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+
+	if res.StatusCode != http.StatusForbidden {
+		t.Errorf("got status %d for a suspended account", res.StatusCode)
+	}
+}
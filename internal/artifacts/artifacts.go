@@ -0,0 +1,253 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package artifacts collects the files that Apollo makes browsers download, such as CSV exports and
+// invoices, into the output directory and lists them in a manifest.
+package artifacts
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+	"github.com/rs/zerolog/log"
+)
+
+// manifestFile is the name of the file listing the artifacts in a directory.
+const manifestFile = "artifacts.json"
+
+// stagingDir is the directory, inside the collector's, which browsers download files to before
+// they're collected.
+const stagingDir = ".downloads"
+
+// Kinds of artifacts.
+const (
+	KindExport   = "export"
+	KindInvoice  = "invoice"
+	KindDownload = "download"
+)
+
+// Artifact describes a file downloaded by a job's browser.
+type Artifact struct {
+	Name    string    `json:"name"`
+	Kind    string    `json:"kind"`
+	Account string    `json:"account"`
+	JobID   string    `json:"job-id"`
+	URL     string    `json:"url"`
+	File    string    `json:"file"`
+	Size    int64     `json:"size"`
+	Time    time.Time `json:"time"`
+}
+
+// kind returns the kind of an artifact from its file name.
+func kind(name string) string {
+	name = strings.ToLower(name)
+	switch {
+	case strings.Contains(name, "invoice") || strings.Contains(name, "receipt"):
+		return KindInvoice
+	case strings.HasSuffix(name, ".csv") || strings.HasSuffix(name, ".xlsx"):
+		return KindExport
+	default:
+		return KindDownload
+	}
+}
+
+var unsafeChars = regexp.MustCompile(`[^A-Za-z0-9._@+-]+`)
+
+// safeName replaces the characters of a file name which aren't safe in paths.
+func safeName(name string) string {
+	name = unsafeChars.ReplaceAllString(filepath.Base(name), "_")
+	if name == "" || name == "." || name == ".." {
+		return "download"
+	}
+
+	return name
+}
+
+// loadManifest reads the manifest in the given directory. A missing manifest yields no artifacts.
+func loadManifest(dir string) ([]*Artifact, error) {
+	b, err := os.ReadFile(filepath.Join(dir, manifestFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var artifacts []*Artifact
+	return artifacts, json.Unmarshal(b, &artifacts)
+}
+
+// saveManifest atomically writes the manifest to the given directory.
+func saveManifest(dir string, artifacts []*Artifact) error {
+	b, err := json.MarshalIndent(artifacts, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(dir, manifestFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
+
+// Collector collects the files downloaded by browsers into a directory, in a subdirectory per
+// account.
+type Collector struct {
+	dir       string
+	mu        sync.Mutex
+	artifacts []*Artifact
+}
+
+// NewCollector returns a [*Collector] that collects files into the given directory, which is
+// created once the first file is downloaded. Artifacts already listed in the directory's manifest
+// are kept.
+func NewCollector(dir string) (*Collector, error) {
+	artifacts, err := loadManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Collector{dir: dir, artifacts: artifacts}, nil
+}
+
+// Artifacts returns the artifacts collected so far, including those of previous runs.
+func (c *Collector) Artifacts() []*Artifact {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]*Artifact(nil), c.artifacts...)
+}
+
+// Watch makes the browser download files to a directory of its own, and collects each file once it
+// has been downloaded. The returned function stops watching the browser and removes its directory,
+// along with the files still being downloaded.
+func (c *Collector) Watch(browser *rod.Browser, account, jobID string) (stop func(), err error) {
+	staging, err := filepath.Abs(filepath.Join(c.dir, stagingDir, safeName(jobID)))
+	if err != nil {
+		return nil, err
+	}
+
+	err = proto.BrowserSetDownloadBehavior{
+		Behavior:      proto.BrowserSetDownloadBehaviorBehaviorAllowAndName,
+		DownloadPath:  staging,
+		EventsEnabled: true,
+	}.Call(browser)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	logger := log.With().Str("account", account).Logger()
+
+	downloads := make(map[string]*proto.BrowserDownloadWillBegin)
+	wait := browser.Context(ctx).EachEvent(
+		func(e *proto.BrowserDownloadWillBegin) {
+			logger.Debug().Str("file", e.SuggestedFilename).Msg("downloading a file")
+			downloads[e.GUID] = e
+		},
+		func(e *proto.BrowserDownloadProgress) {
+			begin, ok := downloads[e.GUID]
+			if !ok {
+				return
+			}
+
+			switch e.State {
+			case proto.BrowserDownloadProgressStateCompleted:
+				delete(downloads, e.GUID)
+
+				a, err := c.collect(filepath.Join(staging, e.GUID), begin, account, jobID)
+				if err != nil {
+					logger.Error().Err(err).Str("file", begin.SuggestedFilename).Msg("failed to collect a download")
+					return
+				}
+				logger.Info().Str("file", a.File).Str("kind", a.Kind).Msg("collected a download")
+
+			case proto.BrowserDownloadProgressStateCanceled:
+				delete(downloads, e.GUID)
+				logger.Warn().Str("file", begin.SuggestedFilename).Msg("a download was canceled")
+			}
+		},
+	)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		wait()
+	}()
+
+	return func() {
+		cancel()
+		<-done
+
+		_ = os.RemoveAll(staging)
+		// the staging directory's parent is left behind if other browsers are still using it.
+		_ = os.Remove(filepath.Dir(staging))
+	}, nil
+}
+
+// collect moves a downloaded file into the account's directory and adds it to the manifest.
+func (c *Collector) collect(
+	path string,
+	begin *proto.BrowserDownloadWillBegin,
+	account, jobID string,
+) (*Artifact, error) {
+	now := time.Now()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+
+	name := safeName(begin.SuggestedFilename)
+	a := &Artifact{
+		Name:    begin.SuggestedFilename,
+		Kind:    kind(name),
+		Account: account,
+		JobID:   jobID,
+		URL:     begin.URL,
+		File:    filepath.Join(safeName(account), now.Format("20060102-150405")+"-"+name),
+		Size:    info.Size(),
+		Time:    now,
+	}
+
+	// files downloaded within the same second under the same name are told apart by their GUIDs.
+	dest := filepath.Join(c.dir, a.File)
+	if _, err := os.Stat(dest); err == nil {
+		a.File = filepath.Join(safeName(account), now.Format("20060102-150405")+"-"+safeName(begin.GUID)+"-"+name)
+		dest = filepath.Join(c.dir, a.File)
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0o755); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(path, dest); err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.artifacts = append(c.artifacts, a)
+	return a, saveManifest(c.dir, c.artifacts)
+}
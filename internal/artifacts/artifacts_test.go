@@ -0,0 +1,89 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package artifacts
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-rod/rod/lib/proto"
+)
+
+func TestKind(t *testing.T) {
+	for name, want := range map[string]string{
+		"apollo-contacts-export.csv": KindExport,
+		"Invoice-2025-01.pdf":        KindInvoice,
+		"receipt.csv":                KindInvoice,
+		"logo.png":                   KindDownload,
+	} {
+		if got := kind(name); got != want {
+			t.Errorf("kind(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestCollect(t *testing.T) {
+	dir := t.TempDir()
+	c, err := NewCollector(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	begin := &proto.BrowserDownloadWillBegin{
+		GUID:              "0b1c2d3e",
+		URL:               "https://app.apollo.io/export",
+		SuggestedFilename: "../export.csv",
+	}
+
+	var files []string
+	for range 2 {
+		path := filepath.Join(dir, stagingDir, begin.GUID)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("name,email\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		a, err := c.collect(path, begin, "a@example.com", "job")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a.Kind != KindExport || a.Size != 11 {
+			t.Errorf("collect() = %+v", a)
+		}
+		if filepath.Dir(a.File) != "a@example.com" {
+			t.Errorf("collected to %q, outside of the account's directory", a.File)
+		}
+		if _, err := os.Stat(filepath.Join(dir, a.File)); err != nil {
+			t.Error(err)
+		}
+		files = append(files, a.File)
+	}
+
+	if files[0] == files[1] {
+		t.Errorf("both downloads were collected to %q", files[0])
+	}
+
+	// the manifest carries the artifacts over to the next run.
+	c, err = NewCollector(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(c.Artifacts()); got != 2 {
+		t.Errorf("got %d artifacts from the manifest, want 2", got)
+	}
+}
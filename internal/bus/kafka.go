@@ -0,0 +1,107 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bus
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl/plain"
+)
+
+// kafkaTimeout is the time allowed for the Kafka brokers, or the Kafka REST Proxy, to accept a batch
+// of messages.
+const kafkaTimeout = 30 * time.Second
+
+// Kafka is a [Publisher] to a Kafka topic, which produces to the topic's brokers directly.
+type Kafka struct {
+	writer *kafka.Writer
+}
+
+// NewKafka returns a [*Kafka] for the topic at the given URL, which lists the bootstrap brokers
+// (separated by commas) and names the topic as its path, e.g. 'kafka://broker1:9092,broker2:9092/leads'.
+// The 'kafka+tls' scheme connects over TLS, and credentials in the URL are sent with SASL/PLAIN.
+//
+// Messages are partitioned by the hash of their key, and are acknowledged by all in-sync replicas.
+func NewKafka(u *url.URL) (*Kafka, error) {
+	topic := strings.Trim(u.Path, "/")
+	if topic == "" || strings.Contains(topic, "/") {
+		return nil, fmt.Errorf("kafka url %q doesn't name a topic", u.Redacted())
+	}
+
+	var brokers []string
+	for _, broker := range strings.Split(u.Host, ",") {
+		if broker == "" {
+			continue
+		}
+		if _, _, err := net.SplitHostPort(broker); err != nil {
+			broker = net.JoinHostPort(broker, "9092")
+		}
+		brokers = append(brokers, broker)
+	}
+	if len(brokers) == 0 {
+		return nil, fmt.Errorf("kafka url %q doesn't list any brokers", u.Redacted())
+	}
+
+	transport := &kafka.Transport{ClientID: "scrapollo", DialTimeout: kafkaTimeout}
+	if u.Scheme == "kafka+tls" {
+		transport.TLS = &tls.Config{}
+	}
+	if u.User != nil {
+		pass, _ := u.User.Password()
+		transport.SASL = plain.Mechanism{Username: u.User.Username(), Password: pass}
+	}
+
+	return &Kafka{writer: &kafka.Writer{
+		Addr:         kafka.TCP(brokers...),
+		Topic:        topic,
+		Balancer:     &kafka.Hash{},
+		RequiredAcks: kafka.RequireAll,
+		BatchTimeout: 10 * time.Millisecond,
+		WriteTimeout: kafkaTimeout,
+		Transport:    transport,
+	}}, nil
+}
+
+// Publish produces a record for each message, and waits for the brokers to acknowledge them.
+func (k *Kafka) Publish(msgs []Message) error {
+	records := make([]kafka.Message, 0, len(msgs))
+	for _, msg := range msgs {
+		record := kafka.Message{Value: msg.Value}
+		if msg.Key != "" {
+			record.Key = []byte(msg.Key)
+		}
+		for _, h := range msg.Headers {
+			record.Headers = append(record.Headers, kafka.Header{Key: h.Name, Value: []byte(h.Value)})
+		}
+		records = append(records, record)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), kafkaTimeout)
+	defer cancel()
+
+	return k.writer.WriteMessages(ctx, records...)
+}
+
+// Close flushes pending records and closes the connections to the brokers.
+func (k *Kafka) Close() error {
+	return k.writer.Close()
+}
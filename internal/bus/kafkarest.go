@@ -0,0 +1,140 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bus
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// KafkaREST is a [Publisher] to a Kafka topic through the v3 API of the Kafka REST Proxy, for
+// clusters that are only reachable over HTTP.
+type KafkaREST struct {
+	url    string
+	user   *url.Userinfo
+	client *http.Client
+}
+
+// NewKafkaREST returns a [*KafkaREST] for the topic at the given URL of the proxy, e.g.
+// 'kafka-rest+https://proxy:8082/v3/clusters/lkc-1234/topics/leads'. Credentials in the URL are sent
+// with basic authentication.
+func NewKafkaREST(u *url.URL) (*KafkaREST, error) {
+	if !strings.Contains(u.Path, "/topics/") {
+		return nil, fmt.Errorf("kafka rest proxy url %q doesn't name a topic", u.Redacted())
+	}
+
+	endpoint := *u
+	endpoint.Scheme = strings.TrimPrefix(u.Scheme, "kafka-rest+")
+	endpoint.User = nil
+	endpoint.Path = strings.TrimSuffix(u.Path, "/") + "/records"
+
+	return &KafkaREST{
+		url:    endpoint.String(),
+		user:   u.User,
+		client: &http.Client{Timeout: kafkaTimeout},
+	}, nil
+}
+
+// kafkaData is a key or value of a record produced through the REST Proxy.
+type kafkaData struct {
+	Type string `json:"type"`
+	Data any    `json:"data"`
+}
+
+// kafkaHeader is a header of a record produced through the REST Proxy, whose value is base64
+// encoded.
+type kafkaHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// Publish produces a record for each message, whose value is the message's JSON.
+func (k *KafkaREST) Publish(msgs []Message) error {
+	for _, msg := range msgs {
+		record := struct {
+			Key     *kafkaData    `json:"key,omitempty"`
+			Value   kafkaData     `json:"value"`
+			Headers []kafkaHeader `json:"headers,omitempty"`
+		}{
+			Value: kafkaData{Type: "JSON", Data: json.RawMessage(msg.Value)},
+		}
+
+		if msg.Key != "" {
+			record.Key = &kafkaData{Type: "STRING", Data: msg.Key}
+		}
+
+		for _, h := range msg.Headers {
+			record.Headers = append(record.Headers, kafkaHeader{
+				Name:  h.Name,
+				Value: base64.StdEncoding.EncodeToString([]byte(h.Value)),
+			})
+		}
+
+		if err := k.produce(record); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (k *KafkaREST) produce(record any) error {
+	body, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, k.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if k.user != nil {
+		pass, _ := k.user.Password()
+		req.SetBasicAuth(k.user.Username(), pass)
+	}
+
+	res, err := k.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	var result struct {
+		ErrorCode int    `json:"error_code"`
+		Message   string `json:"message"`
+	}
+	msg, _ := io.ReadAll(io.LimitReader(res.Body, 4096))
+	_ = json.Unmarshal(msg, &result)
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("kafka rest proxy responded with status %s: %s", res.Status, strings.TrimSpace(string(msg)))
+	} else if result.ErrorCode != 0 && result.ErrorCode != http.StatusOK {
+		return fmt.Errorf("kafka rest proxy failed to produce record: %d %s", result.ErrorCode, result.Message)
+	}
+
+	return nil
+}
+
+// Close does nothing, since records are produced with plain HTTP requests.
+func (k *KafkaREST) Close() error {
+	return nil
+}
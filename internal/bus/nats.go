@@ -0,0 +1,87 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bus
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// natsTimeout is the time allowed for the NATS server to respond, and to accept a batch of messages.
+const natsTimeout = 10 * time.Second
+
+// natsKeyHeader is the header that carries the key of messages published to NATS, which has no keys
+// of its own.
+const natsKeyHeader = "Key"
+
+// NATS is a [Publisher] to a subject of a NATS server.
+type NATS struct {
+	conn    *nats.Conn
+	subject string
+}
+
+// DialNATS connects to the NATS server at the given URL, whose path is the subject to publish to.
+// Credentials in the URL, i.e. 'user:pass@' or 'token@', are used to authenticate.
+func DialNATS(u *url.URL) (*NATS, error) {
+	subject := strings.Trim(u.Path, "/")
+	if subject == "" || strings.ContainsAny(subject, " \t*>") {
+		return nil, fmt.Errorf("invalid nats subject %q", subject)
+	}
+
+	server := *u
+	server.Path, server.RawPath, server.RawQuery, server.Fragment = "", "", "", ""
+
+	conn, err := nats.Connect(server.String(), nats.Name("scrapollo"), nats.Timeout(natsTimeout))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to nats server %q: %w", u.Redacted(), err)
+	}
+
+	return &NATS{conn: conn, subject: subject}, nil
+}
+
+// Publish publishes the messages to the subject, with their key in the 'Key' header, and waits for
+// the server to process them. Headers are dropped if the server doesn't support them.
+func (n *NATS) Publish(msgs []Message) error {
+	headers := n.conn.HeadersSupported()
+	for _, msg := range msgs {
+		m := nats.NewMsg(n.subject)
+		m.Data = msg.Value
+
+		if headers {
+			if msg.Key != "" {
+				m.Header.Set(natsKeyHeader, msg.Key)
+			}
+			for _, h := range msg.Headers {
+				m.Header.Add(h.Name, h.Value)
+			}
+		}
+
+		if err := n.conn.PublishMsg(m); err != nil {
+			return err
+		}
+	}
+
+	return n.conn.FlushTimeout(natsTimeout)
+}
+
+// Close closes the connection to the server.
+func (n *NATS) Close() error {
+	n.conn.Close()
+	return nil
+}
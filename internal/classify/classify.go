@@ -0,0 +1,122 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package classify classifies the state of the page that a browser action failed on, e.g. a captcha or
+// a maintenance page, so that the runner can recover from the failure in the right way instead of
+// merely retrying.
+package classify
+
+import (
+	"context"
+	"errors"
+	"regexp"
+)
+
+// State represents the state of a page that an action failed on.
+type State string
+
+// The states that pages are classified as.
+const (
+	StateUnknown       State = "unknown"
+	StateCaptcha       State = "captcha"
+	StateLoginPage     State = "login-page"
+	StateEmptyResults  State = "empty-results"
+	StateMaintenance   State = "maintenance"
+	StateBannerOverlap State = "banner-overlap"
+)
+
+// States are the states that a page can be classified as, besides [StateUnknown].
+var States = []State{StateCaptcha, StateLoginPage, StateEmptyResults, StateMaintenance, StateBannerOverlap}
+
+// Observation represents what a page looked like when an action failed on it.
+type Observation struct {
+	URL   string `json:"url"`
+	Title string `json:"title"`
+
+	// Text is the visible text of the page, which may be truncated.
+	Text string `json:"text"`
+
+	// Rows is the number of rows of leads found on the page.
+	Rows int `json:"rows"`
+
+	// Password and Captcha report whether the page has a password field and a captcha widget.
+	Password bool `json:"password"`
+	Captcha  bool `json:"captcha"`
+
+	// Obstructed reports whether the page's results (or its main content) are covered by an overlay,
+	// e.g. a banner or a dialog.
+	Obstructed bool `json:"obstructed"`
+
+	// Screenshot is a JPEG screenshot of the page's viewport.
+	Screenshot []byte `json:"screenshot,omitempty"`
+}
+
+// Classifier is an interface for classifying the state of pages.
+type Classifier interface {
+	// Classify returns the state of the page that the observation was made of, or [StateUnknown] if
+	// it isn't recognized.
+	Classify(ctx context.Context, obs *Observation) (State, error)
+}
+
+// chain is a [Classifier] made of classifiers that are asked in turn.
+type chain []Classifier
+
+// Chain returns a [Classifier] that asks each of the classifiers in turn until one of them recognizes
+// the page's state. Classifiers that fail are skipped, and their errors are only returned if none of
+// the classifiers recognize the state.
+func Chain(classifiers ...Classifier) Classifier {
+	return chain(classifiers)
+}
+
+func (c chain) Classify(ctx context.Context, obs *Observation) (State, error) {
+	var errs []error
+	for _, classifier := range c {
+		state, err := classifier.Classify(ctx, obs)
+		if err != nil {
+			errs = append(errs, err)
+		} else if state != StateUnknown {
+			return state, nil
+		}
+	}
+
+	return StateUnknown, errors.Join(errs...)
+}
+
+var (
+	captchaText     = regexp.MustCompile(`(?i)verify (that )?you are (a )?human|checking (if the site connection is secure|your browser)|are you a robot|complete the security check`)
+	loginURL        = regexp.MustCompile(`(?i)[/#](login|sign-?in)\b`)
+	maintenanceText = regexp.MustCompile(`(?i)(under|scheduled|down for) maintenance|temporarily unavailable|service unavailable|bad gateway|gateway time-?out|(be|are) back (soon|shortly)`)
+	emptyText       = regexp.MustCompile(`(?i)no (people|contacts|results|records|leads) (match|matched|found)|no results found|didn't find any (people|results)|0 results`)
+)
+
+// Heuristics is a [Classifier] that classifies pages by their text and layout. It's cheap enough to
+// run on every failure, but only recognizes the pages it was written for.
+type Heuristics struct{}
+
+func (Heuristics) Classify(_ context.Context, obs *Observation) (State, error) {
+	switch {
+	case obs.Captcha || captchaText.MatchString(obs.Text) || captchaText.MatchString(obs.Title):
+		return StateCaptcha, nil
+	case obs.Password || loginURL.MatchString(obs.URL):
+		return StateLoginPage, nil
+	case maintenanceText.MatchString(obs.Text) || maintenanceText.MatchString(obs.Title):
+		return StateMaintenance, nil
+	case obs.Obstructed:
+		return StateBannerOverlap, nil
+	case obs.Rows == 0 && emptyText.MatchString(obs.Text):
+		return StateEmptyResults, nil
+	default:
+		return StateUnknown, nil
+	}
+}
@@ -0,0 +1,108 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package classify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHeuristics(t *testing.T) {
+	tests := []struct {
+		name string
+		obs  Observation
+		want State
+	}{
+		{name: "turnstile", obs: Observation{Captcha: true, Rows: 25}, want: StateCaptcha},
+		{name: "challenge page", obs: Observation{Title: "Just a moment...", Text: "Verify you are human by completing the action below."}, want: StateCaptcha},
+		{name: "password field", obs: Observation{URL: "https://app.apollo.io/", Password: true}, want: StateLoginPage},
+		{name: "login route", obs: Observation{URL: "https://app.apollo.io/#/login?redirectTo=people"}, want: StateLoginPage},
+		{name: "maintenance", obs: Observation{Text: "Apollo is down for maintenance. We'll be back soon!"}, want: StateMaintenance},
+		{name: "bad gateway", obs: Observation{Title: "502 Bad Gateway"}, want: StateMaintenance},
+		{name: "overlay", obs: Observation{Rows: 25, Obstructed: true}, want: StateBannerOverlap},
+		{name: "no results", obs: Observation{Text: "No people match your criteria"}, want: StateEmptyResults},
+		{name: "no results text with rows", obs: Observation{Rows: 25, Text: "No people match your criteria"}, want: StateUnknown},
+		{name: "results", obs: Observation{URL: "https://app.apollo.io/#/people", Rows: 25, Text: "1 - 25 of 1,234"}, want: StateUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Heuristics{}.Classify(context.Background(), &tt.obs)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Classify() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEndpoint(t *testing.T) {
+	var reply State
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			URL        string  `json:"url"`
+			Screenshot []byte  `json:"screenshot"`
+			States     []State `json:"states"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		if req.URL != "https://app.apollo.io/#/people" || string(req.Screenshot) != "jpeg" || len(req.States) != len(States) {
+			t.Errorf("got request %+v", req)
+		}
+
+		if reply == "" {
+			http.Error(w, "model unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(EndpointResponse{State: reply})
+	}))
+	defer server.Close()
+
+	obs := &Observation{URL: "https://app.apollo.io/#/people", Obstructed: true, Screenshot: []byte("jpeg")}
+	classifier := Chain(NewEndpoint(server.URL), Heuristics{})
+
+	tests := []struct {
+		reply, want State
+		err         bool
+	}{
+		{reply: StateMaintenance, want: StateMaintenance},
+		{reply: "cat", want: StateBannerOverlap},
+		{reply: "", want: StateBannerOverlap},
+	}
+
+	for _, tt := range tests {
+		reply = tt.reply
+		got, err := classifier.Classify(context.Background(), obs)
+		if err != nil {
+			t.Fatalf("reply %q: %v", tt.reply, err)
+		}
+		if got != tt.want {
+			t.Errorf("reply %q: Classify() = %q, want %q", tt.reply, got, tt.want)
+		}
+	}
+
+	// the endpoint's error is only returned when nothing recognizes the page.
+	reply = ""
+	obs.Obstructed = false
+	if got, err := classifier.Classify(context.Background(), obs); got != StateUnknown || err == nil {
+		t.Errorf("Classify() = %q, %v, want %q and an error", got, err, StateUnknown)
+	}
+}
@@ -0,0 +1,90 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package classify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+// endpointTimeout is the time allowed for a model endpoint to classify a page.
+const endpointTimeout = 30 * time.Second
+
+// EndpointRequest is the body POSTed to a model endpoint, i.e. the [Observation] of the page to
+// classify (including its base64 encoded screenshot) along with the states it may be classified as.
+type EndpointRequest struct {
+	*Observation
+	States []State `json:"states"`
+}
+
+// EndpointResponse is the body expected back from a model endpoint.
+type EndpointResponse struct {
+	State State `json:"state"`
+}
+
+// Endpoint is a [Classifier] that has pages classified by a model served over HTTP, e.g. an image
+// classifier of screenshots (see [EndpointRequest] and [EndpointResponse]).
+type Endpoint struct {
+	url    string
+	client *http.Client
+}
+
+// NewEndpoint returns an [*Endpoint] which POSTs the pages to classify to the URL.
+func NewEndpoint(url string) *Endpoint {
+	return &Endpoint{url: url, client: &http.Client{Timeout: endpointTimeout}}
+}
+
+func (e *Endpoint) Classify(ctx context.Context, obs *Observation) (State, error) {
+	body, err := json.Marshal(EndpointRequest{Observation: obs, States: States})
+	if err != nil {
+		return StateUnknown, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return StateUnknown, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := e.client.Do(req)
+	if err != nil {
+		return StateUnknown, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 4096))
+		return StateUnknown, fmt.Errorf("classifier responded with status %s: %s", res.Status, strings.TrimSpace(string(msg)))
+	}
+
+	var response EndpointResponse
+	if err := json.NewDecoder(res.Body).Decode(&response); err != nil {
+		return StateUnknown, fmt.Errorf("invalid classifier response: %w", err)
+	}
+
+	// states the runner doesn't know how to recover from are as good as unknown.
+	if !slices.Contains(States, response.State) {
+		return StateUnknown, nil
+	}
+
+	return response.State, nil
+}
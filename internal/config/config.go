@@ -0,0 +1,109 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/devsheke/scrapollo/internal/io"
+)
+
+// Config represents the contents of a scrapollo configuration file. Score, if set, is the
+// expression computing the score of every lead. ApolloURL, if set, replaces the URL of the Apollo
+// app (e.g. for a mirrored domain) and Regions names the URLs of the apps that accounts may refer
+// to in their 'base-url' column, e.g. 'eu' for EU-hosted tenants. APIURL, if set, replaces the URL
+// of Apollo's REST API, which the accounts with an 'api-key' are scraped through.
+type Config struct {
+	Timeouts  Timeouts          `json:"timeouts"`
+	Credits   Credits           `json:"credits"`
+	Tags      []TagRule         `json:"tags"`
+	Score     string            `json:"score"`
+	Campaigns []Campaign        `json:"campaigns"`
+	Costs     *Costs            `json:"costs"`
+	ApolloURL string            `json:"apollo-url"`
+	Regions   map[string]string `json:"regions"`
+	APIURL    string            `json:"api-url"`
+}
+
+// Campaign represents a group of accounts (those whose 'campaign' column is Name) which is run with
+// outputs and limits of its own. Unset values fall back to those given on the command line, except
+// for the caps, which replace the command line's for the campaign's accounts if any of them is set.
+type Campaign struct {
+	Name                string `json:"name"`
+	OutputDir           string `json:"output-dir"`
+	OutputTemplate      string `json:"output-template"`
+	DailyLimit          int    `json:"daily-limit"`
+	MaxSavesPerHour     int    `json:"max-saves-per-hour"`
+	MaxConcurrentLogins int    `json:"max-concurrent-logins"`
+	LimitsFile          string `json:"limits-file"`
+	Costs               *Costs `json:"costs"`
+}
+
+// Costs represents the prices that the cost of a run is estimated with: that of one credit and of an
+// hour connected to a VPN, in Currency.
+type Costs struct {
+	Credit   float64 `json:"credit"`
+	VpnHour  float64 `json:"vpn-hour"`
+	Currency string  `json:"currency"`
+}
+
+// TagRule represents a rule adding Tag to the leads for which any of Fields (by their CSV names,
+// e.g. 'title', 'industry' or 'keywords') matches Pattern, a regular expression in Go's syntax.
+type TagRule struct {
+	Tag     string   `json:"tag"`
+	Pattern string   `json:"pattern"`
+	Fields  []string `json:"fields"`
+}
+
+// Credits represents the settings used to parse Apollo's credits page.
+type Credits struct {
+	Locales []CreditLocale `json:"locales"`
+}
+
+// CreditLocale represents the patterns used to parse Apollo's credits page in a given locale. Usage
+// must capture the used and maximum credits, Renewal the renewal time (parsed with one of Layouts,
+// in Go's time layout format) and Plan (optional) the name of the plan.
+type CreditLocale struct {
+	Name    string   `json:"name"`
+	Usage   string   `json:"usage"`
+	Renewal string   `json:"renewal"`
+	Layouts []string `json:"layouts"`
+	Plan    string   `json:"plan"`
+}
+
+// Timeouts represents the time limits (in seconds) for specific browser actions.
+// Unset values fall back to the global timeout.
+type Timeouts struct {
+	Login      int `json:"login"`
+	TabSelect  int `json:"tab-select"`
+	SaveDialog int `json:"save-dialog"`
+	TableLoad  int `json:"table-load"`
+	Credits    int `json:"credits"`
+}
+
+// Load reads the configuration from the provided JSON file.
+func Load(file string) (*Config, error) {
+	if ext := io.FileFormat(filepath.Ext(file)); ext != io.JsonFileFormat {
+		return nil, fmt.Errorf("config files must be in JSON format: %w", io.ErrorUnsupportedFileFormat)
+	}
+
+	config := new(Config)
+	if err := io.ReadRecords(file, config); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
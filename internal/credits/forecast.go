@@ -0,0 +1,94 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credits
+
+import (
+	"time"
+)
+
+// Forecast summarises an account's credit history.
+type Forecast struct {
+	Account   string
+	Remaining int
+	Max       int
+	// BurnRate is the average amount of credits used per day since the credits were last renewed.
+	BurnRate float64
+	// ExhaustedAt is when the account is expected to run out of credits at its current burn rate. It is
+	// zero if the account isn't using any credits.
+	ExhaustedAt time.Time
+	// Renewal is when the account's credits are renewed (if known).
+	Renewal time.Time
+	Updated time.Time
+}
+
+// NewForecast summarises the provided records, which must belong to a single account and be ordered
+// by time. It returns nil if there are no records.
+func NewForecast(records []*Record) *Forecast {
+	if len(records) == 0 {
+		return nil
+	}
+
+	latest := records[len(records)-1]
+	forecast := &Forecast{
+		Account:   latest.Account,
+		Remaining: latest.Remaining,
+		Max:       latest.Max,
+		Updated:   latest.Time,
+	}
+
+	if latest.Renewal != nil {
+		forecast.Renewal, _ = latest.Renewal.Get()
+	}
+
+	// only the records since the credits were last renewed (i.e. usage went down) are relevant.
+	first := len(records) - 1
+	for first > 0 && records[first-1].Used <= records[first].Used {
+		first--
+	}
+
+	start := records[first]
+	if days := latest.Time.Sub(start.Time).Hours() / 24; days > 0 {
+		forecast.BurnRate = float64(latest.Used-start.Used) / days
+	}
+
+	if forecast.BurnRate > 0 {
+		days := float64(latest.Remaining) / forecast.BurnRate
+		forecast.ExhaustedAt = latest.Time.Add(time.Duration(days * 24 * float64(time.Hour)))
+	}
+
+	return forecast
+}
+
+// PoolForecast summarises the forecasts of a pool of accounts, assuming that they keep burning
+// through credits at their current rates.
+func PoolForecast(forecasts []*Forecast) *Forecast {
+	pool := &Forecast{Account: "total"}
+	for _, f := range forecasts {
+		pool.Remaining += f.Remaining
+		pool.Max += f.Max
+		pool.BurnRate += f.BurnRate
+
+		if f.Updated.After(pool.Updated) {
+			pool.Updated = f.Updated
+		}
+	}
+
+	if pool.BurnRate > 0 {
+		days := float64(pool.Remaining) / pool.BurnRate
+		pool.ExhaustedAt = pool.Updated.Add(time.Duration(days * 24 * float64(time.Hour)))
+	}
+
+	return pool
+}
@@ -0,0 +1,63 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credits
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewForecast(t *testing.T) {
+	start := time.Date(2025, time.March, 1, 0, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	var records []*Record
+	for i, used := range []int{900, 100, 200, 400} {
+		records = append(records, &Record{
+			Time:      start.Add(time.Duration(i) * day),
+			Account:   "test@example.com",
+			Used:      used,
+			Max:       1000,
+			Remaining: 1000 - used,
+		})
+	}
+
+	f := NewForecast(records)
+
+	// usage is only measured since the credits were renewed on the second day.
+	if f.BurnRate != 150 {
+		t.Fatalf("expected a burn rate of 150, got %f", f.BurnRate)
+	}
+
+	if want := start.Add(3 * day).Add(4 * day); !f.ExhaustedAt.Equal(want) {
+		t.Fatalf("expected credits to run out at %v, got %v", want, f.ExhaustedAt)
+	}
+
+	pool := PoolForecast([]*Forecast{f, {Remaining: 100, Max: 100, Updated: f.Updated}})
+	if pool.Remaining != 700 || pool.BurnRate != 150 {
+		t.Fatalf("unexpected pool forecast: %+v", pool)
+	}
+}
+
+func TestNewForecastIdle(t *testing.T) {
+	f := NewForecast([]*Record{{Account: "test@example.com", Used: 5, Max: 100, Remaining: 95}})
+	if f.BurnRate != 0 || !f.ExhaustedAt.IsZero() {
+		t.Fatalf("expected an idle account to never run out, got %+v", f)
+	}
+
+	if NewForecast(nil) != nil {
+		t.Fatal("expected no forecast without records")
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package credits keeps a per-account history of credit usage, which is used to estimate how quickly
+// accounts burn through their credits.
+package credits
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/models"
+	"github.com/gocarina/gocsv"
+)
+
+// Record represents a single fetch of an account's credit usage.
+type Record struct {
+	Time      time.Time    `csv:"time"`
+	Account   string       `csv:"account"`
+	Used      int          `csv:"used"`
+	Max       int          `csv:"max"`
+	Remaining int          `csv:"remaining"`
+	Renewal   *models.Time `csv:"renewal"`
+	Plan      string       `csv:"plan"`
+}
+
+// History is an append-only time series of the credit usage of each account. Every account's
+// records are written to a separate CSV file in the history's directory.
+type History struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// New returns a [*History] that writes to the provided directory, creating it if necessary.
+func New(dir string) (*History, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	return &History{dir: dir}, nil
+}
+
+// Open returns a [*History] that reads from the provided (existing) directory.
+func Open(dir string) (*History, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, err
+	}
+
+	return &History{dir: dir}, nil
+}
+
+func filename(dir, account string) string {
+	return filepath.Join(dir, account+".csv")
+}
+
+// Append appends the provided record to its account's history. If the record's time is not
+// set, the current time is used.
+func (h *History) Append(record *Record) error {
+	if record.Time.IsZero() {
+		record.Time = time.Now()
+	}
+
+	if record.Renewal == nil {
+		record.Renewal = models.NewTime()
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	f, err := os.OpenFile(filename(h.dir, record.Account), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		return errors.Join(err, f.Close())
+	}
+
+	records := []*Record{record}
+	if info.Size() == 0 {
+		err = gocsv.Marshal(records, f)
+	} else {
+		err = gocsv.MarshalWithoutHeaders(records, f)
+	}
+
+	return errors.Join(err, f.Close())
+}
+
+// Read returns the provided account's history, ordered by time.
+func (h *History) Read(account string) ([]*Record, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	f, err := os.Open(filename(h.dir, account))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []*Record
+	if err := gocsv.UnmarshalFile(f, &records); err != nil {
+		return nil, err
+	}
+
+	slices.SortStableFunc(records, func(a, b *Record) int {
+		return a.Time.Compare(b.Time)
+	})
+
+	return records, nil
+}
+
+// Accounts returns the accounts with a recorded history.
+func (h *History) Accounts() ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(h.dir, "*.csv"))
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := make([]string, len(files))
+	for i, file := range files {
+		accounts[i] = strings.TrimSuffix(filepath.Base(file), ".csv")
+	}
+
+	return accounts, nil
+}
@@ -0,0 +1,169 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package dedupe implements a persistent index of the leads captured by every account, so that
+// accounts targeting overlapping searches don't spend credits saving contacts which have already
+// been captured. The index is kept in a file which is only ever appended to, so that it can be
+// shared by several runs and scrapollo processes at once.
+package dedupe
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/devsheke/scrapollo/internal/models"
+)
+
+// Key returns the identity of a lead: its LinkedIn profile if it has one, or else its name and
+// company. Keys are hashed so that the store doesn't hold personal information.
+func Key(lead *models.Lead) string {
+	identity := ""
+	for _, link := range strings.Split(lead.Links, ",") {
+		if u, err := url.Parse(strings.TrimSpace(link)); err == nil && strings.HasSuffix(u.Hostname(), "linkedin.com") {
+			identity = "linkedin:" + strings.ToLower(strings.TrimSuffix(u.Path, "/"))
+			break
+		}
+	}
+
+	if identity == "" {
+		identity = "name:" + normalize(lead.Name) + "|" + normalize(lead.Company)
+	}
+
+	sum := sha256.Sum256([]byte(identity))
+	return hex.EncodeToString(sum[:16])
+}
+
+// normalize lowercases s and collapses its whitespace.
+func normalize(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
+
+// Store is a persistent set of lead identities.
+type Store struct {
+	mu     sync.Mutex
+	file   *os.File
+	offset int64
+	keys   map[string]struct{}
+}
+
+// Open opens the store kept in the provided file, creating it if it doesn't exist.
+func Open(path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Store{file: file, keys: make(map[string]struct{})}
+	if err := s.sync(); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// sync reads the keys appended to the file since it was last read, e.g. by other processes.
+func (s *Store) sync() error {
+	r := bufio.NewReader(io.NewSectionReader(s.file, s.offset, 1<<62))
+	for {
+		line, err := r.ReadString('\n')
+		if err == io.EOF {
+			// a partial line is being written by another process and is read once it's complete.
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		s.offset += int64(len(line))
+		if key := strings.TrimSpace(line); key != "" {
+			s.keys[key] = struct{}{}
+		}
+	}
+}
+
+// Len returns the number of leads in the store.
+func (s *Store) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.keys)
+}
+
+// Unseen returns the indices of the leads which aren't in the store.
+func (s *Store) Unseen(leads []*models.Lead) ([]int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.sync(); err != nil {
+		return nil, err
+	}
+
+	var unseen []int
+	for i, lead := range leads {
+		if _, ok := s.keys[Key(lead)]; !ok {
+			unseen = append(unseen, i)
+		}
+	}
+
+	return unseen, nil
+}
+
+// Add adds the leads to the store.
+func (s *Store) Add(leads ...*models.Lead) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.sync(); err != nil {
+		return err
+	}
+
+	var b strings.Builder
+	for _, lead := range leads {
+		key := Key(lead)
+		if _, ok := s.keys[key]; ok {
+			continue
+		}
+
+		s.keys[key] = struct{}{}
+		b.WriteString(key + "\n")
+	}
+
+	if b.Len() == 0 {
+		return nil
+	}
+
+	// the keys are written at once, so that other processes never read them half written.
+	if _, err := s.file.WriteString(b.String()); err != nil {
+		return err
+	}
+
+	// reading the keys back moves the offset past them and past any appended by other processes.
+	return s.sync()
+}
+
+// Close closes the store's file.
+func (s *Store) Close() error {
+	return s.file.Close()
+}
@@ -0,0 +1,109 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dedupe
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/devsheke/scrapollo/internal/models"
+)
+
+func TestKey(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		a, b models.Lead
+		same bool
+	}{
+		{
+			name: "same profile",
+			a:    models.Lead{Name: "Jane Doe", Links: "https://www.linkedin.com/in/jane-doe/"},
+			b:    models.Lead{Name: "Jane D.", Links: "http://twitter.com/jane,http://linkedin.com/in/Jane-Doe"},
+			same: true,
+		},
+		{
+			name: "different profiles",
+			a:    models.Lead{Name: "Jane Doe", Links: "https://www.linkedin.com/in/jane-doe"},
+			b:    models.Lead{Name: "Jane Doe", Links: "https://www.linkedin.com/in/jane-doe-2"},
+		},
+		{
+			name: "same name and company",
+			a:    models.Lead{Name: "Jane  Doe", Company: "Acme"},
+			b:    models.Lead{Name: "jane doe", Company: "ACME"},
+			same: true,
+		},
+		{
+			name: "different companies",
+			a:    models.Lead{Name: "Jane Doe", Company: "Acme"},
+			b:    models.Lead{Name: "Jane Doe", Company: "Globex"},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if same := Key(&tc.a) == Key(&tc.b); same != tc.same {
+				t.Errorf("same key = %v; want %v", same, tc.same)
+			}
+		})
+	}
+}
+
+func TestStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dedupe", "leads")
+	leads := []*models.Lead{
+		{Name: "Jane Doe", Company: "Acme"},
+		{Name: "John Doe", Company: "Acme"},
+		{Name: "Jim Doe", Company: "Acme"},
+	}
+
+	a, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close()
+
+	b, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+
+	if err := a.Add(leads[0], leads[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	// leads added by one store are seen by others using the same file.
+	unseen, err := b.Unseen(leads)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{1, 2}; !slices.Equal(unseen, want) {
+		t.Errorf("unseen = %v; want %v", unseen, want)
+	}
+
+	if err := b.Add(leads[1]); err != nil {
+		t.Fatal(err)
+	}
+
+	// and are kept across runs.
+	c, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	if c.Len() != 2 {
+		t.Errorf("len = %d; want 2", c.Len())
+	}
+}
@@ -0,0 +1,221 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package events streams the progress of a run as JSON events to local supervisors and UIs.
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/journal"
+	"github.com/rs/zerolog/log"
+)
+
+// bufferSize is the number of events buffered for each client. Events are dropped for clients
+// that fall further behind, so that a slow client never stalls the scraper.
+const bufferSize = 256
+
+// writeTimeout is the time after which a write to a client is abandoned.
+const writeTimeout = 5 * time.Second
+
+// Stream broadcasts progress events, one JSON object per line, to the clients connected to a Unix
+// socket or to the reader of a named pipe. Events are the [journal.Entry]s recorded by the runner,
+// whose 'action' tells them apart, e.g. 'job-started', 'page-saved', 'page-scraped' or 'error'.
+type Stream struct {
+	path     string
+	listener net.Listener
+
+	mu      sync.Mutex
+	clients map[*client]bool
+	closed  bool
+	wg      sync.WaitGroup
+}
+
+type client struct {
+	conn   io.WriteCloser
+	events chan []byte
+	done   chan struct{}
+}
+
+// Listen returns a [*Stream] publishing events on the provided path. If the path is an existing
+// named pipe (e.g. created with mkfifo), events are written to it once a reader opens it, and again
+// whenever a new reader opens it. Otherwise, a Unix socket is created at the path, replacing any
+// socket left behind by a previous run, and any number of clients may connect to it.
+func Listen(path string) (*Stream, error) {
+	s := &Stream{path: path, clients: make(map[*client]bool)}
+
+	info, err := os.Stat(path)
+	switch {
+	case err == nil && info.Mode()&os.ModeNamedPipe != 0:
+		go s.openPipe()
+		return s, nil
+
+	case err == nil && info.Mode()&os.ModeSocket != 0:
+		if err := os.Remove(path); err != nil {
+			return nil, err
+		}
+
+	case err != nil && !errors.Is(err, os.ErrNotExist):
+		return nil, err
+	}
+
+	if s.listener, err = net.Listen("unix", path); err != nil {
+		return nil, err
+	}
+
+	go s.accept()
+
+	return s, nil
+}
+
+func (s *Stream) accept() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			if !errors.Is(err, net.ErrClosed) {
+				log.Warn().Err(err).Str("path", s.path).Msg("stopped accepting event stream clients")
+			}
+			return
+		}
+
+		s.add(conn)
+	}
+}
+
+// openPipe writes the events to the named pipe, reopening it whenever its reader goes away. Opening
+// a named pipe for writing blocks until it's opened for reading.
+func (s *Stream) openPipe() {
+	for {
+		f, err := os.OpenFile(s.path, os.O_WRONLY, 0)
+		if err != nil {
+			log.Warn().Err(err).Str("path", s.path).Msg("failed to open event pipe")
+			return
+		}
+
+		c := s.add(f)
+		if c == nil {
+			return
+		}
+
+		<-c.done
+	}
+}
+
+// add starts writing events to a new client, returning nil if the [*Stream] is closed.
+func (s *Stream) add(conn io.WriteCloser) *client {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		_ = conn.Close()
+		return nil
+	}
+
+	c := &client{conn: conn, events: make(chan []byte, bufferSize), done: make(chan struct{})}
+	s.clients[c] = true
+
+	s.wg.Add(1)
+	go s.write(c)
+
+	return c
+}
+
+func (s *Stream) write(c *client) {
+	defer s.wg.Done()
+	defer close(c.done)
+	defer c.conn.Close()
+
+	type deadliner interface{ SetWriteDeadline(time.Time) error }
+
+	for b := range c.events {
+		if d, ok := c.conn.(deadliner); ok {
+			_ = d.SetWriteDeadline(time.Now().Add(writeTimeout))
+		}
+
+		if _, err := c.conn.Write(b); err != nil {
+			break
+		}
+	}
+
+	s.remove(c)
+}
+
+func (s *Stream) remove(c *client) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.clients[c] {
+		delete(s.clients, c)
+		close(c.events)
+	}
+}
+
+func (s *Stream) clientCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.clients)
+}
+
+// Publish sends the entry to every connected client. It never blocks: the entry is dropped for
+// clients whose buffer is full, and when no client is connected.
+func (s *Stream) Publish(entry journal.Entry) {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+
+	b, err := json.Marshal(entry)
+	if err != nil {
+		log.Warn().Err(err).Msg("failed to marshal progress event")
+		return
+	}
+	b = append(b, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for c := range s.clients {
+		select {
+		case c.events <- b:
+		default:
+		}
+	}
+}
+
+// Close stops accepting clients and closes the connected ones once they have been sent the events
+// published so far. The socket, if any, is removed.
+func (s *Stream) Close() error {
+	s.mu.Lock()
+	s.closed = true
+	for c := range s.clients {
+		delete(s.clients, c)
+		close(c.events)
+	}
+	s.mu.Unlock()
+
+	var err error
+	if s.listener != nil {
+		err = s.listener.Close()
+	}
+
+	s.wg.Wait()
+
+	return err
+}
@@ -0,0 +1,83 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package events
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/journal"
+)
+
+func TestStream(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.sock")
+
+	s, err := Listen(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	for deadline := time.Now().Add(5 * time.Second); s.clientCount() == 0; {
+		if time.Now().After(deadline) {
+			t.Fatal("client was never accepted")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	s.Publish(journal.Entry{Account: "a@example.com", Action: journal.ActionPageSaved, Page: 2, Leads: 25})
+	s.Publish(journal.Entry{Account: "a@example.com", Action: journal.ActionError, Error: "timed out"})
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	var entries []journal.Entry
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		var entry journal.Entry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatal(err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("got %d events; want 2", len(entries))
+	}
+
+	if e := entries[0]; e.Action != journal.ActionPageSaved || e.Page != 2 || e.Leads != 25 || e.Time.IsZero() {
+		t.Errorf("got event %+v; want the saved page", e)
+	}
+
+	if e := entries[1]; e.Action != journal.ActionError || e.Error != "timed out" {
+		t.Errorf("got event %+v; want the error", e)
+	}
+
+	// the socket is reused by the next run.
+	s, err = Listen(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = s.Close()
+}
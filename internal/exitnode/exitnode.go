@@ -0,0 +1,42 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package exitnode implements VPN providers that drive an exit node managed outside of scrapollo
+// (a Gluetun container or a Tailscale exit node), for hosts that can't install OpenVPN or run
+// privileged processes.
+package exitnode
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultTimeout is the default time allowed for an exit node to connect.
+const DefaultTimeout = time.Minute
+
+// poll calls fn every interval until it returns true or an error, or the context is done.
+func poll(ctx context.Context, interval time.Duration, fn func() (bool, error)) error {
+	for {
+		done, err := fn()
+		if err != nil || done {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
@@ -0,0 +1,225 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exitnode
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/openvpn"
+	openvpngo "github.com/devsheke/scrapollo/pkg/openvpn-go"
+	"github.com/rs/zerolog/log"
+)
+
+// Gluetun status values.
+const (
+	gluetunRunning = "running"
+	gluetunStopped = "stopped"
+)
+
+// Gluetun drives a Gluetun container through its control server. Configs are the countries that
+// Gluetun selects servers from (e.g. 'Germany'); every restart makes Gluetun connect to another
+// server and thus switches the exit IP. The browser connects through Gluetun's HTTP proxy.
+type Gluetun struct {
+	apiKey, proxy, url string
+	client             *http.Client
+	timeout            time.Duration
+
+	mu       sync.RWMutex
+	config   string
+	publicIP string
+	running  bool
+}
+
+// NewGluetun returns a [*Gluetun] for the control server at the given URL (e.g. 'http://127.0.0.1:8000')
+// and the HTTP proxy at the given URL (e.g. 'http://127.0.0.1:8888'). The API key may be empty if the
+// control server doesn't require one.
+func NewGluetun(url, apiKey, proxy string) *Gluetun {
+	return &Gluetun{
+		apiKey:  apiKey,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		proxy:   proxy,
+		timeout: DefaultTimeout,
+		url:     strings.TrimSuffix(url, "/"),
+	}
+}
+
+func (g *Gluetun) request(method, path string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequest(method, g.url+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if g.apiKey != "" {
+		req.Header.Set("X-API-Key", g.apiKey)
+	}
+
+	res, err := g.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("gluetun: %s %s: %s: %s", method, path, res.Status, strings.TrimSpace(string(msg)))
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(res.Body).Decode(out)
+}
+
+func (g *Gluetun) setStatus(status string) error {
+	return g.request(http.MethodPut, "/v1/vpn/status", map[string]string{"status": status}, nil)
+}
+
+func (g *Gluetun) status() (string, error) {
+	var res struct {
+		Status string `json:"status"`
+	}
+	err := g.request(http.MethodGet, "/v1/vpn/status", nil, &res)
+	return res.Status, err
+}
+
+func (g *Gluetun) fetchPublicIP() (string, error) {
+	var res struct {
+		PublicIP string `json:"public_ip"`
+	}
+	err := g.request(http.MethodGet, "/v1/publicip/ip", nil, &res)
+	return res.PublicIP, err
+}
+
+// Restart makes Gluetun reconnect to a server in the provided country (or in the same countries as
+// before, if config is empty) and waits for it to obtain a new public IP.
+func (g *Gluetun) Restart(config string) error {
+	if config != "" {
+		settings := map[string]any{
+			"provider": map[string]any{
+				"server_selection": map[string]any{"countries": []string{config}},
+			},
+		}
+		if err := g.request(http.MethodPut, "/v1/vpn/settings", settings, nil); err != nil {
+			return err
+		}
+	}
+
+	previous, _ := g.fetchPublicIP()
+
+	if err := g.setStatus(gluetunStopped); err != nil {
+		return err
+	}
+	if err := g.setStatus(gluetunRunning); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), g.timeout)
+	defer cancel()
+
+	var publicIP string
+	err := poll(ctx, time.Second, func() (bool, error) {
+		status, err := g.status()
+		if err != nil || status != gluetunRunning {
+			return false, nil
+		}
+
+		publicIP, err = g.fetchPublicIP()
+		return err == nil && publicIP != "" && publicIP != previous, nil
+	})
+	if err != nil {
+		return openvpngo.ErrorVpnTimedOut{Msg: "gluetun did not obtain a new public ip"}
+	}
+
+	log.Debug().Str("config", config).Str("public-ip", publicIP).Msg("gluetun connected")
+
+	g.mu.Lock()
+	if config != "" {
+		g.config = config
+	}
+	g.publicIP, g.running = publicIP, true
+	g.mu.Unlock()
+
+	return nil
+}
+
+// Stop makes Gluetun disconnect from its server.
+func (g *Gluetun) Stop() error {
+	g.mu.Lock()
+	running := g.running
+	g.running = false
+	g.mu.Unlock()
+
+	if !running {
+		return openvpngo.ErrorNoVpnProcess
+	}
+
+	return g.setStatus(gluetunStopped)
+}
+
+// Backup makes Gluetun connect to another server in the last country it was restarted with (or
+// the countries it is configured with) and returns that country. Regions are ignored since they
+// can't be mapped to Gluetun's countries.
+func (g *Gluetun) Backup(string) (string, error) {
+	if err := g.Restart(""); err != nil {
+		return "", err
+	}
+
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	return g.config, nil
+}
+
+// UseConfig is a no-op: Gluetun picks a different server on every restart.
+func (g *Gluetun) UseConfig(string) {}
+
+// Region returns an empty string, since Gluetun's countries aren't mapped to country codes.
+func (g *Gluetun) Region(string) string {
+	return ""
+}
+
+// Proxy returns the URL of Gluetun's HTTP proxy.
+func (g *Gluetun) Proxy() string {
+	return g.proxy
+}
+
+// Status returns the status of Gluetun's connection.
+func (g *Gluetun) Status() openvpn.Status {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	status := openvpn.Status{Config: g.config, Running: g.running}
+	if g.running {
+		status.State, status.RemoteIP = openvpngo.StateConnected, g.publicIP
+	}
+
+	return status
+}
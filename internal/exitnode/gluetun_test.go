@@ -0,0 +1,111 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exitnode
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testGluetun emulates Gluetun's control server, which switches to a new public IP every time
+// the VPN is started.
+func testGluetun(t *testing.T) (*httptest.Server, *[]string) {
+	var (
+		mu       sync.Mutex
+		status   = gluetunRunning
+		ip       = 1
+		requests []string
+	)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if r.Header.Get("X-API-Key") != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		var body map[string]any
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		requests = append(requests, fmt.Sprintf("%s %s %v", r.Method, r.URL.Path, body))
+
+		switch r.Method + " " + r.URL.Path {
+		case "GET /v1/vpn/status":
+			_ = json.NewEncoder(w).Encode(map[string]string{"status": status})
+		case "PUT /v1/vpn/status":
+			status = body["status"].(string)
+			if status == gluetunRunning {
+				ip++
+			}
+		case "GET /v1/publicip/ip":
+			_ = json.NewEncoder(w).Encode(map[string]string{"public_ip": fmt.Sprintf("203.0.113.%d", ip)})
+		case "PUT /v1/vpn/settings":
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &requests
+}
+
+func TestGluetunRestart(t *testing.T) {
+	server, requests := testGluetun(t)
+
+	g := NewGluetun(server.URL, "secret", "http://127.0.0.1:8888")
+	g.timeout = 5 * time.Second
+
+	if err := g.Restart("Germany"); err != nil {
+		t.Fatal(err)
+	}
+
+	status := g.Status()
+	if !status.Running || status.Config != "Germany" || status.RemoteIP != "203.0.113.2" {
+		t.Fatalf("unexpected status: %+v", status)
+	}
+
+	config, err := g.Backup("de")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config != "Germany" || g.Status().RemoteIP != "203.0.113.3" {
+		t.Fatalf("unexpected backup: %q, %+v", config, g.Status())
+	}
+
+	if err := g.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if g.Status().Running {
+		t.Fatal("expected gluetun to be stopped")
+	}
+
+	if got := (*requests)[0]; got != "PUT /v1/vpn/settings map[provider:map[server_selection:map[countries:[Germany]]]]" {
+		t.Fatalf("unexpected first request: %q", got)
+	}
+}
+
+func TestGluetunUnauthorized(t *testing.T) {
+	server, _ := testGluetun(t)
+
+	if err := NewGluetun(server.URL, "wrong", "").Restart(""); err == nil {
+		t.Fatal("expected an error")
+	}
+}
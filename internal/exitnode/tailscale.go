@@ -0,0 +1,278 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exitnode
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/openvpn"
+	openvpngo "github.com/devsheke/scrapollo/pkg/openvpn-go"
+	"github.com/rs/zerolog/log"
+)
+
+// ErrorNoExitNodes is returned when the tailnet has no exit nodes.
+var ErrorNoExitNodes = errors.New("no tailscale exit nodes were found")
+
+// exitNode is a peer of the tailnet that can be used as an exit node.
+type exitNode struct {
+	Name, IP, CountryCode string
+	Online                bool
+}
+
+// tailscaleStatus is the subset of 'tailscale status --json' used by [Tailscale].
+type tailscaleStatus struct {
+	Peer map[string]struct {
+		HostName       string
+		DNSName        string
+		TailscaleIPs   []string
+		ExitNodeOption bool
+		Online         bool
+		Location       *struct {
+			CountryCode string
+		}
+	}
+	ExitNodeStatus *struct {
+		Online       bool
+		TailscaleIPs []string
+	}
+}
+
+// exitNodes returns the exit nodes of the tailnet, sorted by name.
+func (s tailscaleStatus) exitNodes() []exitNode {
+	var nodes []exitNode
+	for _, peer := range s.Peer {
+		if !peer.ExitNodeOption || len(peer.TailscaleIPs) == 0 {
+			continue
+		}
+
+		node := exitNode{
+			Name:   strings.Split(peer.DNSName, ".")[0],
+			IP:     peer.TailscaleIPs[0],
+			Online: peer.Online,
+		}
+		if node.Name == "" {
+			node.Name = peer.HostName
+		}
+		if peer.Location != nil {
+			node.CountryCode = strings.ToLower(peer.Location.CountryCode)
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	slices.SortFunc(nodes, func(a, b exitNode) int { return strings.Compare(a.Name, b.Name) })
+	return nodes
+}
+
+// Tailscale switches the host's Tailscale exit node. Configs are the names of exit nodes in the
+// tailnet. The tailscale CLI must be usable by the current user (see 'tailscale set --operator').
+type Tailscale struct {
+	bin     string
+	timeout time.Duration
+
+	mu     sync.RWMutex
+	config string
+	nodes  []exitNode
+	used   map[string]struct{}
+}
+
+// NewTailscale returns a [*Tailscale] for the exit nodes of the host's tailnet.
+func NewTailscale() (*Tailscale, error) {
+	t := &Tailscale{bin: "tailscale", timeout: DefaultTimeout, used: make(map[string]struct{})}
+
+	status, err := t.status()
+	if err != nil {
+		return nil, err
+	}
+
+	if t.nodes = status.exitNodes(); len(t.nodes) == 0 {
+		return nil, ErrorNoExitNodes
+	}
+
+	return t, nil
+}
+
+func (t *Tailscale) run(args ...string) ([]byte, error) {
+	out, err := exec.Command(t.bin, args...).Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			return nil, fmt.Errorf("tailscale %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return nil, err
+	}
+	return out, nil
+}
+
+func (t *Tailscale) status() (tailscaleStatus, error) {
+	var status tailscaleStatus
+
+	out, err := t.run("status", "--json")
+	if err != nil {
+		return status, err
+	}
+
+	return status, json.Unmarshal(out, &status)
+}
+
+func (t *Tailscale) node(config string) (exitNode, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	i := slices.IndexFunc(t.nodes, func(n exitNode) bool { return n.Name == config || n.IP == config })
+	if i < 0 {
+		return exitNode{}, false
+	}
+	return t.nodes[i], true
+}
+
+// Restart switches to the exit node with the provided name and waits for it to be online.
+func (t *Tailscale) Restart(config string) error {
+	node, ok := t.node(config)
+	if !ok {
+		return openvpngo.ErrorConfigNotFound
+	}
+
+	if _, err := t.run("set", "--exit-node="+node.IP); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), t.timeout)
+	defer cancel()
+
+	err := poll(ctx, time.Second, func() (bool, error) {
+		status, err := t.status()
+		if err != nil {
+			return false, err
+		}
+		return status.ExitNodeStatus != nil && status.ExitNodeStatus.Online, nil
+	})
+	if errors.Is(err, context.DeadlineExceeded) {
+		return openvpngo.ErrorVpnTimedOut{Msg: fmt.Sprintf("exit node %q did not come online", config)}
+	} else if err != nil {
+		return err
+	}
+
+	log.Debug().Str("config", config).Msg("switched tailscale exit node")
+
+	t.mu.Lock()
+	t.config = config
+	t.mu.Unlock()
+
+	t.UseConfig(config)
+
+	return nil
+}
+
+// Stop stops using an exit node.
+func (t *Tailscale) Stop() error {
+	t.mu.Lock()
+	config := t.config
+	t.config = ""
+	t.mu.Unlock()
+
+	if config == "" {
+		return openvpngo.ErrorNoVpnProcess
+	}
+
+	_, err := t.run("set", "--exit-node=")
+	return err
+}
+
+// Backup switches to an unused, online exit node (preferring one from the provided region, if any)
+// and returns its name.
+func (t *Tailscale) Backup(region string) (string, error) {
+	t.mu.RLock()
+	var preferred, others []string
+	for _, node := range t.nodes {
+		if _, used := t.used[node.Name]; used || !node.Online {
+			continue
+		}
+
+		if region != "" && node.CountryCode == strings.ToLower(region) {
+			preferred = append(preferred, node.Name)
+		} else {
+			others = append(others, node.Name)
+		}
+	}
+	t.mu.RUnlock()
+
+	rand.Shuffle(len(preferred), func(i, j int) { preferred[i], preferred[j] = preferred[j], preferred[i] })
+	rand.Shuffle(len(others), func(i, j int) { others[i], others[j] = others[j], others[i] })
+
+	for _, config := range append(preferred, others...) {
+		if err := t.Restart(config); err != nil {
+			log.Debug().Err(err).Str("config", config).Msg("backup exit node failed")
+			continue
+		}
+		return config, nil
+	}
+
+	return "", openvpn.ErrorNoUnusedConfigs
+}
+
+// UseConfig records that the exit node with the provided name was used.
+func (t *Tailscale) UseConfig(config string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.used[config] = struct{}{}
+}
+
+// Region returns the country code of the exit node with the provided name, if it is known.
+func (t *Tailscale) Region(config string) string {
+	node, _ := t.node(config)
+	return node.CountryCode
+}
+
+// Proxy returns an empty string, since the exit node routes all of the host's traffic.
+func (t *Tailscale) Proxy() string {
+	return ""
+}
+
+// Status returns the status of the current exit node.
+func (t *Tailscale) Status() openvpn.Status {
+	t.mu.RLock()
+	config := t.config
+	t.mu.RUnlock()
+
+	status := openvpn.Status{Config: config}
+	if config == "" {
+		return status
+	}
+
+	ts, err := t.status()
+	if err != nil || ts.ExitNodeStatus == nil {
+		return status
+	}
+
+	status.Running = ts.ExitNodeStatus.Online
+	if status.Running {
+		status.State = openvpngo.StateConnected
+	}
+	if len(ts.ExitNodeStatus.TailscaleIPs) > 0 {
+		status.RemoteIP = ts.ExitNodeStatus.TailscaleIPs[0]
+	}
+
+	return status
+}
@@ -0,0 +1,75 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package exitnode
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+const testTailscaleStatus = `{
+  "Self": {"HostName": "scraper", "DNSName": "scraper.tail1234.ts.net."},
+  "Peer": {
+    "nodekey:1": {
+      "HostName": "Frankfurt",
+      "DNSName": "fra-1.tail1234.ts.net.",
+      "TailscaleIPs": ["100.64.0.1", "fd7a:115c:a1e0::1"],
+      "ExitNodeOption": true,
+      "Online": true,
+      "Location": {"Country": "Germany", "CountryCode": "DE", "City": "Frankfurt"}
+    },
+    "nodekey:2": {
+      "HostName": "laptop",
+      "DNSName": "laptop.tail1234.ts.net.",
+      "TailscaleIPs": ["100.64.0.2"],
+      "ExitNodeOption": false,
+      "Online": true
+    },
+    "nodekey:3": {
+      "HostName": "home",
+      "DNSName": "",
+      "TailscaleIPs": ["100.64.0.3"],
+      "ExitNodeOption": true,
+      "Online": false
+    }
+  },
+  "ExitNodeStatus": {"ID": "n1", "Online": true, "TailscaleIPs": ["100.64.0.1/32"]}
+}`
+
+func TestTailscaleExitNodes(t *testing.T) {
+	var status tailscaleStatus
+	if err := json.Unmarshal([]byte(testTailscaleStatus), &status); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []exitNode{
+		{Name: "fra-1", IP: "100.64.0.1", CountryCode: "de", Online: true},
+		{Name: "home", IP: "100.64.0.3"},
+	}
+
+	nodes := status.exitNodes()
+	if len(nodes) != len(want) {
+		t.Fatalf("unexpected exit nodes: %+v", nodes)
+	}
+	for i := range want {
+		if nodes[i] != want[i] {
+			t.Fatalf("unexpected exit node %d: got %+v, want %+v", i, nodes[i], want[i])
+		}
+	}
+
+	if !status.ExitNodeStatus.Online {
+		t.Fatal("expected the exit node to be online")
+	}
+}
@@ -0,0 +1,227 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package extract recovers leads from the HTML of Apollo's pages with an LLM, as a fallback for when
+// the scrape script can't find them, e.g. after Apollo changed its markup. It speaks the chat
+// completions API of OpenAI, which most LLM providers and local servers (e.g. Ollama) offer too.
+package extract
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/models"
+)
+
+// ErrorBudgetExhausted is returned once extracting more leads would exceed the [Limits].
+var ErrorBudgetExhausted = errors.New("llm extraction budget exhausted")
+
+// requestTimeout is the time allowed for the LLM to respond.
+const requestTimeout = 2 * time.Minute
+
+// charsPerToken is the rough number of characters per token, used to estimate the tokens of a
+// request before sending it.
+const charsPerToken = 4
+
+// prompt instructs the LLM to extract the leads from a table's HTML.
+const prompt = `You extract people from the HTML of a table of search results. Reply with a JSON object of the
+form {"leads": [...]} holding one object per person, in the order of the table's rows, with the following
+string fields, left empty when the table doesn't show them: name, title, company, location, employees,
+industry, keywords, links (the person's and company's URLs, separated by spaces), email and phone. Only
+report what the HTML shows; never guess or make up values.`
+
+// Limits caps the cost of extracting leads. Zero values mean no limit.
+type Limits struct {
+	// MaxCalls is the number of requests that may be sent to the LLM, and MaxTokens the number of
+	// tokens that they may use in all (as reported by the LLM).
+	MaxCalls, MaxTokens int
+
+	// MaxInput is the number of characters of sanitized HTML sent per request, beyond which the HTML
+	// is truncated.
+	MaxInput int
+}
+
+// Extractor extracts leads from HTML with an LLM, within its [Limits].
+type Extractor struct {
+	url, key, model string
+	limits          Limits
+	client          *http.Client
+
+	mu            sync.Mutex
+	calls, tokens int
+}
+
+// New returns an [*Extractor] which sends requests to the chat completions endpoint at the URL (e.g.
+// 'https://api.openai.com/v1/chat/completions'), for the model and authenticated with the API key,
+// if it isn't empty.
+func New(url, key, model string, limits Limits) *Extractor {
+	return &Extractor{
+		url:    url,
+		key:    key,
+		model:  model,
+		limits: limits,
+		client: &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// Usage returns the number of requests sent and tokens used so far.
+func (e *Extractor) Usage() (calls, tokens int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.calls, e.tokens
+}
+
+// reserve reserves a request of the estimated number of tokens within the limits.
+func (e *Extractor) reserve(estimate int) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.limits.MaxCalls > 0 && e.calls >= e.limits.MaxCalls {
+		return ErrorBudgetExhausted
+	} else if e.limits.MaxTokens > 0 && e.tokens+estimate > e.limits.MaxTokens {
+		return ErrorBudgetExhausted
+	}
+
+	e.calls++
+	return nil
+}
+
+// use records the tokens used by a request.
+func (e *Extractor) use(tokens int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.tokens += tokens
+}
+
+// chatMessage is a message of a chat completion.
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// Extract returns the leads found in the HTML, which is sanitized (see [Sanitize]) before it's sent.
+// The leads are returned as the LLM reported them, so they must be validated.
+func (e *Extractor) Extract(ctx context.Context, html string) ([]*models.Lead, error) {
+	input := Sanitize(html)
+	if e.limits.MaxInput > 0 && len(input) > e.limits.MaxInput {
+		input = input[:e.limits.MaxInput]
+	}
+
+	if err := e.reserve((len(prompt) + len(input)) / charsPerToken); err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"model":           e.model,
+		"temperature":     0,
+		"response_format": map[string]string{"type": "json_object"},
+		"messages": []chatMessage{
+			{Role: "system", Content: prompt},
+			{Role: "user", Content: input},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if e.key != "" {
+		req.Header.Set("Authorization", "Bearer "+e.key)
+	}
+
+	res, err := e.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(res.Body, 4096))
+		return nil, fmt.Errorf("llm responded with status %s: %s", res.Status, strings.TrimSpace(string(msg)))
+	}
+
+	var completion struct {
+		Choices []struct {
+			Message chatMessage `json:"message"`
+		} `json:"choices"`
+		Usage struct {
+			TotalTokens int `json:"total_tokens"`
+		} `json:"usage"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&completion); err != nil {
+		return nil, fmt.Errorf("invalid llm response: %w", err)
+	}
+
+	if len(completion.Choices) == 0 {
+		return nil, errors.New("llm returned no choices")
+	}
+	content := completion.Choices[0].Message.Content
+
+	// endpoints which don't report their usage are charged with an estimate.
+	if tokens := completion.Usage.TotalTokens; tokens > 0 {
+		e.use(tokens)
+	} else {
+		e.use((len(prompt) + len(input) + len(content)) / charsPerToken)
+	}
+
+	var output struct {
+		Leads []*models.Lead `json:"leads"`
+	}
+	if err := json.Unmarshal([]byte(stripCodeFence(content)), &output); err != nil {
+		return nil, fmt.Errorf("llm returned invalid leads: %w", err)
+	}
+
+	// only the displayed values are taken from the LLM; the rest are the runner's to set.
+	for _, lead := range output.Leads {
+		*lead = models.Lead{
+			Name:      lead.Name,
+			Title:     lead.Title,
+			Company:   lead.Company,
+			Location:  lead.Location,
+			Employees: lead.Employees,
+			Industry:  lead.Industry,
+			Keywords:  lead.Keywords,
+			Links:     lead.Links,
+			Email:     lead.Email,
+			Phone:     lead.Phone,
+		}
+	}
+
+	return output.Leads, nil
+}
+
+// stripCodeFence removes the markdown code fence that some models wrap their JSON in.
+func stripCodeFence(s string) string {
+	s = strings.TrimSpace(s)
+	if !strings.HasPrefix(s, "```") {
+		return s
+	}
+
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "```json"), "```")
+	return strings.TrimSpace(strings.TrimSuffix(s, "```"))
+}
@@ -0,0 +1,134 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extract
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+const testTable = `<table class="zp_table" data-state='{"token":"secret"}'>
+  <script>window.__APP_STATE__ = {"user": "secret"};</script>
+  <style>.zp_row { color: red; }</style>
+  <!-- row 1 -->
+  <tr class="zp_row" data-id="1">
+    <td><a href="https://www.linkedin.com/in/lead1" class="zp_link">Lead 1</a><svg><path d="M0 0"/></svg></td>
+    <td><span class="zp_title">CEO</span></td>
+    <td><img src="logo.png"><div><span>lead1@example.com</span></div></td>
+  </tr>
+</table>`
+
+func TestSanitize(t *testing.T) {
+	got := Sanitize(testTable)
+	want := `<table> <tr> <td><a href="https://www.linkedin.com/in/lead1">Lead 1</a></td> <td><span>CEO</span></td> <td> <div><span>lead1@example.com</span></div></td> </tr> </table>`
+	if got != want {
+		t.Errorf("Sanitize() = %q, want %q", got, want)
+	}
+}
+
+// fakeLLM serves chat completions which reply with the content, reporting the tokens as used.
+func fakeLLM(t *testing.T, content string, tokens int, requests *[]string) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer key" {
+			t.Errorf("Authorization = %q, want %q", got, "Bearer key")
+		}
+
+		var body struct {
+			Model    string        `json:"model"`
+			Messages []chatMessage `json:"messages"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Errorf("failed to decode request: %v", err)
+		}
+		*requests = append(*requests, body.Messages[len(body.Messages)-1].Content)
+
+		reply, _ := json.Marshal(content)
+		fmt.Fprintf(w, `{"choices":[{"message":{"role":"assistant","content":%s}}],"usage":{"total_tokens":%d}}`, reply, tokens)
+	}))
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestExtract(t *testing.T) {
+	var requests []string
+	content := "```json\n" + `{"leads":[{"name":"Lead 1","title":"CEO","email":"lead1@example.com","source-account":"x","score":9}]}` + "\n```"
+	server := fakeLLM(t, content, 100, &requests)
+
+	e := New(server.URL, "key", "model", Limits{MaxInput: 50})
+	leads, err := e.Extract(context.Background(), testTable)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(requests) != 1 || len(requests[0]) != 50 || strings.Contains(requests[0], "secret") {
+		t.Errorf("sent %q, want the first 50 characters of the sanitized html", requests)
+	}
+
+	if len(leads) != 1 {
+		t.Fatalf("got %d leads, want 1", len(leads))
+	}
+	if lead := leads[0]; lead.Name != "Lead 1" || lead.Title != "CEO" || lead.Email != "lead1@example.com" {
+		t.Errorf("got lead %+v", lead)
+	}
+	if lead := leads[0]; lead.Account != "" || lead.Score != 0 {
+		t.Errorf("lead kept fields the llm mustn't set: %+v", lead)
+	}
+
+	if calls, tokens := e.Usage(); calls != 1 || tokens != 100 {
+		t.Errorf("Usage() = %d, %d, want 1, 100", calls, tokens)
+	}
+}
+
+func TestExtractLimits(t *testing.T) {
+	tests := []struct {
+		name   string
+		limits Limits
+		calls  int
+	}{
+		{name: "calls", limits: Limits{MaxCalls: 2}, calls: 2},
+		{name: "tokens", limits: Limits{MaxTokens: 2500}, calls: 3},
+		{name: "estimate", limits: Limits{MaxTokens: 100}, calls: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var requests []string
+			server := fakeLLM(t, `{"leads":[]}`, 1000, &requests)
+
+			e := New(server.URL, "key", "model", tt.limits)
+			for range tt.calls {
+				if _, err := e.Extract(context.Background(), testTable); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if _, err := e.Extract(context.Background(), testTable); !errors.Is(err, ErrorBudgetExhausted) {
+				t.Errorf("Extract() = %v, want %v", err, ErrorBudgetExhausted)
+			}
+			if len(requests) != tt.calls {
+				t.Errorf("sent %d requests, want %d", len(requests), tt.calls)
+			}
+		})
+	}
+}
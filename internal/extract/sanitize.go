@@ -0,0 +1,68 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package extract
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	// discardedElements are the elements which hold nothing of use to the LLM, along with their
+	// contents.
+	discardedElements = regexp.MustCompile(`(?is)<(script|style|svg|noscript|template|iframe)\b.*?</(script|style|svg|noscript|template|iframe)\s*>`)
+	comments          = regexp.MustCompile(`(?s)<!--.*?-->`)
+	tags              = regexp.MustCompile(`(?s)<(/?)([a-zA-Z][a-zA-Z0-9-]*)([^>]*)>`)
+	hrefAttribute     = regexp.MustCompile(`(?is)\shref\s*=\s*("[^"]*"|'[^']*')`)
+	whitespace        = regexp.MustCompile(`\s+`)
+	emptyTags         = regexp.MustCompile(`<(div|span)>\s*</(div|span)>`)
+)
+
+// Sanitize strips the HTML down to its text and structure, to cut the tokens it costs and to keep what
+// it doesn't show (e.g. scripts, styles and the app's state) from being sent to the LLM. Scripts,
+// styles, images and comments are removed, as are every attribute but links' targets.
+func Sanitize(html string) string {
+	html = discardedElements.ReplaceAllString(html, "")
+	html = comments.ReplaceAllString(html, "")
+
+	html = tags.ReplaceAllStringFunc(html, func(tag string) string {
+		m := tags.FindStringSubmatch(tag)
+		closing, name, attrs := m[1], strings.ToLower(m[2]), m[3]
+
+		switch name {
+		case "img", "input", "button", "link", "meta", "br", "hr":
+			return " "
+		}
+
+		if closing == "" && name == "a" {
+			if href := hrefAttribute.FindStringSubmatch(attrs); href != nil {
+				return "<a href=" + href[1] + ">"
+			}
+		}
+
+		return "<" + closing + name + ">"
+	})
+
+	html = whitespace.ReplaceAllString(html, " ")
+	for {
+		stripped := emptyTags.ReplaceAllString(html, "")
+		if stripped == html {
+			break
+		}
+		html = stripped
+	}
+
+	return strings.TrimSpace(html)
+}
@@ -0,0 +1,224 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package feed streams scraped leads to HTTP clients as server-sent events, so that downstream
+// systems can consume them as they're scraped instead of polling the growing output files.
+package feed
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/models"
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultBacklog is the number of recent leads that a [Feed] keeps for clients that reconnect.
+const DefaultBacklog = 1000
+
+// subscriberBuffer is the number of leads queued up for a client before it's considered too slow
+// and disconnected. It can reconnect and resume from the backlog.
+const subscriberBuffer = 256
+
+// heartbeat is how often an idle stream is sent a comment, so that proxies don't time it out.
+const heartbeat = 15 * time.Second
+
+// event is a lead as sent to the clients, along with its ID.
+type event struct {
+	id   uint64
+	data []byte
+}
+
+// Feed is an [io.LeadWriter] which streams the leads written to it to any number of HTTP clients
+// as server-sent events (see [Feed.ServeHTTP]). Each lead is sent as a 'lead' event whose data is
+// the lead as JSON and whose ID increases with every lead, so that clients which reconnect with
+// the 'Last-Event-ID' header resume where they left off, as long as the leads they missed are
+// still in the feed's backlog.
+type Feed struct {
+	mu      sync.Mutex
+	backlog []event
+	size    int
+	last    uint64
+	subs    map[chan event]bool
+	closed  bool
+}
+
+// New returns a [*Feed] which keeps the given number of recent leads for clients that reconnect.
+func New(backlog int) *Feed {
+	return &Feed{size: max(backlog, 0), subs: make(map[chan event]bool)}
+}
+
+// WriteLead sends the lead to the feed's clients.
+func (f *Feed) WriteLead(lead *models.Lead) error {
+	return f.WriteLeads([]*models.Lead{lead})
+}
+
+// WriteLeads sends the leads to the feed's clients.
+func (f *Feed) WriteLeads(leads []*models.Lead) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, lead := range leads {
+		data, err := json.Marshal(lead)
+		if err != nil {
+			return err
+		}
+
+		f.last++
+		e := event{id: f.last, data: data}
+
+		if f.size > 0 {
+			if len(f.backlog) == f.size {
+				f.backlog = append(f.backlog[:0], f.backlog[1:]...)
+			}
+			f.backlog = append(f.backlog, e)
+		}
+
+		for sub := range f.subs {
+			select {
+			case sub <- e:
+			default:
+				log.Warn().Uint64("lead", e.id).Msg("disconnecting slow lead feed client")
+				delete(f.subs, sub)
+				close(sub)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Close disconnects the feed's clients and refuses new ones.
+func (f *Feed) Close() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.closed = true
+	for sub := range f.subs {
+		delete(f.subs, sub)
+		close(sub)
+	}
+}
+
+// subscribe returns a channel of the leads written from now on, along with those in the backlog
+// which come after the lead with the given ID. The channel is nil if the feed is closed.
+func (f *Feed) subscribe(after uint64) (chan event, []event) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return nil, nil
+	}
+
+	var missed []event
+	for _, e := range f.backlog {
+		if e.id > after {
+			missed = append(missed, e)
+		}
+	}
+
+	sub := make(chan event, subscriberBuffer)
+	f.subs[sub] = true
+	return sub, missed
+}
+
+func (f *Feed) unsubscribe(sub chan event) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.subs[sub] {
+		delete(f.subs, sub)
+		close(sub)
+	}
+}
+
+// lastID returns the ID of the last lead written to the feed.
+func (f *Feed) lastID() uint64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.last
+}
+
+// ServeHTTP streams the feed's leads to the client as server-sent events. Only the leads written
+// after the client connected are sent, unless it resumes from the ID in its 'Last-Event-ID' header
+// or in the 'after' query parameter (0 sends the whole backlog).
+func (f *Feed) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	after := f.lastID()
+	for _, resume := range []string{r.Header.Get("Last-Event-ID"), r.URL.Query().Get("after")} {
+		if resume == "" {
+			continue
+		}
+
+		id, err := strconv.ParseUint(resume, 10, 64)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid lead id %q", resume), http.StatusBadRequest)
+			return
+		}
+		after = id
+		break
+	}
+
+	sub, missed := f.subscribe(after)
+	if sub == nil {
+		http.Error(w, "the lead feed is closed", http.StatusServiceUnavailable)
+		return
+	}
+	defer f.unsubscribe(sub)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	for _, e := range missed {
+		writeEvent(w, e)
+	}
+	flusher.Flush()
+
+	ticker := time.NewTicker(heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+
+		case e, ok := <-sub:
+			if !ok {
+				return
+			}
+			writeEvent(w, e)
+			flusher.Flush()
+
+		case <-ticker.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+			flusher.Flush()
+		}
+	}
+}
+
+func writeEvent(w http.ResponseWriter, e event) {
+	fmt.Fprintf(w, "id: %d\nevent: lead\ndata: %s\n\n", e.id, e.data)
+}
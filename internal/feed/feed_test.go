@@ -0,0 +1,85 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package feed
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/devsheke/scrapollo/internal/models"
+)
+
+// readLead reads the next lead event of the stream, returning its ID and lead.
+func readLead(t *testing.T, stream *bufio.Reader) (string, *models.Lead) {
+	t.Helper()
+
+	var id string
+	lead := new(models.Lead)
+	for {
+		line, err := stream.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		switch line = strings.TrimSuffix(line, "\n"); {
+		case strings.HasPrefix(line, "id: "):
+			id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "data: "):
+			if err := json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), lead); err != nil {
+				t.Fatal(err)
+			}
+		case line == "" && id != "":
+			return id, lead
+		}
+	}
+}
+
+func TestFeed(t *testing.T) {
+	f := New(2)
+	srv := httptest.NewServer(f)
+	defer srv.Close()
+	defer f.Close()
+
+	f.WriteLeads([]*models.Lead{{Name: "Lead 1"}, {Name: "Lead 2"}, {Name: "Lead 3"}})
+
+	// a client resuming after the first lead only gets those still in the backlog.
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	req.Header.Set("Last-Event-ID", "1")
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+
+	if ct := res.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("got content type %q", ct)
+	}
+
+	stream := bufio.NewReader(res.Body)
+	if id, lead := readLead(t, stream); id != "2" || lead.Name != "Lead 2" {
+		t.Errorf("got lead %s %q, want 2 %q", id, lead.Name, "Lead 2")
+	}
+
+	readLead(t, stream)
+
+	f.WriteLead(&models.Lead{Name: "Lead 4"})
+	if id, lead := readLead(t, stream); id != "4" || lead.Name != "Lead 4" {
+		t.Errorf("got lead %s %q, want 4 %q", id, lead.Name, "Lead 4")
+	}
+}
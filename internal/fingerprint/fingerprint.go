@@ -0,0 +1,347 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fingerprint provides coherent device profiles which make the browser of an account look
+// like the same device across runs.
+package fingerprint
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// Screen represents the display of a device.
+type Screen struct {
+	// Width and Height are the screen's size and AvailHeight its height without the taskbar or
+	// dock, in CSS pixels.
+	Width, Height, AvailHeight int
+
+	// ViewportWidth and ViewportHeight are the size of the browser's viewport, in CSS pixels.
+	ViewportWidth, ViewportHeight int
+
+	// ScaleFactor is the ratio of physical pixels to CSS pixels.
+	ScaleFactor float64
+}
+
+// Profile represents a device, whose properties are consistent with each other, e.g. the fonts of a
+// profile are those installed on its operating system.
+type Profile struct {
+	// Name identifies the profile, which accounts are assigned by.
+	Name string
+
+	UserAgent string
+
+	// Platform is the value of navigator.platform, e.g. 'Win32'.
+	Platform string
+
+	// OS, OSVersion and Architecture are the operating system and CPU architecture reported by
+	// user-agent client hints, e.g. 'Windows', '15.0.0' and 'x86'.
+	OS, OSVersion, Architecture string
+
+	// BrowserVersion is the full version of Chrome given by the user agent.
+	BrowserVersion string
+
+	// Languages are the user's preferred languages, e.g. 'en-US' and 'en'.
+	Languages []string
+
+	Screen Screen
+
+	// Cores and Memory (in GiB) are the device's number of logical processors and memory.
+	Cores, Memory int
+
+	// Fonts are the fonts installed on the device.
+	Fonts []string
+
+	// Mobile is set for phones, whose browsers get a touch screen with TouchPoints simultaneous touch
+	// points and make Apollo serve its mobile web layout. Model is the phone's model, e.g. 'Pixel 8'.
+	Mobile      bool
+	TouchPoints int
+	Model       string
+}
+
+const chromeVersion = "131.0.6778.86"
+
+var (
+	windowsFonts = []string{
+		"Arial", "Calibri", "Cambria", "Consolas", "Courier New", "Georgia", "Segoe UI", "Tahoma",
+		"Times New Roman", "Trebuchet MS", "Verdana",
+	}
+
+	macFonts = []string{
+		"Arial", "Avenir", "Courier New", "Futura", "Georgia", "Helvetica", "Helvetica Neue", "Menlo",
+		"Monaco", "Times", "Times New Roman", "Verdana",
+	}
+
+	linuxFonts = []string{
+		"DejaVu Sans", "DejaVu Sans Mono", "DejaVu Serif", "Liberation Mono", "Liberation Sans",
+		"Liberation Serif", "Noto Sans", "Ubuntu",
+	}
+
+	androidFonts = []string{
+		"Roboto", "Noto Sans", "Noto Serif", "Droid Sans Mono", "Cutive Mono", "Coming Soon",
+	}
+)
+
+// Profiles is the library of profiles which accounts are assigned. Profiles must never be removed
+// or renamed, as accounts keep theirs across runs, but new ones can be added.
+var Profiles = []*Profile{
+	{
+		Name:           "windows-desktop-1080p",
+		UserAgent:      "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.0.0 Safari/537.36",
+		Platform:       "Win32",
+		OS:             "Windows",
+		OSVersion:      "15.0.0",
+		Architecture:   "x86",
+		BrowserVersion: chromeVersion,
+		Languages:      []string{"en-US", "en"},
+		Screen:         Screen{1920, 1080, 1040, 1920, 945, 1},
+		Cores:          8,
+		Memory:         8,
+		Fonts:          windowsFonts,
+	},
+	{
+		Name:           "windows-laptop",
+		UserAgent:      "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.0.0 Safari/537.36",
+		Platform:       "Win32",
+		OS:             "Windows",
+		OSVersion:      "10.0.0",
+		Architecture:   "x86",
+		BrowserVersion: chromeVersion,
+		Languages:      []string{"en-US", "en"},
+		Screen:         Screen{1536, 864, 824, 1536, 730, 1.25},
+		Cores:          4,
+		Memory:         8,
+		Fonts:          windowsFonts,
+	},
+	{
+		Name:           "windows-desktop-1440p",
+		UserAgent:      "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.0.0 Safari/537.36",
+		Platform:       "Win32",
+		OS:             "Windows",
+		OSVersion:      "15.0.0",
+		Architecture:   "x86",
+		BrowserVersion: chromeVersion,
+		Languages:      []string{"en-GB", "en"},
+		Screen:         Screen{2560, 1440, 1400, 2560, 1305, 1},
+		Cores:          12,
+		Memory:         8,
+		Fonts:          windowsFonts,
+	},
+	{
+		Name:           "macos-laptop",
+		UserAgent:      "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.0.0 Safari/537.36",
+		Platform:       "MacIntel",
+		OS:             "macOS",
+		OSVersion:      "14.5.0",
+		Architecture:   "arm",
+		BrowserVersion: chromeVersion,
+		Languages:      []string{"en-US", "en"},
+		Screen:         Screen{1440, 900, 875, 1440, 789, 2},
+		Cores:          8,
+		Memory:         8,
+		Fonts:          macFonts,
+	},
+	{
+		Name:           "macos-desktop",
+		UserAgent:      "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.0.0 Safari/537.36",
+		Platform:       "MacIntel",
+		OS:             "macOS",
+		OSVersion:      "13.6.0",
+		Architecture:   "arm",
+		BrowserVersion: chromeVersion,
+		Languages:      []string{"en-US", "en"},
+		Screen:         Screen{2240, 1260, 1235, 2240, 1150, 2},
+		Cores:          8,
+		Memory:         8,
+		Fonts:          macFonts,
+	},
+	{
+		Name:           "linux-desktop",
+		UserAgent:      "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.0.0 Safari/537.36",
+		Platform:       "Linux x86_64",
+		OS:             "Linux",
+		OSVersion:      "6.5.0",
+		Architecture:   "x86",
+		BrowserVersion: chromeVersion,
+		Languages:      []string{"en-US", "en"},
+		Screen:         Screen{1920, 1080, 1053, 1920, 966, 1},
+		Cores:          8,
+		Memory:         8,
+		Fonts:          linuxFonts,
+	},
+}
+
+// MobileProfiles is the library of phone profiles which accounts are assigned when emulating mobile
+// devices. Like [Profiles], they must never be removed or renamed.
+var MobileProfiles = []*Profile{
+	{
+		Name:           "android-pixel-8",
+		UserAgent:      "Mozilla/5.0 (Linux; Android 14; Pixel 8) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.0.0 Mobile Safari/537.36",
+		Platform:       "Linux armv8l",
+		OS:             "Android",
+		OSVersion:      "14.0.0",
+		Architecture:   "arm",
+		BrowserVersion: chromeVersion,
+		Languages:      []string{"en-US", "en"},
+		Screen:         Screen{412, 915, 915, 412, 839, 2.625},
+		Cores:          8,
+		Memory:         8,
+		Fonts:          androidFonts,
+		Mobile:         true,
+		TouchPoints:    5,
+		Model:          "Pixel 8",
+	},
+	{
+		Name:           "android-galaxy-s23",
+		UserAgent:      "Mozilla/5.0 (Linux; Android 14; SM-S911B) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/131.0.0.0 Mobile Safari/537.36",
+		Platform:       "Linux armv8l",
+		OS:             "Android",
+		OSVersion:      "14.0.0",
+		Architecture:   "arm",
+		BrowserVersion: chromeVersion,
+		Languages:      []string{"en-US", "en"},
+		Screen:         Screen{360, 780, 780, 360, 668, 3},
+		Cores:          8,
+		Memory:         8,
+		Fonts:          androidFonts,
+		Mobile:         true,
+		TouchPoints:    10,
+		Model:          "SM-S911B",
+	},
+}
+
+// ByName returns the profile with the provided name from [Profiles] or [MobileProfiles].
+func ByName(name string) (*Profile, bool) {
+	for _, profiles := range [][]*Profile{Profiles, MobileProfiles} {
+		for _, p := range profiles {
+			if p.Name == name {
+				return p, true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// Assign returns the profile of the account with the provided email. The profile is derived from the
+// email, so an account is assigned the same profile for as long as [Profiles] doesn't change.
+func Assign(email string) *Profile {
+	return assign(Profiles, email)
+}
+
+// AssignMobile is like [Assign], but returns one of the [MobileProfiles].
+func AssignMobile(email string) *Profile {
+	return assign(MobileProfiles, email)
+}
+
+func assign(profiles []*Profile, email string) *Profile {
+	h := fnv.New32a()
+	h.Write([]byte(email))
+
+	return profiles[h.Sum32()%uint32(len(profiles))]
+}
+
+//go:embed scripts/profile.js
+var profileScript string
+
+// Apply makes the page look like it's running on the profile's device. It must be called before the
+// page navigates anywhere.
+func (p *Profile) Apply(page *rod.Page) error {
+	major := p.BrowserVersion
+	for i, c := range major {
+		if c == '.' {
+			major = major[:i]
+			break
+		}
+	}
+
+	err := proto.NetworkSetUserAgentOverride{
+		UserAgent:      p.UserAgent,
+		AcceptLanguage: acceptLanguage(p.Languages),
+		Platform:       p.Platform,
+		UserAgentMetadata: &proto.EmulationUserAgentMetadata{
+			Brands: []*proto.EmulationUserAgentBrandVersion{
+				{Brand: "Google Chrome", Version: major},
+				{Brand: "Chromium", Version: major},
+				{Brand: "Not_A Brand", Version: "24"},
+			},
+			FullVersion:     p.BrowserVersion,
+			Platform:        p.OS,
+			PlatformVersion: p.OSVersion,
+			Architecture:    p.Architecture,
+			Bitness:         "64",
+			Model:           p.Model,
+			Mobile:          p.Mobile,
+		},
+	}.Call(page)
+	if err != nil {
+		return err
+	}
+
+	err = proto.EmulationSetDeviceMetricsOverride{
+		Width:             p.Screen.ViewportWidth,
+		Height:            p.Screen.ViewportHeight,
+		DeviceScaleFactor: p.Screen.ScaleFactor,
+		ScreenWidth:       &p.Screen.Width,
+		ScreenHeight:      &p.Screen.Height,
+		Mobile:            p.Mobile,
+	}.Call(page)
+	if err != nil {
+		return err
+	}
+
+	if p.TouchPoints > 0 {
+		err = proto.EmulationSetTouchEmulationEnabled{Enabled: true, MaxTouchPoints: &p.TouchPoints}.Call(page)
+		if err != nil {
+			return err
+		}
+	}
+
+	b, err := json.Marshal(map[string]any{
+		"platform":    p.Platform,
+		"languages":   p.Languages,
+		"width":       p.Screen.Width,
+		"height":      p.Screen.Height,
+		"availHeight": p.Screen.AvailHeight,
+		"cores":       p.Cores,
+		"memory":      p.Memory,
+		"fonts":       p.Fonts,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = page.EvalOnNewDocument(fmt.Sprintf("(%s)(%s)", profileScript, b))
+	return err
+}
+
+// acceptLanguage returns the Accept-Language header of the provided languages, in order of
+// preference.
+func acceptLanguage(languages []string) string {
+	header := ""
+	for i, lang := range languages {
+		if i == 0 {
+			header = lang
+			continue
+		}
+		header += fmt.Sprintf(",%s;q=%.1f", lang, max(1-0.1*float64(i), 0.1))
+	}
+
+	return header
+}
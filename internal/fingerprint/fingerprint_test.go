@@ -0,0 +1,72 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fingerprint
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAssign(t *testing.T) {
+	p := Assign("a@example.com")
+	for range 10 {
+		if got := Assign("a@example.com"); got != p {
+			t.Fatalf("Assign() = %q, want %q", got.Name, p.Name)
+		}
+	}
+
+	names := make(map[string]bool)
+	for i := range 100 {
+		names[Assign(strings.Repeat("a", i)+"@example.com").Name] = true
+	}
+	if len(names) < 2 {
+		t.Errorf("Assign() gave %d accounts a single profile", 100)
+	}
+}
+
+func TestProfiles(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, p := range append(Profiles[:len(Profiles):len(Profiles)], MobileProfiles...) {
+		if seen[p.Name] {
+			t.Errorf("duplicate profile %q", p.Name)
+		}
+		seen[p.Name] = true
+
+		if got, ok := ByName(p.Name); !ok || got != p {
+			t.Errorf("ByName(%q) = %v, %t", p.Name, got, ok)
+		}
+
+		// navigator.deviceMemory is capped at 8 GiB.
+		if p.Memory > 8 {
+			t.Errorf("%s: memory = %d, want at most 8", p.Name, p.Memory)
+		}
+		if p.Screen.AvailHeight > p.Screen.Height || p.Screen.ViewportWidth > p.Screen.Width {
+			t.Errorf("%s: viewport or available screen larger than the screen", p.Name)
+		}
+		if p.Mobile != (p.TouchPoints > 0) || p.Mobile != strings.Contains(p.UserAgent, "Mobile") {
+			t.Errorf("%s: mobile = %t, but it has %d touch points and user agent %q", p.Name, p.Mobile, p.TouchPoints, p.UserAgent)
+		}
+	}
+
+	if _, ok := ByName("unknown"); ok {
+		t.Error("ByName(\"unknown\") = true")
+	}
+}
+
+func TestAcceptLanguage(t *testing.T) {
+	if got, want := acceptLanguage([]string{"en-US", "en", "de"}), "en-US,en;q=0.9,de;q=0.8"; got != want {
+		t.Errorf("acceptLanguage() = %q, want %q", got, want)
+	}
+}
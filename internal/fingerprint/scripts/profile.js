@@ -0,0 +1,41 @@
+(profile) => {
+  const define = (target, values) => {
+    for (const [key, value] of Object.entries(values)) {
+      Object.defineProperty(target, key, { get: () => value, configurable: true });
+    }
+  };
+
+  define(Navigator.prototype, {
+    platform: profile.platform,
+    language: profile.languages[0],
+    languages: Object.freeze([...profile.languages]),
+    hardwareConcurrency: profile.cores,
+    deviceMemory: profile.memory,
+  });
+
+  define(Screen.prototype, {
+    width: profile.width,
+    height: profile.height,
+    availWidth: profile.width,
+    availHeight: profile.availHeight,
+    colorDepth: 24,
+    pixelDepth: 24,
+  });
+
+  // fonts are detected by checking whether they're available, so only the profile's fonts (and the
+  // generic families) are reported as such.
+  if (typeof FontFaceSet !== 'undefined') {
+    const generic = ['serif', 'sans-serif', 'monospace', 'cursive', 'fantasy', 'system-ui'];
+    const installed = new Set([...profile.fonts, ...generic].map((font) => font.toLowerCase()));
+    const check = FontFaceSet.prototype.check;
+
+    FontFaceSet.prototype.check = function (font, text) {
+      const match = /^(?:.*\s)?[\d.]+(?:px|pt|em|rem|%)(?:\/\S+)?\s+(.+)$/.exec(font);
+      if (match !== null) {
+        const families = match[1].split(',').map((family) => family.trim().replace(/^["']|["']$/g, '').toLowerCase());
+        if (!families.some((family) => installed.has(family))) return false;
+      }
+      return check.call(this, font, text);
+    };
+  }
+}
@@ -0,0 +1,171 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package fixture records snapshots of the pages visited during a real scraping session and
+// replays them in a browser, so that page actions can be tested against canned pages without
+// live Apollo accounts.
+package fixture
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/go-rod/rod"
+)
+
+// manifestFile is the name of the file listing the fixtures in a directory.
+const manifestFile = "fixtures.json"
+
+// ErrorFixtureNotFound is returned when a fixture isn't listed in its directory's manifest.
+var ErrorFixtureNotFound = errors.New("fixture not found")
+
+// Fixture describes a snapshot of a page.
+type Fixture struct {
+	Name   string    `json:"name"`
+	Action string    `json:"action"`
+	URL    string    `json:"url"`
+	UI     string    `json:"ui,omitempty"`
+	Time   time.Time `json:"time"`
+	File   string    `json:"file"`
+}
+
+// loadManifest reads the manifest in the given directory. A missing manifest yields no fixtures.
+func loadManifest(dir string) ([]*Fixture, error) {
+	b, err := os.ReadFile(filepath.Join(dir, manifestFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	var fixtures []*Fixture
+	return fixtures, json.Unmarshal(b, &fixtures)
+}
+
+// saveManifest atomically writes the manifest to the given directory.
+func saveManifest(dir string, fixtures []*Fixture) error {
+	b, err := json.MarshalIndent(fixtures, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := filepath.Join(dir, manifestFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
+
+// Load returns the fixture with the given name from the directory, along with its HTML.
+func Load(dir, name string) (*Fixture, []byte, error) {
+	fixtures, err := loadManifest(dir)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, f := range fixtures {
+		if f.Name != name {
+			continue
+		}
+
+		html, err := os.ReadFile(filepath.Join(dir, f.File))
+		return f, html, err
+	}
+
+	return nil, nil, fmt.Errorf("%w: %s", ErrorFixtureNotFound, name)
+}
+
+var (
+	scriptTag = regexp.MustCompile(`(?is)<script\b.*?</script\s*>`)
+	email     = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+)
+
+// sanitize removes the scripts from a page's HTML, so that it stays static when replayed, and
+// replaces the email addresses in it, so that fixtures don't leak leads' contact details.
+func sanitize(html string) string {
+	html = scriptTag.ReplaceAllString(html, "")
+	return email.ReplaceAllString(html, "lead@example.com")
+}
+
+// Recorder saves snapshots of pages as fixtures in a directory.
+type Recorder struct {
+	dir      string
+	mu       sync.Mutex
+	fixtures []*Fixture
+}
+
+// NewRecorder returns a [*Recorder] that saves fixtures to the given directory, creating it if
+// necessary. Fixtures already in the directory are kept.
+func NewRecorder(dir string) (*Recorder, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	fixtures, err := loadManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Recorder{dir: dir, fixtures: fixtures}, nil
+}
+
+// Record saves a snapshot of the page's current DOM as a fixture of the given action, which is
+// named after the action and the number of fixtures already recorded for it. The UI variant
+// that the page was rendered in is recorded alongside it.
+func (r *Recorder) Record(page *rod.Page, action, ui string) (*Fixture, error) {
+	info, err := page.Info()
+	if err != nil {
+		return nil, err
+	}
+
+	html, err := page.HTML()
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := 1
+	for _, f := range r.fixtures {
+		if f.Action == action {
+			n++
+		}
+	}
+
+	name := fmt.Sprintf("%s-%03d", action, n)
+	f := &Fixture{
+		Name:   name,
+		Action: action,
+		URL:    info.URL,
+		UI:     ui,
+		Time:   time.Now(),
+		File:   name + ".html",
+	}
+
+	if err := os.WriteFile(filepath.Join(r.dir, f.File), []byte(sanitize(html)), 0o644); err != nil {
+		return nil, err
+	}
+
+	r.fixtures = append(r.fixtures, f)
+	return f, saveManifest(r.dir, r.fixtures)
+}
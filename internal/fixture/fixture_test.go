@@ -0,0 +1,59 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fixture
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSanitize(t *testing.T) {
+	html := `<html><head><script src="app.js"></script><SCRIPT>
+track("jane@acme.com")
+</SCRIPT></head><body><span>jane.doe+work@acme.co.uk</span></body></html>`
+
+	want := `<html><head></head><body><span>lead@example.com</span></body></html>`
+	if got := sanitize(html); got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	fixtures := []*Fixture{{Name: "people-001", Action: "people", File: "people-001.html"}}
+	if err := saveManifest(dir, fixtures); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, "people-001.html"), []byte("<html></html>"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	f, html, err := Load(dir, "people-001")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if f.Action != "people" || string(html) != "<html></html>" {
+		t.Errorf("unexpected fixture %+v with html %q", f, html)
+	}
+
+	if _, _, err := Load(dir, "people-002"); !errors.Is(err, ErrorFixtureNotFound) {
+		t.Errorf("got error %v, want %v", err, ErrorFixtureNotFound)
+	}
+}
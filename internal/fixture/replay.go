@@ -0,0 +1,68 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package fixture
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+
+	"github.com/go-rod/rod"
+	"github.com/go-rod/rod/lib/proto"
+)
+
+// Replay navigates the page to the URL of the fixture with the given name from the directory
+// and serves the fixture's HTML in place of the real page. Every other request made by the page
+// is blocked, so nothing reaches Apollo. The returned function stops replaying.
+func Replay(page *rod.Page, dir, name string) (*Fixture, func() error, error) {
+	f, html, err := Load(dir, name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	target, err := url.Parse(f.URL)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	router := page.HijackRequests()
+	err = router.Add("*", "", func(ctx *rod.Hijack) {
+		u := ctx.Request.URL()
+		if ctx.Request.Type() == proto.NetworkResourceTypeDocument &&
+			u.Host == target.Host && u.Path == target.Path {
+			ctx.Response.
+				SetHeader("Content-Type", "text/html; charset=utf-8").
+				SetBody(html).
+				Payload().ResponseCode = http.StatusOK
+			return
+		}
+
+		ctx.Response.Fail(proto.NetworkErrorReasonBlockedByClient)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	go router.Run()
+
+	err = rod.Try(func() {
+		page.MustNavigate(f.URL).MustWaitLoad()
+	})
+	if err != nil {
+		return nil, nil, errors.Join(err, router.Stop())
+	}
+
+	return f, router.Stop, nil
+}
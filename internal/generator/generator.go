@@ -0,0 +1,112 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package generator generates the input file of a scrape from a list of searches and a pool of
+// accounts, distributing the searches across the accounts so that none of them has to save more
+// leads than it can.
+package generator
+
+import (
+	"fmt"
+
+	"github.com/devsheke/scrapollo/internal/models"
+)
+
+// Search is a search whose leads are to be saved to a list.
+type Search struct {
+	URL  string `json:"url"  csv:"url"`
+	List string `json:"list" csv:"list"`
+
+	// Leads is the number of leads to save from the search.
+	Leads int `json:"leads" csv:"leads"`
+}
+
+// Options configures how many leads each account is given.
+type Options struct {
+	// DailyLimit is the number of leads that an account saves per day at most.
+	DailyLimit int
+
+	// Days is the number of days that the accounts have to save their leads in.
+	Days int
+}
+
+// capacity returns the number of leads that the account can be given, which is also capped by the
+// account's credits if they're known.
+func (o Options) capacity(acc *models.Account) int {
+	capacity := o.DailyLimit * max(o.Days, 1)
+	if acc.Credits > 0 {
+		capacity = min(capacity, acc.Credits)
+	}
+
+	return capacity
+}
+
+// Plan is the result of distributing searches across accounts.
+type Plan struct {
+	// Accounts are the accounts of the pool that have been given a search, which make up the input
+	// file of the scrape.
+	Accounts []*models.Account
+
+	// Unassigned are the searches (or what's left of them) that no account could be given.
+	Unassigned []*Search
+
+	// Shared are the searches that have been split across several accounts. Apollo shows the same
+	// leads to each of them, so their leads should be deduplicated across accounts.
+	Shared []*Search
+}
+
+// Generate distributes the searches across the pool of accounts in order. Each account is given a
+// single search (since the daily limit applies to each row of the input file rather than to each
+// account) and as many of its leads as it can save, so a search may be split across several
+// accounts. Blacklisted accounts are skipped.
+func Generate(searches []*Search, pool []*models.Account, opts Options) (*Plan, error) {
+	if opts.DailyLimit <= 0 {
+		return nil, fmt.Errorf("invalid daily limit: %d", opts.DailyLimit)
+	}
+
+	plan := &Plan{}
+	next := 0
+	for _, search := range searches {
+		if search.URL == "" || search.List == "" || search.Leads <= 0 {
+			return nil, fmt.Errorf("invalid search %+v: searches need a url, a list and a number of leads", *search)
+		}
+
+		needed, accounts := search.Leads, 0
+		for ; needed > 0 && next < len(pool); next++ {
+			acc := pool[next]
+			capacity := opts.capacity(acc)
+			if acc.Blacklisted != "" || capacity <= 0 {
+				continue
+			}
+
+			job := *acc
+			job.URL, job.List = search.URL, search.List
+			job.Target, job.Saved = min(needed, capacity), 0
+
+			plan.Accounts = append(plan.Accounts, &job)
+			needed -= job.Target
+			accounts++
+		}
+
+		if accounts > 1 {
+			plan.Shared = append(plan.Shared, search)
+		}
+
+		if needed > 0 {
+			plan.Unassigned = append(plan.Unassigned, &Search{URL: search.URL, List: search.List, Leads: needed})
+		}
+	}
+
+	return plan, nil
+}
@@ -0,0 +1,73 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package generator
+
+import (
+	"testing"
+
+	"github.com/devsheke/scrapollo/internal/models"
+)
+
+func TestGenerate(t *testing.T) {
+	searches := []*Search{
+		{URL: "https://app.apollo.io/#/people?a", List: "a", Leads: 700},
+		{URL: "https://app.apollo.io/#/people?b", List: "b", Leads: 300},
+		{URL: "https://app.apollo.io/#/people?c", List: "c", Leads: 100},
+	}
+
+	pool := []*models.Account{
+		{Email: "1@example.com"},
+		{Email: "2@example.com", Blacklisted: "banned"},
+		{Email: "3@example.com", Credits: 250},
+		{Email: "4@example.com"},
+	}
+
+	plan, err := Generate(searches, pool, Options{DailyLimit: 500})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		email, list string
+		target      int
+	}{
+		{"1@example.com", "a", 500},
+		{"3@example.com", "a", 200},
+		{"4@example.com", "b", 300},
+	}
+
+	if len(plan.Accounts) != len(want) {
+		t.Fatalf("got %d accounts, want %d", len(plan.Accounts), len(want))
+	}
+
+	for i, w := range want {
+		if acc := plan.Accounts[i]; acc.Email != w.email || acc.List != w.list || acc.Target != w.target {
+			t.Errorf("account %d: got %s saving %d leads to %q, want %s saving %d to %q",
+				i, acc.Email, acc.Target, acc.List, w.email, w.target, w.list)
+		}
+	}
+
+	if len(plan.Shared) != 1 || plan.Shared[0].List != "a" {
+		t.Errorf("unexpected shared searches: %+v", plan.Shared)
+	}
+
+	if len(plan.Unassigned) != 1 || plan.Unassigned[0].List != "c" || plan.Unassigned[0].Leads != 100 {
+		t.Errorf("unexpected unassigned searches: %+v", plan.Unassigned)
+	}
+
+	if pool[0].List != "" {
+		t.Error("expected the pool's accounts to be left untouched")
+	}
+}
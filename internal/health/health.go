@@ -0,0 +1,237 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package health
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/runner"
+	"github.com/rs/zerolog/log"
+)
+
+// Server is an HTTP server that reports the liveness and progress of a [runner.Runner],
+// allowing supervisors (e.g. Kubernetes or systemd) to restart a wedged scraper, and which lets
+// operators blacklist accounts.
+type Server struct {
+	server     *http.Server
+	mux        *http.ServeMux
+	status     func() runner.Status
+	blacklist  func(email, reason string)
+	stallAfter time.Duration
+	token      string
+}
+
+// ServerOpt is a function that configures a [Server].
+type ServerOpt func(*Server)
+
+// Token is a [ServerOpt] func that requires requests to the server's privileged endpoints (e.g.
+// blacklisting accounts) to carry the provided token as a bearer token.
+func Token(token string) ServerOpt {
+	return func(s *Server) {
+		s.token = token
+	}
+}
+
+// NewServer returns a [*Server] listening on the provided address. The scraper is reported as
+// unhealthy once it has been running for longer than stallAfter without making any progress.
+//
+// The endpoint that blacklists accounts is only served if the server requires a [Token], or if it
+// listens on a loopback address.
+func NewServer(addr string, r *runner.Runner, stallAfter time.Duration, opts ...ServerOpt) *Server {
+	s := &Server{status: r.Status, blacklist: r.Blacklist, stallAfter: stallAfter}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	mux := http.NewServeMux()
+	s.mux = mux
+	s.server = &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	mux.HandleFunc("GET /healthz", s.handleHealth)
+	mux.HandleFunc("GET /status", s.handleStatus)
+
+	if s.Protected() {
+		mux.Handle("POST /accounts/{email}/blacklist", s.protect(http.HandlerFunc(s.handleBlacklist)))
+	} else {
+		log.Warn().
+			Str("addr", addr).
+			Msg("not serving the blacklist endpoint on a non-loopback address without a health token")
+	}
+
+	return s
+}
+
+// Protected returns true if the server's privileged endpoints can't be called by other hosts
+// without a token, i.e. if it requires a [Token] or only listens on a loopback address.
+func (s *Server) Protected() bool {
+	return s.token != "" || IsLoopback(s.server.Addr)
+}
+
+// IsLoopback returns true if the provided address (e.g. 'localhost:8080') is a loopback address.
+// Addresses without a host (e.g. ':8080') listen on all interfaces, so they aren't.
+func IsLoopback(addr string) bool {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return false
+	}
+
+	return loopbackHost(host)
+}
+
+// loopbackHost returns true if the provided host (without a port) is a loopback address.
+func loopbackHost(host string) bool {
+	if host == "localhost" {
+		return true
+	}
+
+	ip := net.ParseIP(strings.Trim(host, "[]"))
+	return ip != nil && ip.IsLoopback()
+}
+
+// requestedLoopback returns true if the request's Host header names a loopback address. Requests
+// from pages that rebound their own domain to a loopback address name that domain instead.
+func requestedLoopback(r *http.Request) bool {
+	host, _, err := net.SplitHostPort(r.Host)
+	if err != nil {
+		host = r.Host
+	}
+
+	return loopbackHost(host)
+}
+
+// protect wraps the handler of a privileged endpoint: requests are rejected unless they carry the
+// server's token (if any), and requests from other origins are always rejected, so that a web page
+// visited on the host can't call the endpoint. Without a token, requests must also be addressed to
+// a loopback host, so that pages can't get around the origin check through DNS rebinding.
+func (s *Server) protect(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !sameOrigin(r) {
+			writeJson(w, http.StatusForbidden, map[string]any{"error": "cross-origin requests are not allowed"})
+			return
+		}
+
+		if s.token == "" && !requestedLoopback(r) {
+			writeJson(w, http.StatusForbidden, map[string]any{"error": "requests must be addressed to a loopback host"})
+			return
+		}
+
+		if s.token != "" {
+			token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(s.token)) != 1 {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				writeJson(w, http.StatusUnauthorized, map[string]any{"error": "invalid or missing token"})
+				return
+			}
+		}
+
+		h.ServeHTTP(w, r)
+	})
+}
+
+// sameOrigin returns false if the request was sent by a browser from another origin than the server.
+// Requests without an Origin header (e.g. sent by curl) are allowed.
+func sameOrigin(r *http.Request) bool {
+	if r.Header.Get("Sec-Fetch-Site") == "cross-site" {
+		return false
+	}
+
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	u, err := url.Parse(origin)
+	return err == nil && u.Host == r.Host
+}
+
+// Handle serves the handler at the given pattern alongside the health endpoints, e.g. the lead
+// feed. It must be called before [Server.Start].
+func (s *Server) Handle(pattern string, h http.Handler) {
+	s.mux.Handle(pattern, h)
+}
+
+// HandleProtected serves the handler at the given pattern like [Server.Handle], but protected like
+// the server's privileged endpoints (see [Token]), e.g. the lead feed, which exposes personal data. It
+// must be called before [Server.Start].
+func (s *Server) HandleProtected(pattern string, h http.Handler) {
+	s.mux.Handle(pattern, s.protect(h))
+}
+
+// Start starts serving requests in the background.
+func (s *Server) Start() {
+	log.Info().Str("addr", s.server.Addr).Msg("starting health server")
+
+	go func() {
+		if err := s.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error().Err(err).Msg("health server stopped unexpectedly")
+		}
+	}()
+}
+
+// Stop gracefully shuts the server down.
+func (s *Server) Stop(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
+}
+
+func (s *Server) stalled(status runner.Status) bool {
+	if status.State != runner.StateRunning || s.stallAfter <= 0 {
+		return false
+	}
+
+	return time.Since(status.LastProgress) > s.stallAfter
+}
+
+func writeJson(w http.ResponseWriter, code int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Debug().Err(err).Msg("failed to write health response")
+	}
+}
+
+func (s *Server) handleHealth(w http.ResponseWriter, _ *http.Request) {
+	status := s.status()
+	if s.stalled(status) {
+		writeJson(w, http.StatusServiceUnavailable, map[string]any{
+			"healthy":       false,
+			"current-job":   status.CurrentJob,
+			"last-progress": status.LastProgress,
+		})
+		return
+	}
+
+	writeJson(w, http.StatusOK, map[string]any{"healthy": true})
+}
+
+func (s *Server) handleStatus(w http.ResponseWriter, _ *http.Request) {
+	writeJson(w, http.StatusOK, s.status())
+}
+
+// handleBlacklist blacklists the account with the email in the request's path, for the reason in its
+// (optional) 'reason' form value.
+func (s *Server) handleBlacklist(w http.ResponseWriter, r *http.Request) {
+	email := r.PathValue("email")
+	s.blacklist(email, r.FormValue("reason"))
+	writeJson(w, http.StatusAccepted, map[string]any{"blacklisted": email})
+}
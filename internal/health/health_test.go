@@ -0,0 +1,143 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package health
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/runner"
+)
+
+func testServer(opts ...ServerOpt) (*Server, *[]string) {
+	var blacklisted []string
+	s := &Server{
+		status:    func() runner.Status { return runner.Status{} },
+		blacklist: func(email, _ string) { blacklisted = append(blacklisted, email) },
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s, &blacklisted
+}
+
+func TestIsLoopback(t *testing.T) {
+	for addr, want := range map[string]bool{
+		"localhost:8080": true,
+		"127.0.0.1:8080": true,
+		"[::1]:8080":     true,
+		":8080":          false,
+		"0.0.0.0:8080":   false,
+		"10.0.0.5:8080":  false,
+		"localhost":      false,
+	} {
+		if got := IsLoopback(addr); got != want {
+			t.Errorf("IsLoopback(%q) = %t, want %t", addr, got, want)
+		}
+	}
+}
+
+func TestProtectBlacklist(t *testing.T) {
+	s, blacklisted := testServer(Token("secret"))
+	h := s.protect(http.HandlerFunc(s.handleBlacklist))
+
+	for _, tc := range []struct {
+		name    string
+		headers map[string]string
+		want    int
+	}{
+		{"no token", nil, http.StatusUnauthorized},
+		{"wrong token", map[string]string{"Authorization": "Bearer nope"}, http.StatusUnauthorized},
+		{"cross origin", map[string]string{"Authorization": "Bearer secret", "Origin": "https://evil.example"}, http.StatusForbidden},
+		{"cross site", map[string]string{"Authorization": "Bearer secret", "Sec-Fetch-Site": "cross-site"}, http.StatusForbidden},
+		{"token", map[string]string{"Authorization": "Bearer secret"}, http.StatusAccepted},
+	} {
+		req := httptest.NewRequest(http.MethodPost, "http://localhost:8080/accounts/jane@example.com/blacklist", nil)
+		req.SetPathValue("email", "jane@example.com")
+		for k, v := range tc.headers {
+			req.Header.Set(k, v)
+		}
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != tc.want {
+			t.Errorf("%s: got status %d, want %d", tc.name, rec.Code, tc.want)
+		}
+	}
+
+	if len(*blacklisted) != 1 {
+		t.Errorf("expected only the authorized request to blacklist the account, got %d", len(*blacklisted))
+	}
+}
+
+func TestProtectRejectsRebinding(t *testing.T) {
+	s, blacklisted := testServer()
+	h := s.protect(http.HandlerFunc(s.handleBlacklist))
+
+	for host, want := range map[string]int{
+		"evil.example:8080": http.StatusForbidden,
+		"evil.example":      http.StatusForbidden,
+		"localhost:8080":    http.StatusAccepted,
+		"127.0.0.1:8080":    http.StatusAccepted,
+		"[::1]:8080":        http.StatusAccepted,
+	} {
+		// a page on a rebound domain is same-origin with the server as far as the browser is concerned.
+		req := httptest.NewRequest(http.MethodPost, "http://"+host+"/accounts/jane@example.com/blacklist", nil)
+		req.Host = host
+		req.Header.Set("Origin", "http://"+host)
+		req.SetPathValue("email", "jane@example.com")
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != want {
+			t.Errorf("%s: got status %d, want %d", host, rec.Code, want)
+		}
+	}
+
+	if len(*blacklisted) != 3 {
+		t.Errorf("expected only the loopback requests to blacklist the account, got %d", len(*blacklisted))
+	}
+}
+
+func TestBlacklistNotServedWithoutToken(t *testing.T) {
+	for addr, served := range map[string]bool{"localhost:0": true, ":0": false} {
+		s := NewServer(addr, nil, time.Minute)
+		_, pattern := s.mux.Handler(httptest.NewRequest(http.MethodPost, "/accounts/jane@example.com/blacklist", nil))
+		if got := pattern != ""; got != served {
+			t.Errorf("%s: blacklist endpoint served: %t, want %t", addr, got, served)
+		}
+	}
+}
+
+func TestHandleProtectedRejectsRebinding(t *testing.T) {
+	s := NewServer("localhost:0", nil, time.Minute)
+	s.HandleProtected("GET /leads", http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	for host, want := range map[string]int{"evil.example:8080": http.StatusForbidden, "localhost:8080": http.StatusOK} {
+		req := httptest.NewRequest(http.MethodGet, "http://"+host+"/leads", nil)
+		req.Header.Set("Origin", "http://"+host)
+
+		rec := httptest.NewRecorder()
+		s.mux.ServeHTTP(rec, req)
+		if rec.Code != want {
+			t.Errorf("%s: got status %d, want %d", host, rec.Code, want)
+		}
+	}
+}
@@ -0,0 +1,117 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package io
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/devsheke/scrapollo/internal/models"
+)
+
+// ErrorWriterClosed is returned when leads are written to an [AsyncLeadWriter] which has been closed.
+var ErrorWriterClosed = errors.New("lead writer is closed")
+
+// AsyncLeadWriter is an implementation of a [LeadWriter] that hands leads over to another
+// [LeadWriter] in the background, so that slow sinks (e.g. databases or webhooks) don't hold up the
+// caller. Leads are queued in order, up to the size of the queue, beyond which writing blocks until
+// the underlying writer catches up. Since its errors can't be returned to the caller, they're passed
+// to the writer's error handler instead.
+type AsyncLeadWriter struct {
+	writer  LeadWriter
+	onError func(error)
+
+	mu      sync.RWMutex
+	closed  bool
+	queue   chan []*models.Lead
+	pending sync.WaitGroup
+	done    chan struct{}
+}
+
+// NewAsyncLeadWriter returns an [AsyncLeadWriter] handing leads over to the provided writer, with a
+// queue of the given number of batches of leads. The errors of the writer are passed to onError,
+// which may be nil.
+func NewAsyncLeadWriter(writer LeadWriter, size int, onError func(error)) *AsyncLeadWriter {
+	w := &AsyncLeadWriter{
+		writer:  writer,
+		onError: onError,
+		queue:   make(chan []*models.Lead, max(size, 1)),
+		done:    make(chan struct{}),
+	}
+	go w.run()
+
+	return w
+}
+
+func (w *AsyncLeadWriter) run() {
+	defer close(w.done)
+
+	for leads := range w.queue {
+		if err := w.writer.WriteLeads(leads); err != nil && w.onError != nil {
+			w.onError(err)
+		}
+		w.pending.Done()
+	}
+}
+
+func (w *AsyncLeadWriter) WriteLead(lead *models.Lead) error {
+	return w.WriteLeads([]*models.Lead{lead})
+}
+
+// WriteLeads queues the leads to be written, blocking while the queue is full.
+func (w *AsyncLeadWriter) WriteLeads(leads []*models.Lead) error {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.closed {
+		return ErrorWriterClosed
+	}
+
+	w.pending.Add(1)
+	w.queue <- leads
+
+	return nil
+}
+
+// Queued returns the number of batches of leads waiting to be written.
+func (w *AsyncLeadWriter) Queued() int {
+	return len(w.queue)
+}
+
+// Flush waits for the leads queued so far to be written.
+func (w *AsyncLeadWriter) Flush() {
+	w.pending.Wait()
+}
+
+// Close waits for the queued leads to be written and stops the writer. Leads can't be written to it
+// afterwards.
+func (w *AsyncLeadWriter) Close() {
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.queue)
+	}
+	w.mu.Unlock()
+
+	<-w.done
+}
+
+// Flush waits for the leads written to the provided writer to be written, if it writes them in the
+// background (see [AsyncLeadWriter]).
+func Flush(writer LeadWriter) {
+	if w, ok := writer.(*AsyncLeadWriter); ok {
+		w.Flush()
+	}
+}
@@ -0,0 +1,84 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package io
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/models"
+)
+
+// blockingWriter is a [LeadWriter] which doesn't write leads until it's released.
+type blockingWriter struct {
+	release chan struct{}
+	mu      sync.Mutex
+	leads   []*models.Lead
+}
+
+func (w *blockingWriter) WriteLead(lead *models.Lead) error {
+	return w.WriteLeads([]*models.Lead{lead})
+}
+
+func (w *blockingWriter) WriteLeads(leads []*models.Lead) error {
+	<-w.release
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.leads = append(w.leads, leads...)
+
+	return errors.New("sink is down")
+}
+
+func TestAsyncLeadWriter(t *testing.T) {
+	sink := &blockingWriter{release: make(chan struct{})}
+
+	var errs int
+	w := NewAsyncLeadWriter(sink, 2, func(error) { errs++ })
+
+	// the first batch is taken off the queue by the writer, and the next two fill it up.
+	leads := benchLeads()
+	for i := range 3 {
+		if err := w.WriteLeads(leads[i : i+1]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	written := make(chan struct{})
+	go func() {
+		_ = w.WriteLeads(leads[3:4])
+		close(written)
+	}()
+
+	select {
+	case <-written:
+		t.Fatal("writing to a full queue didn't block")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(sink.release)
+	<-written
+	w.Close()
+
+	if len(sink.leads) != 4 || sink.leads[3] != leads[3] || errs != 4 {
+		t.Errorf("wrote %d leads with %d errors; want 4 leads in order with 4 errors", len(sink.leads), errs)
+	}
+
+	if err := w.WriteLeads(leads); !errors.Is(err, ErrorWriterClosed) {
+		t.Errorf("writing to a closed writer returned %v; want %v", err, ErrorWriterClosed)
+	}
+}
@@ -15,11 +15,15 @@
 package io
 
 import (
+	"bytes"
+	"encoding/csv"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/gocarina/gocsv"
 )
@@ -32,19 +36,23 @@ type FileFormat string
 
 // Supported file formats.
 const (
-	CsvFileFormat  FileFormat = ".json"
-	JsonFileFormat FileFormat = ".csv"
+	CsvFileFormat  FileFormat = ".csv"
+	JsonFileFormat FileFormat = ".json"
+	XlsxFileFormat FileFormat = ".xlsx"
 )
 
-func saveJson(file *os.File, records any) error {
-	b, err := json.MarshalIndent(records, "", "\t")
-	if err != nil {
-		return err
-	}
+// Stdio is the file name which stands for the standard input or output.
+const Stdio = "-"
 
-	_, err = file.Write(b)
-
-	return err
+// ParseFileFormat returns the [FileFormat] with the provided name, e.g. 'csv' or '.csv'. If the
+// format is not supported, [ErrorUnsupportedFileFormat] is returned.
+func ParseFileFormat(name string) (FileFormat, error) {
+	switch format := FileFormat("." + strings.TrimPrefix(strings.ToLower(name), ".")); format {
+	case CsvFileFormat, JsonFileFormat, XlsxFileFormat:
+		return format, nil
+	default:
+		return "", fmt.Errorf("%w: %q", ErrorUnsupportedFileFormat, name)
+	}
 }
 
 // SaveRecords writes the provided records to the given file. The desired [FileFormat]
@@ -57,25 +65,57 @@ func SaveRecords(file string, records any) error {
 	}
 	defer f.Close()
 
-	switch FileFormat(filepath.Ext(file)) {
+	return SaveRecordsTo(f, FileFormat(filepath.Ext(file)), records)
+}
+
+// SaveRecordsTo writes the provided records to w in the given [FileFormat].
+func SaveRecordsTo(w io.Writer, format FileFormat, records any) error {
+	switch format {
 	case CsvFileFormat:
-		return gocsv.MarshalFile(records, f)
+		return gocsv.Marshal(records, w)
 
 	case JsonFileFormat:
-		return saveJson(f, records)
+		b, err := json.MarshalIndent(records, "", "\t")
+		if err != nil {
+			return err
+		}
+
+		_, err = w.Write(b)
+		return err
+
+	case XlsxFileFormat:
+		return saveXlsx(w, records)
 
 	default:
 		return ErrorUnsupportedFileFormat
 	}
 }
 
-func readJson(file *os.File, v any) error {
-	b, err := io.ReadAll(file)
-	if err != nil {
-		return err
-	}
+// ReadRecordsFrom reads records in the given [FileFormat] from r and stores them in the value
+// pointed to by v.
+func ReadRecordsFrom(r io.Reader, format FileFormat, v any) error {
+	switch format {
+	case CsvFileFormat:
+		return gocsv.Unmarshal(r, v)
+
+	case JsonFileFormat:
+		b, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		return json.Unmarshal(b, v)
+
+	case XlsxFileFormat:
+		// xlsx files are zip archives, which can only be read with random access.
+		b, err := io.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		return readXlsx(bytes.NewReader(b), int64(len(b)), v)
 
-	return json.Unmarshal(b, v)
+	default:
+		return ErrorUnsupportedFileFormat
+	}
 }
 
 // ReadRecords reads the records from a file and stores them in the value pointed to by v.
@@ -88,14 +128,100 @@ func ReadRecords(file string, v any) error {
 	}
 	defer f.Close()
 
+	switch format := FileFormat(filepath.Ext(file)); format {
+	case XlsxFileFormat:
+		info, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		return readXlsx(f, info.Size(), v)
+
+	default:
+		return ReadRecordsFrom(f, format, v)
+	}
+}
+
+// ReadColumns returns the columns of the records in a file, i.e. the header of CSV and XLSX files
+// or the keys of JSON objects, in the order they first appear in. If the [FileFormat] from the
+// provided file's extension is unsupported, [ErrorUnsupportedFileFormat] is returned.
+func ReadColumns(file string) ([]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
 	switch FileFormat(filepath.Ext(file)) {
 	case CsvFileFormat:
-		return gocsv.UnmarshalFile(f, v)
+		header, err := csv.NewReader(f).Read()
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return header, err
 
 	case JsonFileFormat:
-		return readJson(f, v)
+		var records []json.RawMessage
+		if err := json.NewDecoder(f).Decode(&records); err != nil {
+			return nil, err
+		}
+
+		var columns []string
+		seen := make(map[string]bool)
+		for _, record := range records {
+			keys, err := objectKeys(record)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, key := range keys {
+				if !seen[key] {
+					seen[key] = true
+					columns = append(columns, key)
+				}
+			}
+		}
+
+		return columns, nil
+
+	case XlsxFileFormat:
+		info, err := f.Stat()
+		if err != nil {
+			return nil, err
+		}
+
+		rows, err := readXlsxRows(f, info.Size())
+		if err != nil || len(rows) == 0 {
+			return nil, err
+		}
+		return rows[0], nil
 
 	default:
-		return ErrorUnsupportedFileFormat
+		return nil, ErrorUnsupportedFileFormat
 	}
 }
+
+// objectKeys returns the keys of a JSON object in the order they appear in.
+func objectKeys(b []byte) ([]string, error) {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	if t, err := dec.Token(); err != nil {
+		return nil, err
+	} else if t != json.Delim('{') {
+		return nil, fmt.Errorf("expected a JSON object, got %v", t)
+	}
+
+	var keys []string
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, t.(string))
+
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+	}
+
+	return keys, nil
+}
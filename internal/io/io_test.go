@@ -0,0 +1,62 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package io
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/devsheke/scrapollo/internal/models"
+)
+
+func TestRecordsStream(t *testing.T) {
+	accounts := []*models.Account{
+		{Email: "a@example.com", URL: "https://app.apollo.io/#/people?page=1", List: "a", Target: 100},
+		{Email: "b@example.com", List: "b, c", Target: 50},
+	}
+
+	for _, name := range []string{"csv", ".json", "XLSX"} {
+		format, err := ParseFileFormat(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var buf bytes.Buffer
+		if err := SaveRecordsTo(&buf, format, accounts); err != nil {
+			t.Fatalf("%s: %s", format, err)
+		}
+
+		var read []*models.Account
+		if err := ReadRecordsFrom(&buf, format, &read); err != nil {
+			t.Fatalf("%s: %s", format, err)
+		}
+
+		if len(read) != len(accounts) {
+			t.Fatalf("%s: read %d accounts; want %d", format, len(read), len(accounts))
+		}
+
+		for i, acc := range read {
+			if acc.Email != accounts[i].Email || acc.URL != accounts[i].URL || acc.List != accounts[i].List ||
+				acc.Target != accounts[i].Target {
+				t.Errorf("%s: account %d = %+v; want %+v", format, i, acc, accounts[i])
+			}
+		}
+	}
+
+	if _, err := ParseFileFormat("txt"); !errors.Is(err, ErrorUnsupportedFileFormat) {
+		t.Errorf("got error %v for an unsupported format", err)
+	}
+}
@@ -15,8 +15,18 @@
 package io
 
 import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
+	"io"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"unicode"
 
 	"github.com/devsheke/scrapollo/internal/models"
 	"github.com/gocarina/gocsv"
@@ -33,86 +43,191 @@ type LeadWriter interface {
 }
 
 // CsvLeadWriter is an implementation of a [LeadWriter] that writes lead data
-// to a CSV file.
+// to a CSV file. The header is only written to new files.
 type CsvLeadWriter struct {
-	file string
+	file *outputFile
 }
 
 // NewCsvLeadWriter returns an instance of a [LeadWriter] that writes lead data
-// to the given CSV file.
-func NewCsvLeadWriter(file string) LeadWriter {
-	return &CsvLeadWriter{file}
+// to the given CSV file, laid out according to the provided [LeadWriterOpt]s.
+func NewCsvLeadWriter(file string, opts ...LeadWriterOpt) LeadWriter {
+	return &CsvLeadWriter{newOutputFile(file, opts...)}
 }
 
 func (c *CsvLeadWriter) WriteLead(lead *models.Lead) error {
-	file, err := os.OpenFile(c.file, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
-
-	return gocsv.MarshalFile([]*models.Lead{lead}, file)
+	return c.WriteLeads([]*models.Lead{lead})
 }
 
 func (c *CsvLeadWriter) WriteLeads(leads []*models.Lead) error {
-	file, err := os.OpenFile(c.file, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
-	if err != nil {
-		return err
-	}
-	defer file.Close()
+	return c.file.write(func(w io.Writer, empty bool) error {
+		if empty {
+			return gocsv.Marshal(leads, w)
+		}
 
-	return gocsv.MarshalFile(leads, file)
+		return gocsv.MarshalWithoutHeaders(leads, w)
+	})
 }
 
 // JsonLeadWriter is an implementation of a [LeadWriter] that writes lead data
-// to a JSON file.
+// to a JSON file, as a JSON object per line. The [LeadSchema] is written alongside
+// the file (e.g. 'leads.schema.json' for 'leads.json') and leads which don't match
+// it aren't written.
 type JsonLeadWriter struct {
-	file string
+	file          *outputFile
+	schema        *JsonSchema
+	schemaWritten bool
 }
 
 // NewJsonLeadWriter returns an instance of a [LeadWriter] that writes lead data
-// to the given JSON file.
-func NewJsonLeadWriter(file string) LeadWriter {
-	return &JsonLeadWriter{file}
+// to the given JSON file, laid out according to the provided [LeadWriterOpt]s.
+func NewJsonLeadWriter(file string, opts ...LeadWriterOpt) LeadWriter {
+	return &JsonLeadWriter{file: newOutputFile(file, opts...), schema: LeadSchema()}
 }
 
 func (j *JsonLeadWriter) WriteLead(lead *models.Lead) error {
-	file, err := os.OpenFile(j.file, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
-	if err != nil {
-		return err
+	return j.WriteLeads([]*models.Lead{lead})
+}
+
+func (j *JsonLeadWriter) WriteLeads(leads []*models.Lead) error {
+	if !j.schemaWritten {
+		if err := writeLeadSchema(j.file.stem + schemaExt); err != nil {
+			return err
+		}
+		j.schemaWritten = true
 	}
-	defer file.Close()
 
-	b, err := json.Marshal(lead)
-	if err != nil {
-		return err
+	leads, invalid := validateLeads(j.schema, leads)
+	if len(leads) == 0 {
+		return invalid
 	}
 
-	_, err = file.Write(b)
+	err := j.file.write(func(w io.Writer, _ bool) error {
+		enc := json.NewEncoder(w)
+		for _, lead := range leads {
+			if err := enc.Encode(lead); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
 
-	return err
+	return errors.Join(invalid, err)
 }
 
-func (j *JsonLeadWriter) WriteLeads(leads []*models.Lead) error {
-	file, err := os.OpenFile(j.file, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+// ReadLeads reads the leads from a file written by a [CsvLeadWriter] or a [JsonLeadWriter]
+// (gzip-compressed or not), or by [SaveRecords]. JSON files may hold a JSON object per line or an
+// array, and CSV files written by older versions, which repeated the header, are accepted too.
+func ReadLeads(file string) ([]*models.Lead, error) {
+	format := FileFormat(filepath.Ext(file))
+	if format == XlsxFileFormat {
+		var leads []*models.Lead
+		err := ReadRecords(file, &leads)
+		return leads, err
+	}
+
+	f, err := os.Open(file)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer file.Close()
+	defer f.Close()
 
-	for _, lead := range leads {
-		b, err := json.Marshal(lead)
+	var r io.Reader = f
+	if format == gzipExt {
+		gz, err := gzip.NewReader(f)
 		if err != nil {
-			return err
+			return nil, err
 		}
+		defer gz.Close()
 
-		// append a new line
-		b = append(b, 10)
+		r, format = gz, FileFormat(filepath.Ext(strings.TrimSuffix(file, gzipExt)))
+	}
 
-		if _, err = file.Write(b); err != nil {
-			return err
+	var leads []*models.Lead
+	switch format {
+	case CsvFileFormat:
+		rows, err := csv.NewReader(r).ReadAll()
+		if err != nil || len(rows) == 0 {
+			return nil, err
+		}
+
+		header := rows[0]
+		rows = slices.DeleteFunc(rows[1:], func(row []string) bool {
+			return slices.Equal(row, header)
+		})
+
+		var b bytes.Buffer
+		w := csv.NewWriter(&b)
+		if err := w.WriteAll(append([][]string{header}, rows...)); err != nil {
+			return nil, err
+		}
+
+		err = gocsv.UnmarshalBytes(b.Bytes(), &leads)
+		return leads, err
+
+	case JsonFileFormat:
+		r := bufio.NewReader(r)
+		if c, err := firstNonSpace(r); err == io.EOF {
+			return nil, nil
+		} else if err != nil {
+			return nil, err
+		} else if c == '[' {
+			err := json.NewDecoder(r).Decode(&leads)
+			return leads, err
+		}
+
+		for dec := json.NewDecoder(r); ; {
+			var lead models.Lead
+			if err := dec.Decode(&lead); err == io.EOF {
+				return leads, nil
+			} else if err != nil {
+				return nil, err
+			}
+			leads = append(leads, &lead)
+		}
+
+	default:
+		return nil, ErrorUnsupportedFileFormat
+	}
+}
+
+// IsLeadFile reports whether the file is an output file of leads written by a [CsvLeadWriter] or a
+// [JsonLeadWriter] (gzip-compressed or not), by looking for the provenance of leads in its first line.
+func IsLeadFile(file string) bool {
+	f, err := os.Open(file)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	switch FileFormat(filepath.Ext(file)) {
+	case gzipExt:
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return false
 		}
+		defer gz.Close()
+		r = gz
+	case CsvFileFormat, JsonFileFormat:
+	default:
+		return false
 	}
 
-	return nil
+	line, _ := bufio.NewReader(r).ReadString('\n')
+	return strings.Contains(line, "source-account") && strings.Contains(line, "scraped-at")
+}
+
+// firstNonSpace returns the first character of r which isn't whitespace, leaving it unread.
+func firstNonSpace(r *bufio.Reader) (byte, error) {
+	for {
+		c, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+
+		if !unicode.IsSpace(rune(c)) {
+			return c, r.UnreadByte()
+		}
+	}
 }
@@ -0,0 +1,111 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package io
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/models"
+)
+
+// benchLeads returns a page's worth of leads.
+func benchLeads() []*models.Lead {
+	leads := make([]*models.Lead, 25)
+	for i := range leads {
+		leads[i] = &models.Lead{
+			Name:      fmt.Sprintf("Lead %d", i),
+			Title:     "Head of Sales",
+			Company:   "Acme",
+			Location:  "Berlin, Germany",
+			Employees: "51-200",
+			Industry:  "Software,Sales",
+			Keywords:  "saas,b2b",
+			Links:     "https://www.linkedin.com/in/lead",
+			Email:     fmt.Sprintf("lead%d@example.com", i),
+			Phone:     "+1 555 0100",
+			LeadSource: models.LeadSource{
+				Account:   "account@example.com",
+				List:      "list",
+				SearchURL: "https://app.apollo.io/#/people",
+				Page:      1,
+				ScrapedAt: time.Now(),
+			},
+		}
+	}
+
+	return leads
+}
+
+func TestReadLeads(t *testing.T) {
+	for _, tc := range []struct {
+		ext       string
+		newWriter func(file string, opts ...LeadWriterOpt) LeadWriter
+	}{
+		{".csv", NewCsvLeadWriter},
+		{".json", NewJsonLeadWriter},
+	} {
+		t.Run(tc.ext, func(t *testing.T) {
+			file := filepath.Join(t.TempDir(), "leads"+tc.ext)
+			writer := tc.newWriter(file)
+
+			leads := benchLeads()
+			for _, page := range [][]*models.Lead{leads[:10], leads[10:]} {
+				if err := writer.WriteLeads(page); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			read, err := ReadLeads(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(read) != len(leads) {
+				t.Fatalf("read %d leads; want %d", len(read), len(leads))
+			}
+
+			for i, lead := range read {
+				if lead.Email != leads[i].Email || lead.Page != leads[i].Page {
+					t.Errorf("lead %d = %+v; want %+v", i, lead, leads[i])
+				}
+			}
+		})
+	}
+}
+
+func benchmarkLeadWriter(b *testing.B, newWriter func(file string, opts ...LeadWriterOpt) LeadWriter, ext string) {
+	leads := benchLeads()
+	writer := newWriter(filepath.Join(b.TempDir(), "leads"+ext))
+
+	b.ResetTimer()
+	for range b.N {
+		if err := writer.WriteLeads(leads); err != nil {
+			b.Fatal(err)
+		}
+	}
+
+	b.ReportMetric(float64(b.N*len(leads))/b.Elapsed().Seconds(), "leads/s")
+}
+
+func BenchmarkCsvLeadWriter(b *testing.B) {
+	benchmarkLeadWriter(b, NewCsvLeadWriter, ".csv")
+}
+
+func BenchmarkJsonLeadWriter(b *testing.B) {
+	benchmarkLeadWriter(b, NewJsonLeadWriter, ".json")
+}
@@ -0,0 +1,229 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package io
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// gzipExt is the extension appended to the names of gzip-compressed files.
+const gzipExt = ".gz"
+
+// LeadWriterOpt represents a function that is used to configure how a [CsvLeadWriter] or a
+// [JsonLeadWriter] lays out the files it writes to.
+type LeadWriterOpt func(o *outputFile)
+
+// Gzip is a [LeadWriterOpt] func that gzip-compresses the written files, whose names get a '.gz'
+// extension.
+func Gzip() LeadWriterOpt {
+	return func(o *outputFile) {
+		o.gzip = true
+	}
+}
+
+// PartitionBySize is a [LeadWriterOpt] func that starts a new file once the current one reaches
+// the provided size (in bytes), e.g. 'leads-2.csv' after 'leads.csv'. Leads are written a batch at
+// a time, so files can exceed the size by up to a batch.
+func PartitionBySize(size int64) LeadWriterOpt {
+	return func(o *outputFile) {
+		o.maxSize = size
+	}
+}
+
+// PartitionByDate is a [LeadWriterOpt] func that writes the leads to a file per day, e.g.
+// 'leads-2025-06-01.csv'.
+func PartitionByDate() LeadWriterOpt {
+	return func(o *outputFile) {
+		o.byDate = true
+	}
+}
+
+// outputFile resolves the file that leads are written to from a base file name and its layout.
+type outputFile struct {
+	stem, ext    string
+	gzip, byDate bool
+	maxSize      int64
+	date         string
+	part         int
+	now          func() time.Time
+}
+
+func newOutputFile(file string, opts ...LeadWriterOpt) *outputFile {
+	ext := filepath.Ext(file)
+	o := &outputFile{stem: strings.TrimSuffix(file, ext), ext: ext, part: 1, now: time.Now}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	return o
+}
+
+// path returns the file that the next leads are written to.
+func (o *outputFile) path() (string, error) {
+	stem := o.stem
+	if o.byDate {
+		if date := o.now().Format(time.DateOnly); date != o.date {
+			o.date, o.part = date, 1
+		}
+		stem += "-" + o.date
+	}
+
+	for {
+		name := stem
+		if o.part > 1 {
+			name += fmt.Sprintf("-%d", o.part)
+		}
+
+		name += o.ext
+		if o.gzip {
+			name += gzipExt
+		}
+
+		if o.maxSize <= 0 {
+			return name, nil
+		}
+
+		info, err := os.Stat(name)
+		if errors.Is(err, os.ErrNotExist) || (err == nil && info.Size() < o.maxSize) {
+			return name, nil
+		} else if err != nil {
+			return "", err
+		}
+
+		o.part++
+	}
+}
+
+// write appends to the current file, passing fn whether the file is empty.
+func (o *outputFile) write(fn func(w io.Writer, empty bool) error) error {
+	name, err := o.path()
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(name, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	if !o.gzip {
+		return fn(file, info.Size() == 0)
+	}
+
+	// every write adds a gzip member to the file, which readers decompress as a single stream.
+	gz := gzip.NewWriter(file)
+	if err := fn(gz, info.Size() == 0); err != nil {
+		gz.Close()
+		return err
+	}
+
+	return gz.Close()
+}
+
+// LeadFiles returns the files that a [CsvLeadWriter] or a [JsonLeadWriter] created for the given
+// file with the same options wrote to, in the order they were written in.
+func LeadFiles(file string, opts ...LeadWriterOpt) ([]string, error) {
+	o := newOutputFile(file, opts...)
+
+	pattern := "^" + regexp.QuoteMeta(filepath.Base(o.stem))
+	if o.byDate {
+		pattern += `-(\d{4}-\d{2}-\d{2})`
+	} else {
+		pattern += "()"
+	}
+	if o.maxSize > 0 {
+		pattern += `(?:-(\d+))?`
+	} else {
+		pattern += "()"
+	}
+	pattern += regexp.QuoteMeta(o.ext)
+	if o.gzip {
+		pattern += regexp.QuoteMeta(gzipExt)
+	}
+	re := regexp.MustCompile(pattern + "$")
+
+	entries, err := os.ReadDir(filepath.Dir(file))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	type partition struct {
+		file, date string
+		part       int
+	}
+
+	var partitions []partition
+	for _, entry := range entries {
+		m := re.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || m == nil {
+			continue
+		}
+
+		part, _ := strconv.Atoi(m[2])
+		partitions = append(partitions, partition{
+			file: filepath.Join(filepath.Dir(file), entry.Name()),
+			date: m[1],
+			part: max(part, 1),
+		})
+	}
+
+	slices.SortFunc(partitions, func(a, b partition) int {
+		if c := strings.Compare(a.date, b.date); c != 0 {
+			return c
+		}
+		return a.part - b.part
+	})
+
+	files := make([]string, len(partitions))
+	for i, p := range partitions {
+		files[i] = p.file
+	}
+
+	return files, nil
+}
+
+// RemoveLeadFiles removes the files returned by [LeadFiles].
+func RemoveLeadFiles(file string, opts ...LeadWriterOpt) error {
+	files, err := LeadFiles(file, opts...)
+	if err != nil {
+		return err
+	}
+
+	for _, f := range files {
+		if err := os.Remove(f); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,98 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package io
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/models"
+)
+
+func TestPartitionBySize(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "leads.csv")
+	opts := []LeadWriterOpt{Gzip(), PartitionBySize(1)}
+
+	writer := NewCsvLeadWriter(file, opts...)
+	leads := benchLeads()
+	for _, page := range [][]*models.Lead{leads[:10], leads[10:20], leads[20:]} {
+		if err := writer.WriteLeads(page); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	files, err := LeadFiles(file, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"leads.csv.gz", "leads-2.csv.gz", "leads-3.csv.gz"}
+	for i := range want {
+		want[i] = filepath.Join(dir, want[i])
+	}
+	if !slices.Equal(files, want) {
+		t.Fatalf("files = %v; want %v", files, want)
+	}
+
+	var read []*models.Lead
+	for _, f := range files {
+		l, err := ReadLeads(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+		read = append(read, l...)
+	}
+
+	if len(read) != len(leads) || read[24].Email != leads[24].Email {
+		t.Errorf("read %d leads; want %d in order", len(read), len(leads))
+	}
+
+	if err := RemoveLeadFiles(file, opts...); err != nil {
+		t.Fatal(err)
+	}
+	if files, _ := LeadFiles(file, opts...); len(files) != 0 {
+		t.Errorf("files left after removal: %v", files)
+	}
+}
+
+func TestPartitionByDate(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2025, 6, 1, 23, 0, 0, 0, time.UTC)
+
+	o := newOutputFile(filepath.Join(dir, "leads.json"), PartitionByDate())
+	o.now = func() time.Time { return now }
+
+	writer := &JsonLeadWriter{file: o, schema: LeadSchema()}
+	leads := benchLeads()
+	for range 2 {
+		if err := writer.WriteLeads(leads[:1]); err != nil {
+			t.Fatal(err)
+		}
+		now = now.Add(2 * time.Hour)
+	}
+
+	files, err := LeadFiles(filepath.Join(dir, "leads.json"), PartitionByDate())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{filepath.Join(dir, "leads-2025-06-01.json"), filepath.Join(dir, "leads-2025-06-02.json")}
+	if !slices.Equal(files, want) {
+		t.Errorf("files = %v; want %v", files, want)
+	}
+}
@@ -0,0 +1,229 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package io
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/models"
+)
+
+// LeadSchemaVersion is the version of the JSON Schema describing a lead. It must be bumped whenever
+// the fields of [models.Lead] change, so that downstream consumers can tell the contracts apart.
+const LeadSchemaVersion = 5
+
+// schemaExt is the extension of the JSON Schema files written alongside JSON output files.
+const schemaExt = ".schema.json"
+
+// JsonSchema is the subset of JSON Schema used to describe leads.
+type JsonSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	ID                   string                 `json:"$id,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Type                 string                 `json:"type"`
+	Format               string                 `json:"format,omitempty"`
+	Pattern              string                 `json:"pattern,omitempty"`
+	Properties           map[string]*JsonSchema `json:"properties,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	AdditionalProperties *bool                  `json:"additionalProperties,omitempty"`
+}
+
+// leadPatterns are the patterns that the values of a lead's fields must match. Emails may have been
+// replaced with their hashes when personal data is scrubbed.
+var leadPatterns = map[string]string{
+	"email": `^([^@\s]+@[^@\s]+|sha256:[0-9a-f]{64})?$`,
+}
+
+// LeadSchema returns the JSON Schema describing a lead, as written to JSON output files (a lead
+// per line).
+func LeadSchema() *JsonSchema {
+	additional := false
+	schema := &JsonSchema{
+		Schema:               "https://json-schema.org/draft/2020-12/schema",
+		ID:                   fmt.Sprintf("urn:scrapollo:lead:%d", LeadSchemaVersion),
+		Title:                "Lead",
+		Description:          "A lead scraped from apollo.io by scrapollo, along with where and when it was scraped.",
+		Type:                 "object",
+		Properties:           make(map[string]*JsonSchema),
+		AdditionalProperties: &additional,
+	}
+
+	var walk func(t reflect.Type)
+	walk = func(t reflect.Type) {
+		for i := range t.NumField() {
+			field := t.Field(i)
+			if field.Anonymous {
+				walk(field.Type)
+				continue
+			}
+
+			name := strings.Split(field.Tag.Get("json"), ",")[0]
+			property := &JsonSchema{Pattern: leadPatterns[name]}
+			switch {
+			case field.Type == reflect.TypeFor[time.Time]():
+				property.Type, property.Format = "string", "date-time"
+			case field.Type.Kind() == reflect.Int:
+				property.Type = "integer"
+			case field.Type.Kind() == reflect.Float64:
+				property.Type = "number"
+			default:
+				property.Type = "string"
+			}
+
+			schema.Properties[name] = property
+			schema.Required = append(schema.Required, name)
+		}
+	}
+	walk(reflect.TypeFor[models.Lead]())
+
+	return schema
+}
+
+// Validate validates a decoded JSON value against the schema.
+func (s *JsonSchema) Validate(v any) error {
+	return s.validate("", v)
+}
+
+func (s *JsonSchema) validate(path string, v any) error {
+	if path == "" {
+		path = "value"
+	}
+
+	switch s.Type {
+	case "object":
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s: expected an object", path)
+		}
+
+		var errs []error
+		for _, name := range s.Required {
+			if _, ok := obj[name]; !ok {
+				errs = append(errs, fmt.Errorf("%s: missing property %q", path, name))
+			}
+		}
+
+		names := make([]string, 0, len(obj))
+		for name := range obj {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+
+		for _, name := range names {
+			property, ok := s.Properties[name]
+			if !ok {
+				if s.AdditionalProperties != nil && !*s.AdditionalProperties {
+					errs = append(errs, fmt.Errorf("%s: unexpected property %q", path, name))
+				}
+				continue
+			}
+
+			errs = append(errs, property.validate(name, obj[name]))
+		}
+
+		return errors.Join(errs...)
+
+	case "integer":
+		if n, ok := v.(float64); !ok || n != float64(int64(n)) {
+			return fmt.Errorf("%s: expected an integer", path)
+		}
+
+	case "number":
+		if _, ok := v.(float64); !ok {
+			return fmt.Errorf("%s: expected a number", path)
+		}
+
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("%s: expected a string", path)
+		}
+
+		if s.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339Nano, str); err != nil {
+				return fmt.Errorf("%s: expected a date-time", path)
+			}
+		}
+
+		if s.Pattern != "" && !compilePattern(s.Pattern).MatchString(str) {
+			return fmt.Errorf("%s: %q doesn't match %q", path, str, s.Pattern)
+		}
+	}
+
+	return nil
+}
+
+// patterns caches the compiled patterns of schemas.
+var patterns sync.Map
+
+func compilePattern(pattern string) *regexp.Regexp {
+	if re, ok := patterns.Load(pattern); ok {
+		return re.(*regexp.Regexp)
+	}
+
+	re := regexp.MustCompile(pattern)
+	patterns.Store(pattern, re)
+	return re
+}
+
+// validateLeads validates the leads against the schema, returning the valid ones and the errors
+// of the invalid ones.
+func validateLeads(schema *JsonSchema, leads []*models.Lead) (valid []*models.Lead, err error) {
+	var errs []error
+	for _, lead := range leads {
+		b, err := json.Marshal(lead)
+		if err != nil {
+			return nil, err
+		}
+
+		var v any
+		if err := json.Unmarshal(b, &v); err != nil {
+			return nil, err
+		}
+
+		if err := schema.Validate(v); err != nil {
+			errs = append(errs, fmt.Errorf("invalid lead %q: %w", lead.Name, err))
+			continue
+		}
+
+		valid = append(valid, lead)
+	}
+
+	return valid, errors.Join(errs...)
+}
+
+// writeLeadSchema writes the lead schema to the file, unless it already holds the same schema.
+func writeLeadSchema(file string) error {
+	b, err := json.MarshalIndent(LeadSchema(), "", "\t")
+	if err != nil {
+		return err
+	}
+
+	if current, err := os.ReadFile(file); err == nil && slices.Equal(current, b) {
+		return nil
+	}
+
+	return os.WriteFile(file, b, 0644)
+}
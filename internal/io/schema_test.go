@@ -0,0 +1,114 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package io
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/devsheke/scrapollo/internal/models"
+)
+
+var update = flag.Bool("update", false, "update the golden lead schema")
+
+// TestLeadSchema guards the lead schema: when the fields of a lead change, the schema no longer
+// matches the golden file and LeadSchemaVersion must be bumped before updating it with -update.
+func TestLeadSchema(t *testing.T) {
+	golden := filepath.Join("testdata", "lead.schema.json")
+
+	b, err := json.MarshalIndent(LeadSchema(), "", "\t")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *update {
+		if err := os.WriteFile(golden, b, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	want, err := os.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var prev JsonSchema
+	if err := json.Unmarshal(want, &prev); err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(b, want) {
+		if prev.ID == LeadSchema().ID {
+			t.Fatal("the lead schema changed: bump LeadSchemaVersion and update the golden file with -update")
+		}
+		t.Fatal("the lead schema doesn't match the golden file: update it with -update")
+	}
+}
+
+func TestJsonLeadWriterValidation(t *testing.T) {
+	dir := t.TempDir()
+	writer := NewJsonLeadWriter(filepath.Join(dir, "leads.json"))
+
+	leads := benchLeads()[:3]
+	leads[1].Email = "Access email"
+
+	err := writer.WriteLeads(leads)
+	if err == nil || !strings.Contains(err.Error(), "email") {
+		t.Errorf("err = %v; want an invalid email error", err)
+	}
+
+	read, err := ReadLeads(filepath.Join(dir, "leads.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(read) != 2 {
+		t.Errorf("read %d leads; want the 2 valid ones", len(read))
+	}
+
+	if _, err := os.Stat(filepath.Join(dir, "leads.schema.json")); err != nil {
+		t.Errorf("schema wasn't written: %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	schema := LeadSchema()
+
+	var lead map[string]any
+	b, _ := json.Marshal(&models.Lead{})
+	if err := json.Unmarshal(b, &lead); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := schema.Validate(lead); err != nil {
+		t.Errorf("valid lead: %v", err)
+	}
+
+	lead["source-page"] = "1"
+	lead["extra"] = true
+	delete(lead, "name")
+
+	err := schema.Validate(lead)
+	for _, want := range []string{`missing property "name"`, `unexpected property "extra"`, "source-page: expected an integer"} {
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("err = %v; want %q", err, want)
+		}
+	}
+}
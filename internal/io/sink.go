@@ -0,0 +1,135 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package io
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/models"
+)
+
+// Sink is a named destination of scraped leads, e.g. the output file, a webhook or a plugin's
+// [LeadWriter].
+type Sink struct {
+	Name   string
+	Writer LeadWriter
+}
+
+// SinkError is returned by a [SinkChain] for each of its sinks which failed to write leads.
+type SinkError struct {
+	Sink string
+	Err  error
+}
+
+func (e *SinkError) Error() string {
+	return fmt.Sprintf("sink %q: %v", e.Sink, e.Err)
+}
+
+func (e *SinkError) Unwrap() error {
+	return e.Err
+}
+
+// SinkErrors returns the [SinkError]s that err (as returned by [SinkChain.WriteLeads]) is made of.
+func SinkErrors(err error) []*SinkError {
+	switch err := err.(type) {
+	case *SinkError:
+		return []*SinkError{err}
+	case interface{ Unwrap() []error }:
+		var errs []*SinkError
+		for _, err := range err.Unwrap() {
+			errs = append(errs, SinkErrors(err)...)
+		}
+		return errs
+	default:
+		return nil
+	}
+}
+
+// SinkChain is an implementation of a [LeadWriter] that fans leads out to several sinks. Every sink
+// is written to regardless of the others failing, so that e.g. a webhook outage doesn't stop leads
+// from being written to the output file, and the failures are returned together as [SinkError]s.
+type SinkChain struct {
+	sinks []Sink
+}
+
+// NewSinkChain returns a [SinkChain] writing to the provided sinks, in order.
+func NewSinkChain(sinks ...Sink) *SinkChain {
+	return &SinkChain{sinks: sinks}
+}
+
+// Sinks returns the sinks of the chain.
+func (c *SinkChain) Sinks() []Sink {
+	return c.sinks
+}
+
+func (c *SinkChain) WriteLead(lead *models.Lead) error {
+	return c.WriteLeads([]*models.Lead{lead})
+}
+
+func (c *SinkChain) WriteLeads(leads []*models.Lead) error {
+	var errs []error
+	for _, sink := range c.sinks {
+		if err := sink.Writer.WriteLeads(leads); err != nil {
+			errs = append(errs, &SinkError{Sink: sink.Name, Err: err})
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// webhookTimeout is the time allowed for a webhook to accept a batch of leads.
+const webhookTimeout = 30 * time.Second
+
+// WebhookLeadWriter is an implementation of a [LeadWriter] that POSTs lead data to a URL, as a JSON
+// object holding the array of leads (e.g. '{"leads": [...]}').
+type WebhookLeadWriter struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookLeadWriter returns an instance of a [LeadWriter] that POSTs lead data to the given URL.
+func NewWebhookLeadWriter(url string) LeadWriter {
+	return &WebhookLeadWriter{url: url, client: &http.Client{Timeout: webhookTimeout}}
+}
+
+func (w *WebhookLeadWriter) WriteLead(lead *models.Lead) error {
+	return w.WriteLeads([]*models.Lead{lead})
+}
+
+func (w *WebhookLeadWriter) WriteLeads(leads []*models.Lead) error {
+	body, err := json.Marshal(struct {
+		Leads []*models.Lead `json:"leads"`
+	}{leads})
+	if err != nil {
+		return err
+	}
+
+	res, err := w.client.Post(w.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %s", res.Status)
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package io
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/devsheke/scrapollo/internal/models"
+)
+
+func TestSinkChain(t *testing.T) {
+	var received []*models.Lead
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Leads []*models.Lead `json:"leads"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			t.Error(err)
+		}
+		received = append(received, body.Leads...)
+	}))
+	defer webhook.Close()
+
+	outage := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer outage.Close()
+
+	file := filepath.Join(t.TempDir(), "leads.csv")
+	chain := NewSinkChain(
+		Sink{Name: "down", Writer: NewWebhookLeadWriter(outage.URL)},
+		Sink{Name: "csv", Writer: NewCsvLeadWriter(file)},
+		Sink{Name: "webhook", Writer: NewWebhookLeadWriter(webhook.URL)},
+	)
+
+	leads := benchLeads()
+	errs := SinkErrors(chain.WriteLeads(leads))
+	if len(errs) != 1 || errs[0].Sink != "down" {
+		t.Errorf("unexpected sink errors: %v", errs)
+	}
+
+	// the failing sink doesn't keep the leads from the others.
+	written, err := ReadLeads(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(written) != len(leads) || len(received) != len(leads) {
+		t.Errorf("wrote %d leads to the file and %d to the webhook; want %d", len(written), len(received), len(leads))
+	}
+}
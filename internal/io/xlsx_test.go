@@ -0,0 +1,98 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package io
+
+import (
+	"archive/zip"
+	"bytes"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestXlsxRoundTrip(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "leads.xlsx")
+
+	leads := benchLeads()
+	leads[0].Name = " <Jane> & \"Doe\" "
+	leads[1].Phone = ""
+
+	if err := SaveRecords(file, leads); err != nil {
+		t.Fatal(err)
+	}
+
+	read, err := ReadLeads(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(read) != len(leads) {
+		t.Fatalf("read %d leads; want %d", len(read), len(leads))
+	}
+
+	for i, lead := range read {
+		if lead.Name != leads[i].Name || lead.Phone != leads[i].Phone || !lead.ScrapedAt.Equal(leads[i].ScrapedAt) {
+			t.Errorf("lead %d = %+v; want %+v", i, lead, leads[i])
+		}
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	for i, want := range map[int]string{0: "A", 25: "Z", 26: "AA", 701: "ZZ", 702: "AAA"} {
+		if got := columnName(i); got != want {
+			t.Errorf("columnName(%d) = %q; want %q", i, got, want)
+		}
+
+		if got, err := columnIndex(want + "7"); err != nil || got != i {
+			t.Errorf("columnIndex(%q) = %d, %v; want %d", want+"7", got, err, i)
+		}
+	}
+}
+
+// TestReadXlsxRows reads a workbook laid out like the ones saved by Excel, with shared strings,
+// rich text, numbers and skipped empty cells.
+func TestReadXlsxRows(t *testing.T) {
+	var b bytes.Buffer
+	z := zip.NewWriter(&b)
+	for name, content := range map[string]string{
+		"xl/workbook.xml": `<workbook xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="Leads" sheetId="1" r:id="rId3"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships><Relationship Id="rId3" Target="/xl/worksheets/leads.xml"/></Relationships>`,
+		"xl/sharedStrings.xml":       `<sst><si><t>name</t></si><si><t>phone</t></si><si><r><t>Jane </t></r><r><t>Doe</t></r></si></sst>`,
+		"xl/worksheets/leads.xml": `<worksheet><sheetData>` +
+			`<row r="1"><c r="A1" t="s"><v>0</v></c><c r="C1" t="s"><v>1</v></c></row>` +
+			`<row r="2"><c r="A2" t="s"><v>2</v></c><c r="C2"><v>5550100</v></c></row>` +
+			`</sheetData></worksheet>`,
+	} {
+		f, err := z.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write([]byte(content))
+	}
+	if err := z.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	rows, err := readXlsxRows(bytes.NewReader(b.Bytes()), int64(b.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][]string{{"name", "", "phone"}, {"Jane Doe", "", "5550100"}}
+	if !slices.EqualFunc(rows, want, slices.Equal) {
+		t.Errorf("rows = %q; want %q", rows, want)
+	}
+}
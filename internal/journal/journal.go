@@ -0,0 +1,214 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package journal
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Action represents an action taken by an account.
+type Action string
+
+// The actions recorded in account journals.
+const (
+	ActionJobStarted     Action = "job-started"
+	ActionJobFinished    Action = "job-finished"
+	ActionJobDropped     Action = "job-dropped"
+	ActionDeadLettered   Action = "dead-lettered"
+	ActionVpnConnected   Action = "vpn-connected"
+	ActionVpnRegion      Action = "vpn-region-mismatch"
+	ActionFailover       Action = "failover"
+	ActionLogin          Action = "login"
+	ActionLoginPrefetch  Action = "login-prefetched"
+	ActionWarmUp         Action = "warm-up"
+	ActionCreditsFetched Action = "credits-fetched"
+	ActionTabSelected    Action = "tab-selected"
+	ActionListCreated    Action = "list-created"
+	ActionPageSaved      Action = "page-saved"
+	ActionBulkSaved      Action = "bulk-saved"
+	ActionSaveUnverified Action = "save-unverified"
+	ActionPageSkipped    Action = "page-skipped"
+	ActionPageReloaded   Action = "page-reloaded"
+	ActionPageScraped    Action = "page-scraped"
+	ActionPageClassified Action = "page-classified"
+	ActionLlmExtracted   Action = "llm-extracted"
+	ActionError          Action = "error"
+)
+
+// Entry represents a single action recorded in an account's journal.
+type Entry struct {
+	Time      time.Time `json:"time"`
+	Account   string    `json:"account"`
+	RunID     string    `json:"run-id,omitempty"`
+	JobID     string    `json:"job-id,omitempty"`
+	Action    Action    `json:"action"`
+	List      string    `json:"list,omitempty"`
+	Tab       string    `json:"tab,omitempty"`
+	Page      int       `json:"page,omitempty"`
+	Leads     int       `json:"leads,omitempty"`
+	Captured  int       `json:"captured,omitempty"`
+	Rows      int       `json:"rows,omitempty"`
+	Recovered int       `json:"recovered,omitempty"`
+	Invalid   int       `json:"invalid,omitempty"`
+	TeamOwned int       `json:"team-owned,omitempty"`
+	Credits   int       `json:"credits,omitempty"`
+	VpnConfig string    `json:"vpn-config,omitempty"`
+	Proxy     string    `json:"proxy,omitempty"`
+	UI        string    `json:"ui,omitempty"`
+	State     string    `json:"state,omitempty"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// Journal is an append-only log of the actions taken by each account. Every account's
+// entries are written to a separate JSONL file in the journal's directory.
+type Journal struct {
+	dir string
+	mu  sync.Mutex
+}
+
+// New returns a [*Journal] that writes to the provided directory, creating it if necessary.
+func New(dir string) (*Journal, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	return &Journal{dir: dir}, nil
+}
+
+func filename(dir, account string) string {
+	return filepath.Join(dir, account+".jsonl")
+}
+
+// Record appends the provided entry to its account's journal. If the entry's time is not
+// set, the current time is used.
+func (j *Journal) Record(entry Entry) error {
+	if entry.Time.IsZero() {
+		entry.Time = time.Now()
+	}
+
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	f, err := os.OpenFile(filename(j.dir, entry.Account), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+
+	_, err = f.Write(append(b, '\n'))
+
+	return errors.Join(err, f.Close())
+}
+
+// Read returns all entries recorded in the provided account's journal.
+func (j *Journal) Read(account string) ([]*Entry, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	f, err := os.Open(filename(j.dir, account))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var entries []*Entry
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		entry := new(Entry)
+		if err := json.Unmarshal(scanner.Bytes(), entry); err != nil {
+			return entries, err
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, scanner.Err()
+}
+
+// Prune removes the entries recorded before the cutoff from every account's journal, removing the
+// journals left empty. Lines which can't be parsed are kept. It returns the number of entries removed.
+func (j *Journal) Prune(before time.Time) (int, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	files, err := filepath.Glob(filepath.Join(j.dir, "*.jsonl"))
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		removed int
+		errs    []error
+	)
+	for _, file := range files {
+		n, err := prune(file, before)
+		removed += n
+		errs = append(errs, err)
+	}
+
+	return removed, errors.Join(errs...)
+}
+
+// prune removes the entries recorded before the cutoff from the journal file.
+func prune(file string, before time.Time) (int, error) {
+	b, err := os.ReadFile(file)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		kept    bytes.Buffer
+		removed int
+	)
+	for _, line := range bytes.SplitAfter(b, []byte("\n")) {
+		var entry Entry
+		if err := json.Unmarshal(line, &entry); err == nil && entry.Time.Before(before) {
+			removed++
+			continue
+		}
+		kept.Write(line)
+	}
+
+	switch {
+	case removed == 0:
+		return 0, nil
+	case len(bytes.TrimSpace(kept.Bytes())) == 0:
+		return removed, os.Remove(file)
+	}
+
+	tmp := file + ".tmp"
+	if err := os.WriteFile(tmp, kept.Bytes(), 0644); err != nil {
+		return 0, err
+	}
+
+	return removed, os.Rename(tmp, file)
+}
@@ -0,0 +1,254 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package limiter implements organisation-wide caps on the activity of all accounts, such as the
+// number of leads saved per hour and the number of concurrent logins. A limiter's state can be kept
+// in a file, which lets several scrapollo processes sharing an egress IP enforce the same caps.
+package limiter
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// saveWindow is the period over which saves are capped.
+	saveWindow = time.Hour
+
+	// loginExpiry is how long a login slot is held at most, so that slots held by processes which
+	// crashed are eventually reclaimed.
+	loginExpiry = 10 * time.Minute
+)
+
+// ErrorLoginCapReached is returned when a login slot can't be acquired because the maximum number
+// of concurrent logins has been reached.
+var ErrorLoginCapReached = errors.New("maximum number of concurrent logins reached")
+
+type saveEvent struct {
+	Time  time.Time `json:"time"`
+	Count int       `json:"count"`
+}
+
+type loginSlot struct {
+	ID    string    `json:"id"`
+	Since time.Time `json:"since"`
+}
+
+// state is the activity tracked by a [Limiter].
+type state struct {
+	Saves  []saveEvent `json:"saves"`
+	Logins []loginSlot `json:"logins"`
+}
+
+// prune drops the saves and logins which no longer count towards the caps.
+func (s *state) prune(now time.Time) {
+	saves := s.Saves[:0]
+	for _, save := range s.Saves {
+		if now.Sub(save.Time) < saveWindow {
+			saves = append(saves, save)
+		}
+	}
+	s.Saves = saves
+
+	logins := s.Logins[:0]
+	for _, login := range s.Logins {
+		if now.Sub(login.Since) < loginExpiry {
+			logins = append(logins, login)
+		}
+	}
+	s.Logins = logins
+}
+
+// Limiter enforces caps on the total number of leads saved per hour and the number of concurrent
+// logins across all accounts. A zero cap disables it.
+type Limiter struct {
+	maxSaves, maxLogins int
+	file                string
+
+	mu    sync.Mutex
+	state state
+	now   func() time.Time
+}
+
+// New returns a [*Limiter] with the provided caps. If file isn't empty, the limiter's state is kept
+// in it so that it's shared by every limiter using the same file.
+func New(maxSavesPerHour, maxConcurrentLogins int, file string) *Limiter {
+	return &Limiter{
+		maxSaves:  maxSavesPerHour,
+		maxLogins: maxConcurrentLogins,
+		file:      file,
+		now:       time.Now,
+	}
+}
+
+// Simulation returns an in-memory [*Limiter] with the same caps which tells the time with now, for
+// simulated runs, which must neither wait for real time to pass nor affect the state of real ones.
+func (l *Limiter) Simulation(now func() time.Time) *Limiter {
+	return &Limiter{maxSaves: l.maxSaves, maxLogins: l.maxLogins, now: now}
+}
+
+// update applies fn to the limiter's current state and persists the result.
+func (l *Limiter) update(fn func(s *state, now time.Time) error) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.file == "" {
+		now := l.now()
+		l.state.prune(now)
+		return fn(&l.state, now)
+	}
+
+	unlock, err := lockFile(l.file)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	var s state
+	if b, err := os.ReadFile(l.file); err == nil {
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	now := l.now()
+	s.prune(now)
+	if err := fn(&s, now); err != nil {
+		return err
+	}
+
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	tmp := l.file + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, l.file)
+}
+
+// ReserveSaves reserves n saves within the hourly cap. If saving n more leads would exceed the cap,
+// nothing is reserved and the time to wait before trying again is returned.
+func (l *Limiter) ReserveSaves(n int) (wait time.Duration, err error) {
+	if l.maxSaves <= 0 {
+		return 0, nil
+	}
+
+	err = l.update(func(s *state, now time.Time) error {
+		total := n
+		for _, save := range s.Saves {
+			total += save.Count
+		}
+
+		// an oversized reservation is allowed once the window is empty, so that it can't wait forever.
+		if total <= l.maxSaves || len(s.Saves) == 0 {
+			s.Saves = append(s.Saves, saveEvent{Time: now, Count: n})
+			return nil
+		}
+
+		// wait until enough of the oldest saves fall out of the window.
+		for _, save := range s.Saves {
+			total -= save.Count
+			if total <= l.maxSaves {
+				wait = save.Time.Add(saveWindow).Sub(now)
+				break
+			}
+		}
+
+		return nil
+	})
+
+	return max(wait, 0), err
+}
+
+// AcquireLogin acquires a login slot, returning [ErrorLoginCapReached] if all of them are held.
+// The returned function releases the slot.
+func (l *Limiter) AcquireLogin(id string) (release func() error, err error) {
+	release = func() error { return nil }
+	if l.maxLogins <= 0 {
+		return release, nil
+	}
+
+	err = l.update(func(s *state, now time.Time) error {
+		if len(s.Logins) >= l.maxLogins {
+			return ErrorLoginCapReached
+		}
+
+		s.Logins = append(s.Logins, loginSlot{ID: id, Since: now})
+		return nil
+	})
+
+	if err != nil {
+		return release, err
+	}
+
+	return func() error {
+		return l.update(func(s *state, _ time.Time) error {
+			for i, login := range s.Logins {
+				if login.ID == id {
+					s.Logins = append(s.Logins[:i], s.Logins[i+1:]...)
+					break
+				}
+			}
+
+			return nil
+		})
+	}, nil
+}
+
+const (
+	lockRetry = 20 * time.Millisecond
+	lockStale = 30 * time.Second
+	lockWait  = 10 * time.Second
+)
+
+// lockFile acquires an exclusive lock on the file by creating a lock file next to it. Lock files
+// older than lockStale are assumed to have been left behind by a crashed process and are removed.
+func lockFile(file string) (unlock func(), err error) {
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		return nil, err
+	}
+
+	lock := file + ".lock"
+	deadline := time.Now().Add(lockWait)
+	for {
+		f, err := os.OpenFile(lock, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			f.Close()
+			return func() { os.Remove(lock) }, nil
+		} else if !errors.Is(err, os.ErrExist) {
+			return nil, err
+		}
+
+		if info, err := os.Stat(lock); err == nil && time.Since(info.ModTime()) > lockStale {
+			os.Remove(lock)
+			continue
+		}
+
+		if time.Now().After(deadline) {
+			return nil, errors.New("timed out waiting for the lock on " + file)
+		}
+
+		time.Sleep(lockRetry)
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package limiter
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestReserveSaves(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	l := New(50, 0, "")
+	l.now = func() time.Time { return now }
+
+	for _, n := range []int{25, 25} {
+		if wait, err := l.ReserveSaves(n); err != nil || wait != 0 {
+			t.Fatalf("ReserveSaves(%d) = %v, %v; want 0, nil", n, wait, err)
+		}
+	}
+
+	now = now.Add(20 * time.Minute)
+	wait, err := l.ReserveSaves(25)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 40 * time.Minute; wait != want {
+		t.Fatalf("wait = %v; want %v", wait, want)
+	}
+
+	now = now.Add(wait)
+	if wait, err := l.ReserveSaves(25); err != nil || wait != 0 {
+		t.Fatalf("ReserveSaves(25) after waiting = %v, %v; want 0, nil", wait, err)
+	}
+}
+
+func TestAcquireLogin(t *testing.T) {
+	l := New(0, 1, "")
+
+	release, err := l.AcquireLogin("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := l.AcquireLogin("b"); !errors.Is(err, ErrorLoginCapReached) {
+		t.Fatalf("err = %v; want %v", err, ErrorLoginCapReached)
+	}
+
+	if err := release(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := l.AcquireLogin("b"); err != nil {
+		t.Fatalf("err = %v after release; want nil", err)
+	}
+}
+
+func TestSharedFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "limits.json")
+	a, b := New(10, 1, file), New(10, 1, file)
+
+	if _, err := a.AcquireLogin("a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.AcquireLogin("b"); !errors.Is(err, ErrorLoginCapReached) {
+		t.Fatalf("err = %v; want %v", err, ErrorLoginCapReached)
+	}
+
+	if wait, err := a.ReserveSaves(10); err != nil || wait != 0 {
+		t.Fatalf("ReserveSaves(10) = %v, %v; want 0, nil", wait, err)
+	}
+	if wait, err := b.ReserveSaves(1); err != nil || wait == 0 {
+		t.Fatalf("ReserveSaves(1) = %v, %v; want a wait", wait, err)
+	}
+}
@@ -0,0 +1,212 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package lint checks input files for mistakes which would otherwise only surface during a run.
+package lint
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/actions"
+	"github.com/devsheke/scrapollo/internal/fingerprint"
+	"github.com/devsheke/scrapollo/internal/models"
+	"github.com/devsheke/scrapollo/internal/runner"
+)
+
+// Severity represents how serious an [Issue] is.
+type Severity string
+
+const (
+	// SeverityError is the [Severity] of issues which make a job fail or do nothing.
+	SeverityError Severity = "error"
+
+	// SeverityWarning is the [Severity] of issues which may be intended, but often aren't.
+	SeverityWarning Severity = "warning"
+)
+
+// requiredColumns are the columns without which no job can run.
+var requiredColumns = []string{"email", "password", "url", "list"}
+
+// Issue represents a mistake found in an input file.
+type Issue struct {
+	// Row is the (1-based) number of the account the issue was found in, or 0 if the issue is about
+	// the whole file.
+	Row      int      `json:"row"      csv:"row"`
+	Email    string   `json:"email"    csv:"email"`
+	Severity Severity `json:"severity" csv:"severity"`
+	Message  string   `json:"message"  csv:"message"`
+}
+
+func (i Issue) String() string {
+	switch {
+	case i.Row == 0:
+		return fmt.Sprintf("%s: %s", i.Severity, i.Message)
+	case i.Email == "":
+		return fmt.Sprintf("%s: account %d: %s", i.Severity, i.Row, i.Message)
+	default:
+		return fmt.Sprintf("%s: account %d (%s): %s", i.Severity, i.Row, i.Email, i.Message)
+	}
+}
+
+// Columns checks the columns of an accounts file, reporting missing required columns and unknown
+// columns, which are usually misspelt and ignored when the file is read.
+func Columns(columns []string) []Issue {
+	known := accountColumns()
+
+	var issues []Issue
+	for _, column := range requiredColumns {
+		if !slices.Contains(columns, column) {
+			issues = append(issues, Issue{
+				Severity: SeverityError,
+				Message:  fmt.Sprintf("missing required column %q", column),
+			})
+		}
+	}
+
+	for _, column := range columns {
+		if !slices.Contains(known, column) {
+			issues = append(issues, Issue{
+				Severity: SeverityWarning,
+				Message:  fmt.Sprintf("unknown column %q is ignored", column),
+			})
+		}
+	}
+
+	return issues
+}
+
+// accountColumns returns the columns of a [models.Account].
+func accountColumns() []string {
+	t := reflect.TypeFor[models.Account]()
+
+	var columns []string
+	for i := range t.NumField() {
+		if tag := t.Field(i).Tag.Get("csv"); tag != "" {
+			columns = append(columns, tag)
+		}
+	}
+
+	return columns
+}
+
+// Accounts checks the accounts of an input file. Besides each account's own fields, it reports
+// duplicate emails and searches or lists shared by several accounts. Blacklisted accounts, which
+// are never run, are only checked for duplicate emails.
+func Accounts(accounts []*models.Account) []Issue {
+	var issues []Issue
+	report := func(row int, acc *models.Account, severity Severity, format string, args ...any) {
+		issues = append(issues, Issue{
+			Row:      row,
+			Email:    acc.Email,
+			Severity: severity,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	emails := make(map[string]int)
+	searches := make(map[string]int)
+	lists := make(map[string]int)
+
+	for i, acc := range accounts {
+		row := i + 1
+
+		email := strings.ToLower(strings.TrimSpace(acc.Email))
+		if email == "" {
+			report(row, acc, SeverityError, "missing email")
+		} else if first, ok := emails[email]; ok {
+			report(row, acc, SeverityError, "duplicate of account %d's email", first)
+		} else {
+			emails[email] = row
+		}
+
+		if acc.Blacklisted != "" {
+			continue
+		}
+
+		// accounts with an API key only log in to save leads when their lists are exported through the API.
+		if acc.Password == "" && acc.APIKey == "" {
+			report(row, acc, SeverityWarning, "missing password, so the account can only log in with cookies")
+		}
+
+		if acc.URL == "" {
+			report(row, acc, SeverityError, "missing search URL")
+		} else if u, err := url.Parse(acc.URL); err != nil || u.Scheme == "" || u.Host == "" {
+			report(row, acc, SeverityError, "invalid search URL %q", acc.URL)
+		} else if base := acc.BaseURL; base == "" {
+			if !strings.HasSuffix(u.Hostname(), "apollo.io") {
+				report(row, acc, SeverityWarning, "search URL %q isn't on apollo.io", acc.URL)
+			}
+		} else if strings.ContainsAny(base, ".:") {
+			// regions are named in the configuration file, so only URLs are checked.
+			if app, err := actions.NormalizeApolloURL(base); err != nil {
+				report(row, acc, SeverityError, "invalid base URL %q", base)
+			} else if a, _ := url.Parse(app); a.Host != u.Host {
+				report(row, acc, SeverityWarning, "search URL %q isn't on the account's Apollo app %q", acc.URL, app)
+			}
+		}
+
+		if acc.List == "" {
+			report(row, acc, SeverityError, "missing list")
+		}
+
+		if acc.Target <= 0 {
+			report(row, acc, SeverityWarning, "target is %d, so no leads will be saved", acc.Target)
+		} else if acc.Saved >= acc.Target {
+			report(row, acc, SeverityWarning, "already saved %d of its %d leads", acc.Saved, acc.Target)
+		}
+
+		if acc.Window != "" {
+			if err := runner.ValidateWindow(acc.Window, acc.Timezone); err != nil {
+				report(row, acc, SeverityError, "%s", err)
+			}
+		} else if acc.Timezone != "" {
+			if _, err := time.LoadLocation(acc.Timezone); err != nil {
+				report(row, acc, SeverityError, "%s", err)
+			}
+		}
+
+		if acc.Profile != "" {
+			if _, ok := fingerprint.ByName(acc.Profile); !ok {
+				report(row, acc, SeverityWarning, "unknown device profile %q will be replaced", acc.Profile)
+			}
+		}
+
+		// accounts sharing a search are shown the same leads, which is fine if they're deduplicated.
+		if acc.URL != "" && acc.List != "" {
+			key := acc.URL + "\x00" + acc.List
+			if first, ok := searches[key]; ok {
+				report(
+					row, acc, SeverityWarning,
+					"shares its search and list with account %d, so leads may be saved twice without --dedupe-store",
+					first,
+				)
+			} else {
+				searches[key] = row
+
+				if first, ok := lists[acc.List]; ok {
+					report(row, acc, SeverityWarning, "saves a different search to account %d's list %q", first, acc.List)
+				} else {
+					lists[acc.List] = row
+				}
+			}
+		}
+	}
+
+	return issues
+}
@@ -0,0 +1,73 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lint
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/devsheke/scrapollo/internal/models"
+)
+
+func TestColumns(t *testing.T) {
+	issues := Columns([]string{"email", "pasword", "url", "list", "target"})
+	if len(issues) != 2 {
+		t.Fatalf("got issues %v; want a missing and an unknown column", issues)
+	}
+
+	if issues[0].Severity != SeverityError || !strings.Contains(issues[0].Message, `"password"`) {
+		t.Errorf("got issue %v; want the missing password column", issues[0])
+	}
+
+	if issues[1].Severity != SeverityWarning || !strings.Contains(issues[1].Message, `"pasword"`) {
+		t.Errorf("got issue %v; want the unknown pasword column", issues[1])
+	}
+}
+
+func TestAccounts(t *testing.T) {
+	const search = "https://app.apollo.io/#/people?page=1"
+
+	accounts := []*models.Account{
+		{Email: "a@example.com", Password: "x", URL: search, List: "a", Target: 100},
+		{Email: "A@example.com ", Password: "x", URL: search, List: "b", Target: 100},
+		{Email: "c@example.com", Password: "x", URL: search, List: "a", Target: 100},
+		{Email: "d@example.com", Password: "x", URL: search + "&q=1", List: "a", Target: 100},
+		{Email: "e@example.com", Password: "x", List: "e", Target: 100, Window: "9-17"},
+		{Email: "f@example.com", Password: "x", URL: "not a url", List: "f", Target: 100, Blacklisted: "banned"},
+		{Email: "g@example.com", Password: "x", URL: "https://app.eu.apollo.io/#/people", List: "g", Target: 100, BaseURL: "eu"},
+		{Email: "h@example.com", Password: "x", URL: "https://apollo.example.com/#/people", List: "h", Target: 100, BaseURL: "apollo.example.com"},
+		{Email: "i@example.com", Password: "x", URL: search + "&q=2", List: "i", Target: 100, BaseURL: "apollo.example.com"},
+	}
+
+	want := map[int]Severity{2: SeverityError, 3: SeverityWarning, 4: SeverityWarning, 5: SeverityError, 9: SeverityWarning}
+
+	got := make(map[int]int)
+	for _, issue := range Accounts(accounts) {
+		if issue.Severity != want[issue.Row] {
+			t.Errorf("unexpected issue %v", issue)
+		}
+		got[issue.Row]++
+	}
+
+	for row := range want {
+		if got[row] == 0 {
+			t.Errorf("no issue reported for account %d", row)
+		}
+	}
+
+	if got[5] != 2 {
+		t.Errorf("got %d issues for account 5; want its missing URL and invalid window", got[5])
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"bytes"
+	"io"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// quietWriter only writes warnings, errors and the messages marked with [Summary].
+type quietWriter struct {
+	io.Writer
+}
+
+var summaryMarker = []byte(`"` + SummaryField + `":true`)
+
+func (w quietWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	if level < zerolog.WarnLevel && !bytes.Contains(p, summaryMarker) {
+		return len(p), nil
+	}
+
+	return w.Write(p)
+}
+
+// moduleHook discards the messages below the level of the module (package) they're logged from,
+// falling back to the default level for modules without one.
+type moduleHook struct {
+	level   zerolog.Level
+	modules map[string]zerolog.Level
+}
+
+func (h moduleHook) Run(e *zerolog.Event, level zerolog.Level, _ string) {
+	threshold, ok := h.modules[callerModule()]
+	if !ok {
+		threshold = h.level
+	}
+
+	if level < threshold {
+		e.Discard()
+	}
+}
+
+// callerModule returns the name of the package that the message being logged comes from, i.e. the
+// first caller outside of zerolog and this package.
+func callerModule() string {
+	pcs := make([]uintptr, 16)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+
+	for {
+		frame, more := frames.Next()
+		if !strings.HasPrefix(frame.Function, "github.com/rs/zerolog") &&
+			!strings.HasPrefix(frame.Function, "github.com/devsheke/scrapollo/internal/logging.") {
+			// e.g. 'github.com/devsheke/scrapollo/internal/runner.(*Runner).saveLeads'.
+			name := frame.Function[strings.LastIndex(frame.Function, "/")+1:]
+			name, _, _ = strings.Cut(name, ".")
+			return name
+		}
+
+		if !more {
+			return ""
+		}
+	}
+}
+
+// sampleHook discards identical messages (below the warn level) once burst of them have been logged
+// in the current period.
+type sampleHook struct {
+	burst  int
+	period time.Duration
+
+	mu       sync.Mutex
+	messages map[string]*sample
+}
+
+type sample struct {
+	start time.Time
+	count int
+}
+
+func newSampleHook(burst int, period time.Duration) *sampleHook {
+	return &sampleHook{burst: burst, period: period, messages: make(map[string]*sample)}
+}
+
+func (h *sampleHook) Run(e *zerolog.Event, level zerolog.Level, msg string) {
+	if level >= zerolog.WarnLevel || msg == "" {
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	now := time.Now()
+	key := level.String() + msg
+	s, ok := h.messages[key]
+	if !ok || now.Sub(s.start) >= h.period {
+		s = &sample{start: now}
+		h.messages[key] = s
+	}
+
+	if s.count++; s.count > h.burst {
+		e.Discard()
+	}
+}
@@ -12,15 +12,48 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
+// Package logging configures the global zerolog logger used throughout scrapollo.
 package logging
 
 import (
+	"fmt"
+	"io"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
 
+// SummaryField is the field marking the messages which summarise the work done on a page (see
+// [Summary]). It's not displayed.
+const SummaryField string = "summary"
+
+// Config represents the configuration of the global logger.
+type Config struct {
+	// Level is the minimum level of logged messages.
+	Level zerolog.Level
+
+	// Modules overrides Level for the messages logged by specific packages, by their name (e.g.
+	// 'runner' or 'actions').
+	Modules map[string]zerolog.Level
+
+	// SampleBurst, if positive, is the number of identical messages (below the warn level) that are
+	// logged per SamplePeriod, e.g. to keep repeated annoyance checks from flooding the logs.
+	SampleBurst  int
+	SamplePeriod time.Duration
+
+	// Caller adds the file and line from which messages were logged.
+	Caller bool
+
+	// Quiet only logs warnings, errors and per-page summaries.
+	Quiet bool
+
+	// Out is where messages are written to, which defaults to the standard output.
+	Out io.Writer
+}
+
 // Init initialises a global logger that uses zerolog.
 func Init(debug bool) {
 	level := zerolog.InfoLevel
@@ -28,6 +61,78 @@ func Init(debug bool) {
 		level = zerolog.DebugLevel
 	}
 
-	writer := zerolog.ConsoleWriter{Out: os.Stdout, TimeFormat: "02/01/06 15:04:05-0700"}
-	log.Logger = zerolog.New(writer).With().Timestamp().Logger().Level(level)
+	Configure(Config{Level: level})
+}
+
+// Configure initialises a global logger that uses zerolog with the provided configuration.
+func Configure(c Config) {
+	if c.Out == nil {
+		c.Out = os.Stdout
+	}
+
+	var out io.Writer = zerolog.ConsoleWriter{
+		Out:           c.Out,
+		TimeFormat:    "02/01/06 15:04:05-0700",
+		FieldsExclude: []string{SummaryField},
+	}
+	if c.Quiet {
+		out = quietWriter{out}
+	}
+
+	ctx := zerolog.New(out).With().Timestamp()
+	if c.Caller {
+		ctx = ctx.Caller()
+	}
+
+	// messages are filtered by module in a hook, so the logger itself must let through the messages
+	// of the most verbose module.
+	level := c.Level
+	for _, l := range c.Modules {
+		level = min(level, l)
+	}
+
+	logger := ctx.Logger().Level(level)
+	if len(c.Modules) > 0 {
+		logger = logger.Hook(moduleHook{level: c.Level, modules: c.Modules})
+	}
+
+	if c.SampleBurst > 0 && c.SamplePeriod > 0 {
+		logger = logger.Hook(newSampleHook(c.SampleBurst, c.SamplePeriod))
+	}
+
+	log.Logger = logger
+
+	// loggers are passed down to browser actions through contexts; fall back to the global
+	// logger when a context doesn't carry one.
+	zerolog.DefaultContextLogger = &log.Logger
+}
+
+// SetRunID attaches the provided run ID to every line logged by the global logger.
+func SetRunID(id string) {
+	log.Logger = log.With().Str("run-id", id).Logger()
+}
+
+// Summary marks the event as a summary of the work done on a page, which is logged in quiet mode.
+func Summary(e *zerolog.Event) *zerolog.Event {
+	return e.Bool(SummaryField, true)
+}
+
+// ParseModuleLevels parses the levels of modules from specs in the 'module=level' format, e.g.
+// 'runner=debug'.
+func ParseModuleLevels(specs []string) (map[string]zerolog.Level, error) {
+	modules := make(map[string]zerolog.Level, len(specs))
+	for _, spec := range specs {
+		module, value, ok := strings.Cut(spec, "=")
+		if !ok || module == "" {
+			return nil, fmt.Errorf("invalid module level %q: expected 'module=level'", spec)
+		}
+
+		level, err := zerolog.ParseLevel(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid module level %q: %w", spec, err)
+		}
+		modules[module] = level
+	}
+
+	return modules, nil
 }
@@ -0,0 +1,94 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logging
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestModuleHook(t *testing.T) {
+	var buf bytes.Buffer
+	hook := moduleHook{level: zerolog.InfoLevel, modules: map[string]zerolog.Level{"logging": zerolog.WarnLevel}}
+	logger := zerolog.New(&buf).Hook(hook)
+
+	// messages logged from this package are attributed to the module of the test's caller, which is
+	// the 'testing' package.
+	logger.Info().Msg("kept")
+	if !strings.Contains(buf.String(), "kept") {
+		t.Errorf("message of a module without a level was discarded: %q", buf.String())
+	}
+
+	buf.Reset()
+	hook.modules["testing"] = zerolog.WarnLevel
+	logger.Info().Msg("dropped")
+	logger.Warn().Msg("warning")
+	if out := buf.String(); strings.Contains(out, "dropped") || !strings.Contains(out, "warning") {
+		t.Errorf("module level was not applied: %q", out)
+	}
+}
+
+func TestSampleHook(t *testing.T) {
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf).Hook(newSampleHook(2, time.Hour))
+
+	for range 5 {
+		logger.Debug().Msg("checking annoyances")
+		logger.Warn().Msg("failed to remove annoyance")
+	}
+
+	if n := strings.Count(buf.String(), "checking annoyances"); n != 2 {
+		t.Errorf("got %d sampled messages, want 2", n)
+	}
+
+	if n := strings.Count(buf.String(), "failed to remove annoyance"); n != 5 {
+		t.Errorf("got %d warnings, want all 5", n)
+	}
+}
+
+func TestQuietWriter(t *testing.T) {
+	var buf bytes.Buffer
+	logger := zerolog.New(quietWriter{&buf})
+
+	logger.Info().Msg("logging in")
+	Summary(logger.Info()).Msg("saved leads")
+	logger.Error().Msg("scraping error")
+
+	if out := buf.String(); strings.Contains(out, "logging in") ||
+		!strings.Contains(out, "saved leads") || !strings.Contains(out, "scraping error") {
+		t.Errorf("unexpected quiet output: %q", out)
+	}
+}
+
+func TestParseModuleLevels(t *testing.T) {
+	modules, err := ParseModuleLevels([]string{"runner=debug", "actions=warn"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if modules["runner"] != zerolog.DebugLevel || modules["actions"] != zerolog.WarnLevel {
+		t.Errorf("unexpected module levels: %v", modules)
+	}
+
+	for _, spec := range []string{"runner", "runner=loud", "=debug"} {
+		if _, err := ParseModuleLevels([]string{spec}); err == nil {
+			t.Errorf("no error for %q", spec)
+		}
+	}
+}
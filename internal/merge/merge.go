@@ -0,0 +1,100 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package merge consolidates leads from several output files into a single deduplicated set.
+// Leads are matched like in the dedupe store, and conflicts between rows of the same lead are
+// resolved by a list of [Rule]s.
+package merge
+
+import (
+	"fmt"
+
+	"github.com/devsheke/scrapollo/internal/dedupe"
+	"github.com/devsheke/scrapollo/internal/models"
+)
+
+// Rule decides which of two rows of the same lead is kept.
+type Rule string
+
+// The supported rules.
+const (
+	// PreferEmail keeps the row which has an email.
+	PreferEmail Rule = "email"
+
+	// PreferRecent keeps the row which was scraped most recently.
+	PreferRecent Rule = "recent"
+)
+
+// DefaultRules are the rules used when none are provided.
+var DefaultRules = []Rule{PreferEmail, PreferRecent}
+
+// ParseRule parses the name of a [Rule].
+func ParseRule(s string) (Rule, error) {
+	switch r := Rule(s); r {
+	case PreferEmail, PreferRecent:
+		return r, nil
+	default:
+		return "", fmt.Errorf("unsupported conflict resolution rule: %q", s)
+	}
+}
+
+// prefers reports whether the rule prefers b over a, a over b or neither (1, -1 and 0).
+func (r Rule) prefers(a, b *models.Lead) int {
+	switch r {
+	case PreferEmail:
+		switch {
+		case a.Email == "" && b.Email != "":
+			return 1
+		case a.Email != "" && b.Email == "":
+			return -1
+		}
+	case PreferRecent:
+		return b.ScrapedAt.Compare(a.ScrapedAt)
+	}
+
+	return 0
+}
+
+// Merge returns one row per lead, in the order leads first appear in. When a lead has several
+// rows, the rules are applied in order until one of them prefers a row; if none does, the first
+// row is kept.
+func Merge(leads []*models.Lead, rules ...Rule) []*models.Lead {
+	if len(rules) == 0 {
+		rules = DefaultRules
+	}
+
+	var merged []*models.Lead
+	index := make(map[string]int)
+	for _, lead := range leads {
+		key := dedupe.Key(lead)
+
+		i, ok := index[key]
+		if !ok {
+			index[key] = len(merged)
+			merged = append(merged, lead)
+			continue
+		}
+
+		for _, rule := range rules {
+			if p := rule.prefers(merged[i], lead); p > 0 {
+				merged[i] = lead
+				break
+			} else if p < 0 {
+				break
+			}
+		}
+	}
+
+	return merged
+}
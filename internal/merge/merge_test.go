@@ -0,0 +1,75 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package merge
+
+import (
+	"testing"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/models"
+)
+
+func TestMerge(t *testing.T) {
+	now := time.Now()
+	lead := func(name, email string, age time.Duration) *models.Lead {
+		return &models.Lead{
+			Name:       name,
+			Company:    "Acme",
+			Email:      email,
+			LeadSource: models.LeadSource{ScrapedAt: now.Add(-age)},
+		}
+	}
+
+	leads := []*models.Lead{
+		lead("Jane", "jane@old.example", 48*time.Hour),
+		lead("John", "", time.Hour),
+		lead("Jane", "jane@new.example", time.Hour),
+		lead("John", "john@example.com", 24*time.Hour),
+		lead("Jane", "", 0),
+	}
+
+	for _, tc := range []struct {
+		name  string
+		rules []Rule
+		want  []string
+	}{
+		{"default", nil, []string{"jane@new.example", "john@example.com"}},
+		{"recent", []Rule{PreferRecent}, []string{"", ""}},
+		{"email", []Rule{PreferEmail}, []string{"jane@old.example", "john@example.com"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			merged := Merge(leads, tc.rules...)
+			if len(merged) != len(tc.want) {
+				t.Fatalf("merged %d leads; want %d", len(merged), len(tc.want))
+			}
+
+			for i, lead := range merged {
+				if lead.Email != tc.want[i] {
+					t.Errorf("lead %d email = %q; want %q", i, lead.Email, tc.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseRule(t *testing.T) {
+	if _, err := ParseRule("email"); err != nil {
+		t.Error(err)
+	}
+
+	if _, err := ParseRule("oldest"); err == nil {
+		t.Error("expected an error for an unsupported rule")
+	}
+}
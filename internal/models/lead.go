@@ -0,0 +1,90 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/devsheke/scrapollo/internal/parse"
+)
+
+// Normalize parses the lead's displayed employee count and location into its [LeadDetails].
+func (l *Lead) Normalize() {
+	l.EmployeesMin, l.EmployeesMax, _ = ParseEmployees(l.Employees)
+	l.City, l.State, l.Country = ParseLocation(l.Location)
+}
+
+// employeeRange matches employee counts like '51-200', '1K - 5K', '10,001+' or '1.000–5.000'.
+var employeeRange = regexp.MustCompile(`^([\d.,'\s]+[KkMm]?)\s*(?:[-–—]\s*([\d.,'\s]+[KkMm]?)|(\+))?$`)
+
+// ParseEmployees parses an employee count or range as displayed by Apollo, e.g. '51-200', '1K-5K'
+// or '10,001+', into its bounds. The maximum of an open range is 0. Thousands separators used by
+// other locales ('1.000', '1 000' or '1'000') and decimal commas ('2,5K') are understood.
+func ParseEmployees(s string) (lo, hi int, ok bool) {
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) {
+			return ' '
+		}
+		return r
+	}, strings.TrimSpace(s))
+
+	m := employeeRange.FindStringSubmatch(s)
+	if m == nil {
+		return 0, 0, false
+	}
+
+	var err error
+	if lo, err = parse.Count(m[1]); err != nil {
+		return 0, 0, false
+	}
+
+	switch {
+	case m[3] == "+":
+		return lo, 0, true
+	case m[2] == "":
+		return lo, lo, true
+	}
+
+	if hi, err = parse.Count(m[2]); err != nil || hi < lo {
+		return 0, 0, false
+	}
+
+	return lo, hi, true
+}
+
+// ParseLocation splits a location as displayed by Apollo, e.g. 'San Francisco, California, United
+// States' or 'Berlin, Germany', into its city, state and country. A single part is taken to be the
+// country, two parts a city and its country.
+func ParseLocation(s string) (city, state, country string) {
+	var parts []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+
+	switch n := len(parts); n {
+	case 0:
+		return "", "", ""
+	case 1:
+		return "", "", parts[0]
+	case 2:
+		return parts[0], "", parts[1]
+	default:
+		return strings.Join(parts[:n-2], ", "), parts[n-2], parts[n-1]
+	}
+}
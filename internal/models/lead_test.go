@@ -0,0 +1,62 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import "testing"
+
+func TestParseEmployees(t *testing.T) {
+	for _, tc := range []struct {
+		in     string
+		lo, hi int
+		ok     bool
+	}{
+		{"51-200", 51, 200, true},
+		{"1K-5K", 1000, 5000, true},
+		{"1k - 5k", 1000, 5000, true},
+		{"10,001+", 10001, 0, true},
+		{"10K+", 10000, 0, true},
+		{"1.000–5.000", 1000, 5000, true},
+		{"1 001-5 000", 1001, 5000, true},
+		{"2,5K", 2500, 2500, true},
+		{"73", 73, 73, true},
+		{"", 0, 0, false},
+		{"200-51", 0, 0, false},
+		{"unknown", 0, 0, false},
+	} {
+		lo, hi, ok := ParseEmployees(tc.in)
+		if lo != tc.lo || hi != tc.hi || ok != tc.ok {
+			t.Errorf("ParseEmployees(%q) = %d, %d, %v; want %d, %d, %v", tc.in, lo, hi, ok, tc.lo, tc.hi, tc.ok)
+		}
+	}
+}
+
+func TestParseLocation(t *testing.T) {
+	for _, tc := range []struct {
+		in                   string
+		city, state, country string
+	}{
+		{"San Francisco, California, United States", "San Francisco", "California", "United States"},
+		{"Berlin, Germany", "Berlin", "", "Germany"},
+		{"Germany", "", "", "Germany"},
+		{"Brooklyn, New York, New York, United States", "Brooklyn, New York", "New York", "United States"},
+		{" , ", "", "", ""},
+	} {
+		city, state, country := ParseLocation(tc.in)
+		if city != tc.city || state != tc.state || country != tc.country {
+			t.Errorf("ParseLocation(%q) = %q, %q, %q; want %q, %q, %q",
+				tc.in, city, state, country, tc.city, tc.state, tc.country)
+		}
+	}
+}
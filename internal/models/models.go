@@ -20,18 +20,60 @@ import (
 	"github.com/go-rod/rod/lib/proto"
 )
 
-// Lead represents a lead from apollo.io.
+// Lead represents a lead from apollo.io. Changing its fields changes the JSON Schema of JSON output
+// files, whose version (io.LeadSchemaVersion) must be bumped.
 type Lead struct {
-	Name      string `json:"name"      csv:"name"`
-	Title     string `json:"title"     csv:"title"`
-	Company   string `json:"company"   csv:"company"`
-	Location  string `json:"location"  csv:"location"`
-	Employees string `json:"employees" csv:"employees"`
-	Industry  string `json:"industry"  csv:"industry"`
-	Keywords  string `json:"keywords"  csv:"keywords"`
-	Links     string `json:"links"     csv:"links"`
-	Email     string `json:"email"     csv:"email"`
-	Phone     string `json:"phone"     csv:"phone"`
+	Name      string  `json:"name"      csv:"name"`
+	Title     string  `json:"title"     csv:"title"`
+	Company   string  `json:"company"   csv:"company"`
+	Location  string  `json:"location"  csv:"location"`
+	Employees string  `json:"employees" csv:"employees"`
+	Industry  string  `json:"industry"  csv:"industry"`
+	Keywords  string  `json:"keywords"  csv:"keywords"`
+	Links     string  `json:"links"     csv:"links"`
+	Email     string  `json:"email"     csv:"email"`
+	Phone     string  `json:"phone"     csv:"phone"`
+	Tags      string  `json:"tags"      csv:"tags"`
+	Score     float64 `json:"score"     csv:"score"`
+	LeadDetails
+	LeadSource
+}
+
+// LeadDetails represents the details of a [Lead] parsed from its displayed values, see
+// [Lead.Normalize].
+type LeadDetails struct {
+	EmployeesMin int    `json:"employees-min" csv:"employees-min"`
+	EmployeesMax int    `json:"employees-max" csv:"employees-max"`
+	City         string `json:"city"          csv:"city"`
+	State        string `json:"state"         csv:"state"`
+	Country      string `json:"country"       csv:"country"`
+}
+
+// LeadSource represents the provenance of a [Lead], i.e., where and when it was scraped.
+type LeadSource struct {
+	Account   string    `json:"source-account" csv:"source-account"`
+	List      string    `json:"source-list"    csv:"source-list"`
+	SearchURL string    `json:"source-url"     csv:"source-url"`
+	Page      int       `json:"source-page"    csv:"source-page"`
+	ScrapedAt time.Time `json:"scraped-at"     csv:"scraped-at"`
+	RunID     string    `json:"run-id"         csv:"run-id"`
+	JobID     string    `json:"job-id"         csv:"job-id"`
+}
+
+// AccountHealth represents the results of checking the health of an [Account].
+type AccountHealth struct {
+	Email         string `json:"email"          csv:"email"`
+	HasCookies    bool   `json:"has-cookies"    csv:"has-cookies"`
+	CookiesValid  bool   `json:"cookies-valid"  csv:"cookies-valid"`
+	CookieExpiry  *Time  `json:"cookie-expiry"  csv:"cookie-expiry"`
+	CanLogin      bool   `json:"can-login"      csv:"can-login"`
+	Credits       int    `json:"credits"        csv:"credits"`
+	CreditRefresh *Time  `json:"credit-refresh" csv:"credit-refresh"`
+	Plan          string `json:"plan"           csv:"plan"`
+	TimedOut      bool   `json:"timed-out"      csv:"timed-out"`
+	Timeout       *Time  `json:"timeout"        csv:"timeout"`
+	ListExists    bool   `json:"list-exists"    csv:"list-exists"`
+	Error         string `json:"error"          csv:"error"`
 }
 
 // Account represents an apollo.io user account.
@@ -41,11 +83,21 @@ type Account struct {
 	URL           string `json:"url"            csv:"url"`
 	List          string `json:"list"           csv:"list"`
 	VpnFile       string `json:"vpn-file"       csv:"vpn-file"`
+	VpnRegion     string `json:"vpn-region"     csv:"vpn-region"`
 	Saved         int    `json:"saved"          csv:"saved"`
 	Target        int    `json:"target"         csv:"target"`
 	Credits       int    `json:"credits"        csv:"credits"`
 	CreditRefresh *Time  `json:"credit-refresh" csv:"credit-refresh"`
 	Timeout       *Time  `json:"timeout"        csv:"timeout"`
+	WarmUp        int    `json:"warm-up"        csv:"warm-up"`
+	Window        string `json:"window"         csv:"window"`
+	Timezone      string `json:"timezone"       csv:"timezone"`
+	Blacklisted   string `json:"blacklisted"    csv:"blacklisted"`
+	Profile       string `json:"profile"        csv:"profile"`
+	Org           string `json:"org"            csv:"org"`
+	Campaign      string `json:"campaign"       csv:"campaign"`
+	BaseURL       string `json:"base-url"       csv:"base-url"`
+	APIKey        string `json:"api-key"        csv:"api-key"`
 	loginCookies  []*proto.NetworkCookie
 }
 
@@ -64,6 +116,24 @@ func (a *Account) CheckCookieValidity() bool {
 	return true
 }
 
+// CookieExpiry returns the earliest expiry time of the [*Account]'s login cookies, ignoring session
+// cookies. The second return value is false if there are no cookies with an expiry time.
+func (a *Account) CookieExpiry() (time.Time, bool) {
+	var earliest time.Time
+	for _, cookie := range a.loginCookies {
+		expiry := cookie.Expires.Time()
+		if expiry.Year() == 1970 {
+			continue
+		}
+
+		if earliest.IsZero() || expiry.Before(earliest) {
+			earliest = expiry
+		}
+	}
+
+	return earliest, !earliest.IsZero()
+}
+
 // CanScrape returns true if an [*Account] has enough credits to continue
 // scraping leads.
 func (a *Account) CanScrape() bool {
@@ -14,7 +14,10 @@
 
 package models
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 // TimeFormat is the time layout used by Apollo to display time.
 const TimeFormat string = "Jan 02, 2006 3:04 PM"
@@ -66,42 +69,68 @@ func (t *Time) Reset() {
 	t.time = time.Time{}
 }
 
-func (t *Time) marshal() (string, error) {
+// ParseApolloTime parses a time displayed by Apollo (see [TimeFormat]). Apollo displays times in the
+// browser's time zone, which is assumed to be the local one.
+func ParseApolloTime(value string) (time.Time, error) {
+	return time.ParseInLocation(TimeFormat, value, time.Local)
+}
+
+func (t *Time) marshal() string {
 	if !t.valid {
-		return "", nil
+		return ""
 	}
 
-	return t.time.Format(TimeFormat), nil
+	return t.time.Format(time.RFC3339)
 }
 
+// unmarshal parses RFC3339 times, falling back to Apollo's display format (see [TimeFormat]) which
+// was used by previous versions. Empty values are treated as null.
 func (t *Time) unmarshal(record string) error {
 	if record == "" {
+		t.Reset()
 		return nil
 	}
 
-	time, err := time.Parse(TimeFormat, record)
+	_time, err := time.Parse(time.RFC3339, record)
 	if err != nil {
-		return err
+		var _err error
+		if _time, _err = ParseApolloTime(record); _err != nil {
+			return err
+		}
 	}
 
-	*t = Time{valid: true, time: time}
+	t.Set(_time)
 
 	return nil
 }
 
 func (t *Time) MarshalCSV() (string, error) {
-	return t.marshal()
+	return t.marshal(), nil
 }
 
 func (t *Time) UnmarshalCSV(record string) error {
 	return t.unmarshal(record)
 }
 
+// MarshalJSON marshals the time as a quoted RFC3339 string, or null if the time is not valid.
 func (t *Time) MarshalJSON() ([]byte, error) {
-	s, err := t.marshal()
-	return []byte(s), err
+	if !t.valid {
+		return []byte("null"), nil
+	}
+
+	return json.Marshal(t.marshal())
 }
 
 func (t *Time) UnmarshalJSON(field []byte) error {
-	return t.unmarshal(string(field))
+	if string(field) == "null" {
+		t.Reset()
+		return nil
+	}
+
+	var record string
+	if err := json.Unmarshal(field, &record); err != nil {
+		return err
+	}
+
+	return t.unmarshal(record)
 }
@@ -0,0 +1,108 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package models
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestTimeJSON(t *testing.T) {
+	loc := time.FixedZone("IST", 5*60*60+30*60)
+	want := time.Date(2025, time.March, 4, 13, 45, 0, 0, loc)
+
+	b, err := json.Marshal(struct{ T *Time }{NewTimeValid(want)})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := string(b); got != `{"T":"2025-03-04T13:45:00+05:30"}` {
+		t.Fatalf("unexpected JSON: %s", got)
+	}
+
+	var v struct{ T *Time }
+	if err := json.Unmarshal(b, &v); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, ok := v.T.Get(); !ok || !got.Equal(want) {
+		t.Fatalf("expected %v, got %v (valid: %t)", want, got, ok)
+	}
+}
+
+func TestTimeJSONNull(t *testing.T) {
+	b, err := json.Marshal(struct{ T *Time }{NewTime()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := string(b); got != `{"T":null}` {
+		t.Fatalf("unexpected JSON: %s", got)
+	}
+
+	for _, field := range []string{`null`, `""`} {
+		v := struct{ T *Time }{NewTimeValid(time.Now())}
+		if err := json.Unmarshal([]byte(`{"T":`+field+`}`), &v); err != nil {
+			t.Fatal(err)
+		}
+
+		if v.T != nil && v.T.Valid() {
+			t.Fatalf("expected %s to unmarshal to an invalid time", field)
+		}
+	}
+}
+
+func TestTimeUnmarshalApolloFormat(t *testing.T) {
+	var _t Time
+	if err := _t.UnmarshalCSV("Mar 04, 2025 1:45 PM"); err != nil {
+		t.Fatal(err)
+	}
+
+	want := time.Date(2025, time.March, 4, 13, 45, 0, 0, time.Local)
+	if got, ok := _t.Get(); !ok || !got.Equal(want) {
+		t.Fatalf("expected %v, got %v (valid: %t)", want, got, ok)
+	}
+
+	if err := _t.UnmarshalCSV("not a time"); err == nil {
+		t.Fatal("expected an error for an invalid time")
+	}
+}
+
+func TestTimeCSV(t *testing.T) {
+	want := time.Date(2025, time.March, 4, 13, 45, 0, 0, time.UTC)
+
+	record, err := NewTimeValid(want).MarshalCSV()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if record != "2025-03-04T13:45:00Z" {
+		t.Fatalf("unexpected record: %s", record)
+	}
+
+	var _t Time
+	if err := _t.UnmarshalCSV(record); err != nil {
+		t.Fatal(err)
+	}
+
+	if got, ok := _t.Get(); !ok || !got.Equal(want) {
+		t.Fatalf("expected %v, got %v (valid: %t)", want, got, ok)
+	}
+
+	if record, _ := NewTime().MarshalCSV(); record != "" {
+		t.Fatalf("expected an empty record for an invalid time, got %q", record)
+	}
+}
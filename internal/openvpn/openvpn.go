@@ -1,17 +1,18 @@
 package openvpn
 
 import (
-	"context"
 	"errors"
+	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
 	"slices"
+	"sync"
 	"time"
 
 	"math/rand/v2"
 
 	openvpn "github.com/devsheke/scrapollo/pkg/openvpn-go"
-	"github.com/go-cmd/cmd"
 	"github.com/rs/zerolog/log"
 )
 
@@ -21,30 +22,113 @@ var (
 
 	// ErrorNoUnusedConfigs indicates that all given configurations have been used previously.
 	ErrorNoUnusedConfigs = errors.New("no unused openvpn configuration files were found")
+
+	// ErrorConcurrentTunnels indicates that concurrent tunnels were requested without split-tunnel mode.
+	ErrorConcurrentTunnels = errors.New("concurrent openvpn tunnels require split-tunnel mode")
+)
+
+const (
+	// DefaultTimeout is the default time allowed for OpenVPN to connect.
+	DefaultTimeout = time.Minute
+
+	// backupTimeout is the max time allowed for finding a working backup config.
+	backupTimeout = 5 * time.Minute
 )
 
 // Manager is a type that is used to configure and control a instances of OpenVPN.
 type Manager struct {
 	args, auth, dir string
 	configs         []string
-	process         *cmd.Cmd
-	status          <-chan cmd.Status
+	cooldown        time.Duration
+	mu              sync.RWMutex
+	regions         map[string]string
+	reserved        map[string]struct{}
+	splitTunnel     bool
+	started         time.Time
+	state           state
+	stateFile       string
 	timeout         time.Duration
-	used            map[string]struct{}
+	tunnels         map[int]*Tunnel
+}
+
+// ManagerOpt is a function that configures a [Manager].
+type ManagerOpt func(*Manager)
+
+// Cooldown is a [ManagerOpt] func that configures how long a config is considered used for after it was
+// last used, including in previous runs recorded in the state file. With a zero cooldown, configs are only
+// considered used for the rest of the run they were used in.
+func Cooldown(d time.Duration) ManagerOpt {
+	return func(v *Manager) {
+		v.cooldown = d
+	}
+}
+
+// SplitTunnel is a [ManagerOpt] func that configures the [Manager] to only route connections made
+// through its SOCKS5 proxy (see [Manager.Proxy]) through the VPN, leaving the host's other traffic
+// on its own network. It is only supported on Linux.
+func SplitTunnel(enabled bool) ManagerOpt {
+	return func(v *Manager) {
+		v.splitTunnel = enabled
+	}
+}
+
+// StateFile is a [ManagerOpt] func that configures the file in which the usage history of the configs is
+// persisted across runs.
+func StateFile(path string) ManagerOpt {
+	return func(v *Manager) {
+		v.stateFile = path
+	}
+}
+
+// Status describes an instance of OpenVPN controlled by a [Manager].
+type Status struct {
+	// Tunnel is the ID of the instance's [Tunnel].
+	Tunnel int `json:"tunnel"`
+
+	// Config is the config the instance was started with.
+	Config string `json:"config,omitempty"`
+
+	// Running is true if the instance is running.
+	Running bool `json:"running"`
+
+	openvpn.Status
 }
 
 // NewManager returns a configured instance of [*Manager].
-func NewManager(configsDir, auth, args string) (*Manager, error) {
+func NewManager(configsDir, auth, args string, opts ...ManagerOpt) (*Manager, error) {
 	configs, err := loadConfigs(configsDir)
 	if err != nil {
 		return nil, err
 	}
 	v := &Manager{
-		args:    args,
-		auth:    auth,
-		configs: configs,
-		dir:     configsDir,
-		used:    make(map[string]struct{}),
+		args:     args,
+		auth:     auth,
+		configs:  configs,
+		dir:      configsDir,
+		regions:  make(map[string]string, len(configs)),
+		reserved: make(map[string]struct{}),
+		started:  time.Now(),
+		state:    state{LastUsed: make(map[string]time.Time)},
+		timeout:  DefaultTimeout,
+		tunnels:  make(map[int]*Tunnel),
+	}
+
+	for _, opt := range opts {
+		opt(v)
+	}
+
+	for _, config := range configs {
+		v.regions[config] = parseRegion(config, filepath.Join(configsDir, config))
+	}
+
+	if _, err := v.Tunnel(0); err != nil {
+		return nil, err
+	}
+
+	if v.stateFile != "" {
+		if v.state, err = loadState(v.stateFile); err != nil {
+			return nil, fmt.Errorf("failed to load vpn state file: %w", err)
+		}
 	}
 
 	return v, nil
@@ -67,61 +151,155 @@ func loadConfigs(dir string) ([]string, error) {
 	return configs, nil
 }
 
-// Start spawns a new instance of OpenVPN with the provided config.
-func (v *Manager) Start(config string) error {
-	if !slices.Contains(v.configs, config) {
-		return openvpn.ErrorConfigNotFound
+// Tunnel returns the [Manager]'s tunnel with the given ID, creating it if necessary. Each tunnel is
+// a separate instance of OpenVPN, with its own tun device, routing table and exit IP, which allows
+// concurrent workers to each use their own VPN connection. Tunnel 0 is the one controlled by the
+// [Manager]'s own methods. Tunnels other than 0 require split-tunnel mode, since concurrent tunnels
+// can't all route the host's traffic.
+func (v *Manager) Tunnel(id int) (*Tunnel, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if t, ok := v.tunnels[id]; ok {
+		return t, nil
 	}
-	config = filepath.Join(v.dir, config)
 
-	var err error
-	v.process, v.status, err = openvpn.Start(config, v.auth, v.args, v.timeout)
-	if err != nil {
-		return err
+	if id != 0 && !v.splitTunnel {
+		return nil, ErrorConcurrentTunnels
 	}
 
-	if _, used := v.used[config]; !used {
-		v.UseConfig(config)
+	t, err := newTunnel(v, id)
+	if err != nil {
+		return nil, err
 	}
+	v.tunnels[id] = t
+
+	return t, nil
+}
 
-	return nil
+// tunnel returns the [Manager]'s default tunnel.
+func (v *Manager) tunnel() *Tunnel {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	return v.tunnels[0]
+}
+
+// Start spawns a new instance of OpenVPN with the provided config.
+func (v *Manager) Start(config string) error {
+	return v.tunnel().Start(config)
+}
+
+// Proxy returns the URL of the SOCKS5 proxy whose connections are routed through the VPN in
+// split-tunnel mode, or an empty string if split tunnelling is disabled.
+func (v *Manager) Proxy() string {
+	return v.tunnel().Proxy()
 }
 
 // Stop attemps to stop the currently running instance of OpenVPN.
 func (v *Manager) Stop() error {
-	return openvpn.Stop(v.process)
+	return v.tunnel().Stop()
+}
+
+// Close stops all of the [Manager]'s tunnels, removing their split-tunnel routes and closing their
+// SOCKS5 proxies. It is meant to be called once the [Manager] is no longer needed.
+func (v *Manager) Close() error {
+	v.mu.RLock()
+	tunnels := slices.Collect(maps.Values(v.tunnels))
+	v.mu.RUnlock()
+
+	var errs []error
+	for _, t := range tunnels {
+		if err := t.close(); err != nil {
+			errs = append(errs, fmt.Errorf("tunnel %d: %w", t.id, err))
+		}
+	}
+
+	return errors.Join(errs...)
 }
 
 // Restart restarts the currently running instance of OpenVPN with the provided config.
 func (v *Manager) Restart(config string) error {
-	var err error
-	v.process, v.status, err = openvpn.Restart(
-		v.process,
-		filepath.Join(v.dir, config),
-		v.auth,
-		v.args,
-		v.timeout,
-	)
+	return v.tunnel().Restart(config)
+}
 
-	return err
+// Status returns the status of the [Manager]'s instance of OpenVPN, as reported by its
+// management interface.
+func (v *Manager) Status() Status {
+	return v.tunnel().Status()
 }
 
-// UseConfig adds the provided config to a cache of previously used config files.
+// Backup is meant to be used after starting an OpenVPN instance fails. This function
+// attempts to start an instance of OpenVPN and returns the config used to spawn
+// the instance if successful in trying to do so.
+//
+// If a region is provided, unused configs from that region are preferred, falling
+// back to unused configs from any region.
+func (v *Manager) Backup(region string) (string, error) {
+	return v.tunnel().Backup(region)
+}
+
+// UseConfig records that the provided config was just used, persisting it to the state file (if any).
 func (v *Manager) UseConfig(config string) {
-	v.used[config] = struct{}{}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	v.state.LastUsed[config] = time.Now()
+	if v.stateFile == "" {
+		return
+	}
+
+	if err := v.state.save(v.stateFile); err != nil {
+		log.Warn().Err(err).Str("file", v.stateFile).Msg("failed to save vpn state")
+	}
 }
 
-// IsConfigUsed returns true if the provided config has been used before.
+// IsConfigUsed returns true if the provided config was used within the cooldown (or during the
+// current run, if there is no cooldown).
 func (v *Manager) IsConfigUsed(config string) bool {
-	_, used := v.used[config]
-	return used
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	lastUsed, ok := v.state.LastUsed[config]
+	if !ok {
+		return false
+	}
+
+	if v.cooldown > 0 {
+		return time.Since(lastUsed) < v.cooldown
+	}
+	return !lastUsed.Before(v.started)
 }
 
-func (v *Manager) filterUnused() []string {
+// Region returns the region (country code) of the provided config, parsed from its name or its
+// remote, or an empty string if it is unknown.
+func (v *Manager) Region(config string) string {
+	return v.regions[config]
+}
+
+// active returns true if the provided config is being used by one of the [Manager]'s tunnels.
+func (v *Manager) active(config string) bool {
+	v.mu.RLock()
+	tunnels := slices.Collect(maps.Values(v.tunnels))
+	v.mu.RUnlock()
+
+	return slices.ContainsFunc(tunnels, func(t *Tunnel) bool {
+		status := t.Status()
+		return status.Running && status.Config == config
+	})
+}
+
+// filterUnused returns the unused configs in the provided region (or in any region, if it is empty)
+// in random order.
+func (v *Manager) filterUnused(region string) []string {
 	configs := make([]string, 0, len(v.configs))
 
+	region = normalizeRegion(region)
 	for _, config := range v.configs {
-		if !v.IsConfigUsed(config) {
+		if region != "" && v.regions[config] != region {
+			continue
+		}
+
+		if !v.IsConfigUsed(config) && !v.active(config) && !v.reservedBy(config) {
 			configs = append(configs, config)
 		}
 	}
@@ -133,35 +311,38 @@ func (v *Manager) filterUnused() []string {
 	return configs
 }
 
-// Backup is meant to be used after starting an OpenVPN instance fails. This function
-// attempts to start an instance of OpenVPN and returns the config used to spawn
-// the instance if successful in trying to do so.
-func (v *Manager) Backup() (string, error) {
-	log.Debug().Msg("fetching backup config since previous failed")
+// reserve reserves the provided config while a tunnel starts it as a backup, so that concurrent backups
+// don't start the same config. It returns false if the config is already reserved, or if it was used or
+// started in the meantime.
+func (v *Manager) reserve(config string) bool {
+	v.mu.Lock()
+	if _, ok := v.reserved[config]; ok {
+		v.mu.Unlock()
+		return false
+	}
+	v.reserved[config] = struct{}{}
+	v.mu.Unlock()
 
-	unused := v.filterUnused()
-	if len(unused) < 1 {
-		return "", ErrorNoUnusedConfigs
+	// checked after reserving the config, since a tunnel records using it before releasing it.
+	if v.IsConfigUsed(config) || v.active(config) {
+		v.release(config)
+		return false
 	}
 
-	usedCache := make(map[int]int)
+	return true
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
-	defer cancel()
-	for {
-		select {
-		case <-ctx.Done():
-			return "", openvpn.ErrorVpnTimedOut{Msg: "too many retries"}
-		default:
-		}
-		r := rand.IntN(len(unused))
-		if _, used := usedCache[r]; used {
-			continue
-		}
+// release releases the provided config reserved by [Manager.reserve].
+func (v *Manager) release(config string) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	delete(v.reserved, config)
+}
 
-		if err := v.Start(unused[r]); err == nil {
-			log.Debug().Str("config", unused[r]).Msg("got backup config")
-			return unused[r], nil
-		}
-	}
+// reservedBy returns true if the provided config is reserved by one of the [Manager]'s tunnels.
+func (v *Manager) reservedBy(config string) bool {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+	_, ok := v.reserved[config]
+	return ok
 }
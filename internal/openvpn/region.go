@@ -0,0 +1,107 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openvpn
+
+import (
+	"bufio"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// countryPrefix matches the country code that VPN providers prefix the names of their configs and
+// servers with (e.g. 'de-berlin-1.ovpn', 'us1234.nordvpn.com.udp.ovpn' or 'NL_Amsterdam.ovpn').
+var countryPrefix = regexp.MustCompile(`^([a-z]{2})(?:[^a-z]|$)`)
+
+// continents maps ISO 3166-1 alpha-2 country codes to the continent they are on.
+var continents = map[string]string{
+	// Africa
+	"dz": "af", "eg": "af", "gh": "af", "ke": "af", "ma": "af", "ng": "af", "tn": "af", "za": "af",
+
+	// Asia
+	"ae": "as", "bd": "as", "cn": "as", "hk": "as", "id": "as", "il": "as", "in": "as", "jp": "as",
+	"kr": "as", "kz": "as", "my": "as", "ph": "as", "pk": "as", "qa": "as", "sa": "as", "sg": "as",
+	"th": "as", "tr": "as", "tw": "as", "vn": "as",
+
+	// Europe
+	"al": "eu", "at": "eu", "ba": "eu", "be": "eu", "bg": "eu", "ch": "eu", "cy": "eu", "cz": "eu",
+	"de": "eu", "dk": "eu", "ee": "eu", "es": "eu", "fi": "eu", "fr": "eu", "gb": "eu", "gr": "eu",
+	"hr": "eu", "hu": "eu", "ie": "eu", "is": "eu", "it": "eu", "lt": "eu", "lu": "eu", "lv": "eu",
+	"md": "eu", "mk": "eu", "mt": "eu", "nl": "eu", "no": "eu", "pl": "eu", "pt": "eu", "ro": "eu",
+	"rs": "eu", "se": "eu", "si": "eu", "sk": "eu", "ua": "eu",
+
+	// North America
+	"ca": "na", "cr": "na", "mx": "na", "pa": "na", "us": "na",
+
+	// Oceania
+	"au": "oc", "nz": "oc",
+
+	// South America
+	"ar": "sa", "bo": "sa", "br": "sa", "cl": "sa", "co": "sa", "ec": "sa", "pe": "sa", "uy": "sa",
+	"ve": "sa",
+}
+
+// Continent returns the continent of the given region (country code), or an empty string if it
+// is unknown.
+func Continent(region string) string {
+	return continents[normalizeRegion(region)]
+}
+
+// normalizeRegion lowercases a region and maps aliases used by VPN providers to their ISO code.
+func normalizeRegion(region string) string {
+	region = strings.ToLower(strings.TrimSpace(region))
+	if region == "uk" {
+		return "gb"
+	}
+	return region
+}
+
+// parseRegion returns the region (country code) of a config, parsed from its name or, failing
+// that, from the hostname of its first remote. An empty string is returned if it is unknown.
+func parseRegion(name, path string) string {
+	if region := countryRegion(name); region != "" {
+		return region
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "remote" {
+			return countryRegion(fields[1])
+		}
+	}
+
+	return ""
+}
+
+// countryRegion returns the country code that the given name is prefixed with, if it is a known one.
+func countryRegion(name string) string {
+	match := countryPrefix.FindStringSubmatch(strings.ToLower(name))
+	if match == nil {
+		return ""
+	}
+
+	region := normalizeRegion(match[1])
+	if _, ok := continents[region]; !ok {
+		return ""
+	}
+	return region
+}
@@ -0,0 +1,61 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openvpn
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParseRegion(t *testing.T) {
+	dir := t.TempDir()
+	remote := filepath.Join(dir, "server-1.ovpn")
+	if err := os.WriteFile(remote, []byte("client\ndev tun\nremote jp42.example.com 1194\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct{ name, path, want string }{
+		{"de-berlin-1.ovpn", "", "de"},
+		{"us1234.nordvpn.com.udp.ovpn", "", "us"},
+		{"NL_Amsterdam.ovpn", "", "nl"},
+		{"uk-london.ovpn", "", "gb"},
+		{"server-1.ovpn", remote, "jp"},
+		{"server-2.ovpn", filepath.Join(dir, "missing.ovpn"), ""},
+	}
+
+	for _, tt := range tests {
+		if got := parseRegion(tt.name, tt.path); got != tt.want {
+			t.Errorf("parseRegion(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFilterUnusedByRegion(t *testing.T) {
+	v, _ := testManager(t)
+
+	if unused := v.filterUnused("DE"); len(unused) != 1 || unused[0] != "de-1.ovpn" {
+		t.Fatalf("unexpected configs in region 'DE': %v", unused)
+	}
+
+	v.UseConfig("de-1.ovpn")
+	if unused := v.filterUnused("de"); len(unused) != 0 {
+		t.Fatalf("unexpected configs in region 'de': %v", unused)
+	}
+
+	if Continent("de") != Continent("nl") || Continent("de") == Continent("us") {
+		t.Fatal("unexpected continents")
+	}
+}
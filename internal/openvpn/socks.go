@@ -0,0 +1,198 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openvpn
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+)
+
+// SOCKS5 constants (RFC 1928).
+const (
+	socksVersion       byte = 5
+	socksNoAuth        byte = 0
+	socksNoMethods     byte = 0xff
+	socksConnect       byte = 1
+	socksIPv4          byte = 1
+	socksDomain        byte = 3
+	socksIPv6          byte = 4
+	socksSucceeded     byte = 0
+	socksHostFailure   byte = 4
+	socksBadCommand    byte = 7
+	socksBadAddrFormat byte = 8
+)
+
+// socksServer is a minimal SOCKS5 server that only supports unauthenticated CONNECT requests,
+// which it dials with the provided function.
+type socksServer struct {
+	dial     func(network, addr string) (net.Conn, error)
+	listener net.Listener
+	wg       sync.WaitGroup
+}
+
+// newSocksServer starts a SOCKS5 server on a free local port.
+func newSocksServer(dial func(network, addr string) (net.Conn, error)) (*socksServer, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &socksServer{dial: dial, listener: l}
+	s.wg.Add(1)
+	go s.serve()
+
+	return s, nil
+}
+
+// addr returns the address the server is listening on.
+func (s *socksServer) addr() string {
+	return s.listener.Addr().String()
+}
+
+func (s *socksServer) close() error {
+	err := s.listener.Close()
+	s.wg.Wait()
+	return err
+}
+
+func (s *socksServer) serve() {
+	defer s.wg.Done()
+
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		go func() {
+			defer conn.Close()
+			if err := s.handle(conn); err != nil {
+				log.Debug().Err(err).Msg("socks connection failed")
+			}
+		}()
+	}
+}
+
+func (s *socksServer) handle(conn net.Conn) error {
+	// greeting: version, number of methods, methods.
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return err
+	}
+	if header[0] != socksVersion {
+		return fmt.Errorf("unsupported socks version: %d", header[0])
+	}
+
+	methods := make([]byte, header[1])
+	if _, err := io.ReadFull(conn, methods); err != nil {
+		return err
+	}
+	if !slices.Contains(methods, socksNoAuth) {
+		_, _ = conn.Write([]byte{socksVersion, socksNoMethods})
+		return errors.New("client does not support unauthenticated connections")
+	}
+	if _, err := conn.Write([]byte{socksVersion, socksNoAuth}); err != nil {
+		return err
+	}
+
+	// request: version, command, reserved, address type, address, port.
+	request := make([]byte, 4)
+	if _, err := io.ReadFull(conn, request); err != nil {
+		return err
+	}
+	if request[1] != socksConnect {
+		_ = reply(conn, socksBadCommand)
+		return fmt.Errorf("unsupported socks command: %d", request[1])
+	}
+
+	host, err := readAddr(conn, request[3])
+	if err != nil {
+		_ = reply(conn, socksBadAddrFormat)
+		return err
+	}
+
+	port := make([]byte, 2)
+	if _, err := io.ReadFull(conn, port); err != nil {
+		return err
+	}
+	addr := net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(port))))
+
+	target, err := s.dial("tcp", addr)
+	if err != nil {
+		_ = reply(conn, socksHostFailure)
+		return fmt.Errorf("failed to dial %s: %w", addr, err)
+	}
+	defer target.Close()
+
+	if err := reply(conn, socksSucceeded); err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+	go func() {
+		_, _ = io.Copy(target, conn)
+		if tcp, ok := target.(*net.TCPConn); ok {
+			_ = tcp.CloseWrite()
+		}
+		close(done)
+	}()
+	_, _ = io.Copy(conn, target)
+	<-done
+
+	return nil
+}
+
+// readAddr reads a SOCKS5 address of the given type.
+func readAddr(r io.Reader, addrType byte) (string, error) {
+	switch addrType {
+	case socksIPv4, socksIPv6:
+		ip := make(net.IP, net.IPv4len)
+		if addrType == socksIPv6 {
+			ip = make(net.IP, net.IPv6len)
+		}
+		if _, err := io.ReadFull(r, ip); err != nil {
+			return "", err
+		}
+		return ip.String(), nil
+
+	case socksDomain:
+		length := make([]byte, 1)
+		if _, err := io.ReadFull(r, length); err != nil {
+			return "", err
+		}
+		domain := make([]byte, length[0])
+		if _, err := io.ReadFull(r, domain); err != nil {
+			return "", err
+		}
+		return string(domain), nil
+
+	default:
+		return "", fmt.Errorf("unsupported socks address type: %d", addrType)
+	}
+}
+
+// reply sends a SOCKS5 reply with the given status. The bound address is always reported as 0.0.0.0:0.
+func reply(w io.Writer, status byte) error {
+	_, err := w.Write([]byte{socksVersion, status, 0, socksIPv4, 0, 0, 0, 0, 0, 0})
+	return err
+}
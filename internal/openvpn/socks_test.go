@@ -0,0 +1,86 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openvpn
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"testing"
+)
+
+func TestSocksServer(t *testing.T) {
+	echo, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer echo.Close()
+
+	go func() {
+		conn, err := echo.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		_, _ = io.Copy(conn, conn)
+	}()
+
+	var dialed string
+	server, err := newSocksServer(func(network, addr string) (net.Conn, error) {
+		dialed = addr
+		return net.Dial(network, echo.Addr().String())
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.close()
+
+	conn, err := net.Dial("tcp", server.addr())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// greeting, then a CONNECT request for example.com:443.
+	request := []byte{5, 1, 0, 5, 1, 0, 3, 11}
+	request = append(request, "example.com"...)
+	request = append(request, 0x01, 0xbb)
+	if _, err := conn.Write(request); err != nil {
+		t.Fatal(err)
+	}
+
+	replies := make([]byte, 12)
+	if _, err := io.ReadFull(conn, replies); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(replies[:4], []byte{5, 0, 5, 0}) {
+		t.Fatalf("unexpected replies: %v", replies)
+	}
+
+	if dialed != "example.com:443" {
+		t.Fatalf("unexpected address dialed: %q", dialed)
+	}
+
+	if _, err := conn.Write([]byte("ping")); err != nil {
+		t.Fatal(err)
+	}
+	pong := make([]byte, 4)
+	if _, err := io.ReadFull(conn, pong); err != nil {
+		t.Fatal(err)
+	}
+	if string(pong) != "ping" {
+		t.Fatalf("unexpected echo: %q", pong)
+	}
+}
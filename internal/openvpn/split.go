@@ -0,0 +1,137 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openvpn
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ErrorTunnelDown is returned when dialing through a split tunnel whose VPN is not connected.
+var ErrorTunnelDown = errors.New("the vpn tunnel is not connected")
+
+// splitTable is the routing table of the first split tunnel. Each tunnel gets its own table.
+const splitTable = 5151
+
+// splitTunnel routes the connections made through its SOCKS5 proxy, and only those, through the VPN.
+type splitTunnel struct {
+	// current returns the current address of the tunnel.
+	current func() string
+
+	// device and table are the tunnel's tun device and routing table.
+	device string
+	table  int
+
+	mu      sync.Mutex
+	routed  string
+	server  *socksServer
+	timeout time.Duration
+}
+
+func newSplitTunnel(id int, current func() string) (*splitTunnel, error) {
+	if err := splitTunnelSupported(); err != nil {
+		return nil, err
+	}
+
+	s := &splitTunnel{
+		current: current,
+		device:  fmt.Sprintf("scrapollo%d", id),
+		table:   splitTable + id,
+		timeout: 30 * time.Second,
+	}
+
+	server, err := newSocksServer(s.dial)
+	if err != nil {
+		return nil, err
+	}
+	s.server = server
+
+	return s, nil
+}
+
+// args returns the OpenVPN arguments used in split-tunnel mode. OpenVPN is prevented from changing
+// the host's routes, so that only connections made from the tunnel's address are routed through it.
+func (s *splitTunnel) args() []string {
+	return []string{"--route-nopull", "--dev", s.device, "--dev-type", "tun"}
+}
+
+// route routes connections made from the given address of the tunnel through it.
+func (s *splitTunnel) route(localIP string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if localIP == s.routed {
+		return nil
+	}
+
+	if s.routed != "" {
+		if err := unrouteSplitTunnel(s.table, s.routed); err != nil {
+			log.Debug().Err(err).Msg("failed to remove split tunnel route")
+		}
+		s.routed = ""
+	}
+
+	if localIP == "" {
+		return ErrorTunnelDown
+	}
+
+	if err := routeSplitTunnel(s.device, s.table, localIP); err != nil {
+		return err
+	}
+	s.routed = localIP
+
+	return nil
+}
+
+// unroute removes the tunnel's routes.
+func (s *splitTunnel) unroute() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.routed == "" {
+		return nil
+	}
+
+	err := unrouteSplitTunnel(s.table, s.routed)
+	s.routed = ""
+
+	return err
+}
+
+// dial connects to the given address from the tunnel's address, re-routing the tunnel if its
+// address changed (e.g. after OpenVPN reconnected).
+func (s *splitTunnel) dial(network, addr string) (net.Conn, error) {
+	localIP := s.current()
+	if err := s.route(localIP); err != nil {
+		return nil, err
+	}
+
+	dialer := net.Dialer{
+		LocalAddr: &net.TCPAddr{IP: net.ParseIP(localIP)},
+		Timeout:   s.timeout,
+	}
+
+	return dialer.Dial(network, addr)
+}
+
+// proxy returns the URL of the tunnel's SOCKS5 proxy.
+func (s *splitTunnel) proxy() string {
+	return "socks5://" + s.server.addr()
+}
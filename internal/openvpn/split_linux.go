@@ -0,0 +1,63 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package openvpn
+
+import (
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	openvpn "github.com/devsheke/scrapollo/pkg/openvpn-go"
+)
+
+func splitTunnelSupported() error {
+	if _, err := exec.LookPath("ip"); err != nil {
+		return fmt.Errorf("split tunnelling requires iproute2: %w", err)
+	}
+	return nil
+}
+
+// routeSplitTunnel routes connections made from the tunnel's address through it, using a
+// separate routing table so that the host's default route is left untouched.
+func routeSplitTunnel(device string, table int, localIP string) error {
+	if err := ip("route", "replace", "default", "dev", device, "table", strconv.Itoa(table)); err != nil {
+		return err
+	}
+	return ip("rule", "add", "from", localIP, "table", strconv.Itoa(table))
+}
+
+// unrouteSplitTunnel removes the rule routing the tunnel's address. The route itself is removed
+// along with the tun device when OpenVPN exits.
+func unrouteSplitTunnel(table int, localIP string) error {
+	return ip("rule", "del", "from", localIP, "table", strconv.Itoa(table))
+}
+
+// ip runs an iproute2 command, through sudo if the current process is not elevated.
+func ip(args ...string) error {
+	name := "ip"
+	if !openvpn.IsElevated() {
+		name, args = "sudo", append([]string{"-n", "ip"}, args...)
+	}
+
+	out, err := exec.Command(name, args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%s %s: %w: %s", name, strings.Join(args, " "), err, strings.TrimSpace(string(out)))
+	}
+
+	return nil
+}
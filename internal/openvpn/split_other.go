@@ -0,0 +1,35 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !linux
+
+package openvpn
+
+import "errors"
+
+// ErrorSplitTunnelUnsupported is returned when split tunnelling is requested on a platform
+// other than Linux.
+var ErrorSplitTunnelUnsupported = errors.New("split tunnelling is only supported on linux")
+
+func splitTunnelSupported() error {
+	return ErrorSplitTunnelUnsupported
+}
+
+func routeSplitTunnel(string, int, string) error {
+	return ErrorSplitTunnelUnsupported
+}
+
+func unrouteSplitTunnel(int, string) error {
+	return ErrorSplitTunnelUnsupported
+}
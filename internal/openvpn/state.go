@@ -0,0 +1,69 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openvpn
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// state is the usage history of a [Manager]'s configs that is persisted across runs.
+type state struct {
+	// LastUsed maps the name of each config to when it was last used.
+	LastUsed map[string]time.Time `json:"last-used"`
+}
+
+// loadState reads the state file at the given path. A missing file yields an empty state.
+func loadState(path string) (state, error) {
+	s := state{LastUsed: make(map[string]time.Time)}
+
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	} else if err != nil {
+		return s, err
+	}
+
+	if err := json.Unmarshal(b, &s); err != nil {
+		return s, err
+	}
+	if s.LastUsed == nil {
+		s.LastUsed = make(map[string]time.Time)
+	}
+
+	return s, nil
+}
+
+// save atomically writes the state to the file at the given path.
+func (s state) save(path string) error {
+	b, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp, path)
+}
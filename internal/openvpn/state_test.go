@@ -0,0 +1,85 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openvpn
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func testManager(t *testing.T, opts ...ManagerOpt) (*Manager, string) {
+	dir := t.TempDir()
+	configs := filepath.Join(dir, "configs")
+	if err := os.Mkdir(configs, 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"de-1.ovpn", "nl-1.ovpn"} {
+		if err := os.WriteFile(filepath.Join(configs, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	stateFile := filepath.Join(dir, "vpn-state.json")
+	v, err := NewManager(configs, "", "", append(opts, StateFile(stateFile))...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return v, stateFile
+}
+
+func TestConfigCooldown(t *testing.T) {
+	v, stateFile := testManager(t, Cooldown(time.Hour))
+	v.UseConfig("de-1.ovpn")
+
+	if !v.IsConfigUsed("de-1.ovpn") || v.IsConfigUsed("nl-1.ovpn") {
+		t.Fatal("expected only de-1.ovpn to be used")
+	}
+
+	// a new run still honours the cooldown of configs used in previous runs.
+	next, err := NewManager(v.dir, "", "", Cooldown(time.Hour), StateFile(stateFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !next.IsConfigUsed("de-1.ovpn") {
+		t.Fatal("expected de-1.ovpn to be cooling down")
+	}
+
+	next.state.LastUsed["de-1.ovpn"] = time.Now().Add(-2 * time.Hour)
+	if next.IsConfigUsed("de-1.ovpn") {
+		t.Fatal("expected de-1.ovpn's cooldown to have expired")
+	}
+}
+
+func TestConfigUsedWithoutCooldown(t *testing.T) {
+	v, stateFile := testManager(t)
+	v.UseConfig("de-1.ovpn")
+
+	if !v.IsConfigUsed("de-1.ovpn") {
+		t.Fatal("expected de-1.ovpn to be used")
+	}
+
+	// without a cooldown, configs used in previous runs are available again.
+	next, err := NewManager(v.dir, "", "", StateFile(stateFile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next.IsConfigUsed("de-1.ovpn") {
+		t.Fatal("expected de-1.ovpn to be unused")
+	}
+}
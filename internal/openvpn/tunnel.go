@@ -0,0 +1,223 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openvpn
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+
+	openvpn "github.com/devsheke/scrapollo/pkg/openvpn-go"
+	"github.com/go-cmd/cmd"
+	"github.com/rs/zerolog/log"
+)
+
+// Tunnel is a single instance of OpenVPN controlled by a [Manager]. See [Manager.Tunnel].
+type Tunnel struct {
+	id      int
+	manager *Manager
+
+	mu      sync.RWMutex
+	config  string
+	process *openvpn.Process
+	split   *splitTunnel
+	status  <-chan cmd.Status
+}
+
+func newTunnel(v *Manager, id int) (*Tunnel, error) {
+	t := &Tunnel{id: id, manager: v}
+	if v.splitTunnel {
+		split, err := newSplitTunnel(id, t.localIP)
+		if err != nil {
+			return nil, err
+		}
+		t.split = split
+	}
+
+	return t, nil
+}
+
+// ID returns the tunnel's ID.
+func (t *Tunnel) ID() int {
+	return t.id
+}
+
+// Start spawns a new instance of OpenVPN with the provided config.
+func (t *Tunnel) Start(config string) error {
+	v := t.manager
+	if !slices.Contains(v.configs, config) {
+		return openvpn.ErrorConfigNotFound
+	}
+
+	args := v.args
+	if t.split != nil {
+		args = strings.Join(append([]string{args}, t.split.args()...), " ")
+	}
+
+	process, status, err := openvpn.Start(filepath.Join(v.dir, config), v.auth, args, v.timeout)
+	t.setProcess(config, process, status)
+	if err != nil {
+		return err
+	}
+
+	v.UseConfig(config)
+
+	if t.split != nil {
+		if err := t.split.route(process.Connection().LocalIP); err != nil {
+			return errors.Join(err, t.Stop())
+		}
+	}
+
+	return nil
+}
+
+// Proxy returns the URL of the SOCKS5 proxy whose connections are routed through the tunnel in
+// split-tunnel mode, or an empty string if split tunnelling is disabled.
+func (t *Tunnel) Proxy() string {
+	if t.split == nil {
+		return ""
+	}
+	return t.split.proxy()
+}
+
+// localIP returns the tunnel's address, if it is running.
+func (t *Tunnel) localIP() string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.process == nil {
+		return ""
+	}
+	return t.process.Connection().LocalIP
+}
+
+// Stop attemps to stop the tunnel's instance of OpenVPN.
+func (t *Tunnel) Stop() error {
+	t.mu.RLock()
+	process := t.process
+	t.mu.RUnlock()
+
+	err := openvpn.Stop(process)
+	if t.split != nil {
+		if _err := t.split.unroute(); _err != nil {
+			log.Warn().Err(_err).Int("tunnel", t.id).Msg("failed to remove split tunnel route")
+		}
+	}
+
+	return err
+}
+
+// close stops the tunnel's instance of OpenVPN (if any) and its SOCKS5 proxy in split-tunnel mode.
+func (t *Tunnel) close() error {
+	err := t.Stop()
+	if errors.Is(err, openvpn.ErrorNoVpnProcess) {
+		err = nil
+	}
+
+	if t.split != nil {
+		err = errors.Join(err, t.split.server.close())
+	}
+
+	return err
+}
+
+// Restart restarts the tunnel's instance of OpenVPN with the provided config.
+func (t *Tunnel) Restart(config string) error {
+	if err := t.Stop(); err != nil && !errors.Is(err, openvpn.ErrorNoVpnProcess) {
+		return err
+	}
+
+	return t.Start(config)
+}
+
+func (t *Tunnel) setProcess(config string, process *openvpn.Process, status <-chan cmd.Status) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.config, t.process, t.status = config, process, status
+}
+
+// Status returns the status of the tunnel's instance of OpenVPN, as reported by its management
+// interface.
+func (t *Tunnel) Status() Status {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	if t.process == nil {
+		return Status{Tunnel: t.id}
+	}
+
+	status := Status{Tunnel: t.id, Config: t.config, Status: t.process.Connection()}
+	select {
+	case <-t.process.Done():
+	default:
+		status.Running = true
+	}
+
+	return status
+}
+
+// Backup is meant to be used after starting an OpenVPN instance fails. This function
+// attempts to start an instance of OpenVPN and returns the config used to spawn
+// the instance if successful in trying to do so. Configs used by other tunnels are skipped.
+//
+// If a region is provided, unused configs from that region are preferred, falling
+// back to unused configs from any region.
+func (t *Tunnel) Backup(region string) (string, error) {
+	log.Debug().Int("tunnel", t.id).Str("region", region).Msg("fetching backup config since previous failed")
+
+	v := t.manager
+	unused := v.filterUnused(region)
+	if region != "" {
+		unused = append(unused, slices.DeleteFunc(v.filterUnused(""), func(config string) bool {
+			return slices.Contains(unused, config)
+		})...)
+	}
+
+	if len(unused) < 1 {
+		return "", ErrorNoUnusedConfigs
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), backupTimeout)
+	defer cancel()
+
+	// the unused configs are shuffled, so they are tried in order.
+	for _, config := range unused {
+		select {
+		case <-ctx.Done():
+			return "", openvpn.ErrorVpnTimedOut{Msg: "too many retries"}
+		default:
+		}
+
+		if !v.reserve(config) {
+			continue
+		}
+
+		err := t.Start(config)
+		v.release(config)
+		if err != nil {
+			log.Debug().Err(err).Str("config", config).Msg("backup config failed")
+			continue
+		}
+
+		log.Debug().Int("tunnel", t.id).Str("config", config).Msg("got backup config")
+		return config, nil
+	}
+
+	return "", ErrorNoUnusedConfigs
+}
@@ -0,0 +1,65 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openvpn
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestConcurrentTunnelsRequireSplitTunnel(t *testing.T) {
+	v, _ := testManager(t)
+
+	tunnel, err := v.Tunnel(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tunnel != v.tunnel() {
+		t.Fatal("expected tunnel 0 to be the manager's default tunnel")
+	}
+
+	if _, err := v.Tunnel(1); !errors.Is(err, ErrorConcurrentTunnels) {
+		t.Fatalf("expected %v, got %v", ErrorConcurrentTunnels, err)
+	}
+
+	if status := tunnel.Status(); status.Running || status.Config != "" {
+		t.Fatalf("unexpected status of a stopped tunnel: %+v", status)
+	}
+}
+
+func TestBackupConfigReservation(t *testing.T) {
+	v, _ := testManager(t)
+
+	if !v.reserve("de-1.ovpn") {
+		t.Fatal("expected an unused config to be reserved")
+	}
+	if v.reserve("de-1.ovpn") {
+		t.Fatal("expected a reserved config not to be reserved twice")
+	}
+	if unused := v.filterUnused(""); len(unused) != 1 || unused[0] != "nl-1.ovpn" {
+		t.Fatalf("expected the reserved config to be skipped, got %v", unused)
+	}
+
+	// a config that was started while it was reserved can't be reserved again once released.
+	v.UseConfig("de-1.ovpn")
+	v.release("de-1.ovpn")
+	if v.reserve("de-1.ovpn") {
+		t.Fatal("expected a used config not to be reserved")
+	}
+
+	if err := v.Close(); err != nil {
+		t.Fatalf("expected closing stopped tunnels to succeed, got %v", err)
+	}
+}
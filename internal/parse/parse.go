@@ -0,0 +1,161 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package parse parses the numbers, ranges and dates that Apollo displays, tolerating the
+// thousands separators and formats of different locales and small changes to its wording.
+package parse
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ErrorInvalidNumber is returned when a number can't be parsed.
+var ErrorInvalidNumber = errors.New("invalid number")
+
+// CountPattern matches a count, which may have thousands separators ('2,500', '2.500', '2 500' or
+// '2'500') or be abbreviated ('2.5K').
+const CountPattern = `\d+(?:[.,\x{00a0}\x{202f}' ]\d{3})*(?:[.,]\d+)?\s*[KkMm]?`
+
+var (
+	// rangePattern matches ranges in the 'X - Y of Z' format, with any separator between X and Y
+	// and any words between Y and Z, e.g. '1 – 25 of about 2,500' or '1 bis 25 von 2.500'.
+	rangePattern = regexp.MustCompile(`(` + CountPattern + `)\s*(?:-|–|—|to|bis|à|a|al)\s*(` + CountPattern + `)\D+?(` + CountPattern + `)`)
+
+	countPattern = regexp.MustCompile(CountPattern)
+)
+
+// Count parses a count, ignoring thousands separators and expanding 'K' and 'M' abbreviations. The
+// last separator is taken for a decimal one unless it's followed by exactly three digits, so both
+// '2.5K' and '2,5K' are 2500 while '2.500' is 2500 too. Fractions of counts without abbreviations
+// are dropped.
+func Count(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("%w %q", ErrorInvalidNumber, s)
+	}
+
+	multiplier := 1.0
+	switch s[len(s)-1] {
+	case 'K', 'k':
+		multiplier = 1e3
+	case 'M', 'm':
+		multiplier = 1e6
+	}
+
+	number := s
+	if multiplier > 1 {
+		number = strings.TrimSpace(s[:len(s)-1])
+	}
+
+	whole, fraction := splitDecimal(number)
+	digits := strings.Map(func(r rune) rune {
+		switch {
+		case r >= '0' && r <= '9':
+			return r
+		case r == ',' || r == '.' || r == '\'' || unicode.IsSpace(r):
+			return -1
+		}
+		return 'x'
+	}, whole)
+
+	n, err := strconv.Atoi(digits)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("%w %q", ErrorInvalidNumber, s)
+	}
+
+	if multiplier == 1 || fraction == "" {
+		return n * int(multiplier), nil
+	}
+
+	f, err := strconv.ParseFloat(strconv.Itoa(n)+"."+fraction, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w %q", ErrorInvalidNumber, s)
+	}
+
+	return int(f * multiplier), nil
+}
+
+// splitDecimal splits a number at its decimal separator, if it has one.
+func splitDecimal(s string) (whole, fraction string) {
+	i := strings.LastIndexAny(s, ".,")
+	if i < 0 {
+		return s, ""
+	}
+
+	fraction = s[i+1:]
+	if len(fraction) == 3 || strings.TrimFunc(fraction, unicode.IsDigit) != "" {
+		return s, ""
+	}
+
+	return s[:i], fraction
+}
+
+// FindCount parses the first count found in the text, e.g. the page number of 'Page 3'.
+func FindCount(text string) (int, error) {
+	match := countPattern.FindString(text)
+	if match == "" {
+		return 0, fmt.Errorf("%w: no number in %q", ErrorInvalidNumber, text)
+	}
+
+	return Count(match)
+}
+
+// RangeError is returned when a range (e.g. '1 - 25 of 2,500') can't be parsed.
+type RangeError struct {
+	// Text is the range's text.
+	Text string
+
+	// Reason is why the text couldn't be parsed.
+	Reason string
+}
+
+func (e *RangeError) Error() string {
+	return fmt.Sprintf("failed to parse range %q: %s", e.Text, e.Reason)
+}
+
+// Range parses a range of results in the 'X - Y of Z' format, such as the footer of Apollo's
+// results table, returning the (1-based) positions of the first and last results and their total.
+// Texts which aren't in that format fall back to their first three counts.
+func Range(text string) (start, end, total int, err error) {
+	var numbers []string
+	if match := rangePattern.FindStringSubmatch(text); match != nil {
+		numbers = match[1:]
+	} else if numbers = countPattern.FindAllString(text, -1); len(numbers) < 3 {
+		return 0, 0, 0, &RangeError{Text: text, Reason: fmt.Sprintf("found %d numbers, expected 3", len(numbers))}
+	}
+
+	values := make([]int, 3)
+	for i, number := range numbers[:3] {
+		if values[i], err = Count(number); err != nil {
+			return 0, 0, 0, &RangeError{Text: text, Reason: err.Error()}
+		}
+	}
+
+	start, end, total = values[0], values[1], values[2]
+	switch {
+	case start < 1:
+		return 0, 0, 0, &RangeError{Text: text, Reason: fmt.Sprintf("first result %d is before the first one", start)}
+	case end < start:
+		return 0, 0, 0, &RangeError{Text: text, Reason: fmt.Sprintf("last result %d is before the first one", end)}
+	case total < end:
+		return 0, 0, 0, &RangeError{Text: text, Reason: fmt.Sprintf("last result %d is after the total %d", end, total)}
+	}
+
+	return start, end, total, nil
+}
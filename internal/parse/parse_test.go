@@ -0,0 +1,136 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parse
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRange(t *testing.T) {
+	tests := []struct {
+		text              string
+		start, end, total int
+	}{
+		{"1 - 25 of 1,234", 1, 25, 1234},
+		{"26 - 50 of 2,500", 26, 50, 2500},
+		{"1-7 of 7", 1, 7, 7},
+		{"1 – 25 of 10,000", 1, 25, 10000},
+		{"Showing 101 - 125 of about 2,345 results", 101, 125, 2345},
+		{"1 to 25 of 2.5K", 1, 25, 2500},
+		{"1 bis 25 von 2.500", 1, 25, 2500},
+		{"1 - 25 sur 2 500", 1, 25, 2500},
+		{"1 - 25 of 1.2M", 1, 25, 1200000},
+		{"Results 1 / 25 / 300", 1, 25, 300},
+	}
+
+	for _, tt := range tests {
+		start, end, total, err := Range(tt.text)
+		if err != nil {
+			t.Errorf("%q: %s", tt.text, err)
+			continue
+		}
+
+		if start != tt.start || end != tt.end || total != tt.total {
+			t.Errorf("%q: got %d - %d of %d; want %d - %d of %d", tt.text, start, end, total, tt.start, tt.end, tt.total)
+		}
+	}
+
+	for _, text := range []string{"", "No people match your criteria", "1 - 25", "25 - 1 of 100", "1 - 25 of 10"} {
+		_, _, _, err := Range(text)
+
+		var rangeErr *RangeError
+		if !errors.As(err, &rangeErr) || rangeErr.Text != text {
+			t.Errorf("%q: got error %v; want a *RangeError", text, err)
+		}
+	}
+}
+
+func TestCount(t *testing.T) {
+	tests := []struct {
+		text string
+		n    int
+	}{
+		{"25", 25},
+		{"2,500", 2500},
+		{"2.500", 2500},
+		{"2 500", 2500},
+		{"2\u202f500", 2500},
+		{"2'500", 2500},
+		{"1,234,567", 1234567},
+		{"2,345.00", 2345},
+		{"2.5K", 2500},
+		{"2,5k", 2500},
+		{" 1.2 M ", 1200000},
+		{"10K", 10000},
+	}
+
+	for _, tt := range tests {
+		n, err := Count(tt.text)
+		if err != nil {
+			t.Errorf("%q: %s", tt.text, err)
+		} else if n != tt.n {
+			t.Errorf("%q: got %d; want %d", tt.text, n, tt.n)
+		}
+	}
+
+	for _, text := range []string{"", "K", "-5", "12a", "about"} {
+		if _, err := Count(text); !errors.Is(err, ErrorInvalidNumber) {
+			t.Errorf("%q: got error %v; want %v", text, err, ErrorInvalidNumber)
+		}
+	}
+}
+
+func TestFindCount(t *testing.T) {
+	if n, err := FindCount("Page 1,024"); err != nil || n != 1024 {
+		t.Errorf("got %d (%v); want 1024", n, err)
+	}
+
+	if _, err := FindCount("Page"); !errors.Is(err, ErrorInvalidNumber) {
+		t.Errorf("got error %v; want %v", err, ErrorInvalidNumber)
+	}
+}
+
+func TestTime(t *testing.T) {
+	layouts := []string{"Jan 2, 2006 3:04 PM", "Jan 2, 2006", "02.01.2006 15:04"}
+	afternoon := time.Date(2025, time.March, 4, 13, 45, 0, 0, time.UTC)
+	day := time.Date(2025, time.March, 4, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		text string
+		want time.Time
+	}{
+		{"Mar 4, 2025 1:45 PM", afternoon},
+		{"March 4th, 2025 at 1:45 pm", afternoon},
+		{"Mar. 4 2025, 1:45 p.m.", afternoon},
+		{"mar  04,\u00a02025 1:45PM", afternoon},
+		{"Mar 4, 2025", day},
+		{"04.03.2025 13:45", afternoon},
+	}
+
+	for _, tt := range tests {
+		got, err := Time(tt.text, layouts, time.UTC)
+		if err != nil {
+			t.Errorf("%q: %s", tt.text, err)
+		} else if !got.Equal(tt.want) {
+			t.Errorf("%q: got %s; want %s", tt.text, got, tt.want)
+		}
+	}
+
+	if _, err := Time("soon", layouts, time.UTC); err == nil {
+		t.Error("expected an error for a text without a date")
+	}
+}
@@ -0,0 +1,77 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package parse
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+var (
+	// ordinalPattern matches the suffixes of ordinal days, e.g. the 'th' of '4th'.
+	ordinalPattern = regexp.MustCompile(`(?i)\b(\d{1,2})(?:st|nd|rd|th)\b`)
+
+	// meridiemPattern matches 'AM' and 'PM' after a time in any case and with or without dots, e.g.
+	// the 'p.m.' of '1:45 p.m.'.
+	meridiemPattern = regexp.MustCompile(`(?i)(\d)\s?([ap])\.?\s?m\b\.?`)
+
+	// monthPattern matches the English names of months, which are shortened to the abbreviations
+	// of Go's layouts, along with a trailing dot or the 'Sept' abbreviation.
+	monthPattern = regexp.MustCompile(`(?i)\b(jan|feb|mar|apr|may|jun|jul|aug|sep|oct|nov|dec)[a-z]*\b\.?`)
+
+	// atPattern matches the words between dates and times, e.g. the 'at' of 'Mar 4, 2025 at 1:45 PM'.
+	atPattern = regexp.MustCompile(`(?i)\s+(?:at|um|à)\s+`)
+)
+
+// Time parses a date or time with the first of the layouts that matches it, in the provided
+// location. Before it's parsed, the text is normalised: whitespace is collapsed, ordinal suffixes
+// ('4th') and words between the date and time ('at') are dropped, English month names are
+// abbreviated ('March' or 'Mar.' become 'Mar') and 'a.m.' or 'pm' become 'AM' and 'PM'. Layouts
+// match with or without their commas, so 'Mar 4 2025' is parsed by 'Jan 2, 2006'.
+func Time(s string, layouts []string, loc *time.Location) (time.Time, error) {
+	normalized := normalizeTime(s)
+
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, normalized, loc); err == nil {
+			return t, nil
+		}
+
+		if t, err := time.ParseInLocation(withoutCommas(layout), withoutCommas(normalized), loc); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, fmt.Errorf("failed to parse time %q", s)
+}
+
+func normalizeTime(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	s = ordinalPattern.ReplaceAllString(s, "$1")
+	s = atPattern.ReplaceAllString(s, " ")
+	s = meridiemPattern.ReplaceAllStringFunc(s, func(m string) string {
+		match := meridiemPattern.FindStringSubmatch(m)
+		return match[1] + " " + strings.ToUpper(match[2]) + "M"
+	})
+
+	return monthPattern.ReplaceAllStringFunc(s, func(m string) string {
+		return strings.ToUpper(m[:1]) + strings.ToLower(m[1:3])
+	})
+}
+
+func withoutCommas(s string) string {
+	return strings.Join(strings.Fields(strings.ReplaceAll(s, ",", " ")), " ")
+}
@@ -0,0 +1,99 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privacy
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/models"
+)
+
+func TestScrub(t *testing.T) {
+	lead := &models.Lead{Email: " Ada@Example.com", Phone: "+1 555 0100"}
+	ScrubHash.Scrub(lead)
+
+	if !strings.HasPrefix(lead.Email, HashPrefix) || lead.Email != Hash("ada@example.com") {
+		t.Errorf("email hashed to %q", lead.Email)
+	}
+	if lead.Phone != Hash("+15550100") {
+		t.Errorf("phone hashed to %q", lead.Phone)
+	}
+
+	// scrubbing twice doesn't hash the hashes.
+	email := lead.Email
+	if ScrubHash.Scrub(lead); lead.Email != email {
+		t.Error("a hashed email was hashed again")
+	}
+
+	ScrubRemove.Scrub(lead)
+	if lead.Email != "" || lead.Phone != "" {
+		t.Errorf("personal data wasn't removed: %+v", lead)
+	}
+
+	if _, err := ParseScrubMode("encrypt"); err == nil {
+		t.Error("an unsupported scrub mode was accepted")
+	}
+}
+
+func TestPurge(t *testing.T) {
+	dir := t.TempDir()
+	old := time.Now().Add(-48 * time.Hour)
+
+	files := map[string]bool{
+		"2025-06-01/snapshot.png": true,
+		"2025-06-02/snapshot.png": false,
+		"leads.csv":               true,
+		"leads.keep":              false,
+	}
+	for name, expired := range files {
+		file := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(file, nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if expired || name == "leads.keep" {
+			if err := os.Chtimes(file, old, old); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	removed, err := Purge(dir, time.Now().Add(-24*time.Hour), func(path string) bool {
+		return filepath.Ext(path) != ".keep"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(removed) != 2 {
+		t.Errorf("removed %v; want the 2 expired files", removed)
+	}
+	for name, expired := range files {
+		if _, err := os.Stat(filepath.Join(dir, name)); (err == nil) == expired {
+			t.Errorf("%s: expired = %t, but exists = %t", name, expired, err == nil)
+		}
+	}
+
+	// the directories left empty are removed too.
+	if _, err := os.Stat(filepath.Join(dir, "2025-06-01")); err == nil {
+		t.Error("an empty directory was kept")
+	}
+}
@@ -0,0 +1,81 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package privacy
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+)
+
+// Purge removes the files under dir (recursively) which were last modified before the cutoff and
+// for which match (if not nil) returns true, along with the directories that it leaves empty. It
+// returns the removed files. A missing dir has nothing to purge.
+func Purge(dir string, before time.Time, match func(path string) bool) ([]string, error) {
+	var (
+		removed []string
+		errs    []error
+	)
+
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			errs = append(errs, err)
+			return nil
+		}
+
+		if !info.ModTime().Before(before) || (match != nil && !match(path)) {
+			return nil
+		}
+
+		if err := os.Remove(path); err != nil {
+			errs = append(errs, err)
+			return nil
+		}
+		removed = append(removed, path)
+
+		return nil
+	})
+	errs = append(errs, err)
+
+	// directories left empty (e.g. a day's partition) are removed too, deepest first.
+	var dirs []string
+	for _, file := range removed {
+		for d := filepath.Dir(file); len(d) > len(dir) && !slices.Contains(dirs, d); d = filepath.Dir(d) {
+			dirs = append(dirs, d)
+		}
+	}
+	slices.SortFunc(dirs, func(a, b string) int { return len(b) - len(a) })
+	for _, d := range dirs {
+		// removing a directory which isn't empty fails, which is expected.
+		_ = os.Remove(d)
+	}
+
+	return removed, errors.Join(errs...)
+}
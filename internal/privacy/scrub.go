@@ -0,0 +1,78 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package privacy helps with data protection obligations such as the GDPR's: it scrubs personal
+// data (emails and phone numbers) from leads before they're written, and purges files and journal
+// entries once they're older than a retention period.
+package privacy
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/devsheke/scrapollo/internal/models"
+)
+
+// ScrubMode represents how personal data is scrubbed from leads.
+type ScrubMode string
+
+// The supported scrub modes.
+const (
+	// ScrubNone leaves leads as they are.
+	ScrubNone ScrubMode = ""
+
+	// ScrubHash replaces emails and phone numbers with their SHA-256 hashes (see [Hash]), so that
+	// leads can still be matched with other data sets without their contact details being kept.
+	ScrubHash ScrubMode = "hash"
+
+	// ScrubRemove removes emails and phone numbers.
+	ScrubRemove ScrubMode = "remove"
+)
+
+// HashPrefix is the prefix of the hashes that scrubbed values are replaced with.
+const HashPrefix = "sha256:"
+
+// ParseScrubMode parses a scrub mode ('hash' or 'remove', or an empty string for none).
+func ParseScrubMode(s string) (ScrubMode, error) {
+	switch mode := ScrubMode(s); mode {
+	case ScrubNone, ScrubHash, ScrubRemove:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unsupported scrub mode: %q", s)
+	}
+}
+
+// Scrub scrubs the lead's email and phone number according to the mode.
+func (m ScrubMode) Scrub(lead *models.Lead) {
+	switch m {
+	case ScrubHash:
+		lead.Email, lead.Phone = Hash(lead.Email), Hash(strings.Join(strings.Fields(lead.Phone), ""))
+	case ScrubRemove:
+		lead.Email, lead.Phone = "", ""
+	}
+}
+
+// Hash returns the hex-encoded SHA-256 hash of the trimmed and lowercased value, prefixed with
+// [HashPrefix]. Empty and already hashed values are returned as they are.
+func Hash(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" || strings.HasPrefix(value, HashPrefix) {
+		return value
+	}
+
+	sum := sha256.Sum256([]byte(value))
+	return HashPrefix + hex.EncodeToString(sum[:])
+}
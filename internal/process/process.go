@@ -0,0 +1,86 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package process applies the post-processing steps of a scrape (normalisation, tagging, scoring,
+// filtering and scrubbing) to leads after the fact, e.g. to reprocess the output files of earlier scrapes once
+// new rules have been added.
+package process
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/devsheke/scrapollo/internal/models"
+	"github.com/devsheke/scrapollo/internal/privacy"
+	"github.com/devsheke/scrapollo/internal/scoring"
+	"github.com/devsheke/scrapollo/internal/tagging"
+)
+
+// Pipeline represents the steps that leads are processed with. Steps left unset are skipped, so
+// that e.g. the tags of leads are kept if there are no tagging rules.
+type Pipeline struct {
+	// Tags are the rules that leads are tagged with (see [tagging.Apply]).
+	Tags []*tagging.Rule
+
+	// Scorer computes the score of leads.
+	Scorer scoring.Scorer
+
+	// Filter drops the leads for which it computes 0 (or false, for an expression).
+	Filter scoring.Scorer
+
+	// Scrub scrubs the personal data of the leads which are kept.
+	Scrub privacy.ScrubMode
+}
+
+// Process normalises, tags, scores, filters and scrubs the leads, in that order, returning those
+// which are kept. Leads which fail to be scored keep a score of 0 and leads which fail to be filtered are
+// dropped, and the failures are returned alongside the leads.
+func (p *Pipeline) Process(leads []*models.Lead) ([]*models.Lead, error) {
+	var (
+		kept []*models.Lead
+		errs []error
+	)
+
+	for _, lead := range leads {
+		lead.Normalize()
+
+		if len(p.Tags) > 0 {
+			tagging.Apply(lead, p.Tags)
+		}
+
+		if p.Scorer != nil {
+			score, err := p.Scorer.Score(lead)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to score %q: %w", lead.Name, err))
+			}
+			lead.Score = score
+		}
+
+		if p.Filter != nil {
+			keep, err := p.Filter.Score(lead)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to filter %q: %w", lead.Name, err))
+				continue
+			}
+			if keep == 0 {
+				continue
+			}
+		}
+
+		p.Scrub.Scrub(lead)
+		kept = append(kept, lead)
+	}
+
+	return kept, errors.Join(errs...)
+}
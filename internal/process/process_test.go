@@ -0,0 +1,63 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package process
+
+import (
+	"testing"
+
+	"github.com/devsheke/scrapollo/internal/models"
+	"github.com/devsheke/scrapollo/internal/scoring"
+	"github.com/devsheke/scrapollo/internal/tagging"
+)
+
+func TestPipeline(t *testing.T) {
+	rule, err := tagging.NewRule("exec", "(?i)cto|vp", "title")
+	if err != nil {
+		t.Fatal(err)
+	}
+	scorer, err := scoring.Compile(`employees_min >= 50 ? 10 : 1`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	filter, err := scoring.Compile(`country == "Germany"`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	leads := []*models.Lead{
+		{Name: "Ada", Title: "CTO", Employees: "51-200", Location: "Berlin, Germany"},
+		{Name: "Bob", Title: "Engineer", Employees: "1-10", Location: "Munich, Bavaria, Germany"},
+		{Name: "Cat", Title: "VP Sales", Employees: "51-200", Location: "Paris, France"},
+	}
+
+	p := &Pipeline{Tags: []*tagging.Rule{rule}, Scorer: scorer, Filter: filter}
+	kept, err := p.Process(leads)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(kept) != 2 || kept[0].Name != "Ada" || kept[1].Name != "Bob" {
+		t.Fatalf("unexpected leads kept: %+v", kept)
+	}
+	if kept[0].Tags != "exec" || kept[0].Score != 10 || kept[1].Tags != "" || kept[1].Score != 1 {
+		t.Errorf("unexpected tags or scores: %+v, %+v", kept[0], kept[1])
+	}
+
+	// unset steps leave the leads' tags and scores alone.
+	leads[0].Tags, leads[0].Score = "kept", 5
+	if kept, _ := (&Pipeline{}).Process(leads[:1]); kept[0].Tags != "kept" || kept[0].Score != 5 {
+		t.Errorf("unset steps changed the lead: %+v", kept[0])
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package report generates browsable reports from the files produced during a run.
+package report
+
+import (
+	"cmp"
+	_ "embed"
+	"encoding/json"
+	"html/template"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/devsheke/scrapollo/internal/actions"
+	"github.com/rs/zerolog/log"
+)
+
+// ErrorReportFilename is the name of the error report written to the errors directory.
+const ErrorReportFilename string = "index.html"
+
+//go:embed templates/errors.html
+var errorsTemplate string
+
+var errorsTmpl = template.Must(template.New("errors").Parse(errorsTemplate))
+
+// accountErrors groups the error snapshots of a single account.
+type accountErrors struct {
+	Account   string
+	Snapshots []*actions.ErrorSnapshot
+}
+
+// WriteErrorReport aggregates all of the error snapshots saved in errorDir into a single HTML
+// report, with one section per account and one entry per failure. It returns the path to the
+// report, which is empty if there are no snapshots.
+func WriteErrorReport(errorDir string) (string, error) {
+	files, err := filepath.Glob(filepath.Join(errorDir, "*.json"))
+	if err != nil {
+		return "", err
+	}
+
+	accounts := make(map[string]*accountErrors)
+	for _, file := range files {
+		b, err := os.ReadFile(file)
+		if err != nil {
+			return "", err
+		}
+
+		snapshot := new(actions.ErrorSnapshot)
+		if err := json.Unmarshal(b, snapshot); err != nil {
+			log.Warn().Err(err).Str("file", file).Msg("skipping malformed error snapshot")
+			continue
+		}
+
+		group, ok := accounts[snapshot.Account]
+		if !ok {
+			group = &accountErrors{Account: snapshot.Account}
+			accounts[snapshot.Account] = group
+		}
+		group.Snapshots = append(group.Snapshots, snapshot)
+	}
+
+	if len(accounts) == 0 {
+		return "", nil
+	}
+
+	groups := make([]*accountErrors, 0, len(accounts))
+	for _, group := range accounts {
+		slices.SortFunc(group.Snapshots, func(a, b *actions.ErrorSnapshot) int {
+			return a.Time.Compare(b.Time)
+		})
+		groups = append(groups, group)
+	}
+
+	slices.SortFunc(groups, func(a, b *accountErrors) int {
+		return cmp.Compare(a.Account, b.Account)
+	})
+
+	file := filepath.Join(errorDir, ErrorReportFilename)
+	f, err := os.OpenFile(file, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0644)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	return file, errorsTmpl.Execute(f, groups)
+}
@@ -0,0 +1,213 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+
+	"github.com/devsheke/scrapollo/internal/actions"
+	"github.com/devsheke/scrapollo/internal/apolloapi"
+	"github.com/devsheke/scrapollo/internal/io"
+	"github.com/devsheke/scrapollo/internal/journal"
+	"github.com/devsheke/scrapollo/internal/models"
+	"github.com/devsheke/scrapollo/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// apiPageSize is the number of people requested from each page of a search through Apollo's API.
+const apiPageSize = 25
+
+// APIURL is a [RunnerOpt] func that specifies the base URL of Apollo's REST API, through which the
+// accounts with an API key are scraped (see [apolloapi.DefaultURL]).
+func APIURL(u string) RunnerOpt {
+	return func(r *Runner) {
+		r.apiURL = u
+	}
+}
+
+// APIExport is a [RunnerOpt] func that configures the [Runner] to save the leads of the accounts with
+// an API key with the browser, which costs no API credits, and to only export their lists through
+// Apollo's API rather than scraping them. Lists are scraped as usual if they can't be exported.
+func APIExport(b bool) RunnerOpt {
+	return func(r *Runner) {
+		r.apiExport = b
+	}
+}
+
+// runAPIJob runs a job whose account has an API key through Apollo's REST API instead of a browser,
+// mirroring [Runner.saveLeads]. The people of the account's search are saved by enriching them, which
+// reveals their emails for a credit each, and are written to the job's outputs right away. Pages cut
+// short by the account's target, credits or daily limit aren't revisited, so their remaining people
+// are only saved by a later search with --dedupe-store.
+func (r *Runner) runAPIJob(job *job) (err error) {
+	ctx, span := tracing.Start(
+		r.jobContext(job),
+		"runner.apiJob",
+		attribute.String("account", job.acc.Email),
+		attribute.String("job-id", job.id),
+		attribute.String("list", job.acc.List),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	search, err := apolloapi.ParseSearchURL(job.acc.URL)
+	if err != nil {
+		return err
+	}
+
+	client := apolloapi.NewClient(r.apiURL, job.acc.APIKey)
+	deadline, cause := r.budget(job)
+
+	if _, ok := job.startedAt.Get(); !ok {
+		r.restoreSavedToday(job)
+	}
+
+	if _, ok := job.startedAt.Get(); !ok {
+		job.start(r.now())
+	}
+
+	_, writers, drain := r.listWriters(job)
+	defer drain()
+
+	job.log.Info().Msg("saving leads through apollo's api")
+	for {
+		if !deadline.IsZero() && !r.now().Before(deadline) {
+			return cause
+		}
+
+		if err := r.checkBlacklist(job); err != nil {
+			return err
+		}
+
+		if job.acc.IsDone() {
+			job.log.Info().Str("list", job.acc.List).Msg("finished saving leads")
+			return nil
+		}
+
+		if job.hitDailyLimit(r.dailyLimit(job), r.now()) {
+			return ErrorDailyLimit
+		}
+
+		if !job.acc.CanScrape() {
+			return ErrorNoCredits
+		}
+
+		number := job.pagesScraped + 1
+		res, err := client.SearchPeople(ctx, search, number, apiPageSize)
+		if err != nil {
+			return err
+		} else if len(res.People) == 0 {
+			return actions.ErrorListEnd
+		}
+
+		people := res.People
+		if r.dedupe != nil {
+			if people, err = r.unseenPeople(people); err != nil {
+				return err
+			}
+		}
+
+		remaining := min(job.acc.Target-job.acc.Saved, job.acc.Credits, r.dailyLimit(job)-job.savedToday)
+		people = people[:min(len(people), max(remaining, 0))]
+		if err := r.throttleSaves(ctx, job, len(people)); err != nil {
+			return err
+		}
+
+		// the people enriched before an error are still written, since their credits have been spent.
+		var leads []*models.Lead
+		var enrichErr error
+		for _, person := range people {
+			enriched, err := client.Enrich(ctx, person.ID)
+			if err != nil {
+				enrichErr = err
+				break
+			}
+			leads = append(leads, enriched.Lead())
+		}
+
+		r.incrementSaved(job, len(leads))
+		r.record(job, journal.Entry{
+			Action:   journal.ActionPageSaved,
+			Page:     number,
+			Leads:    len(leads),
+			Captured: len(res.People) - len(people),
+		})
+
+		report := &actions.ScrapeReport{Rows: len(res.People), Extracted: len(leads)}
+		r.writeLeads(job, writers, number, leads, report)
+
+		if enrichErr != nil {
+			return enrichErr
+		}
+
+		if res.LastPage() && !job.acc.IsDone() {
+			return actions.ErrorListEnd
+		}
+	}
+}
+
+// unseenPeople returns the people who haven't been captured yet, according to the [Runner]'s dedupe
+// store.
+func (r *Runner) unseenPeople(people []*apolloapi.Person) ([]*apolloapi.Person, error) {
+	leads := make([]*models.Lead, len(people))
+	for i, person := range people {
+		leads[i] = person.Lead()
+	}
+
+	unseen, err := r.dedupe.Unseen(leads)
+	if err != nil {
+		return nil, err
+	}
+
+	fresh := make([]*apolloapi.Person, len(unseen))
+	for i, index := range unseen {
+		fresh[i] = people[index]
+	}
+
+	return fresh, nil
+}
+
+// exportList writes the leads saved to the job's list through Apollo's API, starting from its first
+// page that hasn't been scraped yet. Pages are as large as the 'People' page's, so that the list can
+// still be scraped from where the export stopped.
+func (r *Runner) exportList(ctx context.Context, job *job, writers io.LeadWriter) error {
+	client := apolloapi.NewClient(r.apiURL, job.acc.APIKey)
+	id, err := client.LabelID(ctx, job.acc.List)
+	if err != nil {
+		return err
+	}
+
+	job.log.Info().Str("list", job.acc.List).Msg("exporting leads through apollo's api")
+	for {
+		number := job.pagesScraped + 1
+		res, err := client.SearchContacts(ctx, id, number, apiPageSize)
+		if err != nil {
+			return err
+		} else if len(res.Contacts) == 0 {
+			return nil
+		}
+
+		leads := make([]*models.Lead, len(res.Contacts))
+		for i, contact := range res.Contacts {
+			leads[i] = contact.Lead()
+		}
+
+		report := &actions.ScrapeReport{Rows: len(leads), Extracted: len(leads)}
+		r.writeLeads(job, writers, number, leads, report)
+
+		if res.LastPage() {
+			return nil
+		}
+	}
+}
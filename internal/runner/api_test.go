@@ -0,0 +1,109 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/apollotest"
+	"github.com/devsheke/scrapollo/internal/models"
+)
+
+// TestRunnerAPI runs a job whose account has an API key against the mock's REST API, which doesn't
+// need a browser.
+func TestRunnerAPI(t *testing.T) {
+	srv := apollotest.NewServer(60)
+	defer srv.Close()
+	srv.AddAPIKey("key")
+
+	acc := &models.Account{
+		Email:   "test@example.com",
+		URL:     srv.URL + "/#/people?personTitles[]=ceo",
+		List:    "test",
+		Target:  30,
+		Credits: 100,
+		APIKey:  "key",
+	}
+
+	collector := &leadCollector{}
+	r, err := New([]*models.Account{acc},
+		APIURL(srv.URL+apollotest.APIPath),
+		LeadWriters(collector),
+		OutputDir(t.TempDir()),
+		Timeout(5*time.Second),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	if acc.Saved != 30 || acc.Credits != 70 {
+		t.Errorf("got %d leads saved and %d credits left, want 30 and 70", acc.Saved, acc.Credits)
+	}
+
+	if len(collector.leads) != 30 {
+		t.Fatalf("got %d leads, want 30", len(collector.leads))
+	}
+
+	lead := collector.leads[0]
+	if lead.Email != "lead1@example.com" || lead.Company != "Company 1" || lead.Country != "Germany" {
+		t.Errorf("unexpected lead: %+v", lead)
+	}
+}
+
+// TestExportList checks that a list is exported through the API from its first page that hasn't
+// been scraped yet.
+func TestExportList(t *testing.T) {
+	srv := apollotest.NewServer(60)
+	defer srv.Close()
+	srv.AddAPIKey("key")
+
+	ids := make([]int, 40)
+	for i := range ids {
+		ids[i] = i
+	}
+	srv.SaveToList("test", ids...)
+
+	acc := &models.Account{Email: "test@example.com", List: "test", APIKey: "key"}
+	r, err := New([]*models.Account{acc},
+		APIExport(true),
+		APIURL(srv.URL+apollotest.APIPath),
+		OutputDir(t.TempDir()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	job := r.jobs.Front().Value.(*job)
+	job.pagesScraped = 1
+
+	collector := &leadCollector{}
+	if err := r.exportList(context.Background(), job, collector); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(collector.leads) != 15 || job.pagesScraped != 2 {
+		t.Fatalf("got %d leads over %d pages, want 15 over 2", len(collector.leads), job.pagesScraped)
+	}
+
+	if lead := collector.leads[0]; lead.Email != "lead26@example.com" || lead.Page != 2 {
+		t.Errorf("unexpected lead: %+v", lead)
+	}
+}
@@ -0,0 +1,58 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import "time"
+
+// The default backoff of jobs that fail with a generic error (see [ErrorBackoff]).
+const (
+	defaultErrorBackoff    = time.Minute
+	defaultMaxErrorBackoff = 30 * time.Minute
+)
+
+// ErrorBackoff is a [RunnerOpt] func that configures how long the account of a job which failed with a
+// generic error is timed out for before the job is picked up again, so that a broken job isn't rerun
+// in a hot loop when there are few accounts. The backoff starts at base and doubles with each
+// consecutive failure in which the job made no progress, up to max. Jobs are requeued right away if
+// base is 0.
+func ErrorBackoff(base, max time.Duration) RunnerOpt {
+	return func(r *Runner) {
+		r.errorBackoff, r.maxErrorBackoff = base, max
+	}
+}
+
+// backOff times the job's account out after it failed with a generic error, returning how long for
+// (see [ErrorBackoff]).
+func (r *Runner) backOff(job *job) time.Duration {
+	if r.errorBackoff <= 0 {
+		return 0
+	}
+
+	// failures only compound while the job is stuck, i.e. hasn't saved or scraped anything since.
+	progress := [2]int{job.acc.Saved, job.pagesScraped}
+	if progress != job.failedAt {
+		job.failures = 0
+	}
+	job.failures, job.failedAt = job.failures+1, progress
+
+	backoff := r.errorBackoff << min(job.failures-1, 16)
+	if r.maxErrorBackoff > 0 {
+		backoff = min(backoff, r.maxErrorBackoff)
+	}
+
+	job.acc.Timeout.Set(r.now().Add(backoff))
+
+	return backoff
+}
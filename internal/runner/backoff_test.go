@@ -0,0 +1,48 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/models"
+)
+
+func TestBackOff(t *testing.T) {
+	r := &Runner{errorBackoff: time.Minute, maxErrorBackoff: 5 * time.Minute}
+	job := &job{acc: &models.Account{Timeout: models.NewTime()}}
+
+	for i, want := range []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute, 5 * time.Minute} {
+		if got := r.backOff(job); got != want {
+			t.Errorf("failure %d: got backoff %v, want %v", i+1, got, want)
+		}
+	}
+
+	if t1, ok := job.acc.Timeout.Get(); !ok || time.Until(t1) < 4*time.Minute {
+		t.Errorf("got timeout %v, want about 5 minutes from now", t1)
+	}
+
+	// the backoff starts over once the job makes progress.
+	job.acc.Saved += 25
+	if got := r.backOff(job); got != time.Minute {
+		t.Errorf("got backoff %v after progress, want %v", got, time.Minute)
+	}
+
+	r.errorBackoff = 0
+	if got := r.backOff(job); got != 0 {
+		t.Errorf("got backoff %v while disabled, want 0", got)
+	}
+}
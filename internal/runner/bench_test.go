@@ -0,0 +1,71 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/apollotest"
+	"github.com/devsheke/scrapollo/internal/models"
+)
+
+// BenchmarkScrapeLoop measures how many pages a job saves and scrapes per minute against a mock
+// of the Apollo app. It's skipped if no browser is installed.
+func BenchmarkScrapeLoop(b *testing.B) {
+	skipWithoutBrowser(b)
+
+	const leads, target = 200, 100
+
+	var pages int
+	start := time.Now()
+	for range b.N {
+		_, _, collector := runMockJob(b, leads, target)
+		pages += target/apollotest.PerPage + len(collector.leads)/apollotest.PerPage
+	}
+
+	b.ReportMetric(float64(pages)/time.Since(start).Minutes(), "pages/min")
+}
+
+// BenchmarkSaveProgress measures the overhead of saving the progress of a run with many accounts.
+func BenchmarkSaveProgress(b *testing.B) {
+	for _, n := range []int{10, 100, 1000} {
+		b.Run(fmt.Sprintf("accounts=%d", n), func(b *testing.B) {
+			accounts := make([]*models.Account, n)
+			for i := range accounts {
+				accounts[i] = &models.Account{
+					Email:  fmt.Sprintf("account%d@example.com", i),
+					URL:    "https://app.apollo.io/#/people",
+					List:   fmt.Sprintf("list-%d", i),
+					Saved:  i,
+					Target: 1000,
+				}
+			}
+
+			r, err := New(accounts, OutputDir(b.TempDir()), CsvOutput())
+			if err != nil {
+				b.Fatal(err)
+			}
+
+			b.ResetTimer()
+			for range b.N {
+				if err := r._saveProgress(); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/actions"
+	"github.com/devsheke/scrapollo/internal/journal"
+	"github.com/go-rod/rod"
+	"github.com/rs/zerolog/log"
+)
+
+// ErrorAccountBlacklisted is returned when a job is stopped because its account has been blacklisted.
+var ErrorAccountBlacklisted = errors.New("the account has been blacklisted")
+
+// blacklist holds the accounts which must no longer be used, along with the reasons they were
+// blacklisted. Accounts are blacklisted through [Runner.Blacklist] or by being listed in the
+// blacklist file, which is read again whenever it changes.
+type blacklist struct {
+	file string
+
+	mu       sync.Mutex
+	added    map[string]string
+	listed   map[string]string
+	modified time.Time
+}
+
+// reason returns the reason the account with the provided email was blacklisted. The second return
+// value is false if it hasn't been blacklisted.
+func (b *blacklist) reason(email string) (string, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if reason, ok := b.added[email]; ok {
+		return reason, true
+	}
+
+	b.readFile()
+	reason, ok := b.listed[email]
+	return reason, ok
+}
+
+// add blacklists the account with the provided email.
+func (b *blacklist) add(email, reason string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.added == nil {
+		b.added = make(map[string]string)
+	}
+	b.added[email] = reason
+}
+
+// readFile reads the blacklist file if it has changed since it was last read. Each of its lines holds
+// the email of an account, optionally followed by the reason it's blacklisted; empty lines and lines
+// starting with '#' are ignored.
+func (b *blacklist) readFile() {
+	if b.file == "" {
+		return
+	}
+
+	info, err := os.Stat(b.file)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Warn().Err(err).Str("file", b.file).Msg("failed to read blacklist")
+		}
+		b.listed, b.modified = nil, time.Time{}
+		return
+	} else if info.ModTime().Equal(b.modified) {
+		return
+	}
+
+	f, err := os.Open(b.file)
+	if err != nil {
+		log.Warn().Err(err).Str("file", b.file).Msg("failed to read blacklist")
+		return
+	}
+	defer f.Close()
+
+	listed := make(map[string]string)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		email, reason, _ := strings.Cut(line, " ")
+		listed[email] = strings.TrimSpace(reason)
+	}
+
+	if err := scanner.Err(); err != nil {
+		log.Warn().Err(err).Str("file", b.file).Msg("failed to read blacklist")
+		return
+	}
+
+	b.listed, b.modified = listed, info.ModTime()
+}
+
+// Blacklist marks the account with the provided email as not to be used, e.g. because it was banned.
+// Its job is dropped at the next safe point: before it's started, or after the page it's saving. It's
+// safe to call Blacklist while the [Runner] is running.
+func (r *Runner) Blacklist(email, reason string) {
+	log.Info().Str("account", email).Str("reason", reason).Msg("blacklisting account")
+	r.blacklist.add(email, reason)
+}
+
+// checkBlacklist returns [ErrorAccountBlacklisted] if the job's account has been blacklisted, recording
+// the reason in the account.
+func (r *Runner) checkBlacklist(job *job) error {
+	if job.acc.Blacklisted != "" {
+		return ErrorAccountBlacklisted
+	}
+
+	reason, ok := r.blacklist.reason(job.acc.Email)
+	if !ok {
+		return nil
+	}
+
+	if reason == "" {
+		reason = "blacklisted"
+	}
+	job.acc.Blacklisted = reason
+
+	return ErrorAccountBlacklisted
+}
+
+// dropJob removes the job at the front of the queue, keeping its account for the progress output.
+func (r *Runner) dropJob(job *job) {
+	job.log.Warn().Str("reason", job.acc.Blacklisted).Msg("account blacklisted, dropping its job")
+	r.record(job, journal.Entry{Action: journal.ActionJobDropped, Error: job.acc.Blacklisted})
+
+	r.results.update(job, func(result *JobResult) {
+		if result.Status != JobDropped {
+			result.Status, result.Err = JobDropped, ErrorAccountBlacklisted
+		}
+	})
+
+	r.jobs.Remove(r.jobs.Front())
+	r.dropped = append(r.dropped, job.acc)
+	r.status.update(func(status *Status) { status.PendingJobs = r.jobs.Len() })
+}
+
+// accountStatus returns the error of the interstitial shown on the page if the account can no longer
+// be used (see [actions.CheckAccountStatus]), and err otherwise.
+func accountStatus(page *rod.Page, err error) error {
+	if blocked := actions.CheckAccountStatus(page); blocked != nil {
+		return blocked
+	}
+
+	return err
+}
@@ -0,0 +1,62 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBlacklist(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "blacklist.txt")
+	b := &blacklist{file: file}
+
+	if _, ok := b.reason("a@example.com"); ok {
+		t.Fatal("account blacklisted without a blacklist file")
+	}
+
+	data := "# banned accounts\na@example.com banned by apollo\n\nb@example.com\n"
+	if err := os.WriteFile(file, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if reason, ok := b.reason("a@example.com"); !ok || reason != "banned by apollo" {
+		t.Errorf("got reason %q (%t), want %q", reason, ok, "banned by apollo")
+	}
+
+	if reason, ok := b.reason("b@example.com"); !ok || reason != "" {
+		t.Errorf("got reason %q (%t), want no reason", reason, ok)
+	}
+
+	// the file is read again once it changes.
+	if err := os.WriteFile(file, []byte("b@example.com\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(file, later, later); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := b.reason("a@example.com"); ok {
+		t.Error("account still blacklisted after being removed from the file")
+	}
+
+	b.add("c@example.com", "suspended")
+	if reason, ok := b.reason("c@example.com"); !ok || reason != "suspended" {
+		t.Errorf("got reason %q (%t), want %q", reason, ok, "suspended")
+	}
+}
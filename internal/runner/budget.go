@@ -0,0 +1,69 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrorTimeBudgetExceeded is returned by [Runner.Start] when the run is stopped for exceeding its
+// time budget. Progress is saved before stopping so that the run can be resumed.
+var ErrorTimeBudgetExceeded = errors.New("time budget exceeded")
+
+var (
+	// ErrorMaxRuntime is returned when the run exceeds its maximum runtime.
+	ErrorMaxRuntime = fmt.Errorf("%w: the run exceeded its maximum runtime", ErrorTimeBudgetExceeded)
+
+	// ErrorMaxJobDuration is returned when a job exceeds its maximum duration.
+	ErrorMaxJobDuration = fmt.Errorf("%w: the job exceeded its maximum duration", ErrorTimeBudgetExceeded)
+)
+
+// runDeadlineExceeded returns true if the run has exceeded its maximum runtime.
+func (r *Runner) runDeadlineExceeded() bool {
+	return !r.deadline.IsZero() && !r.now().Before(r.deadline)
+}
+
+// budget returns the time by which the job, starting now, must stop and the error it stops with:
+// when it exceeds its maximum duration, the run exceeds its maximum runtime or the activity window
+// of the job's account closes, whichever comes first. The deadline is zero if there's none.
+func (r *Runner) budget(job *job) (deadline time.Time, cause error) {
+	deadline, cause = r.deadline, ErrorMaxRuntime
+	if r.maxJobDuration > 0 {
+		if jobDeadline := r.now().Add(r.maxJobDuration); deadline.IsZero() || jobDeadline.Before(deadline) {
+			deadline, cause = jobDeadline, ErrorMaxJobDuration
+		}
+	}
+
+	if _, close, ok := r.window(job); ok && (deadline.IsZero() || close.Before(deadline)) {
+		deadline, cause = close, ErrorOutsideWindow
+	}
+
+	return deadline, cause
+}
+
+// startBudget aborts the job by cancelling its context once it exceeds its time budget (see
+// [Runner.budget]). The returned function stops the timer.
+func (r *Runner) startBudget(job *job, cancel context.CancelCauseFunc) (stop func()) {
+	deadline, cause := r.budget(job)
+	if deadline.IsZero() {
+		return func() {}
+	}
+
+	timer := time.AfterFunc(time.Until(deadline), func() { cancel(cause) })
+	return func() { timer.Stop() }
+}
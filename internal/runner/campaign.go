@@ -0,0 +1,245 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/devsheke/scrapollo/internal/io"
+	"github.com/devsheke/scrapollo/internal/limiter"
+	"github.com/rs/zerolog/log"
+)
+
+// CampaignReportFile is the name of the file, in a campaign's output directory, that the results of
+// the campaign's jobs are written to at the end of a run.
+const CampaignReportFile string = "campaign-report.json"
+
+// Campaign represents a named group of accounts, e.g. those working for the same client, which
+// are run alongside other campaigns by the same [Runner] but have outputs and limits of their own.
+// Accounts join a campaign with their 'campaign' column; those without one are run with the
+// [Runner]'s own settings.
+type Campaign struct {
+	Name string
+
+	// OutputDir is the directory that the leads of the campaign's accounts are written to. It
+	// defaults to a directory named after the campaign inside the [Runner]'s output directory.
+	OutputDir string
+
+	// OutputTemplate is the name of the campaign's output files (see [OutputTemplate]). It
+	// defaults to the [Runner]'s.
+	OutputTemplate string
+
+	// DailyLimit is the number of leads that each of the campaign's accounts may save per day.
+	// It defaults to the [Runner]'s.
+	DailyLimit int
+
+	// Limiter caps the activity of the campaign's accounts (see [Limits]). It defaults to the
+	// [Runner]'s.
+	Limiter *limiter.Limiter
+
+	// CostRates are the rates that the cost of the campaign's jobs is estimated with (see
+	// [CostReport]). They default to the [Runner]'s.
+	CostRates *CostRates
+}
+
+// Campaigns is a [RunnerOpt] func that configures the [Campaign]s that accounts can be part of.
+func Campaigns(campaigns ...*Campaign) RunnerOpt {
+	return func(r *Runner) {
+		r.campaigns = append(r.campaigns, campaigns...)
+	}
+}
+
+// prepareCampaigns fills in the defaults of the [Runner]'s campaigns and creates their output
+// directories.
+func (r *Runner) prepareCampaigns() error {
+	seen := make(map[string]bool, len(r.campaigns))
+	for _, c := range r.campaigns {
+		if c.Name == "" {
+			return errors.New("campaigns must have a name")
+		} else if seen[c.Name] {
+			return fmt.Errorf("duplicate campaign %q", c.Name)
+		}
+		seen[c.Name] = true
+
+		if c.OutputDir == "" {
+			c.OutputDir = filepath.Join(r.outputDir, c.Name)
+		}
+
+		if err := os.MkdirAll(c.OutputDir, 0755); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// joinCampaign assigns the job to the campaign named by its account's 'campaign' column.
+func (r *Runner) joinCampaign(job *job) error {
+	if job.acc.Campaign == "" {
+		return nil
+	}
+
+	for _, c := range r.campaigns {
+		if c.Name == job.acc.Campaign {
+			job.campaign = c
+			return nil
+		}
+	}
+
+	return fmt.Errorf("account %s is part of unknown campaign %q", job.acc.Email, job.acc.Campaign)
+}
+
+// jobOutputDir returns the directory that the job's leads are written to.
+func (r *Runner) jobOutputDir(job *job) string {
+	if job.campaign != nil {
+		return job.campaign.OutputDir
+	}
+
+	return r.outputDir
+}
+
+// jobOutputTemplate returns the name of the files that the job's leads are written to.
+func (r *Runner) jobOutputTemplate(job *job) string {
+	if job.campaign != nil && job.campaign.OutputTemplate != "" {
+		return job.campaign.OutputTemplate
+	}
+
+	return r.outputTemplate
+}
+
+// dailyLimit returns the number of leads that the job's account may save per day.
+func (r *Runner) dailyLimit(job *job) int {
+	if job.campaign != nil && job.campaign.DailyLimit > 0 {
+		return job.campaign.DailyLimit
+	}
+
+	return r.limit
+}
+
+// jobLimiter returns the [*limiter.Limiter] capping the activity of the job's account, if any.
+func (r *Runner) jobLimiter(job *job) *limiter.Limiter {
+	if job.campaign != nil && job.campaign.Limiter != nil {
+		return job.campaign.Limiter
+	}
+
+	return r.limiter
+}
+
+// CampaignResult reports the outcome of a campaign's jobs.
+type CampaignResult struct {
+	Campaign string `json:"campaign"`
+
+	// Accounts is the number of the campaign's accounts, of which Finished finished their jobs,
+	// Pending are left to be run later and Failed failed or were dropped.
+	Accounts int `json:"accounts"`
+	Finished int `json:"finished"`
+	Pending  int `json:"pending"`
+	Failed   int `json:"failed"`
+
+	// Saved, Scraped, TeamOwned and the bytes received and sent are the totals of the campaign's
+	// [JobResult]s.
+	Saved         int   `json:"saved"`
+	Scraped       int   `json:"scraped"`
+	TeamOwned     int   `json:"team-owned"`
+	BytesReceived int64 `json:"bytes-received"`
+	BytesSent     int64 `json:"bytes-sent"`
+
+	Jobs []CampaignJob `json:"jobs"`
+}
+
+// CampaignJob summarises a [JobResult] in a [CampaignResult].
+type CampaignJob struct {
+	Account       string    `json:"account"`
+	List          string    `json:"list"`
+	Status        JobStatus `json:"status"`
+	Saved         int       `json:"saved"`
+	Scraped       int       `json:"scraped"`
+	TeamOwned     int       `json:"team-owned"`
+	BytesReceived int64     `json:"bytes-received"`
+	BytesSent     int64     `json:"bytes-sent"`
+	Outputs       []string  `json:"outputs"`
+	Error         string    `json:"error,omitempty"`
+}
+
+// CampaignResults returns the results of each of the [Runner]'s campaigns, in the order they were
+// configured. Like [Runner.Results], it's meant to be called once [Runner.Start] has returned.
+func (r *Runner) CampaignResults() []CampaignResult {
+	results := make([]CampaignResult, len(r.campaigns))
+	index := make(map[string]int, len(r.campaigns))
+	for i, c := range r.campaigns {
+		results[i].Campaign, index[c.Name] = c.Name, i
+	}
+
+	for _, result := range r.Results() {
+		i, ok := index[result.Campaign]
+		if !ok {
+			continue
+		}
+
+		c := &results[i]
+		c.Accounts++
+		switch result.Status {
+		case JobFinished:
+			c.Finished++
+		case JobPending:
+			c.Pending++
+		default:
+			c.Failed++
+		}
+		c.Saved += result.Saved
+		c.Scraped += result.Scraped
+		c.TeamOwned += result.TeamOwned
+		c.BytesReceived += result.Traffic.Received
+		c.BytesSent += result.Traffic.Sent
+
+		job := CampaignJob{
+			Account:       result.Account,
+			List:          result.List,
+			Status:        result.Status,
+			Saved:         result.Saved,
+			Scraped:       result.Scraped,
+			TeamOwned:     result.TeamOwned,
+			BytesReceived: result.Traffic.Received,
+			BytesSent:     result.Traffic.Sent,
+			Outputs:       result.Outputs,
+		}
+		if result.Err != nil {
+			job.Error = unwrapError(result.Err).Error()
+		}
+		c.Jobs = append(c.Jobs, job)
+	}
+
+	return results
+}
+
+// writeCampaignReports writes the results of each campaign to its output directory.
+func (r *Runner) writeCampaignReports() {
+	if len(r.campaigns) == 0 {
+		return
+	}
+
+	for i, result := range r.CampaignResults() {
+		file := filepath.Join(r.campaigns[i].OutputDir, CampaignReportFile)
+		if err := io.SaveRecords(file, result); err != nil {
+			log.Warn().Err(err).Str("campaign", result.Campaign).Msg("failed to write campaign report")
+			continue
+		}
+
+		log.Info().Str("campaign", result.Campaign).Str("file", file).Msg("saved campaign report")
+	}
+}
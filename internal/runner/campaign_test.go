@@ -0,0 +1,73 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/devsheke/scrapollo/internal/models"
+)
+
+func TestCampaigns(t *testing.T) {
+	dir := t.TempDir()
+	accounts := []*models.Account{
+		{Email: "a@example.com", List: "a", Campaign: "acme"},
+		{Email: "b@example.com", List: "b", Campaign: "globex"},
+		{Email: "c@example.com", List: "c"},
+	}
+
+	acme := &Campaign{Name: "acme", DailyLimit: 100}
+	globex := &Campaign{Name: "globex", OutputDir: filepath.Join(dir, "clients", "globex"), OutputTemplate: "{account}"}
+	r, err := New(accounts, OutputDir(dir), Campaigns(acme, globex))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jobs := make(map[string]*job)
+	for _, job := range r.jobs.iter() {
+		jobs[job.acc.Email] = job
+	}
+
+	for _, tt := range []struct {
+		email, dir, file string
+		limit            int
+	}{
+		{"a@example.com", filepath.Join(dir, "acme"), "a", 100},
+		{"b@example.com", globex.OutputDir, "b@example.com", 500},
+		{"c@example.com", dir, "c", 500},
+	} {
+		job := jobs[tt.email]
+		if got := r.jobOutputDir(job); got != tt.dir {
+			t.Errorf("%s: output dir = %q; want %q", tt.email, got, tt.dir)
+		}
+		if got := r.outputName(job); got != tt.file {
+			t.Errorf("%s: output name = %q; want %q", tt.email, got, tt.file)
+		}
+		if got := r.dailyLimit(job); got != tt.limit {
+			t.Errorf("%s: daily limit = %d; want %d", tt.email, got, tt.limit)
+		}
+	}
+
+	results := r.CampaignResults()
+	if len(results) != 2 || results[0].Campaign != "acme" || results[0].Accounts != 1 || results[0].Pending != 1 {
+		t.Errorf("unexpected campaign results: %+v", results)
+	}
+
+	accounts = append(accounts, &models.Account{Email: "d@example.com", Campaign: "initech"})
+	if _, err := New(accounts, OutputDir(dir), Campaigns(&Campaign{Name: "acme"}, &Campaign{Name: "globex"})); err == nil {
+		t.Error("accounts of unknown campaigns were accepted")
+	}
+}
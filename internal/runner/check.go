@@ -0,0 +1,131 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"errors"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/actions"
+	"github.com/devsheke/scrapollo/internal/models"
+	"github.com/rs/zerolog/log"
+)
+
+// CheckAccounts checks the health of each of the [Runner]'s accounts by inspecting their cookies and
+// timeouts, logging in with their passwords, fetching their credit usage and looking for their lists.
+// Successfully logging in refreshes an account's cookies.
+func (r *Runner) CheckAccounts() []*models.AccountHealth {
+	report := make([]*models.AccountHealth, 0, r.jobs.Len())
+	for _, job := range r.jobs.iter() {
+		if err := job.begin(); err != nil {
+			report = append(report, &models.AccountHealth{Email: job.acc.Email, Error: err.Error()})
+			continue
+		}
+
+		job.log.Info().Msg("checking account health")
+
+		health := r.checkAccount(job)
+		if health.Error != "" {
+			job.log.Warn().Str("error", health.Error).Msg("account check failed")
+		}
+
+		report = append(report, health)
+	}
+
+	if err := r.saveCookies(); err != nil {
+		log.Warn().Err(err).Msg("failed to save refreshed cookies")
+	}
+
+	return report
+}
+
+func (r *Runner) checkAccount(job *job) *models.AccountHealth {
+	acc := job.acc
+	health := &models.AccountHealth{
+		Email:         acc.Email,
+		CookieExpiry:  models.NewTime(),
+		CreditRefresh: models.NewTime(),
+		Timeout:       acc.Timeout,
+		CookiesValid:  acc.CheckCookieValidity(),
+	}
+
+	_, health.HasCookies = acc.GetLoginCookies()
+	if expiry, ok := acc.CookieExpiry(); ok {
+		health.CookieExpiry.Set(expiry)
+	}
+
+	if t, ok := acc.Timeout.Get(); ok && time.Now().Before(t) {
+		health.TimedOut = true
+	}
+
+	fail := func(err error) *models.AccountHealth {
+		health.Error = unwrapError(err).Error()
+		return health
+	}
+
+	if err := r.connectVpn(job); err != nil {
+		return fail(err)
+	}
+	defer r.disconnectVpn(job)
+
+	bw, err := newBrowserWrapper(r.browserOptions(job))
+	if err != nil {
+		return fail(err)
+	}
+	defer bw.close()
+	bw.browser = bw.browser.Context(r.jobContext(job))
+
+	// log in without any cookies to make sure that the account's password works.
+	probe := *acc
+	probe.SetLoginCookies(nil)
+
+	release, err := r.acquireLogin(bw.browser.GetContext(), job)
+	if err != nil {
+		return fail(err)
+	}
+
+	page, err := actions.ApolloLogin(bw.browser, &probe, r.timeouts.Login, r.stealth, job.profile)
+	release()
+	if page != nil {
+		err = accountStatus(page, err)
+	}
+	if err != nil {
+		return fail(err)
+	}
+
+	health.CanLogin = true
+	if cookies, ok := probe.GetLoginCookies(); ok {
+		acc.SetLoginCookies(cookies)
+	}
+
+	if err := r.removeAnnoyances(page); err != nil {
+		return fail(err)
+	}
+
+	credits, err := actions.FetchCreditUsage(page, acc, r.creditLocales, r.timeouts.Credits)
+	if err != nil {
+		return fail(err)
+	}
+	health.Credits, health.CreditRefresh, health.Plan = credits.Remaining(), credits.Renewal, credits.Plan
+	r.recordCredits(job, credits)
+
+	exists, err := actions.ListExists(page, acc.List, r.timeout)
+	if err != nil {
+		return fail(errors.Join(errors.New("failed to look up list"), err))
+	}
+	health.ListExists = exists
+
+	return health
+}
@@ -0,0 +1,127 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/go-rod/rod/lib/launcher"
+	"github.com/go-rod/rod/lib/launcher/flags"
+	"github.com/rs/zerolog/log"
+)
+
+// reservedBrowserFlags are the Chrome flags that scrapollo relies on to control its browsers, which
+// can't be set with [BrowserFlags].
+var reservedBrowserFlags = []string{"remote-debugging-port", "user-data-dir"}
+
+// BrowserFlags is a [RunnerOpt] func that passes additional command line flags to the [Runner]'s
+// browsers, as 'name' or 'name=value' with or without the leading dashes, e.g.
+// '--lang=de-DE' or 'disable-gpu'. A flag prefixed with '!' (e.g. '!enable-automation') removes a
+// flag that the browser is launched with by default instead.
+func BrowserFlags(flags ...string) RunnerOpt {
+	return func(r *Runner) {
+		r.browserFlags = append(r.browserFlags, flags...)
+	}
+}
+
+// Extensions is a [RunnerOpt] func that loads the unpacked Chrome extensions in the provided
+// directories into the [Runner]'s browsers, e.g. a cookie manager. Classic headless browsers can't
+// load extensions, so browsers run in Chrome's new headless mode instead when they're headless.
+func Extensions(dirs ...string) RunnerOpt {
+	return func(r *Runner) {
+		r.extensions = append(r.extensions, dirs...)
+	}
+}
+
+// browserFlag is a parsed [BrowserFlags] flag.
+type browserFlag struct {
+	name   flags.Flag
+	value  string
+	remove bool
+}
+
+// parseBrowserFlag parses one of the flags given to [BrowserFlags].
+func parseBrowserFlag(s string) (browserFlag, error) {
+	var flag browserFlag
+	if rest, ok := strings.CutPrefix(s, "!"); ok {
+		s, flag.remove = rest, true
+	}
+
+	name, value, _ := strings.Cut(strings.TrimLeft(strings.TrimSpace(s), "-"), "=")
+	switch {
+	case name == "":
+		return flag, fmt.Errorf("invalid browser flag %q", s)
+	case slices.Contains(reservedBrowserFlags, name):
+		return flag, fmt.Errorf("browser flag %q is set by scrapollo and can't be changed", name)
+	}
+
+	flag.name, flag.value = flags.Flag(name), value
+	return flag, nil
+}
+
+// prepareBrowser checks the browser flags and extensions, resolving the extensions' directories.
+func (r *Runner) prepareBrowser() error {
+	for _, s := range r.browserFlags {
+		flag, err := parseBrowserFlag(s)
+		if err != nil {
+			return err
+		}
+		r.parsedBrowserFlags = append(r.parsedBrowserFlags, flag)
+	}
+
+	for i, dir := range r.extensions {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return err
+		}
+
+		if _, err := os.Stat(filepath.Join(abs, "manifest.json")); err != nil {
+			return fmt.Errorf("%q isn't an unpacked extension: %w", dir, err)
+		}
+		r.extensions[i] = abs
+	}
+
+	if len(r.extensions) > 0 && r.display == displayHeadless {
+		log.Info().Msg("running browsers in Chrome's new headless mode, as classic headless browsers can't load extensions")
+		r.display = displayHeadlessNew
+	}
+
+	return nil
+}
+
+// applyBrowserFlags configures the launcher with the browser flags and extensions.
+func applyBrowserFlags(l *launcher.Launcher, browserFlags []browserFlag, extensions []string) *launcher.Launcher {
+	if len(extensions) > 0 {
+		l = l.Set("load-extension", strings.Join(extensions, ",")).
+			Set("disable-extensions-except", strings.Join(extensions, ","))
+	}
+
+	for _, flag := range browserFlags {
+		switch {
+		case flag.remove:
+			l = l.Delete(flag.name)
+		case flag.value == "":
+			l = l.Set(flag.name)
+		default:
+			l = l.Set(flag.name, flag.value)
+		}
+	}
+
+	return l
+}
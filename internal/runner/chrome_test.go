@@ -0,0 +1,63 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/devsheke/scrapollo/internal/models"
+	"github.com/go-rod/rod/lib/launcher"
+)
+
+func TestBrowserFlags(t *testing.T) {
+	dir := t.TempDir()
+	extension := filepath.Join(dir, "extension")
+	if err := os.MkdirAll(extension, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(extension, "manifest.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	accounts := []*models.Account{{Email: "a@example.com", List: "a"}}
+	r, err := New(accounts, OutputDir(dir), Headless(true),
+		BrowserFlags("--lang=de-DE", "disable-gpu", "!enable-automation"), Extensions(extension))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r.display != displayHeadlessNew {
+		t.Errorf("browsers loading extensions use display %d; want Chrome's new headless mode", r.display)
+	}
+
+	args := applyBrowserFlags(launcher.New(), r.parsedBrowserFlags, r.extensions).FormatArgs()
+	for _, want := range []string{"--lang=de-DE", "--disable-gpu", "--load-extension=" + extension} {
+		if !slices.Contains(args, want) {
+			t.Errorf("browser flags %v don't contain %q", args, want)
+		}
+	}
+	if slices.Contains(args, "--enable-automation") {
+		t.Errorf("browser flags %v contain a removed flag", args)
+	}
+
+	for _, opt := range []RunnerOpt{BrowserFlags("--user-data-dir=/tmp"), BrowserFlags("--"), Extensions(dir)} {
+		if _, err := New(accounts, OutputDir(dir), opt); err == nil {
+			t.Error("invalid browser flags or extensions were accepted")
+		}
+	}
+}
@@ -0,0 +1,167 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/actions"
+	"github.com/devsheke/scrapollo/internal/apolloapi"
+	"github.com/devsheke/scrapollo/internal/classify"
+	"github.com/devsheke/scrapollo/internal/journal"
+	"github.com/go-rod/rod"
+)
+
+// The errors that failures are routed to once the pages they happened on are classified (see
+// [ClassifyFailures]).
+var (
+	ErrorSessionExpired    = errors.New("the account's session expired")
+	ErrorApolloMaintenance = errors.New("apollo is down for maintenance")
+	ErrorPageObstructed    = errors.New("an overlay covered the page")
+	ErrorNoResults         = errors.New("the search has no more results")
+)
+
+// DefaultMaintenanceWait is the default time that jobs wait for once Apollo is found to be down for
+// maintenance.
+const DefaultMaintenanceWait = 15 * time.Minute
+
+// observeTimeout is the time allowed for observing the page that a job failed on.
+const observeTimeout = 10 * time.Second
+
+// ClassifyFailures is a [RunnerOpt] func that configures a [classify.Classifier] to classify the pages
+// that jobs fail on with, so that they're recovered from in the right way rather than merely retried:
+//   - on a captcha, the job is handled like one that hit a security challenge while logging in;
+//   - on the login page, the account's cookies are dropped and the job is retried right away;
+//   - while Apollo is down for maintenance, every job waits for maintenanceWait (or
+//     [DefaultMaintenanceWait] if it's zero);
+//   - when an overlay covers the page, every known annoyance is looked out for from then on and the job
+//     is retried right away;
+//   - when the search has no results (left), the job moves on to scraping the leads it saved, or
+//     finishes if there are none.
+func ClassifyFailures(classifier classify.Classifier, maintenanceWait time.Duration) RunnerOpt {
+	return func(r *Runner) {
+		r.classifier = classifier
+		r.maintenanceWait = cmp.Or(maintenanceWait, DefaultMaintenanceWait)
+	}
+}
+
+// classifiable reports whether the failure of a job is worth classifying the page of, i.e. whether
+// its error doesn't already tell how to recover from it.
+func classifiable(err error) bool {
+	switch {
+	case errors.Is(err, actions.ErrorSecurityChallenge),
+		errors.Is(err, actions.ErrorListEnd),
+		errors.Is(err, ErrorNoCredits),
+		errors.Is(err, ErrorTimeBudgetExceeded),
+		errors.Is(err, apolloapi.ErrorUnauthorized),
+		actions.IsAccountBlocked(err):
+		return false
+	default:
+		return true
+	}
+}
+
+// classifyFailure classifies the page that the job failed on with the error (if failures are
+// classified), and returns its state along with the error that the failure is routed to, which is
+// the error itself if the state isn't recognized.
+func (r *Runner) classifyFailure(page *rod.Page, job *job, err error) (classify.State, error) {
+	if r.classifier == nil || page == nil || page.GetContext().Err() != nil || !classifiable(err) {
+		return "", err
+	}
+
+	obs, obsErr := actions.ObservePage(page, observeTimeout)
+	if obsErr != nil {
+		job.log.Warn().Err(obsErr).Msg("failed to observe the page for classification")
+		return "", err
+	}
+
+	state, classifyErr := r.classifier.Classify(page.GetContext(), obs)
+	if classifyErr != nil {
+		job.log.Warn().Err(classifyErr).Msg("failed to classify the page")
+	}
+
+	if state == classify.StateUnknown {
+		job.log.Debug().Msg("the page that the job failed on wasn't recognized")
+		return state, err
+	}
+
+	job.log.Warn().Err(unwrapError(err)).Str("state", string(state)).Msg("classified the page that the job failed on")
+	r.record(job, journal.Entry{
+		Action: journal.ActionPageClassified,
+		State:  string(state),
+		Error:  unwrapError(err).Error(),
+	})
+
+	return state, r.routeFailure(job, state)
+}
+
+// routeFailure prepares the recovery of the job from a failure on a page of the state, and returns the
+// error that the failure is routed to (see [ClassifyFailures]).
+func (r *Runner) routeFailure(job *job, state classify.State) error {
+	switch state {
+	case classify.StateCaptcha:
+		return actions.ErrorSecurityChallenge
+
+	case classify.StateLoginPage:
+		// the session is gone, so the account logs in with its password next.
+		job.acc.SetLoginCookies(nil)
+		return ErrorSessionExpired
+
+	case classify.StateMaintenance:
+		return ErrorApolloMaintenance
+
+	case classify.StateBannerOverlap:
+		r.watchAllAnnoyances(job)
+		return ErrorPageObstructed
+
+	default: // classify.StateEmptyResults
+		if r.scrapeOnly() || job.acc.Saved == 0 {
+			return actions.ErrorListEnd
+		}
+
+		// as when the search's last page is reached, the leads saved so far are scraped next.
+		job.acc.Target = job.acc.Saved
+		return ErrorNoResults
+	}
+}
+
+// watchAllAnnoyances makes the [Runner] look out for every known annoyance, since one that it wasn't
+// looking out for covered the job's page.
+func (r *Runner) watchAllAnnoyances(job *job) {
+	var added []string
+	for _, annoyance := range actions.KnownAnnoyances {
+		if !slices.Contains(r.annoyances, annoyance) {
+			r.annoyances = append(r.annoyances, annoyance)
+			added = append(added, annoyance.Name)
+		}
+	}
+
+	if len(added) > 0 {
+		job.log.Info().Strs("annoyances", added).Msg("looking out for every known annoyance from now on")
+	}
+}
+
+// waitForMaintenance times out every job's account until Apollo's maintenance is likely over.
+func (r *Runner) waitForMaintenance() {
+	until := r.now().Add(r.maintenanceWait)
+	for _, job := range r.jobs.iter() {
+		if timeout, ok := job.acc.Timeout.Get(); !ok || timeout.Before(until) {
+			job.acc.Timeout.Set(until)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"sync"
+	"time"
+)
+
+// clock tells the time of a [Runner] and waits for it to pass. It's the wall clock, except when the
+// run is simulated (see [Simulate]).
+type clock interface {
+	Now() time.Time
+	Sleep(d time.Duration)
+	After(d time.Duration) <-chan time.Time
+}
+
+// wallClock is the [clock] of real runs.
+type wallClock struct{}
+
+func (wallClock) Now() time.Time { return time.Now() }
+
+func (wallClock) Sleep(d time.Duration) { time.Sleep(d) }
+
+func (wallClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// virtualClock is the [clock] of simulated runs, whose time only passes when it's waited for, so
+// that waiting takes no time at all.
+type virtualClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func newVirtualClock(start time.Time) *virtualClock {
+	return &virtualClock{now: start}
+}
+
+func (c *virtualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+func (c *virtualClock) Sleep(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if d > 0 {
+		c.now = c.now.Add(d)
+	}
+}
+
+func (c *virtualClock) After(d time.Duration) <-chan time.Time {
+	c.Sleep(d)
+
+	ch := make(chan time.Time, 1)
+	ch <- c.Now()
+	return ch
+}
+
+// runClock returns the [Runner]'s clock, which is the wall clock unless the run is simulated.
+func (r *Runner) runClock() clock {
+	if r.clock == nil {
+		return wallClock{}
+	}
+
+	return r.clock
+}
+
+// now returns the current time of the [Runner]'s clock.
+func (r *Runner) now() time.Time {
+	return r.runClock().Now()
+}
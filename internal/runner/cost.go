@@ -0,0 +1,180 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"path/filepath"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/io"
+	"github.com/rs/zerolog/log"
+)
+
+// CostReportFile is the name of the file, in the output directory, that the cost report is written
+// to at the end of a run.
+const CostReportFile string = "cost-report.json"
+
+// CostRates represents the prices of the resources consumed by jobs, which the cost of scraping is
+// estimated from.
+type CostRates struct {
+	// Currency is the currency of the prices, e.g. 'USD'.
+	Currency string
+
+	// Credit is the price of one of Apollo's credits and VpnHour the price of an hour connected to
+	// a VPN.
+	Credit, VpnHour float64
+}
+
+// cost returns the cost of the credits and VPN time consumed.
+func (c CostRates) cost(credits int, vpnTime time.Duration) float64 {
+	return float64(credits)*c.Credit + vpnTime.Hours()*c.VpnHour
+}
+
+// CostReport is a [RunnerOpt] func that configures the [Runner] to write a report of the cost of each
+// account's and campaign's jobs at the end of a run, estimated with the provided rates. Campaigns
+// may have rates of their own (see [Campaign]).
+func CostReport(rates CostRates) RunnerOpt {
+	return func(r *Runner) {
+		r.costRates = &rates
+	}
+}
+
+// jobCostRates returns the rates that the cost of the job is estimated with.
+func (r *Runner) jobCostRates(job *job) CostRates {
+	if job.campaign != nil && job.campaign.CostRates != nil {
+		return *job.campaign.CostRates
+	}
+
+	return *r.costRates
+}
+
+// Cost reports what the jobs of an account or a campaign consumed during a run and what they cost.
+// Bytes is the number of bytes that their browsers transferred, Leads the number of leads that were
+// scraped, Amount what they cost in all and PerLead what each of them cost.
+type Cost struct {
+	Credits  int     `json:"credits"`
+	VpnHours float64 `json:"vpn-hours"`
+	Bytes    int64   `json:"bytes"`
+	Leads    int     `json:"leads"`
+	Amount   float64 `json:"cost"`
+	PerLead  float64 `json:"per-lead"`
+	Currency string  `json:"currency,omitempty"`
+}
+
+// add adds the consumption of a job, which cost the provided amount, to the cost.
+func (c *Cost) add(result JobResult, cost float64) {
+	c.Credits += result.Credits
+	c.VpnHours += result.VpnTime.Hours()
+	c.Bytes += result.Traffic.Total()
+	c.Leads += result.Scraped
+	c.Amount += cost
+
+	if c.Leads > 0 {
+		c.PerLead = c.Amount / float64(c.Leads)
+	}
+}
+
+// AccountCost is the [Cost] of an account's job.
+type AccountCost struct {
+	Account  string `json:"account"`
+	Campaign string `json:"campaign,omitempty"`
+	Cost
+}
+
+// CampaignCost is the [Cost] of a campaign's jobs.
+type CampaignCost struct {
+	Campaign string `json:"campaign"`
+	Cost
+}
+
+// CostReportResult reports the cost of a run, broken down by account and by campaign.
+type CostReportResult struct {
+	Accounts  []AccountCost  `json:"accounts"`
+	Campaigns []CampaignCost `json:"campaigns,omitempty"`
+	Total     Cost           `json:"total"`
+}
+
+// Costs returns the cost of the run so far, estimated with the rates configured with [CostReport].
+// Like [Runner.Results], it's meant to be called once [Runner.Start] has returned. Costs in different
+// currencies aren't converted, so the total's currency is only set if every rate shares it.
+func (r *Runner) Costs() CostReportResult {
+	var report CostReportResult
+	if r.costRates == nil {
+		return report
+	}
+
+	campaigns := make(map[string]*CampaignCost, len(r.campaigns))
+	for _, c := range r.campaigns {
+		report.Campaigns = append(report.Campaigns, CampaignCost{Campaign: c.Name})
+	}
+	for i := range report.Campaigns {
+		campaigns[report.Campaigns[i].Campaign] = &report.Campaigns[i]
+	}
+
+	r.results.mu.Lock()
+	rates := make(map[string]CostRates, len(r.results.jobs))
+	for _, job := range r.results.jobs {
+		rates[job.acc.Email] = r.jobCostRates(job)
+	}
+	r.results.mu.Unlock()
+
+	currencies := make(map[string]bool)
+	for _, result := range r.Results() {
+		rate := rates[result.Account]
+		cost := rate.cost(result.Credits, result.VpnTime)
+		currencies[rate.Currency] = true
+
+		account := AccountCost{Account: result.Account, Campaign: result.Campaign}
+		account.Currency = rate.Currency
+		account.add(result, cost)
+		report.Accounts = append(report.Accounts, account)
+
+		if c, ok := campaigns[result.Campaign]; ok {
+			c.Currency = rate.Currency
+			c.add(result, cost)
+		}
+		report.Total.add(result, cost)
+	}
+
+	if len(currencies) == 1 {
+		for currency := range currencies {
+			report.Total.Currency = currency
+		}
+	}
+
+	return report
+}
+
+// writeCostReport writes the cost report to the output directory, if the [Runner] estimates costs.
+func (r *Runner) writeCostReport() {
+	if r.costRates == nil {
+		return
+	}
+
+	report := r.Costs()
+	file := filepath.Join(r.outputDir, CostReportFile)
+	if err := io.SaveRecords(file, report); err != nil {
+		log.Warn().Err(err).Msg("failed to write cost report")
+		return
+	}
+
+	log.Info().
+		Int("credits", report.Total.Credits).
+		Float64("vpn-hours", report.Total.VpnHours).
+		Float64("cost", report.Total.Amount).
+		Str("currency", report.Total.Currency).
+		Str("file", file).
+		Msg("saved cost report")
+}
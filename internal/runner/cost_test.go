@@ -0,0 +1,68 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/actions"
+	"github.com/devsheke/scrapollo/internal/models"
+)
+
+func TestCosts(t *testing.T) {
+	accounts := []*models.Account{
+		{Email: "a@example.com", List: "a", Campaign: "acme"},
+		{Email: "b@example.com", List: "b", Campaign: "acme"},
+		{Email: "c@example.com", List: "c"},
+	}
+
+	acme := &Campaign{Name: "acme", CostRates: &CostRates{Currency: "EUR", Credit: 0.2}}
+	rates := CostRates{Currency: "USD", Credit: 0.1, VpnHour: 2}
+	r, err := New(accounts, OutputDir(t.TempDir()), Campaigns(acme), CostReport(rates))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, job := range r.jobs.iter() {
+		r.incrementSaved(job, 50)
+		r.addTraffic(job, actions.Traffic{Received: 1000, Sent: 24})
+		r.results.update(job, func(result *JobResult) {
+			result.Scraped = 100
+			result.VpnTime = 30 * time.Minute
+		})
+	}
+
+	report := r.Costs()
+	costs := make(map[string]AccountCost)
+	for _, c := range report.Accounts {
+		costs[c.Account] = c
+	}
+
+	near := func(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+	if c := costs["a@example.com"]; c.Credits != 50 || !near(c.Amount, 10) || !near(c.PerLead, 0.1) || c.Currency != "EUR" {
+		t.Errorf("unexpected cost of a campaign's account: %+v", c)
+	}
+	if c := costs["c@example.com"]; !near(c.VpnHours, 0.5) || !near(c.Amount, 6) || !near(c.PerLead, 0.06) || c.Currency != "USD" {
+		t.Errorf("unexpected cost of an account: %+v", c)
+	}
+	if len(report.Campaigns) != 1 || report.Campaigns[0].Leads != 200 || !near(report.Campaigns[0].Amount, 20) {
+		t.Errorf("unexpected campaign costs: %+v", report.Campaigns)
+	}
+	if report.Total.Credits != 150 || report.Total.Bytes != 3072 || !near(report.Total.Amount, 26) || report.Total.Currency != "" {
+		t.Errorf("unexpected total cost: %+v", report.Total)
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/journal"
+	"github.com/rs/zerolog/log"
+)
+
+// ErrorTooManyFailures is the error of jobs which were dead-lettered (see [MaxFailures]).
+var ErrorTooManyFailures = errors.New("job failed too many times")
+
+// deadLetterFilePrefix is the prefix of the file that dead-lettered jobs are written to, which is
+// followed by the run's ID.
+const deadLetterFilePrefix = "scrapollo-dead-letter"
+
+// MaxFailures is a [RunnerOpt] func that configures the number of times a job can fail with a generic
+// error before it's given up on, rather than cycling in the queue forever. Given up jobs are moved to
+// a dead-letter list, which is written to the output directory with their full error history, and
+// their accounts are kept in the progress file so that a later run can retry them. Jobs are never
+// given up on if n is 0.
+func MaxFailures(n int) RunnerOpt {
+	return func(r *Runner) {
+		r.maxFailures = n
+	}
+}
+
+// jobError is an error that a run of a job ended with.
+type jobError struct {
+	Time  time.Time `json:"time"`
+	JobID string    `json:"job-id"`
+	Error string    `json:"error"`
+}
+
+// deadLetter is a job that was given up on, as written to the dead-letter file.
+type deadLetter struct {
+	Account  string     `json:"account"`
+	List     string     `json:"list"`
+	Saved    int        `json:"saved"`
+	Target   int        `json:"target"`
+	Failures int        `json:"failures"`
+	Time     time.Time  `json:"time"`
+	Errors   []jobError `json:"errors"`
+}
+
+// failedTooOften counts the generic failure of the job, and returns true if it has now failed as many
+// times as allowed (see [MaxFailures]).
+func (r *Runner) failedTooOften(job *job) bool {
+	job.totalFailures++
+	return r.maxFailures > 0 && job.totalFailures >= r.maxFailures
+}
+
+// deadLetterJob removes the job at the front of the queue and adds it to the dead-letter file,
+// keeping its account for the progress output.
+func (r *Runner) deadLetterJob(job *job) {
+	job.log.Error().Int("failures", job.totalFailures).Msg("job failed too many times, moving it to the dead-letter list")
+	r.record(job, journal.Entry{Action: journal.ActionDeadLettered, Error: ErrorTooManyFailures.Error()})
+
+	r.results.update(job, func(result *JobResult) {
+		result.Err = errors.Join(ErrorTooManyFailures, result.Err)
+	})
+
+	r.jobs.Remove(r.jobs.Front())
+	r.dropped = append(r.dropped, job.acc)
+	r.deadLetters = append(r.deadLetters, &deadLetter{
+		Account:  job.acc.Email,
+		List:     job.acc.List,
+		Saved:    job.acc.Saved,
+		Target:   job.acc.Target,
+		Failures: job.totalFailures,
+		Time:     r.now(),
+		Errors:   job.errors,
+	})
+	r.status.update(func(status *Status) { status.PendingJobs = r.jobs.Len() })
+
+	if err := r.writeDeadLetters(); err != nil {
+		log.Warn().Err(err).Msg("failed to write the dead-letter list")
+	}
+}
+
+// writeDeadLetters writes every job dead-lettered so far to the run's dead-letter file.
+func (r *Runner) writeDeadLetters() error {
+	b, err := json.MarshalIndent(r.deadLetters, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	file := filepath.Join(r.outputDir, deadLetterFilePrefix+"-"+r.runID+".json")
+	log.Info().Str("file", file).Msg("saving dead-letter list")
+
+	return os.WriteFile(file, b, 0644)
+}
@@ -0,0 +1,63 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/models"
+)
+
+func TestMaxFailures(t *testing.T) {
+	dir := t.TempDir()
+	acc := &models.Account{Email: "a@example.com", List: "a", Target: 100, Credits: 1000}
+
+	sim := Simulation{ErrorRate: 1, Seed: 42, Horizon: 24 * time.Hour}
+	r, err := New([]*models.Account{acc}, OutputDir(dir), CsvOutput(), Simulate(sim), MaxFailures(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.Start(); err != nil {
+		t.Fatalf("the run stopped with %v; want the failing job to be dead-lettered", err)
+	}
+
+	if result := r.Results()[0]; result.Runs != 3 || result.Status != JobFailed {
+		t.Errorf("unexpected result of the dead-lettered job: %+v", result)
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, SimulationDir, deadLetterFilePrefix+"-*.json"))
+	if len(files) != 1 {
+		t.Fatalf("found dead-letter files %v; want 1", files)
+	}
+
+	b, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var letters []deadLetter
+	if err := json.Unmarshal(b, &letters); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(letters) != 1 || letters[0].Account != acc.Email || letters[0].Failures != 3 || len(letters[0].Errors) != 3 {
+		t.Errorf("unexpected dead-letter list: %+v", letters)
+	}
+}
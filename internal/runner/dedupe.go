@@ -0,0 +1,107 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"github.com/devsheke/scrapollo/internal/actions"
+	"github.com/devsheke/scrapollo/internal/journal"
+	"github.com/devsheke/scrapollo/internal/models"
+	"github.com/go-rod/rod"
+)
+
+// uncaptured returns the leads on the current page which haven't been captured by any account yet
+// and their rows. The rows are nil if none of the page's leads have been captured, so that the
+// whole page is saved.
+func (r *Runner) uncaptured(page *rod.Page) (leads []*models.Lead, rows []int, err error) {
+	all, err := actions.ListLeads(page, r.timeouts.TableLoad)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rows, err = r.dedupe.Unseen(all)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, row := range rows {
+		leads = append(leads, all[row])
+	}
+
+	if len(rows) == len(all) {
+		rows = nil
+	}
+
+	return leads, rows, nil
+}
+
+// skipCaptured moves on to the next page of the job's search, since every lead on the current page
+// has already been captured. The job's target is lowered to the leads saved so far once the search
+// runs out of pages.
+func (r *Runner) skipCaptured(page *rod.Page, job *job, pageData *actions.PageData) error {
+	job.log.Info().Int("page", pageData.Number).Msg("leads already captured, skipping page")
+	r.record(job, journal.Entry{
+		Action:   journal.ActionPageSkipped,
+		Page:     pageData.Number,
+		Captured: pageData.Size,
+	})
+
+	return r.skipPage(page, job, pageData)
+}
+
+// skipTeamOwned moves on to the next page when none of the current page's leads were saved because
+// they're all in the contacts of another seat of the account's team. They'd stay on the page, so
+// saving it again would only be refused again.
+func (r *Runner) skipTeamOwned(page *rod.Page, job *job, pageData *actions.PageData, teamOwned int) error {
+	job.log.Info().Int("page", pageData.Number).Msg("leads already in the team's contacts, skipping page")
+	r.record(job, journal.Entry{
+		Action:    journal.ActionPageSkipped,
+		Page:      pageData.Number,
+		Captured:  pageData.Size - teamOwned,
+		TeamOwned: teamOwned,
+	})
+
+	return r.skipPage(page, job, pageData)
+}
+
+// skipPage moves on to the page after the current one without saving its leads, finishing the job's
+// list if it's the last one.
+func (r *Runner) skipPage(page *rod.Page, job *job, pageData *actions.PageData) error {
+	if pageData.LastPage {
+		job.acc.Target = job.acc.Saved
+		return nil
+	}
+
+	switch err := pageData.NextPage(page, r.timeouts.TableLoad); err {
+	case nil:
+		r.status.progress()
+		return nil
+	case actions.ErrorListEnd:
+		job.acc.Target = job.acc.Saved
+		return nil
+	default:
+		return err
+	}
+}
+
+// markCaptured adds the leads to the dedupe store (if any).
+func (r *Runner) markCaptured(job *job, leads []*models.Lead) {
+	if r.dedupe == nil {
+		return
+	}
+
+	if err := r.dedupe.Add(leads...); err != nil {
+		job.log.Warn().Err(err).Msg("failed to add leads to dedupe store")
+	}
+}
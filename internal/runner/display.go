@@ -0,0 +1,80 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/go-rod/rod/lib/launcher"
+	"github.com/rs/zerolog/log"
+)
+
+// display is the way in which the browsers launched by the [Runner] display their pages.
+type display int
+
+const (
+	// displayHeadless runs browsers in classic headless mode.
+	displayHeadless display = iota
+
+	// displayHeadful runs browsers on the host's display.
+	displayHeadful
+
+	// displayXvfb runs headful browsers on a virtual display started with xvfb-run.
+	displayXvfb
+
+	// displayHeadlessNew runs browsers in Chrome's new headless mode, which is the headful browser
+	// without a window.
+	displayHeadlessNew
+)
+
+// xvfbScreen is the screen of the virtual displays started for browsers.
+const xvfbScreen = "1920x1080x24"
+
+// resolveDisplay returns the way in which browsers display their pages. Virtual headful browsers
+// run on the host's display if it has one, on a virtual display started with Xvfb on Linux servers
+// without one, and in Chrome's new headless mode if Xvfb isn't installed.
+func (r *Runner) resolveDisplay() display {
+	switch {
+	case !r.virtualDisplay && r.headless:
+		return displayHeadless
+
+	case !r.virtualDisplay, runtime.GOOS != "linux", os.Getenv("DISPLAY") != "":
+		return displayHeadful
+	}
+
+	if _, err := exec.LookPath("xvfb-run"); err != nil {
+		log.Warn().Msg("xvfb-run wasn't found, so browsers will run in Chrome's new headless mode instead")
+		return displayHeadlessNew
+	}
+
+	log.Info().Str("screen", xvfbScreen).Msg("running browsers on virtual displays")
+	return displayXvfb
+}
+
+// apply configures the launcher to display pages in this way.
+func (d display) apply(l *launcher.Launcher) *launcher.Launcher {
+	switch d {
+	case displayHeadful:
+		return l.Headless(false)
+	case displayXvfb:
+		return l.Headless(false).XVFB("--auto-servernum", "--server-args=-screen 0 "+xvfbScreen)
+	case displayHeadlessNew:
+		return l.HeadlessNew(true)
+	default:
+		return l.Headless(true)
+	}
+}
@@ -0,0 +1,66 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"testing"
+
+	"github.com/go-rod/rod/lib/launcher"
+	"github.com/go-rod/rod/lib/launcher/flags"
+)
+
+func TestResolveDisplay(t *testing.T) {
+	t.Setenv("DISPLAY", ":0")
+
+	for _, tt := range []struct {
+		headless, virtual bool
+		want              display
+	}{
+		{headless: true, want: displayHeadless},
+		{headless: false, want: displayHeadful},
+		{headless: true, virtual: true, want: displayHeadful},
+	} {
+		r := &Runner{headless: tt.headless, virtualDisplay: tt.virtual}
+		if got := r.resolveDisplay(); got != tt.want {
+			t.Errorf("resolveDisplay() with headless=%t, virtual=%t = %d, want %d", tt.headless, tt.virtual, got, tt.want)
+		}
+	}
+}
+
+func TestDisplayApply(t *testing.T) {
+	for _, tt := range []struct {
+		display  display
+		headless []string
+		xvfb     bool
+	}{
+		{displayHeadless, nil, false},
+		{displayHeadful, nil, false},
+		{displayXvfb, nil, true},
+		{displayHeadlessNew, []string{"new"}, false},
+	} {
+		l := tt.display.apply(launcher.New())
+
+		headless, ok := l.GetFlags(flags.Headless)
+		if want := tt.display == displayHeadless || tt.display == displayHeadlessNew; ok != want {
+			t.Errorf("display %d: headless = %t, want %t", tt.display, ok, want)
+		} else if len(headless) != len(tt.headless) || (len(headless) > 0 && headless[0] != tt.headless[0]) {
+			t.Errorf("display %d: headless = %v, want %v", tt.display, headless, tt.headless)
+		}
+
+		if l.Has(flags.XVFB) != tt.xvfb {
+			t.Errorf("display %d: xvfb = %t, want %t", tt.display, l.Has(flags.XVFB), tt.xvfb)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"math"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/actions"
+)
+
+// JobProgress reports how far the current job is from its target, and when it's expected to reach it.
+type JobProgress struct {
+	Saved   int `json:"saved"`
+	Target  int `json:"target"`
+	Percent int `json:"percent"`
+
+	// The rates at which pages and leads have been saved by the job's current run.
+	PagesPerMinute float64 `json:"pages-per-minute"`
+	LeadsPerMinute float64 `json:"leads-per-minute"`
+
+	// ETA is when the target is expected to be reached, taking the daily limit into account. It's
+	// unset until the job has saved a page.
+	ETA time.Time `json:"eta,omitempty"`
+}
+
+// rateMeter measures the rate at which a job saves pages and leads.
+type rateMeter struct {
+	since        time.Time
+	pages, leads int
+}
+
+func newRateMeter() *rateMeter {
+	return &rateMeter{since: time.Now()}
+}
+
+// add records that a page with the provided number of leads has been saved.
+func (m *rateMeter) add(leads int) {
+	m.pages++
+	m.leads += leads
+}
+
+// perMinute returns the number of pages and leads saved per minute.
+func (m *rateMeter) perMinute() (pages, leads float64) {
+	minutes := time.Since(m.since).Minutes()
+	if minutes <= 0 {
+		return 0, 0
+	}
+
+	return float64(m.pages) / minutes, float64(m.leads) / minutes
+}
+
+// jobProgress computes the progress of the job, whose current page of the list is described by
+// pageData. The target is capped by the number of leads left in the list, as given by its total size.
+func (r *Runner) jobProgress(job *job, meter *rateMeter, pageData *actions.PageData) JobProgress {
+	p := JobProgress{Saved: job.acc.Saved, Target: job.acc.Target}
+	if available := job.acc.Saved + pageData.TotalSize - (pageData.Start - 1); pageData.TotalSize > 0 && available < p.Target {
+		p.Target = available
+	}
+
+	if p.Target > 0 {
+		p.Percent = min(100, p.Saved*100/p.Target)
+	}
+
+	p.PagesPerMinute, p.LeadsPerMinute = meter.perMinute()
+	p.ETA = estimateETA(p.Target-p.Saved, job.savedToday, r.dailyLimit(job), p.LeadsPerMinute, time.Now())
+
+	return p
+}
+
+// estimateETA estimates when the remaining leads will have been saved at the provided rate (in leads
+// per minute), given that at most limit leads can be saved per day, savedToday of which have already
+// been saved today. Like the [Runner], the estimate pauses for 24 hours whenever the daily limit is
+// hit. The zero time is returned if there's no rate to go by.
+func estimateETA(remaining, savedToday, limit int, rate float64, now time.Time) time.Time {
+	if remaining <= 0 {
+		return now
+	} else if rate <= 0 {
+		return time.Time{}
+	}
+
+	eta := now.Add(time.Duration(math.Ceil(float64(remaining) / rate * float64(time.Minute))))
+	if today := max(limit-savedToday, 0); limit > 0 && remaining > today {
+		days := (remaining - today + limit - 1) / limit
+		eta = eta.Add(time.Duration(days) * 24 * time.Hour)
+	}
+
+	return eta
+}
@@ -0,0 +1,48 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEstimateETA(t *testing.T) {
+	now := time.Date(2025, time.March, 4, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name                         string
+		remaining, savedToday, limit int
+		rate                         float64
+		want                         time.Time
+	}{
+		{"done", 0, 0, 500, 10, now},
+		{"no rate", 100, 0, 500, 0, time.Time{}},
+		{"today", 100, 0, 500, 10, now.Add(10 * time.Minute)},
+		{"no limit", 1000, 0, 0, 10, now.Add(100 * time.Minute)},
+		{"tomorrow", 100, 450, 500, 10, now.Add(24*time.Hour + 10*time.Minute)},
+		{"days", 1200, 0, 500, 10, now.Add(48*time.Hour + 120*time.Minute)},
+		{"limit hit", 500, 500, 500, 10, now.Add(24*time.Hour + 50*time.Minute)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := estimateETA(tt.remaining, tt.savedToday, tt.limit, tt.rate, now)
+			if !got.Equal(tt.want) {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
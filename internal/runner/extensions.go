@@ -0,0 +1,206 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"container/list"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/actions"
+	"github.com/devsheke/scrapollo/internal/openvpn"
+	"github.com/go-rod/rod"
+	"github.com/rs/zerolog/log"
+)
+
+// VpnProvider is an interface for connecting jobs to a VPN. It is implemented by [*openvpn.Manager],
+// which spawns OpenVPN itself, and by the providers in the exitnode package, which drive an exit
+// node managed elsewhere.
+type VpnProvider interface {
+	// Restart (re)connects to the VPN with the provided config.
+	Restart(config string) error
+
+	// Stop disconnects from the VPN. ErrorNoVpnProcess (from openvpn-go) is returned if it
+	// isn't connected.
+	Stop() error
+
+	// Backup connects to the VPN with an unused config (preferring one from the provided
+	// region, if any) and returns it.
+	Backup(region string) (string, error)
+
+	// UseConfig records that the provided config was used.
+	UseConfig(config string)
+
+	// Region returns the region (country code) of the provided config, if it is known.
+	Region(config string) string
+
+	// Proxy returns the proxy that the browser must connect through to use the VPN, if any.
+	Proxy() string
+
+	// Status returns the status of the VPN connection.
+	Status() openvpn.Status
+}
+
+// Candidate describes a pending job as presented to a [JobScheduler].
+type Candidate struct {
+	Email, List            string
+	Saved, Target, Credits int
+
+	// Org is the organisation whose credit pool the candidate's account shares with its other
+	// seats (if any).
+	Org string
+}
+
+// JobScheduler is an interface for deciding which of the pending jobs is run next.
+type JobScheduler interface {
+	// Next returns the index of the candidate that should be run next.
+	Next(candidates []Candidate) (int, error)
+}
+
+// Notification represents a noteworthy event that occurred while running a job.
+type Notification struct {
+	Time    time.Time
+	Account string
+	Event   string
+	Message string
+	RunID   string
+	JobID   string
+}
+
+// Notifier is an interface for delivering [Notification]s to external systems.
+type Notifier interface {
+	Notify(Notification) error
+}
+
+// The events that [Notifier]s are notified of.
+const (
+	EventJobFinished       string = "job-finished"
+	EventJobFailed         string = "job-failed"
+	EventDailyLimit        string = "daily-limit"
+	EventNoCredits         string = "no-credits"
+	EventSecurityChallenge string = "security-challenge"
+	EventAccountBanned     string = "account-banned"
+	EventVpnRegion         string = "vpn-region-mismatch"
+	EventDirectConnection  string = "direct-connection"
+)
+
+// PageCapture is the rendered HTML of a page of leads, as presented to a [PageHook].
+type PageCapture struct {
+	Account, List, RunID, JobID string
+	Page                        int
+	URL                         string
+
+	// Table is the outer HTML of the page's leads table, and Rows the outer HTML of each of its rows.
+	Table string
+	Rows  []string
+}
+
+// PageHook is an interface for receiving the rendered HTML of every page of leads that's scraped,
+// e.g. to extract leads with a custom parser alongside the built-in one.
+type PageHook interface {
+	OnPage(PageCapture) error
+}
+
+// schedule lets the configured [JobScheduler] (if any) move the job that should be run next to the
+// front of the queue. Jobs that are timed out are not presented to the scheduler.
+func (r *Runner) schedule() {
+	if r.scheduler == nil {
+		return
+	}
+
+	var candidates []Candidate
+	var elements []*list.Element
+	for element := r.jobs.Front(); element != nil; element = element.Next() {
+		job, _ := element.Value.(*job)
+		if t, ok := job.acc.Timeout.Get(); ok && time.Now().Before(t) {
+			continue
+		}
+
+		candidates = append(candidates, Candidate{
+			Email:   job.acc.Email,
+			List:    job.acc.List,
+			Saved:   job.acc.Saved,
+			Target:  job.acc.Target,
+			Credits: job.acc.Credits,
+			Org:     job.acc.Org,
+		})
+		elements = append(elements, element)
+	}
+
+	if len(candidates) == 0 {
+		return
+	}
+
+	idx, err := r.scheduler.Next(candidates)
+	if err != nil {
+		log.Warn().Err(err).Msg("scheduler failed to pick the next job")
+		return
+	} else if idx < 0 || idx >= len(candidates) {
+		log.Warn().Int("index", idx).Msg("scheduler picked an invalid job")
+		return
+	}
+
+	r.jobs.MoveToFront(elements[idx])
+}
+
+// capturePage presents the rendered HTML of the current page of leads to the configured [PageHook]s
+// (if any). Failures are only logged, since the page's leads are scraped regardless.
+func (r *Runner) capturePage(page *rod.Page, job *job, number int) {
+	if len(r.pageHooks) == 0 {
+		return
+	}
+
+	html, err := actions.CapturePageHTML(page, r.timeouts.TableLoad)
+	if err != nil {
+		job.log.Warn().Err(err).Int("page", number).Msg("failed to capture the page's html")
+		return
+	}
+
+	capture := PageCapture{
+		Account: job.acc.Email,
+		List:    job.acc.List,
+		RunID:   r.runID,
+		JobID:   job.id,
+		Page:    number,
+		Table:   html.Table,
+		Rows:    html.Rows,
+	}
+	if info, err := page.Info(); err == nil {
+		capture.URL = info.URL
+	}
+
+	for _, hook := range r.pageHooks {
+		if err := hook.OnPage(capture); err != nil {
+			job.log.Warn().Err(err).Int("page", number).Msg("page hook failed")
+		}
+	}
+}
+
+// notify delivers a [Notification] to all configured [Notifier]s.
+func (r *Runner) notify(job *job, event, message string) {
+	notification := Notification{
+		Time:    time.Now(),
+		Account: job.acc.Email,
+		Event:   event,
+		Message: message,
+		RunID:   r.runID,
+		JobID:   job.id,
+	}
+
+	for _, notifier := range r.notifiers {
+		if err := notifier.Notify(notification); err != nil {
+			job.log.Warn().Err(err).Str("event", event).Msg("failed to deliver notification")
+		}
+	}
+}
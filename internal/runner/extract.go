@@ -0,0 +1,107 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"github.com/devsheke/scrapollo/internal/actions"
+	"github.com/devsheke/scrapollo/internal/journal"
+	"github.com/devsheke/scrapollo/internal/models"
+	"github.com/go-rod/rod"
+)
+
+// scrapeTable scrapes the leads of the current page, falling back to extracting them with the LLM (if
+// one is configured) when the scrape script finds none even though the page shows results. reloaded
+// is whether the page was already reloaded after timing out (see [Runner.needsExtraction]).
+func (r *Runner) scrapeTable(page *rod.Page, job *job, pageData *actions.PageData, reloaded bool) (
+	[]*models.Lead, *actions.ScrapeReport, error,
+) {
+	leads, report, err := actions.ScrapeLeads(page, r.timeouts.TableLoad)
+	if r.needsExtraction(pageData, report, err, reloaded) {
+		capture := func() (string, error) { return actions.CaptureResultsHTML(page, r.timeouts.TableLoad) }
+		return r.extractLeads(page, job, pageData, capture, leads, report, err)
+	}
+
+	return leads, report, err
+}
+
+// needsExtraction reports whether the leads of a page should be extracted with the LLM, i.e. whether
+// the page shows results but the scrape script either couldn't find their rows or couldn't scrape any
+// of them. Pages whose leads were all skipped for having no email are left alone. Since timeouts are
+// often transient, the rows not showing up in time only counts once the page was already reloaded
+// after timing out, and didn't fix it.
+func (r *Runner) needsExtraction(
+	pageData *actions.PageData,
+	report *actions.ScrapeReport,
+	err error,
+	reloaded bool,
+) bool {
+	if r.extractor == nil || pageData.Size == 0 {
+		return false
+	} else if err != nil {
+		return reloaded && actions.IsTimeout(err)
+	}
+
+	return report.Extracted == 0 && report.NoEmail == 0
+}
+
+// extractLeads extracts the leads of the current page from the sanitized HTML of its results, as
+// returned by capture, with the LLM. The results of the scrape script are returned instead if that
+// fails or finds no leads either.
+func (r *Runner) extractLeads(
+	page *rod.Page,
+	job *job,
+	pageData *actions.PageData,
+	capture func() (string, error),
+	leads []*models.Lead,
+	report *actions.ScrapeReport,
+	scrapeErr error,
+) ([]*models.Lead, *actions.ScrapeReport, error) {
+	log := job.log.With().Int("page", pageData.Number).Logger()
+
+	html, err := capture()
+	if err != nil {
+		log.Warn().Err(err).Msg("failed to capture the page's html for the llm")
+		return leads, report, scrapeErr
+	}
+
+	extracted, err := r.extractor.Extract(page.GetContext(), html)
+	if err != nil {
+		log.Warn().Err(err).Msg("failed to extract the page's leads with the llm")
+		return leads, report, scrapeErr
+	}
+
+	valid, extractReport := actions.ValidateLeads(page, extracted)
+	if len(valid) == 0 {
+		log.Warn().Msg("the llm found no leads on the page either")
+		return leads, report, scrapeErr
+	}
+
+	calls, tokens := r.extractor.Usage()
+	log.Warn().
+		Int("leads", len(valid)).
+		Int("llm-calls", calls).
+		Int("llm-tokens", tokens).
+		Msg("scrape script found no leads; extracted them with the llm")
+	r.record(job, journal.Entry{
+		Action:    journal.ActionLlmExtracted,
+		Page:      pageData.Number,
+		Leads:     len(valid),
+		Rows:      extractReport.Rows,
+		Recovered: extractReport.Recovered,
+		Invalid:   extractReport.Invalid,
+	})
+
+	return valid, extractReport, nil
+}
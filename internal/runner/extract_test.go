@@ -0,0 +1,48 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/devsheke/scrapollo/internal/actions"
+	"github.com/devsheke/scrapollo/internal/extract"
+)
+
+func TestNeedsExtraction(t *testing.T) {
+	r := &Runner{extractor: extract.New("http://localhost", "", "", extract.Limits{})}
+	pageData := &actions.PageData{Size: 25}
+
+	for _, tc := range []struct {
+		name     string
+		report   *actions.ScrapeReport
+		err      error
+		reloaded bool
+		want     bool
+	}{
+		{"timeout before reload", nil, context.DeadlineExceeded, false, false},
+		{"timeout after reload", nil, context.DeadlineExceeded, true, true},
+		{"other error", nil, errors.New("boom"), true, false},
+		{"no leads", &actions.ScrapeReport{Rows: 25}, nil, false, true},
+		{"no emails", &actions.ScrapeReport{Rows: 25, NoEmail: 25}, nil, false, false},
+		{"leads", &actions.ScrapeReport{Rows: 25, Extracted: 25}, nil, false, false},
+	} {
+		if got := r.needsExtraction(pageData, tc.report, tc.err, tc.reloaded); got != tc.want {
+			t.Errorf("%s: got %t, want %t", tc.name, got, tc.want)
+		}
+	}
+}
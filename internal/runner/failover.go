@@ -0,0 +1,131 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/journal"
+)
+
+// FailoverPolicy determines what the [Runner] does when a job's VPN fails to connect and no
+// backup config is left.
+type FailoverPolicy string
+
+const (
+	// FailoverNone fails the job.
+	FailoverNone FailoverPolicy = "none"
+
+	// FailoverProxy falls back to the first reachable proxy, failing the job if none are.
+	FailoverProxy FailoverPolicy = "proxy"
+
+	// FailoverDirect falls back to the first reachable proxy and, if none are, to a direct
+	// connection which exposes the host's own IP address.
+	FailoverDirect FailoverPolicy = "direct"
+)
+
+// ErrorProxyCredentials is returned for proxies whose URLs contain credentials, which Chrome ignores
+// when it's told to connect through them.
+var ErrorProxyCredentials = errors.New("proxies with credentials are not supported")
+
+// proxyDialTimeout is the max time allowed for checking whether a proxy is reachable.
+const proxyDialTimeout = 5 * time.Second
+
+// ParseFailoverPolicy parses a [FailoverPolicy] from its name.
+func ParseFailoverPolicy(s string) (FailoverPolicy, error) {
+	switch p := FailoverPolicy(s); p {
+	case FailoverNone, FailoverProxy, FailoverDirect:
+		return p, nil
+	default:
+		return "", fmt.Errorf("unsupported failover policy: %q", s)
+	}
+}
+
+// failover falls back to a proxy or a direct connection, as permitted by the [Runner]'s
+// [FailoverPolicy], after the job's VPN failed to connect with the given error.
+func (r *Runner) failover(job *job, cause error) error {
+	if r.failoverPolicy == "" || r.failoverPolicy == FailoverNone {
+		return cause
+	}
+
+	job.log.Warn().Err(cause).Str("policy", string(r.failoverPolicy)).Msg("vpn failed, failing over")
+
+	for _, proxy := range r.proxies {
+		if err := dialProxy(proxy); err != nil {
+			job.log.Warn().Err(err).Str("proxy", proxy).Msg("proxy is unreachable")
+			continue
+		}
+
+		job.proxy = proxy
+		job.log.Warn().Str("proxy", proxy).Msg("connecting through proxy instead of vpn")
+		r.record(job, journal.Entry{Action: journal.ActionFailover, Proxy: proxy, Error: cause.Error()})
+
+		return nil
+	}
+
+	if r.failoverPolicy != FailoverDirect {
+		return fmt.Errorf("no reachable proxies to fail over to: %w", cause)
+	}
+
+	job.log.Warn().Msg("!!! NO VPN OR PROXY AVAILABLE: CONNECTING DIRECTLY, EXPOSING THIS HOST'S IP ADDRESS TO APOLLO !!!")
+	r.record(job, journal.Entry{Action: journal.ActionFailover, Proxy: "direct", Error: cause.Error()})
+	r.notify(job, EventDirectConnection, "vpn and proxies failed, connecting directly")
+
+	return nil
+}
+
+// ValidateProxy checks that the proxy with the given URL (e.g. 'socks5://127.0.0.1:1080') can be
+// failed over to. Since the browser is pointed at proxies with Chrome's '--proxy-server' flag, which
+// ignores credentials, proxies that require authentication can't be used.
+func ValidateProxy(proxy string) error {
+	_, err := parseProxy(proxy)
+	return err
+}
+
+// parseProxy parses the proxy's URL. Its errors only ever name the proxy's host, since the URL may
+// contain credentials, which url.Parse's errors quote in full.
+func parseProxy(proxy string) (*url.URL, error) {
+	u, err := url.Parse(proxy)
+	if err != nil {
+		return nil, errors.New("malformed proxy url")
+	}
+
+	if u.Host == "" {
+		return nil, errors.New("proxy url lacks a scheme or host")
+	} else if u.User != nil {
+		return nil, fmt.Errorf("proxy at %q: %w", u.Host, ErrorProxyCredentials)
+	}
+
+	return u, nil
+}
+
+// dialProxy checks whether the proxy with the given URL (e.g. 'socks5://127.0.0.1:1080') is reachable.
+func dialProxy(proxy string) error {
+	u, err := parseProxy(proxy)
+	if err != nil {
+		return err
+	}
+
+	conn, err := net.DialTimeout("tcp", u.Host, proxyDialTimeout)
+	if err != nil {
+		return err
+	}
+
+	return conn.Close()
+}
@@ -0,0 +1,96 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/io"
+	"github.com/devsheke/scrapollo/internal/models"
+	"github.com/rs/zerolog/log"
+)
+
+// ProgressHistoryDir is the directory (inside the output directory) into which the timestamped
+// snapshots of the progress file are written (see [ProgressHistory]).
+const ProgressHistoryDir string = "progress-history"
+
+// DefaultProgressHistoryTemplate is the default name of the snapshots of the progress file, which
+// keeps one of them per hour.
+const DefaultProgressHistoryTemplate string = "progress-{date}T{hour}"
+
+// progressHistory keeps timestamped snapshots of the progress file, so that a run which corrupts it
+// can be resumed from an earlier state.
+type progressHistory struct {
+	every    time.Duration
+	template string
+	last     time.Time
+}
+
+// ProgressHistory is a [RunnerOpt] func that configures the [Runner] to write a timestamped snapshot of
+// its progress at most every so often, in addition to the latest progress file. The snapshots are
+// named after the template, in which '{run-id}', '{date}', '{hour}' and '{time}' are replaced with
+// the run's ID, the current date, hour and time, and may be partitioned into directories by date
+// with a '/' (e.g. '{date}/progress-{time}'). A zero interval disables the snapshots.
+func ProgressHistory(every time.Duration, template string) RunnerOpt {
+	return func(r *Runner) {
+		if every <= 0 {
+			r.progressHistory = nil
+			return
+		}
+
+		if template == "" {
+			template = DefaultProgressHistoryTemplate
+		}
+		r.progressHistory = &progressHistory{every: every, template: template}
+	}
+}
+
+// snapshotName returns the name (without extension) of the snapshot of the progress taken at the
+// provided time.
+func (h *progressHistory) snapshotName(runID string, now time.Time) string {
+	return strings.NewReplacer(
+		"{run-id}", runID,
+		"{date}", now.Format(time.DateOnly),
+		"{hour}", now.Format("15"),
+		"{time}", now.Format("15.04.05"),
+	).Replace(h.template)
+}
+
+// snapshotProgress writes a snapshot of the accounts' progress, if one is due.
+func (r *Runner) snapshotProgress(accs []*models.Account) {
+	h := r.progressHistory
+	if h == nil || time.Since(h.last) < h.every {
+		return
+	}
+
+	now := time.Now()
+	file := filepath.Join(r.outputDir, ProgressHistoryDir, filepath.FromSlash(h.snapshotName(r.runID, now)))
+	file += string(r.outputFormat)
+	if err := os.MkdirAll(filepath.Dir(file), 0o755); err != nil {
+		log.Warn().Err(err).Msg("failed to create progress history directory")
+		return
+	}
+
+	if err := io.SaveRecords(file, accs); err != nil {
+		log.Warn().Err(err).Str("file", file).Msg("failed to save progress snapshot")
+		return
+	}
+
+	h.last = now
+	log.Debug().Str("file", file).Msg("saved progress snapshot")
+}
@@ -0,0 +1,54 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/models"
+)
+
+func TestProgressHistory(t *testing.T) {
+	dir := t.TempDir()
+	accounts := []*models.Account{{Email: "a@example.com", List: "a"}}
+	r, err := New(accounts, OutputDir(dir), CsvOutput(), ProgressHistory(time.Hour, "{date}/progress-{hour}"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r._saveProgress(); err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	want := filepath.Join(dir, ProgressHistoryDir, now.Format(time.DateOnly), "progress-"+now.Format("15")+string(r.outputFormat))
+	if _, err := os.Stat(want); err != nil {
+		t.Fatalf("no progress snapshot was written: %v", err)
+	}
+
+	// snapshots are only due once the interval has passed.
+	if err := os.Remove(want); err != nil {
+		t.Fatal(err)
+	}
+	if err := r._saveProgress(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(want); err == nil {
+		t.Error("a progress snapshot was written before the interval passed")
+	}
+}
@@ -0,0 +1,434 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"bufio"
+	"encoding/json"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/actions"
+	"github.com/devsheke/scrapollo/internal/apollotest"
+	"github.com/devsheke/scrapollo/internal/events"
+	"github.com/devsheke/scrapollo/internal/journal"
+	"github.com/devsheke/scrapollo/internal/models"
+	"github.com/go-rod/rod/lib/launcher"
+	"github.com/rs/zerolog"
+)
+
+func TestMain(m *testing.M) {
+	// the runner's debug logs would drown out the results of tests and benchmarks.
+	zerolog.SetGlobalLevel(zerolog.WarnLevel)
+	os.Exit(m.Run())
+}
+
+// leadCollector is an [io.LeadWriter] which keeps the leads written to it in memory.
+type leadCollector struct {
+	mu    sync.Mutex
+	leads []*models.Lead
+}
+
+func (c *leadCollector) WriteLead(lead *models.Lead) error {
+	return c.WriteLeads([]*models.Lead{lead})
+}
+
+func (c *leadCollector) WriteLeads(leads []*models.Lead) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.leads = append(c.leads, leads...)
+	return nil
+}
+
+// skipWithoutBrowser skips the test or benchmark if no browser is installed.
+func skipWithoutBrowser(tb testing.TB) {
+	tb.Helper()
+
+	if _, ok := os.LookupEnv("BROWSER"); !ok {
+		if _, ok := launcher.LookPath(); !ok {
+			tb.Skip("no browser found")
+		}
+	}
+}
+
+// runMockJob runs a job which saves target leads out of the provided number of leads on a mock of
+// the Apollo app and then scrapes them, with the provided options on top of the defaults.
+func runMockJob(tb testing.TB, leads, target int, opts ...RunnerOpt) (*apollotest.Server, *models.Account, *leadCollector) {
+	tb.Helper()
+
+	srv := apollotest.NewServer(leads)
+	tb.Cleanup(srv.Close)
+	srv.AddAccount("test@example.com", "password")
+
+	acc := &models.Account{
+		Email:    "test@example.com",
+		Password: "password",
+		URL:      srv.URL + "/#/people",
+		List:     "test",
+		Target:   target,
+	}
+
+	collector := &leadCollector{}
+	r, err := New([]*models.Account{acc}, append([]RunnerOpt{
+		ApolloURL(srv.URL),
+		FetchCredits(true),
+		Headless(true),
+		LeadWriters(collector),
+		OutputDir(tb.TempDir()),
+		Tab("new"),
+		Timeout(20 * time.Second),
+	}, opts...)...)
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	if err := r.Start(); err != nil {
+		tb.Fatal(err)
+	}
+
+	return srv, acc, collector
+}
+
+// TestRunnerIntegration runs a whole job against a mock of the Apollo app. It's skipped if no
+// browser is installed.
+func TestRunnerIntegration(t *testing.T) {
+	skipWithoutBrowser(t)
+
+	srv, acc, collector := runMockJob(t, 60, 50)
+
+	if saved := srv.Saved("test"); saved != 50 {
+		t.Errorf("got %d leads saved on the server, want 50", saved)
+	}
+
+	if acc.Saved != 50 {
+		t.Errorf("got %d leads saved by the account, want 50", acc.Saved)
+	}
+
+	if len(collector.leads) == 0 {
+		t.Fatal("no leads were scraped")
+	}
+
+	if lead := collector.leads[0]; lead.Email == "" || lead.SearchURL != acc.URL {
+		t.Errorf("unexpected lead: %+v", lead)
+	}
+}
+
+// TestRunnerPipelineScrape checks that scraping pages while the next ones load scrapes the same
+// leads. It's skipped if no browser is installed.
+func TestRunnerPipelineScrape(t *testing.T) {
+	skipWithoutBrowser(t)
+
+	_, _, want := runMockJob(t, 60, 50)
+	_, _, got := runMockJob(t, 60, 50, PipelineScrape(true))
+
+	if len(got.leads) != len(want.leads) {
+		t.Fatalf("got %d leads scraped, want %d", len(got.leads), len(want.leads))
+	}
+
+	for i, lead := range got.leads {
+		if lead.Email != want.leads[i].Email {
+			t.Errorf("lead %d: got email %q, want %q", i, lead.Email, want.leads[i].Email)
+		}
+	}
+}
+
+// TestRunnerOverlapRecycle checks that the browser can be restarted while a second tab scrapes the
+// pages that have been saved, which must be run with -race to catch the tab using the browser while
+// it's replaced. It's skipped if no browser is installed.
+func TestRunnerOverlapRecycle(t *testing.T) {
+	skipWithoutBrowser(t)
+
+	_, _, want := runMockJob(t, 60, 50)
+
+	// any browser uses more than a byte of memory, so it's restarted after every saved page.
+	srv, acc, collector := runMockJob(t, 60, 50, OverlapScrape(true), MaxBrowserMemory(1))
+
+	if saved := srv.Saved("test"); saved != 50 || acc.Saved != 50 {
+		t.Errorf("got %d leads saved on the server and %d by the account, want 50", saved, acc.Saved)
+	}
+
+	seen := make(map[string]bool)
+	for _, lead := range collector.leads {
+		if seen[lead.Email] {
+			t.Errorf("lead %q was scraped twice", lead.Email)
+		}
+		seen[lead.Email] = true
+	}
+
+	if len(seen) != len(want.leads) {
+		t.Errorf("got %d leads scraped, want %d", len(seen), len(want.leads))
+	}
+}
+
+// TestRunnerBulkSave checks that an account's leads are saved at once when every lead of the search
+// is to be saved. It's skipped if no browser is installed.
+func TestRunnerBulkSave(t *testing.T) {
+	skipWithoutBrowser(t)
+
+	srv, acc, collector := runMockJob(t, 40, 40, BulkSave(true))
+
+	if saved := srv.Saved("test"); saved != 40 || acc.Saved != 40 {
+		t.Errorf("got %d leads saved on the server and %d by the account, want 40", saved, acc.Saved)
+	}
+
+	if len(collector.leads) == 0 {
+		t.Fatal("no leads were scraped")
+	}
+}
+
+// TestRunnerVerifySaves checks that saves are verified against the size of the account's list. It's
+// skipped if no browser is installed.
+func TestRunnerVerifySaves(t *testing.T) {
+	skipWithoutBrowser(t)
+
+	srv, acc, _ := runMockJob(t, 60, 50, VerifySaves(true))
+
+	if saved := srv.Saved("test"); saved != 50 || acc.Saved != 50 {
+		t.Errorf("got %d leads saved on the server and %d by the account, want 50", saved, acc.Saved)
+	}
+}
+
+// pageCollector is a [PageHook] which collects the pages it's presented.
+type pageCollector struct {
+	pages []PageCapture
+}
+
+func (c *pageCollector) OnPage(capture PageCapture) error {
+	c.pages = append(c.pages, capture)
+	return nil
+}
+
+// TestRunnerPageHooks checks that page hooks receive the HTML of every scraped page. It's skipped if
+// no browser is installed.
+func TestRunnerPageHooks(t *testing.T) {
+	skipWithoutBrowser(t)
+
+	hook := &pageCollector{}
+	_, _, collector := runMockJob(t, 60, 50, PageHooks(hook))
+
+	rows := 0
+	for _, page := range hook.pages {
+		rows += len(page.Rows)
+		if page.Account != "test@example.com" || page.Table == "" || page.URL == "" {
+			t.Errorf("unexpected page capture %d: %+v", page.Page, page)
+		}
+		for _, row := range page.Rows {
+			if !strings.Contains(page.Table, row) {
+				t.Errorf("page %d's table lacks its row %q", page.Page, row)
+			}
+		}
+	}
+
+	if len(hook.pages) == 0 || rows != len(collector.leads) {
+		t.Errorf("captured %d rows on %d pages, want %d", rows, len(hook.pages), len(collector.leads))
+	}
+}
+
+// TestRunnerPrefetchLogins checks that a job runs with the cookies and credits of its prefetched
+// login. It's skipped if no browser is installed.
+func TestRunnerPrefetchLogins(t *testing.T) {
+	skipWithoutBrowser(t)
+
+	srv, acc, _ := runMockJob(t, 60, 50, PrefetchLogins(2, 0))
+
+	if saved := srv.Saved("test"); saved != 50 || acc.Saved != 50 {
+		t.Errorf("got %d leads saved on the server and %d by the account, want 50", saved, acc.Saved)
+	}
+	if _, ok := acc.GetLoginCookies(); !ok {
+		t.Error("expected the account's cookies to be refreshed")
+	}
+}
+
+// TestRunnerAPIExport checks that leads saved with the browser are exported through the API when the
+// account has an API key. It's skipped if no browser is installed.
+func TestRunnerAPIExport(t *testing.T) {
+	skipWithoutBrowser(t)
+
+	srv := apollotest.NewServer(60)
+	defer srv.Close()
+	srv.AddAccount("test@example.com", "password")
+	srv.AddAPIKey("key")
+
+	acc := &models.Account{
+		Email:    "test@example.com",
+		Password: "password",
+		URL:      srv.URL + "/#/people",
+		List:     "test",
+		Target:   50,
+		APIKey:   "key",
+	}
+
+	collector := &leadCollector{}
+	r, err := New([]*models.Account{acc},
+		APIExport(true),
+		APIURL(srv.URL+apollotest.APIPath),
+		ApolloURL(srv.URL),
+		FetchCredits(true),
+		Headless(true),
+		LeadWriters(collector),
+		OutputDir(t.TempDir()),
+		Tab("new"),
+		Timeout(20*time.Second),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	if saved := srv.Saved("test"); saved != 50 || len(collector.leads) != 50 {
+		t.Errorf("got %d leads saved and %d exported, want 50", saved, len(collector.leads))
+	}
+}
+
+// TestRunnerProgressEvents checks that the journal's entries are streamed as progress events. It's
+// skipped if no browser is installed.
+func TestRunnerProgressEvents(t *testing.T) {
+	skipWithoutBrowser(t)
+
+	path := filepath.Join(t.TempDir(), "events.sock")
+	stream, err := events.Listen(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	received := make(chan map[journal.Action]int)
+	go func() {
+		seen := make(map[journal.Action]int)
+		scanner := bufio.NewScanner(conn)
+		for scanner.Scan() {
+			var entry journal.Entry
+			if json.Unmarshal(scanner.Bytes(), &entry) == nil {
+				seen[entry.Action]++
+			}
+		}
+		received <- seen
+	}()
+
+	runMockJob(t, 40, 40, Journal(false), ProgressEvents(stream))
+	if err := stream.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	seen := <-received
+	for _, action := range []journal.Action{journal.ActionPageSaved, journal.ActionPageScraped, journal.ActionJobFinished} {
+		if seen[action] == 0 {
+			t.Errorf("no %q event was published, got %v", action, seen)
+		}
+	}
+}
+
+// TestRunnerBannedAccount checks that a suspended account's job is dropped instead of being retried.
+// It's skipped if no browser is installed.
+func TestRunnerBannedAccount(t *testing.T) {
+	skipWithoutBrowser(t)
+
+	srv := apollotest.NewServer(10)
+	t.Cleanup(srv.Close)
+	srv.AddAccount("test@example.com", "password")
+	srv.Suspend("test@example.com")
+
+	acc := &models.Account{
+		Email:    "test@example.com",
+		Password: "password",
+		URL:      srv.URL + "/#/people",
+		List:     "test",
+		Target:   10,
+	}
+
+	r, err := New(
+		[]*models.Account{acc},
+		ApolloURL(srv.URL),
+		Headless(true),
+		OutputDir(t.TempDir()),
+		Tab("new"),
+		Timeout(10*time.Second),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "banned: " + actions.ErrorAccountSuspended.Error(); acc.Blacklisted != want {
+		t.Errorf("got account blacklisted for %q, want %q", acc.Blacklisted, want)
+	}
+
+	results := r.Results()
+	if len(results) != 1 || results[0].Status != JobDropped || results[0].Err != actions.ErrorAccountSuspended {
+		t.Errorf("unexpected job results: %+v", results)
+	}
+}
+
+// TestRunnerTeamOwnedLeads checks that leads which Apollo refuses to save, because they're already
+// owned by the account's team, are counted separately and skipped. It's skipped if no browser is
+// installed.
+func TestRunnerTeamOwnedLeads(t *testing.T) {
+	skipWithoutBrowser(t)
+
+	srv := apollotest.NewServer(60)
+	t.Cleanup(srv.Close)
+	srv.AddAccount("test@example.com", "password")
+	srv.OwnByTeam(0, 1, 2)
+
+	acc := &models.Account{
+		Email:    "test@example.com",
+		Password: "password",
+		URL:      srv.URL + "/#/people",
+		List:     "test",
+		Target:   50,
+	}
+
+	r, err := New([]*models.Account{acc},
+		ApolloURL(srv.URL),
+		FetchCredits(true),
+		Headless(true),
+		LeadWriters(&leadCollector{}),
+		OutputDir(t.TempDir()),
+		Tab("new"),
+		Timeout(20*time.Second),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	if saved := srv.Saved("test"); saved != acc.Saved {
+		t.Errorf("got %d leads saved on the server, but %d by the account", saved, acc.Saved)
+	}
+
+	if results := r.Results(); results[0].TeamOwned < 3 {
+		t.Errorf("got %d team-owned leads, want at least 3", results[0].TeamOwned)
+	}
+}
@@ -19,24 +19,125 @@ import (
 	"errors"
 	"iter"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/devsheke/scrapollo/internal/actions"
+	"github.com/devsheke/scrapollo/internal/fingerprint"
 	"github.com/devsheke/scrapollo/internal/models"
+	"github.com/go-rod/rod"
+	"github.com/rs/zerolog"
+	"github.com/rs/zerolog/log"
 )
 
 type job struct {
 	acc        *models.Account
 	savedToday int
 	startedAt  *models.Time
+	unwatch    func()
+
+	// pagesScraped is the number of pages of the account's list that have already been scraped.
+	// When the list is split, it's the number of pages of the partition being scraped.
+	pagesScraped int
+
+	// partitionsDone are the partitions of the account's list that have already been scraped, by
+	// their URLs, when the list is split.
+	partitionsDone map[string]bool
+
+	// proxy is the proxy that the job's browser connects through when its VPN failed or when
+	// the VPN is split-tunnelled.
+	proxy string
+
+	// window is the activity window of the job's account, if it has one.
+	window *activityWindow
+
+	// vpnSince is when the job's VPN was connected, if it is.
+	vpnSince time.Time
+
+	// campaign is the campaign that the job's account is part of, if any.
+	campaign *Campaign
+
+	// profile is the device profile of the job's account, if the runner uses them.
+	profile *fingerprint.Profile
+
+	// apolloURL is the URL of the Apollo app that the job's account is served from, if it isn't
+	// the [Runner]'s (see [Regions]).
+	apolloURL string
+
+	// ui is the variant of the Apollo UI that was detected when the job's account logged in.
+	ui actions.UIVariant
+
+	// failures is the number of consecutive generic errors that the job failed with, without making
+	// any progress since failedAt, i.e. the leads saved and pages scraped at its last failure.
+	failures int
+	failedAt [2]int
+
+	// totalFailures is the number of generic errors that the job failed with in all, and errors the
+	// history of the errors that its runs ended with.
+	totalFailures int
+	errors        []jobError
+
+	// reloaded is whether the job's page was reloaded after timing out during its current run (see
+	// [Runner.reloadAfterTimeout]). It's only used by the job's own goroutine.
+	reloaded bool
+
+	// creditsFetched is whether the account's credits were fetched when its login was prefetched,
+	// which makes fetching them again on the job's first run unnecessary.
+	creditsFetched bool
+
+	// id is the correlation ID of the job's current run, which is attached to its logs and outputs.
+	id  string
+	log zerolog.Logger
+
+	mu   sync.Mutex
+	page *rod.Page
+}
+
+// currentPage returns a copy of the page that the job is currently working on.
+func (j *job) currentPage() *rod.Page {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.page == nil {
+		return nil
+	}
+	return j.page.Context(j.page.GetContext())
 }
 
-func (j *job) hitDailyLimit(limit int) bool {
+// setPage sets the page that the job is currently working on.
+func (j *job) setPage(page *rod.Page) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.page = page
+}
+
+// replacePage replaces the contents of the job's current page with those of newPage.
+func (j *job) replacePage(page, newPage *rod.Page) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	*page = *newPage
+}
+
+// begin assigns a new correlation ID to the job for its next run.
+func (j *job) begin() error {
+	id, err := newID()
+	if err != nil {
+		return err
+	}
+
+	j.id = id
+	j.log = log.With().Str("job-id", id).Str("account", j.acc.Email).Logger()
+
+	return nil
+}
+
+func (j *job) hitDailyLimit(limit int, now time.Time) bool {
 	startedAt, ok := j.startedAt.Get()
 	if !ok {
 		return false
 	}
 
-	cond := time.Now().Before(startedAt.Add(24 * time.Hour))
+	cond := now.Before(startedAt.Add(24 * time.Hour))
 	if cond && j.savedToday >= limit {
 		j.reset()
 		return true
@@ -51,13 +152,21 @@ func (j *job) incrementSaved(amount int) {
 	j.savedToday += amount
 }
 
+// stopWatching stops the annoyance watcher running on the job's current page (if any).
+func (j *job) stopWatching() {
+	if j.unwatch != nil {
+		j.unwatch()
+		j.unwatch = nil
+	}
+}
+
 func (j *job) reset() {
 	j.savedToday = 0
 	j.startedAt.Reset()
 }
 
-func (j *job) start() {
-	j.startedAt = models.NewTimeValid(time.Now())
+func (j *job) start(now time.Time) {
+	j.startedAt = models.NewTimeValid(now)
 }
 
 type queue struct {
@@ -65,21 +174,28 @@ type queue struct {
 }
 
 func newQueue(accs []*models.Account) *queue {
-	q := list.New()
+	q := &queue{list.New()}
 	for _, acc := range accs {
-		job := &job{
-			acc:       acc,
-			startedAt: models.NewTime(),
-		}
+		q.push(acc)
+	}
 
-		if acc.List == "" {
-			acc.List = "scrapollo-run-" + strings.ReplaceAll(acc.Email, "@", "_")
-		}
+	return q
+}
+
+// push adds a job for the account to the back of the queue.
+func (q *queue) push(acc *models.Account) *job {
+	job := &job{
+		acc:       acc,
+		startedAt: models.NewTime(),
+		log:       log.With().Str("account", acc.Email).Logger(),
+	}
 
-		q.PushBack(job)
+	if acc.List == "" {
+		acc.List = "scrapollo-run-" + strings.ReplaceAll(acc.Email, "@", "_")
 	}
 
-	return &queue{q}
+	q.PushBack(job)
+	return job
 }
 
 func (q *queue) isEmpty() bool {
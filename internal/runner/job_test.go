@@ -0,0 +1,173 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/models"
+)
+
+// queueEmails returns the emails of the accounts in the queue, in order.
+func queueEmails(q *queue) []string {
+	var emails []string
+	for _, job := range q.iter() {
+		emails = append(emails, job.acc.Email)
+	}
+	return emails
+}
+
+func TestRearrangeJobs(t *testing.T) {
+	now := time.Now()
+	accounts := []*models.Account{
+		{Email: "late@example.com", Timeout: models.NewTimeValid(now.Add(2 * time.Hour))},
+		{Email: "free1@example.com", Timeout: models.NewTime()},
+		{Email: "soon@example.com", Timeout: models.NewTimeValid(now.Add(time.Minute))},
+		{Email: "free2@example.com", Timeout: models.NewTime()},
+	}
+
+	r := &Runner{jobs: newQueue(accounts)}
+	r.rearrangeJobs()
+
+	want := []string{"free1@example.com", "free2@example.com", "soon@example.com", "late@example.com"}
+	if got := queueEmails(r.jobs); !slices.Equal(got, want) {
+		t.Errorf("got queue %v; want %v", got, want)
+	}
+}
+
+// TestRearrangeJobsProperties checks the ordering of random queues, whose timeouts often tie.
+func TestRearrangeJobsProperties(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	now := time.Now()
+
+	for i := range 500 {
+		accounts := make([]*models.Account, rng.IntN(20))
+		for j := range accounts {
+			accounts[j] = &models.Account{Email: fmt.Sprintf("%d@example.com", j), Timeout: models.NewTime()}
+			if rng.IntN(3) > 0 {
+				accounts[j].Timeout.Set(now.Add(time.Duration(rng.IntN(5)) * time.Minute))
+			}
+		}
+
+		r := &Runner{jobs: newQueue(accounts)}
+		r.rearrangeJobs()
+
+		var got []*job
+		for _, job := range r.jobs.iter() {
+			got = append(got, job)
+		}
+
+		if len(got) != len(accounts) {
+			t.Fatalf("queue %d: got %d jobs; want %d", i, len(got), len(accounts))
+		}
+
+		for j := 1; j < len(got); j++ {
+			prev, cur := got[j-1], got[j]
+			switch c := byTimeout(prev, cur); {
+			case c > 0:
+				t.Fatalf("queue %d: %s is ordered before %s", i, prev.acc.Email, cur.acc.Email)
+			case c == 0 && slices.Index(accounts, prev.acc) > slices.Index(accounts, cur.acc):
+				t.Fatalf("queue %d: %s and %s swapped turns", i, prev.acc.Email, cur.acc.Email)
+			}
+		}
+
+		seen := make(map[*models.Account]bool)
+		for _, job := range got {
+			if seen[job.acc] || !slices.Contains(accounts, job.acc) {
+				t.Fatalf("queue %d: %s is duplicated or unknown", i, job.acc.Email)
+			}
+			seen[job.acc] = true
+		}
+	}
+}
+
+func TestQueueRequeue(t *testing.T) {
+	q := newQueue([]*models.Account{{Email: "a@example.com"}, {Email: "b@example.com"}, {Email: "c@example.com"}})
+
+	if err := q.requeue(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := queueEmails(q), []string{"b@example.com", "c@example.com", "a@example.com"}; !slices.Equal(got, want) {
+		t.Errorf("got queue %v; want %v", got, want)
+	}
+
+	if err := newQueue(nil).requeue(); err == nil {
+		t.Error("requeueing in an empty queue succeeded")
+	}
+}
+
+// pickScheduler is a [JobScheduler] which picks the candidate with the given email, or returns its
+// index and error.
+type pickScheduler struct {
+	email string
+	index int
+	err   error
+
+	candidates []Candidate
+}
+
+func (s *pickScheduler) Next(candidates []Candidate) (int, error) {
+	s.candidates = candidates
+	for i, c := range candidates {
+		if c.Email == s.email {
+			return i, nil
+		}
+	}
+	return s.index, s.err
+}
+
+func TestSchedule(t *testing.T) {
+	accounts := func() []*models.Account {
+		return []*models.Account{
+			{Email: "a@example.com", Timeout: models.NewTime()},
+			{Email: "b@example.com", Timeout: models.NewTimeValid(time.Now().Add(time.Hour))},
+			{Email: "c@example.com", Timeout: models.NewTime()},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		scheduler *pickScheduler
+		want      []string
+	}{
+		{"picked", &pickScheduler{email: "c@example.com"}, []string{"c@example.com", "a@example.com", "b@example.com"}},
+		{"timed out", &pickScheduler{email: "b@example.com", index: -1}, []string{"a@example.com", "b@example.com", "c@example.com"}},
+		{"invalid", &pickScheduler{index: 5}, []string{"a@example.com", "b@example.com", "c@example.com"}},
+		{"failed", &pickScheduler{err: errors.New("boom")}, []string{"a@example.com", "b@example.com", "c@example.com"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Runner{jobs: newQueue(accounts()), scheduler: tt.scheduler}
+			r.schedule()
+
+			if got := queueEmails(r.jobs); !slices.Equal(got, tt.want) {
+				t.Errorf("got queue %v; want %v", got, tt.want)
+			}
+
+			// timed out accounts are never offered to the scheduler.
+			for _, c := range tt.scheduler.candidates {
+				if c.Email == "b@example.com" {
+					t.Error("the timed out account was a candidate")
+				}
+			}
+		})
+	}
+}
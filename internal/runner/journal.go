@@ -0,0 +1,121 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/actions"
+	"github.com/devsheke/scrapollo/internal/credits"
+	"github.com/devsheke/scrapollo/internal/journal"
+	"github.com/devsheke/scrapollo/internal/models"
+)
+
+// record appends the provided entry to the job's account journal (if journaling is enabled) and
+// publishes it as a progress event (if enabled).
+func (r *Runner) record(job *job, entry journal.Entry) {
+	if r.journal == nil && r.events == nil {
+		return
+	}
+
+	entry.Time = r.now()
+	entry.Account = job.acc.Email
+	entry.RunID, entry.JobID = r.runID, job.id
+	if entry.List == "" {
+		entry.List = job.acc.List
+	}
+
+	if r.events != nil {
+		r.events.Publish(entry)
+	}
+
+	if r.journal == nil {
+		return
+	}
+
+	if err := r.journal.Record(entry); err != nil {
+		job.log.Warn().Err(err).Msg("failed to write journal entry")
+	}
+}
+
+// restoreSavedToday sets the number of leads saved by the job's account in the last 24 hours from
+// its journal, so that restarting a run doesn't reset its daily limit. The job's day starts with
+// the first of these saves. Nothing is restored if journaling is disabled.
+func (r *Runner) restoreSavedToday(job *job) {
+	if r.journal == nil {
+		return
+	}
+
+	entries, err := r.journal.Read(job.acc.Email)
+	if err != nil {
+		job.log.Warn().Err(err).Msg("failed to read journal")
+	}
+
+	var first time.Time
+	var saved int
+	since := r.now().Add(-24 * time.Hour)
+	for _, entry := range entries {
+		if entry.Time.Before(since) {
+			continue
+		}
+
+		if entry.Action != journal.ActionPageSaved && entry.Action != journal.ActionBulkSaved {
+			continue
+		}
+
+		if first.IsZero() || entry.Time.Before(first) {
+			first = entry.Time
+		}
+		saved += entry.Leads
+	}
+
+	if saved == 0 {
+		return
+	}
+
+	job.startedAt, job.savedToday = models.NewTimeValid(first), saved
+	job.log.Info().
+		Int("saved", saved).
+		Time("since", first).
+		Msg("restored the leads saved in the last 24 hours from the journal")
+}
+
+// recordCredits appends the fetched credit usage to the account's credit history (if enabled).
+func (r *Runner) recordCredits(job *job, info *actions.CreditInfo) {
+	r.record(job, journal.Entry{Action: journal.ActionCreditsFetched, Credits: info.Remaining()})
+
+	if r.creditHistory == nil {
+		return
+	}
+
+	err := r.creditHistory.Append(&credits.Record{
+		Account:   job.acc.Email,
+		Used:      info.Used,
+		Max:       info.Max,
+		Remaining: info.Remaining(),
+		Renewal:   info.Renewal,
+		Plan:      info.Plan,
+	})
+
+	if err != nil {
+		job.log.Warn().Err(err).Msg("failed to write credit history")
+	}
+}
+
+// recordError appends an error entry to the job's account journal and to its error history.
+func (r *Runner) recordError(job *job, err error) {
+	job.errors = append(job.errors, jobError{Time: r.now(), JobID: job.id, Error: unwrapError(err).Error()})
+	r.record(job, journal.Entry{Action: journal.ActionError, Error: unwrapError(err).Error()})
+}
@@ -0,0 +1,56 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/journal"
+	"github.com/devsheke/scrapollo/internal/models"
+)
+
+func TestRestoreSavedToday(t *testing.T) {
+	j, err := journal.New(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const email = "test@example.com"
+	now := time.Now()
+	for _, entry := range []journal.Entry{
+		{Time: now.Add(-30 * time.Hour), Action: journal.ActionPageSaved, Leads: 25},
+		{Time: now.Add(-5 * time.Hour), Action: journal.ActionPageSaved, Leads: 25},
+		{Time: now.Add(-4 * time.Hour), Action: journal.ActionPageScraped, Leads: 25},
+		{Time: now.Add(-3 * time.Hour), Action: journal.ActionBulkSaved, Leads: 100},
+	} {
+		entry.Account = email
+		if err := j.Record(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r := &Runner{journal: j, jobs: newQueue([]*models.Account{{Email: email}})}
+	job := r.jobs.Front().Value.(*job)
+	r.restoreSavedToday(job)
+
+	if job.savedToday != 125 {
+		t.Errorf("restored %d leads saved today; want 125", job.savedToday)
+	}
+
+	if started, ok := job.startedAt.Get(); !ok || !started.Equal(now.Add(-5*time.Hour)) {
+		t.Errorf("day started at %v; want the first save of the last 24 hours", started)
+	}
+}
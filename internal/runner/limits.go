@@ -0,0 +1,89 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/limiter"
+)
+
+// limitPollInterval is how often the runner checks whether a cap that's been reached has freed up.
+const limitPollInterval = 5 * time.Second
+
+// waitForLimit waits for the provided duration or until the context is done. Waiting on a cap isn't
+// a stall, so progress is recorded to keep the watchdog at bay.
+func (r *Runner) waitForLimit(ctx context.Context, d time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return context.Cause(ctx)
+	case <-r.runClock().After(d):
+	}
+
+	r.status.progress()
+	return nil
+}
+
+// acquireLogin waits for a login slot to be available under the concurrent login cap. The returned
+// function releases the slot.
+func (r *Runner) acquireLogin(ctx context.Context, job *job) (release func(), err error) {
+	l := r.jobLimiter(job)
+	if l == nil {
+		return func() {}, nil
+	}
+
+	for logged := false; ; logged = true {
+		release, err := l.AcquireLogin(job.id)
+		if err == nil {
+			return func() {
+				if err := release(); err != nil {
+					job.log.Warn().Err(err).Msg("failed to release login slot")
+				}
+			}, nil
+		} else if !errors.Is(err, limiter.ErrorLoginCapReached) {
+			return nil, err
+		}
+
+		if !logged {
+			job.log.Info().Msg("concurrent login cap reached, waiting for a slot")
+		}
+
+		if err := r.waitForLimit(ctx, limitPollInterval); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// throttleSaves waits until n more leads can be saved under the hourly save cap.
+func (r *Runner) throttleSaves(ctx context.Context, job *job, n int) error {
+	l := r.jobLimiter(job)
+	if l == nil {
+		return nil
+	}
+
+	for {
+		wait, err := l.ReserveSaves(n)
+		if err != nil || wait == 0 {
+			return err
+		}
+
+		job.log.Info().Dur("wait", wait).Msg("hourly save cap reached, waiting")
+		if err := r.waitForLimit(ctx, min(wait, time.Minute)); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"sync"
+
+	"github.com/devsheke/scrapollo/internal/actions"
+	"github.com/go-rod/rod"
+)
+
+// savePipeline coordinates the save flow of a job with a second tab that scrapes the pages of the
+// job's list as soon as they have been filled by saved leads.
+type savePipeline struct {
+	mu       sync.Mutex
+	cond     *sync.Cond
+	saved    int
+	pageSize int
+	finished bool
+}
+
+func newSavePipeline(saved int) *savePipeline {
+	p := &savePipeline{saved: saved}
+	p.cond = sync.NewCond(&p.mu)
+	return p
+}
+
+// leadsSaved updates the number of leads saved to the list and the size of a page of leads.
+func (p *savePipeline) leadsSaved(saved, pageSize int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.saved = saved
+	p.pageSize = max(p.pageSize, pageSize)
+	p.cond.Broadcast()
+}
+
+// finish marks the save flow as finished.
+func (p *savePipeline) finish() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.finished = true
+	p.cond.Broadcast()
+}
+
+// waitForPage blocks until the list has enough leads to fill the page with the given number, returning
+// false if the save flow finishes first.
+func (p *savePipeline) waitForPage(number int) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	for !p.finished && (p.pageSize == 0 || p.saved < number*p.pageSize) {
+		p.cond.Wait()
+	}
+
+	return !p.finished
+}
+
+// startOverlapScrape opens a second tab which scrapes the job's list while leads are still being saved
+// on the job's page. The returned stop func finishes the pipeline and waits for the tab to close; any
+// pages left unscraped are picked up by [Runner.scrapeLeads] once saving is done.
+func (r *Runner) startOverlapScrape(bw *browserWrapper, job *job, p *savePipeline) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		if err := r.scrapeWhileSaving(bw, job, p); err != nil {
+			job.log.Warn().
+				Err(err).
+				Msg("stopped scraping leads while saving")
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			p.finish()
+			<-done
+		})
+	}
+}
+
+func (r *Runner) scrapeWhileSaving(bw *browserWrapper, job *job, p *savePipeline) error {
+	if !p.waitForPage(job.pagesScraped + 1) {
+		return nil
+	}
+
+	tab, err := actions.NewPage(bw.browser, r.stealth, job.profile)
+	if err != nil {
+		return err
+	}
+	defer tab.Close()
+
+	tab = actions.WithUIVariant(tab, job.ui)
+
+	if r.watchAnnoyances && len(r.annoyances) > 0 {
+		if unwatch, err := actions.WatchAnnoyances(tab, r.annoyances); err == nil {
+			defer unwatch()
+		}
+	}
+
+	if err := actions.LocateList(tab, job.acc.List, r.timeout); err != nil {
+		return err
+	}
+
+	_, writers, drain := r.listWriters(job)
+	defer drain()
+	for located := true; ; located = false {
+		number := job.pagesScraped + 1
+		if !p.waitForPage(number) {
+			return nil
+		}
+
+		job.log.Debug().Int("page", number).Msg("scraping saved page")
+
+		// the list's filters are kept in the URL, so reloading is enough to pick up newly saved leads.
+		if !located {
+			if err := rod.Try(func() { tab.MustReload().MustWaitDOMStable() }); err != nil {
+				return err
+			}
+		}
+
+		if err := r.removeAnnoyances(tab); err != nil {
+			return err
+		}
+
+		if number > 1 {
+			if err := actions.GoToPage(tab, number, r.timeouts.TableLoad); err != nil {
+				return err
+			}
+		}
+
+		r.recordFixture(tab, job)
+
+		pageData, err := actions.GetPageData(tab, r.timeouts.TableLoad)
+		if err != nil {
+			return err
+		}
+
+		leads, report, err := r.scrapeTable(tab, job, pageData, false)
+		if err != nil {
+			return err
+		}
+		r.capturePage(tab, job, pageData.Number)
+		r.writeLeads(job, writers, pageData.Number, leads, report)
+	}
+}
@@ -0,0 +1,101 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// pausePollInterval is how often a paused runner checks whether it's been resumed.
+const pausePollInterval = 2 * time.Second
+
+// pauseSwitch tells whether the [Runner] has been paused by an operator, either with a signal
+// (see pauseSignal and resumeSignal) or by creating its control file.
+type pauseSwitch struct {
+	file     string
+	signaled atomic.Bool
+}
+
+// isPaused reports whether the runner is paused.
+func (p *pauseSwitch) isPaused() bool {
+	if p.signaled.Load() {
+		return true
+	}
+
+	if p.file == "" {
+		return false
+	}
+
+	_, err := os.Stat(p.file)
+	return err == nil
+}
+
+// watchSignals pauses and resumes the runner upon receiving pauseSignal and resumeSignal. The
+// returned function stops watching for them.
+func (p *pauseSwitch) watchSignals() (stop func()) {
+	if pauseSignal == nil {
+		return func() {}
+	}
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, pauseSignal, resumeSignal)
+
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case sig := <-signals:
+				paused := sig == pauseSignal
+				if p.signaled.Swap(paused) != paused {
+					log.Info().Bool("paused", paused).Str("signal", sig.String()).Msg("received pause signal")
+				}
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
+
+// waitWhilePaused blocks while the runner is paused or until the context is done.
+func (r *Runner) waitWhilePaused(ctx context.Context) error {
+	if !r.pause.isPaused() {
+		return nil
+	}
+
+	log.Info().Msg("runner paused, waiting to be resumed")
+	prev := r.status.get().State
+	r.status.update(func(status *Status) { status.State = StatePaused })
+
+	for r.pause.isPaused() {
+		if err := r.waitForLimit(ctx, pausePollInterval); err != nil {
+			return err
+		}
+	}
+
+	log.Info().Msg("runner resumed")
+	r.status.update(func(status *Status) { status.State = prev })
+	return nil
+}
@@ -0,0 +1,110 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"iter"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// poolTimeout is how long the seats of an organisation whose credit pool is exhausted are timed
+// out for when the pool's renewal time isn't known.
+const poolTimeout = 24 * time.Hour
+
+// orgKey returns the key identifying the organisation of the job's account, which is empty if the
+// account isn't part of one.
+func orgKey(job *job) string {
+	return strings.ToLower(strings.TrimSpace(job.acc.Org))
+}
+
+// seats returns the other queued jobs whose accounts are seats of the job's organisation.
+func (r *Runner) seats(of *job) iter.Seq[*job] {
+	return func(yield func(*job) bool) {
+		org := orgKey(of)
+		if org == "" {
+			return
+		}
+
+		for _, seat := range r.jobs.iter() {
+			if seat != of && orgKey(seat) == org && !yield(seat) {
+				return
+			}
+		}
+	}
+}
+
+// poolCredits makes the seats of each organisation share its credit pool. Credits are only ever
+// used up until they're renewed, so the lowest balance among the seats is the most recent one.
+func (r *Runner) poolCredits(jobs iter.Seq2[int, *job]) {
+	pooled := make(map[string]*job)
+	for _, job := range jobs {
+		org := orgKey(job)
+		if org == "" {
+			continue
+		}
+
+		if lowest, ok := pooled[org]; !ok || job.acc.Credits < lowest.acc.Credits {
+			pooled[org] = job
+		}
+	}
+
+	for org, lowest := range pooled {
+		log.Debug().Str("org", org).Int("credits", lowest.acc.Credits).Msg("pooling the credits of the organisation's seats")
+		r.shareCredits(lowest)
+	}
+}
+
+// shareCredits copies the credit balance of the job's account, and when it's renewed, to the other
+// seats of its organisation, after they've been fetched or used up.
+func (r *Runner) shareCredits(job *job) {
+	refresh, ok := job.acc.CreditRefresh.Get()
+	for seat := range r.seats(job) {
+		seat.acc.Credits = job.acc.Credits
+		if ok {
+			seat.acc.CreditRefresh.Set(refresh)
+		}
+	}
+}
+
+// exhaustPool times out every seat of the job's organisation, including the job's own, until its
+// credit pool is renewed. The seats share the pool, so none of them can save leads in the meantime,
+// and logging them in anyway would only race for the credits of the next renewal. It returns false
+// if the job's account isn't part of an organisation.
+func (r *Runner) exhaustPool(job *job) bool {
+	if orgKey(job) == "" {
+		return false
+	}
+
+	now := r.now()
+	until := now.Add(poolTimeout)
+	if refresh, ok := job.acc.CreditRefresh.Get(); ok && refresh.After(now) {
+		until = refresh
+	}
+
+	job.acc.Timeout.Set(until)
+	for seat := range r.seats(job) {
+		seat.acc.Timeout.Set(until)
+	}
+
+	job.log.Warn().
+		Str("org", job.acc.Org).
+		Time("until", until).
+		Msg("the organisation's credit pool is exhausted, timing out its seats")
+
+	return true
+}
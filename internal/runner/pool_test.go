@@ -0,0 +1,95 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/models"
+)
+
+func TestCreditPools(t *testing.T) {
+	refresh := time.Now().Add(72 * time.Hour).Truncate(time.Second)
+	accounts := []*models.Account{
+		{Email: "a@example.com", Org: "acme", Credits: 400},
+		{Email: "b@example.com", Org: "ACME ", Credits: 250},
+		{Email: "c@example.com", Org: "", Credits: 100},
+		{Email: "d@example.com", Org: "globex", Credits: 50},
+	}
+	for _, acc := range accounts {
+		acc.Timeout, acc.CreditRefresh = models.NewTime(), models.NewTime()
+	}
+
+	r := &Runner{jobs: newQueue(accounts)}
+	r.poolCredits(r.jobs.iter())
+
+	for i, want := range []int{250, 250, 100, 50} {
+		if got := accounts[i].Credits; got != want {
+			t.Errorf("%s: credits = %d after pooling; want %d", accounts[i].Email, got, want)
+		}
+	}
+
+	a, _ := r.jobs.Front().Value.(*job)
+	a.incrementSaved(250)
+	a.acc.CreditRefresh.Set(refresh)
+	r.shareCredits(a)
+
+	if got := accounts[1].Credits; got != 0 {
+		t.Errorf("b@example.com: credits = %d after a seat used the pool; want 0", got)
+	}
+	if got, _ := accounts[1].CreditRefresh.Get(); !got.Equal(refresh) {
+		t.Errorf("b@example.com: credit refresh = %s; want %s", got, refresh)
+	}
+
+	if !r.exhaustPool(a) {
+		t.Fatal("exhaustPool() = false for a seat of an organisation")
+	}
+	for i, acc := range accounts {
+		until, ok := acc.Timeout.Get()
+		if i < 2 && (!ok || !until.Equal(refresh)) {
+			t.Errorf("%s: timed out until %s; want %s", acc.Email, until, refresh)
+		} else if i >= 2 && ok {
+			t.Errorf("%s: timed out by another organisation's pool", acc.Email)
+		}
+	}
+}
+
+func TestExhaustPoolUsesRunClock(t *testing.T) {
+	start := time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+	accounts := []*models.Account{
+		{Email: "a@example.com", Org: "acme"},
+		{Email: "b@example.com", Org: "acme"},
+	}
+	for _, acc := range accounts {
+		acc.Timeout, acc.CreditRefresh = models.NewTime(), models.NewTime()
+	}
+
+	r := &Runner{jobs: newQueue(accounts), clock: newVirtualClock(start)}
+	a, _ := r.jobs.Front().Value.(*job)
+
+	// a renewal time which has passed by the run's clock is ignored.
+	a.acc.CreditRefresh.Set(start.Add(-time.Hour))
+	if !r.exhaustPool(a) {
+		t.Fatal("exhaustPool() = false for a seat of an organisation")
+	}
+
+	want := start.Add(poolTimeout)
+	for _, acc := range accounts {
+		if until, ok := acc.Timeout.Get(); !ok || !until.Equal(want) {
+			t.Errorf("%s: timed out until %s; want %s", acc.Email, until, want)
+		}
+	}
+}
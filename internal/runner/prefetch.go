@@ -0,0 +1,199 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/actions"
+	"github.com/devsheke/scrapollo/internal/journal"
+	"github.com/rs/zerolog/log"
+)
+
+// PrefetchLogins is a [RunnerOpt] func that configures the [Runner] to log all of its accounts in
+// before it starts scraping, up to parallel at a time and with their logins spread over the given
+// duration, which refreshes their cookies and fetches their credits up front. Jobs then log in with
+// fresh cookies, and the [JobScheduler] plans with accurate credits from the start.
+//
+// Accounts that connect through the [Runner]'s VPN are logged in one at a time after the others, since
+// a VPN connection routes every browser's traffic. Prefetching is disabled if parallel is 0.
+func PrefetchLogins(parallel int, spread time.Duration) RunnerOpt {
+	return func(r *Runner) {
+		r.prefetchLogins, r.prefetchSpread = parallel, spread
+	}
+}
+
+// prefetch logs in the accounts of the jobs that may run right away (see [PrefetchLogins]).
+func (r *Runner) prefetch() {
+	if r.prefetchLogins <= 0 || r.sim != nil {
+		return
+	}
+
+	var direct, vpn []*job
+	for _, job := range r.jobs.iter() {
+		if job.acc.APIKey != "" && !r.apiExport {
+			continue
+		} else if r.checkBlacklist(job) != nil {
+			continue
+		} else if t, ok := job.acc.Timeout.Get(); ok && r.now().Before(t) {
+			continue
+		} else if open, _, ok := r.window(job); ok && r.now().Before(open) {
+			continue
+		}
+
+		if r.vpn != nil && (job.acc.VpnFile != "" || job.acc.VpnRegion != "") {
+			vpn = append(vpn, job)
+		} else {
+			direct = append(direct, job)
+		}
+	}
+
+	if len(direct)+len(vpn) == 0 {
+		return
+	}
+
+	log.Info().
+		Int("accounts", len(direct)+len(vpn)).
+		Int("parallel", r.prefetchLogins).
+		Dur("spread", r.prefetchSpread).
+		Msg("prefetching logins")
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		failed   int
+		interval time.Duration
+		slots    = make(chan struct{}, r.prefetchLogins)
+		started  = time.Now()
+	)
+	if len(direct) > 0 {
+		interval = r.prefetchSpread / time.Duration(len(direct))
+	}
+
+	// the bookkeeping of logged in accounts is serialised, since the credits of an organisation's
+	// seats are shared and the cookies of all accounts are saved together.
+	finish := func(job *job, err error) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		if err != nil {
+			failed++
+			job.log.Warn().Err(unwrapError(err)).Msg("failed to prefetch login")
+			r.record(job, journal.Entry{Action: journal.ActionLoginPrefetch, Error: unwrapError(err).Error()})
+			return
+		}
+
+		r.record(job, journal.Entry{Action: journal.ActionLoginPrefetch, Credits: job.acc.Credits})
+		r.shareCredits(job)
+	}
+
+	for i, job := range direct {
+		time.Sleep(time.Until(started.Add(time.Duration(i) * interval)))
+
+		slots <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+
+			finish(job, r.prefetchLogin(job, &mu))
+		}()
+	}
+	wg.Wait()
+
+	for _, job := range vpn {
+		finish(job, r.prefetchLogin(job, &mu))
+	}
+
+	if err := r.saveCookies(); err != nil {
+		log.Warn().Err(err).Msg("failed to save prefetched cookies")
+	}
+
+	log.Info().
+		Int("accounts", len(direct)+len(vpn)-failed).
+		Int("failed", failed).
+		Dur("elapsed", time.Since(started)).
+		Msg("prefetched logins")
+}
+
+// prefetchLogin logs the job's account in and fetches its credits. The account itself is only updated
+// while holding mu.
+func (r *Runner) prefetchLogin(job *job, mu *sync.Mutex) error {
+	if err := job.begin(); err != nil {
+		return err
+	}
+
+	if err := r.connectVpn(job); err != nil {
+		return err
+	}
+	defer r.disconnectVpn(job)
+
+	bw, err := newBrowserWrapper(r.browserOptions(job))
+	if err != nil {
+		return err
+	}
+	defer bw.close()
+	bw.browser = bw.browser.Context(r.jobContext(job))
+
+	// the account is logged in as a copy, since the cookies of all accounts are read when saving them.
+	mu.Lock()
+	probe := *job.acc
+	mu.Unlock()
+
+	release, err := r.acquireLogin(bw.browser.GetContext(), job)
+	if err != nil {
+		return err
+	}
+
+	page, err := actions.ApolloLogin(bw.browser, &probe, r.timeouts.Login, r.stealth, job.profile)
+	if errors.Is(err, actions.ErrorSecurityChallenge) && r.captchaSolver != nil {
+		err = actions.SolveSecurityChallenge(page, &probe, r.captchaSolver, r.timeouts.Login)
+	}
+	release()
+	if page != nil {
+		err = accountStatus(page, err)
+	}
+	if err != nil {
+		return err
+	}
+
+	if cookies, ok := probe.GetLoginCookies(); ok {
+		mu.Lock()
+		job.acc.SetLoginCookies(cookies)
+		mu.Unlock()
+	}
+
+	if err := r.removeAnnoyances(page); err != nil {
+		return err
+	}
+
+	credits, err := actions.FetchCreditUsage(page, &probe, r.creditLocales, r.timeouts.Credits)
+	if err != nil {
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	job.acc.Credits, job.acc.CreditRefresh = credits.Remaining(), credits.Renewal
+	job.creditsFetched = true
+	r.recordCredits(job, credits)
+
+	return nil
+}
@@ -0,0 +1,59 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"iter"
+
+	"github.com/devsheke/scrapollo/internal/fingerprint"
+	"github.com/rs/zerolog/log"
+)
+
+// assignProfiles gives each of the jobs' accounts a device profile, if the [Runner] uses them.
+// Accounts keep the profile named by their 'profile' column, which is saved to progress files, so
+// they look like the same device across runs. Accounts without one, or with an unknown one, are
+// assigned a profile derived from their email. When emulating phones, accounts are only given
+// mobile profiles.
+func (r *Runner) assignProfiles(jobs iter.Seq2[int, *job]) {
+	if !r.deviceProfiles && !r.mobileView {
+		return
+	}
+
+	assign := fingerprint.Assign
+	if r.mobileView {
+		assign = fingerprint.AssignMobile
+	}
+
+	for _, job := range jobs {
+		logger := log.With().Str("account", job.acc.Email).Logger()
+
+		if job.acc.Profile != "" {
+			p, ok := fingerprint.ByName(job.acc.Profile)
+			switch {
+			case !ok:
+				logger.Warn().Str("profile", job.acc.Profile).Msg("replacing the account's unknown device profile")
+			case r.mobileView && !p.Mobile:
+				logger.Info().Str("profile", job.acc.Profile).Msg("replacing the account's desktop profile to emulate a phone")
+			default:
+				job.profile = p
+				continue
+			}
+		}
+
+		job.profile = assign(job.acc.Email)
+		job.acc.Profile = job.profile.Name
+		logger.Debug().Str("profile", job.profile.Name).Msg("assigned a device profile to the account")
+	}
+}
@@ -0,0 +1,44 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"testing"
+
+	"github.com/devsheke/scrapollo/internal/models"
+)
+
+func TestMobileView(t *testing.T) {
+	accounts := []*models.Account{
+		{Email: "a@example.com", List: "a"},
+		{Email: "b@example.com", List: "b", Profile: "windows-laptop"},
+		{Email: "c@example.com", List: "c", Profile: "android-galaxy-s23"},
+	}
+
+	r, err := New(accounts, OutputDir(t.TempDir()), MobileView(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, job := range r.jobs.iter() {
+		if job.profile == nil || !job.profile.Mobile || job.acc.Profile != job.profile.Name {
+			t.Errorf("%s: got profile %+v; want a mobile one", job.acc.Email, job.profile)
+		}
+
+		if job.acc.Email == "c@example.com" && job.profile.Name != "android-galaxy-s23" {
+			t.Errorf("the mobile profile of an account was replaced with %q", job.profile.Name)
+		}
+	}
+}
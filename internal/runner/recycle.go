@@ -0,0 +1,165 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"bufio"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/go-rod/rod"
+	"github.com/rs/zerolog/log"
+)
+
+// errorMemoryUnsupported is returned when the browser's memory usage can't be measured on the
+// current platform.
+var errorMemoryUnsupported = errors.New("measuring browser memory usage is not supported")
+
+// recycle recreates the job's scraping page every [Runner.recyclePages] pages, and restarts
+// the whole browser when its memory usage exceeds [Runner.maxBrowserMemory].
+func (r *Runner) recycle(page *rod.Page, bw *browserWrapper, job *job, pages int) error {
+	if due, restart := r.recycling(bw, job, pages); due {
+		return r.newScrapingPage(page, bw, job, restart)
+	}
+
+	return nil
+}
+
+// recycling reports whether the job's scraping page is due to be recreated after the given number
+// of pages, and whether the whole browser must be restarted (see [Runner.recycle]).
+func (r *Runner) recycling(bw *browserWrapper, job *job, pages int) (due, restart bool) {
+	if r.maxBrowserMemory > 0 {
+		rss, err := bw.memoryUsage()
+		switch {
+		case err != nil:
+			log.Debug().Err(err).Msg("failed to measure browser memory usage")
+
+		case rss > r.maxBrowserMemory:
+			job.log.Info().
+				Uint64("rss", rss).
+				Msg("browser memory usage exceeded threshold, restarting browser")
+
+			return true, true
+		}
+	}
+
+	return r.recyclePages > 0 && pages > 0 && pages%r.recyclePages == 0, false
+}
+
+// restart closes the wrapped browser and launches a new one in its place. The new browser
+// inherits the context of the previous one.
+func (bw *browserWrapper) restart() error {
+	ctx := bw.browser.GetContext()
+	if err := bw.close(); err != nil {
+		log.Warn().Err(err).Msg("failed to close browser before restarting it")
+	}
+
+	wrapper, err := newBrowserWrapper(bw.opts)
+	if err != nil {
+		return err
+	}
+
+	*bw = *wrapper
+	bw.browser = bw.browser.Context(ctx)
+
+	return nil
+}
+
+// memoryUsage returns the combined resident set size (in bytes) of the browser process
+// and all of its descendants. This is only supported on systems with procfs.
+func (bw *browserWrapper) memoryUsage() (uint64, error) {
+	root := bw.launcher.PID()
+
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return 0, errors.Join(errorMemoryUnsupported, err)
+	}
+
+	parents := make(map[int]int, len(entries))
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil {
+			continue
+		}
+
+		if ppid, err := parentPID(pid); err == nil {
+			parents[pid] = ppid
+		}
+	}
+
+	var total uint64
+	for pid := range parents {
+		if !isDescendant(pid, root, parents) {
+			continue
+		}
+
+		rss, err := residentMemory(pid)
+		if err != nil {
+			continue
+		}
+		total += rss
+	}
+
+	return total, nil
+}
+
+func isDescendant(pid, root int, parents map[int]int) bool {
+	for depth := 0; pid > 1 && depth < len(parents); depth++ {
+		if pid == root {
+			return true
+		}
+		pid = parents[pid]
+	}
+
+	return pid == root
+}
+
+func parentPID(pid int) (int, error) {
+	b, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "stat"))
+	if err != nil {
+		return 0, err
+	}
+
+	// the process name is wrapped in parentheses and may contain spaces,
+	// so the fields are read from after the closing parenthesis.
+	stat := string(b)
+	fields := strings.Fields(stat[strings.LastIndexByte(stat, ')')+1:])
+	if len(fields) < 2 {
+		return 0, errors.New("unexpected stat format")
+	}
+
+	return strconv.Atoi(fields[1])
+}
+
+func residentMemory(pid int) (uint64, error) {
+	f, err := os.Open(filepath.Join("/proc", strconv.Itoa(pid), "status"))
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if value, ok := strings.CutPrefix(scanner.Text(), "VmRSS:"); ok {
+			kb, err := strconv.ParseUint(strings.TrimSuffix(strings.TrimSpace(value), " kB"), 10, 64)
+			return kb * 1024, err
+		}
+	}
+
+	return 0, scanner.Err()
+}
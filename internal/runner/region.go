@@ -0,0 +1,61 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/devsheke/scrapollo/internal/actions"
+)
+
+// Regions is a [RunnerOpt] func that names the URLs of the Apollo apps that some tenants are served
+// from, e.g. 'eu' for an EU-hosted tenant, so that accounts can refer to them by name in their
+// 'base-url' column rather than by URL.
+func Regions(regions map[string]string) RunnerOpt {
+	return func(r *Runner) {
+		if r.regions == nil {
+			r.regions = make(map[string]string, len(regions))
+		}
+
+		for name, url := range regions {
+			r.regions[strings.ToLower(name)] = url
+		}
+	}
+}
+
+// resolveApolloURL sets the URL of the Apollo app that the job's account is served from, given by
+// its 'base-url' column as the name of one of the [Runner]'s regions, a URL or a host. Accounts
+// without one use the [Runner]'s app.
+func (r *Runner) resolveApolloURL(job *job) error {
+	base := strings.TrimSpace(job.acc.BaseURL)
+	if base == "" {
+		return nil
+	}
+
+	if url, ok := r.regions[strings.ToLower(base)]; ok {
+		base = url
+	} else if !strings.Contains(base, ".") && !strings.Contains(base, ":") {
+		return fmt.Errorf("account %s uses unknown region %q", job.acc.Email, job.acc.BaseURL)
+	}
+
+	url, err := actions.NormalizeApolloURL(base)
+	if err != nil {
+		return fmt.Errorf("account %s: %w", job.acc.Email, err)
+	}
+
+	job.apolloURL = url
+	return nil
+}
@@ -0,0 +1,54 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"testing"
+
+	"github.com/devsheke/scrapollo/internal/models"
+)
+
+func TestRegions(t *testing.T) {
+	dir := t.TempDir()
+	accounts := []*models.Account{
+		{Email: "a@example.com", List: "a"},
+		{Email: "b@example.com", List: "b", BaseURL: "EU"},
+		{Email: "c@example.com", List: "c", BaseURL: "apollo.example.com/"},
+		{Email: "d@example.com", List: "d", BaseURL: "http://localhost:8080"},
+	}
+
+	regions := Regions(map[string]string{"eu": "https://app.eu.apollo.io"})
+	r, err := New(accounts, OutputDir(dir), regions)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]string{
+		"a@example.com": "",
+		"b@example.com": "https://app.eu.apollo.io",
+		"c@example.com": "https://apollo.example.com",
+		"d@example.com": "http://localhost:8080",
+	}
+	for _, job := range r.jobs.iter() {
+		if job.apolloURL != want[job.acc.Email] {
+			t.Errorf("%s: Apollo URL = %q; want %q", job.acc.Email, job.apolloURL, want[job.acc.Email])
+		}
+	}
+
+	accounts = append(accounts, &models.Account{Email: "e@example.com", List: "e", BaseURL: "us"})
+	if _, err := New(accounts, OutputDir(dir), regions); err == nil {
+		t.Error("accounts of unknown regions were accepted")
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"errors"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/io"
+	"github.com/devsheke/scrapollo/internal/models"
+	"github.com/rs/zerolog/log"
+)
+
+// accountPollInterval is how often the accounts file is checked for changes.
+const accountPollInterval = 10 * time.Second
+
+// accountWatcher tracks changes to the file the [Runner]'s accounts were read from, so that the
+// accounts added to it can be merged into the queue.
+type accountWatcher struct {
+	file string
+
+	// known holds the emails of every account that's been queued, including those whose jobs are done.
+	known   map[string]bool
+	changed atomic.Bool
+}
+
+// watch flags the accounts for reloading when their file is modified or reloadSignal is received.
+// The returned function stops watching.
+func (w *accountWatcher) watch() (stop func()) {
+	if w.file == "" {
+		return func() {}
+	}
+
+	signals := make(chan os.Signal, 1)
+	if reloadSignal != nil {
+		signal.Notify(signals, reloadSignal)
+	}
+
+	modified := func() time.Time {
+		info, err := os.Stat(w.file)
+		if err != nil {
+			return time.Time{}
+		}
+		return info.ModTime()
+	}
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(accountPollInterval)
+		defer ticker.Stop()
+
+		last := modified()
+		for {
+			select {
+			case <-done:
+				return
+			case sig := <-signals:
+				log.Info().Str("signal", sig.String()).Msg("received reload signal")
+				w.changed.Store(true)
+			case <-ticker.C:
+				if t := modified(); !t.Equal(last) {
+					last = t
+					w.changed.Store(true)
+				}
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(signals)
+		close(done)
+	}
+}
+
+// reloadAccounts merges the accounts that have been added to the accounts file since it was last read
+// into the queue, if it has changed. Accounts which are already known are left as they are.
+func (r *Runner) reloadAccounts() {
+	if !r.accounts.changed.Swap(false) {
+		return
+	}
+
+	var accounts []*models.Account
+	if err := io.ReadRecords(r.accounts.file, &accounts); err != nil {
+		log.Warn().Err(err).Str("file", r.accounts.file).Msg("failed to reload accounts")
+		return
+	}
+
+	added := newQueue(nil)
+	for _, acc := range accounts {
+		if r.accounts.known[acc.Email] {
+			continue
+		}
+
+		job := added.push(acc)
+		if err := errors.Join(prepareJob(job), r.joinCampaign(job), r.resolveApolloURL(job)); err != nil {
+			log.Warn().Err(err).Msg("skipping invalid account")
+			added.Remove(added.Back())
+			continue
+		}
+		r.accounts.known[acc.Email] = true
+		r.results.track(job)
+	}
+
+	if added.isEmpty() {
+		return
+	}
+
+	if err := r.loadCookies(added.iter()); err != nil {
+		log.Warn().Err(err).Msg("failed to load the cookies of reloaded accounts")
+	}
+
+	r.restoreTimeouts(added.iter())
+	r.assignProfiles(added.iter())
+
+	if r.vpn != nil {
+		for _, job := range added.iter() {
+			r.vpn.UseConfig(job.acc.VpnFile)
+		}
+	}
+
+	log.Info().Int("accounts", added.Len()).Str("file", r.accounts.file).Msg("added accounts to the queue")
+	r.jobs.PushBackList(added.List)
+
+	// the added seats of organisations are pooled along with the queued ones.
+	r.poolCredits(r.jobs.iter())
+	r.status.update(func(status *Status) { status.PendingJobs = r.jobs.Len() })
+}
@@ -0,0 +1,112 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/devsheke/scrapollo/internal/models"
+)
+
+func TestReloadAccounts(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "accounts.json")
+
+	writeAccounts := func(emails ...string) {
+		t.Helper()
+
+		data := "["
+		for i, email := range emails {
+			if i > 0 {
+				data += ","
+			}
+			data += `{"email":"` + email + `","target":10}`
+		}
+
+		if err := os.WriteFile(file, []byte(data+"]"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	queued := func(r *Runner) (emails []string) {
+		for _, job := range r.jobs.iter() {
+			emails = append(emails, job.acc.Email)
+		}
+		return emails
+	}
+
+	r, err := New(
+		[]*models.Account{{Email: "a@example.com"}, {Email: "b@example.com"}},
+		OutputDir(dir),
+		WatchAccounts(file),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the first job is done and has left the queue, so it must not be added again.
+	r.jobs.Remove(r.jobs.Front())
+
+	writeAccounts("a@example.com", "b@example.com", "c@example.com")
+	r.reloadAccounts()
+	if got := queued(r); !slices.Equal(got, []string{"b@example.com"}) {
+		t.Fatalf("reloaded accounts before the file changed: %v", got)
+	}
+
+	r.accounts.changed.Store(true)
+	r.reloadAccounts()
+	if got, want := queued(r), []string{"b@example.com", "c@example.com"}; !slices.Equal(got, want) {
+		t.Fatalf("got queue %v, want %v", got, want)
+	}
+
+	job, _ := r.jobs.Back().Value.(*job)
+	if job.acc.Timeout == nil || job.acc.List == "" {
+		t.Error("reloaded account was not prepared")
+	}
+}
+
+func TestReloadAccountsPoolsCredits(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "accounts.json")
+
+	r, err := New(
+		[]*models.Account{{Email: "a@example.com", Org: "acme", Credits: 400}},
+		OutputDir(dir),
+		WatchAccounts(file),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := `[{"email":"a@example.com","org":"acme","credits":400},{"email":"b@example.com","org":"acme","credits":100,"target":10}]`
+	if err := os.WriteFile(file, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r.accounts.changed.Store(true)
+	r.reloadAccounts()
+
+	for _, job := range r.jobs.iter() {
+		if job.acc.Credits != 100 {
+			t.Errorf("%s: credits = %d after reloading; want the pool's 100", job.acc.Email, job.acc.Credits)
+		}
+	}
+	if r.jobs.Len() != 2 {
+		t.Errorf("got %d queued jobs, want 2", r.jobs.Len())
+	}
+}
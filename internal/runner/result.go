@@ -0,0 +1,200 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/actions"
+	"github.com/devsheke/scrapollo/internal/apolloapi"
+	"github.com/devsheke/scrapollo/internal/io"
+	"github.com/rs/zerolog/log"
+)
+
+// JobStatus represents the outcome of an account's job.
+type JobStatus string
+
+// The outcomes of jobs.
+const (
+	// JobPending is the status of jobs which haven't been run yet, or which were requeued to be run
+	// later (e.g. after hitting their daily limit or running out of credits).
+	JobPending JobStatus = "pending"
+	// JobFinished is the status of jobs which saved and scraped their target number of leads.
+	JobFinished JobStatus = "finished"
+	// JobFailed is the status of jobs whose last run failed with an unexpected error.
+	JobFailed JobStatus = "failed"
+	// JobDropped is the status of jobs whose account was blacklisted or banned.
+	JobDropped JobStatus = "dropped"
+)
+
+// JobResult reports the outcome of an account's job, across all of the times it was run.
+type JobResult struct {
+	Account  string
+	List     string
+	Campaign string
+	Status   JobStatus
+
+	// Saved is the total number of leads saved to the account's list, including those saved by
+	// previous runs, and Scraped the number of leads scraped by this run.
+	Saved, Scraped int
+
+	// TeamOwned is the number of leads that this run didn't save because they're already in the
+	// contacts of another seat of the account's team.
+	TeamOwned int
+
+	// Credits is the number of credits that this run used up saving leads, and VpnTime the time it
+	// spent connected to a VPN.
+	Credits int
+	VpnTime time.Duration
+
+	// Traffic is the number of bytes that the job's browsers transferred, e.g. to attribute the
+	// bandwidth metered by a VPN provider to the account.
+	Traffic actions.Traffic
+
+	// Runs is the number of times the job was run and Duration the time spent running it.
+	Runs     int
+	Duration time.Duration
+
+	// Err is the error that the job's last run ended with, if any.
+	Err error
+
+	// Outputs are the files that the job's leads were written to.
+	Outputs []string
+}
+
+// jobResults tracks the results of every job that's been queued.
+type jobResults struct {
+	mu      sync.Mutex
+	jobs    []*job
+	results map[*job]*JobResult
+	outputs map[*job][]string
+}
+
+// track starts tracking the job's result.
+func (t *jobResults) track(j *job) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.results == nil {
+		t.results, t.outputs = make(map[*job]*JobResult), make(map[*job][]string)
+	}
+
+	t.jobs = append(t.jobs, j)
+	t.results[j] = &JobResult{Status: JobPending}
+}
+
+// update calls fn with the job's result, if it's being tracked.
+func (t *jobResults) update(job *job, fn func(result *JobResult)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if result, ok := t.results[job]; ok {
+		fn(result)
+	}
+}
+
+// output records that the job's leads are written to the file.
+func (t *jobResults) output(job *job, file string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if !slices.Contains(t.outputs[job], file) {
+		t.outputs[job] = append(t.outputs[job], file)
+	}
+}
+
+// finish records the outcome of a run of the job which took the provided time.
+func (t *jobResults) finish(job *job, took time.Duration, err error) {
+	t.update(job, func(result *JobResult) {
+		result.Runs++
+		result.Duration += took
+		result.Status, result.Err = jobStatus(err), err
+
+		if result.Status == JobFinished {
+			result.Err = nil
+		}
+	})
+}
+
+// incrementSaved records that the job saved the provided number of leads, using up as many of its
+// account's credits, which are shared with the other seats of its organisation.
+func (r *Runner) incrementSaved(job *job, saved int) {
+	job.incrementSaved(saved)
+	r.shareCredits(job)
+	r.results.update(job, func(result *JobResult) { result.Credits += saved })
+}
+
+// addTraffic adds the bytes transferred by one of the job's browsers to its result.
+func (r *Runner) addTraffic(job *job, traffic actions.Traffic) {
+	job.log.Debug().
+		Int64("bytes-received", traffic.Received).
+		Int64("bytes-sent", traffic.Sent).
+		Msg("closed a browser")
+
+	r.results.update(job, func(result *JobResult) { result.Traffic = result.Traffic.Add(traffic) })
+}
+
+// countTeamOwned adds the leads that weren't saved because they're owned by the account's team to
+// the job's result.
+func (r *Runner) countTeamOwned(job *job, teamOwned int) {
+	if teamOwned > 0 {
+		r.results.update(job, func(result *JobResult) { result.TeamOwned += teamOwned })
+	}
+}
+
+// jobStatus returns the status of a job whose last run ended with the provided error.
+func jobStatus(err error) JobStatus {
+	switch {
+	case err == nil, err == ErrorTargetReached, err == actions.ErrorListEnd:
+		return JobFinished
+	case err == ErrorAccountBlacklisted, err == apolloapi.ErrorUnauthorized, actions.IsAccountBlocked(err):
+		return JobDropped
+	case err == ErrorDailyLimit, err == ErrorNoCredits, err == ErrorWarmingUp, err == ErrorOutsideWindow,
+		err == ErrorJobStalled, err == ErrorMaxRuntime, err == ErrorMaxJobDuration:
+		return JobPending
+	default:
+		return JobFailed
+	}
+}
+
+// Results returns the results of the jobs of every account that's been queued, in the order they
+// were queued. It can be called while the [Runner] is running, but is meant to be called once
+// [Runner.Start] has returned.
+func (r *Runner) Results() []JobResult {
+	r.results.mu.Lock()
+	defer r.results.mu.Unlock()
+
+	results := make([]JobResult, 0, len(r.results.jobs))
+	for _, job := range r.results.jobs {
+		result := *r.results.results[job]
+		result.Account, result.List, result.Saved = job.acc.Email, job.acc.List, job.acc.Saved
+		result.Campaign = job.acc.Campaign
+
+		for _, file := range r.results.outputs[job] {
+			files, err := io.LeadFiles(file, r.outputLayout...)
+			if err != nil {
+				log.Warn().Err(err).Str("file", file).Msg("failed to list output files")
+				continue
+			}
+			result.Outputs = append(result.Outputs, files...)
+		}
+
+		results = append(results, result)
+	}
+
+	return results
+}
@@ -0,0 +1,110 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/io"
+	"github.com/devsheke/scrapollo/internal/journal"
+	"github.com/devsheke/scrapollo/internal/models"
+	"github.com/devsheke/scrapollo/internal/privacy"
+	"github.com/rs/zerolog/log"
+)
+
+// minJournalRetention is the minimum age of the journal entries which are purged, since the daily
+// limits of accounts are counted from the last day of their journals.
+const minJournalRetention = 24 * time.Hour
+
+// Retention is a [RunnerOpt] func that configures the [Runner] to purge the data in its output
+// directory that is older than the provided duration when it starts: error snapshots and reports,
+// journal entries (no sooner than after a day), downloads, progress snapshots and output files of
+// leads. A zero value keeps everything.
+func Retention(d time.Duration) RunnerOpt {
+	return func(r *Runner) {
+		r.retention = d
+	}
+}
+
+// ScrubPII is a [RunnerOpt] func that configures how the emails and phone numbers of leads are
+// scrubbed before they're written (see [privacy.ScrubMode]).
+func ScrubPII(mode privacy.ScrubMode) RunnerOpt {
+	return func(r *Runner) {
+		r.scrub = mode
+	}
+}
+
+// scrubLeads scrubs the personal data of the leads, if the [Runner] is configured to.
+func (r *Runner) scrubLeads(leads []*models.Lead) {
+	if r.scrub == privacy.ScrubNone {
+		return
+	}
+
+	for _, lead := range leads {
+		r.scrub.Scrub(lead)
+	}
+}
+
+// purgeExpired purges the data in the output directory which is older than the retention period.
+func (r *Runner) purgeExpired() {
+	if r.retention <= 0 {
+		return
+	}
+
+	before := time.Now().Add(-r.retention)
+	purge := func(kind, dir string, match func(string) bool) {
+		removed, err := privacy.Purge(dir, before, match)
+		if err != nil {
+			log.Warn().Err(err).Str("dir", dir).Msgf("failed to purge expired %s", kind)
+		}
+		if len(removed) > 0 {
+			log.Info().Str("dir", dir).Int("files", len(removed)).Msgf("purged expired %s", kind)
+		}
+	}
+
+	purge("error snapshots", r.errorDir, nil)
+	purge("downloads", filepath.Join(r.outputDir, ArtifactsDir), nil)
+	purge("progress snapshots", filepath.Join(r.outputDir, ProgressHistoryDir), nil)
+
+	// campaigns' output directories outside of the output directory are purged separately.
+	purge("output files", r.outputDir, io.IsLeadFile)
+	for _, c := range r.campaigns {
+		if rel, err := filepath.Rel(r.outputDir, c.OutputDir); err != nil || !filepath.IsLocal(rel) {
+			purge("output files", c.OutputDir, io.IsLeadFile)
+		}
+	}
+
+	dir := filepath.Join(r.outputDir, "journal")
+	if _, err := os.Stat(dir); err != nil {
+		return
+	}
+
+	j := r.journal
+	if j == nil {
+		if j, _ = journal.New(dir); j == nil {
+			return
+		}
+	}
+
+	removed, err := j.Prune(time.Now().Add(-max(r.retention, minJournalRetention)))
+	if err != nil {
+		log.Warn().Err(err).Str("dir", dir).Msg("failed to purge expired journal entries")
+	}
+	if removed > 0 {
+		log.Info().Str("dir", dir).Int("entries", removed).Msg("purged expired journal entries")
+	}
+}
@@ -0,0 +1,73 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/io"
+	"github.com/devsheke/scrapollo/internal/journal"
+	"github.com/devsheke/scrapollo/internal/models"
+)
+
+func TestPurgeExpired(t *testing.T) {
+	dir := t.TempDir()
+	accounts := []*models.Account{{Email: "a@example.com", List: "a"}}
+	r, err := New(accounts, OutputDir(dir), CsvOutput(), Journal(true), Retention(7*24*time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	old := time.Now().Add(-30 * 24 * time.Hour)
+	leads := filepath.Join(dir, "a.csv")
+	if err := io.NewCsvLeadWriter(leads).WriteLeads([]*models.Lead{{Name: "Ada"}}); err != nil {
+		t.Fatal(err)
+	}
+	progress := filepath.Join(dir, "scrapollo-progress-old.csv")
+	if err := io.SaveRecords(progress, accounts); err != nil {
+		t.Fatal(err)
+	}
+	for _, file := range []string{leads, progress} {
+		if err := os.Chtimes(file, old, old); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, at := range []time.Time{old, time.Now()} {
+		if err := r.journal.Record(journal.Entry{Time: at, Account: "a@example.com", Action: journal.ActionJobStarted}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r.purgeExpired()
+
+	if _, err := os.Stat(leads); err == nil {
+		t.Error("an expired output file was kept")
+	}
+	if _, err := os.Stat(progress); err != nil {
+		t.Error("a progress file was purged as an output file")
+	}
+
+	entries, err := r.journal.Read("a@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Time.Before(old.Add(time.Hour)) {
+		t.Errorf("unexpected journal entries after purging: %+v", entries)
+	}
+}
@@ -16,58 +16,168 @@ package runner
 
 import (
 	"container/list"
+	"context"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"slices"
+	"strings"
 	"time"
 
 	"github.com/devsheke/scrapollo/internal/actions"
+	"github.com/devsheke/scrapollo/internal/apolloapi"
+	"github.com/devsheke/scrapollo/internal/artifacts"
+	"github.com/devsheke/scrapollo/internal/classify"
 	"github.com/devsheke/scrapollo/internal/io"
+	"github.com/devsheke/scrapollo/internal/journal"
+	"github.com/devsheke/scrapollo/internal/logging"
 	"github.com/devsheke/scrapollo/internal/models"
+	vpn "github.com/devsheke/scrapollo/internal/openvpn"
+	"github.com/devsheke/scrapollo/internal/report"
+	"github.com/devsheke/scrapollo/internal/splitter"
+	"github.com/devsheke/scrapollo/internal/tagging"
+	"github.com/devsheke/scrapollo/internal/tracing"
 	"github.com/devsheke/scrapollo/pkg/openvpn-go"
 	"github.com/go-rod/rod"
 	"github.com/go-rod/rod/lib/launcher"
+	"github.com/go-rod/rod/lib/launcher/flags"
 	"github.com/go-rod/rod/lib/proto"
 	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 var (
 	ErrorDailyLimit    = errors.New("the daily limit for saving leads has been hit")
 	ErrorNoCredits     = errors.New("no more credits available for saving leads")
 	ErrorTargetReached = errors.New("target number of leads have been saved")
+	ErrorWarmingUp     = errors.New("the account is still warming up")
 )
 
+// browserOptions configures the browsers launched by [newBrowserWrapper].
+type browserOptions struct {
+	display display
+
+	// proxy is the proxy that the browser connects through (if any).
+	proxy string
+
+	// blocker blocks the requests that scraping doesn't need (if set).
+	blocker *actions.ResourceBlocker
+
+	// cacheDir is the directory of the browser's HTTP cache, which is shared by every browser
+	// launched with it so that Apollo's static assets aren't downloaded again by each of them.
+	cacheDir string
+
+	// artifacts collects the files downloaded by the browser on behalf of the job's account (if set).
+	artifacts      *artifacts.Collector
+	account, jobID string
+
+	// traffic is given the bytes that the browser transferred once it's closed (if set).
+	traffic func(actions.Traffic)
+
+	// flags and extensions are the additional flags that the browser is launched with and the
+	// unpacked extensions that it loads.
+	flags      []browserFlag
+	extensions []string
+}
+
+// browserOptions returns the options of the browsers launched for the job.
+func (r *Runner) browserOptions(job *job) browserOptions {
+	return browserOptions{
+		display:    r.display,
+		proxy:      job.proxy,
+		blocker:    r.blocker,
+		cacheDir:   r.browserCacheDir,
+		artifacts:  r.artifacts,
+		account:    job.acc.Email,
+		jobID:      job.id,
+		traffic:    func(traffic actions.Traffic) { r.addTraffic(job, traffic) },
+		flags:      r.parsedBrowserFlags,
+		extensions: r.extensions,
+	}
+}
+
 type browserWrapper struct {
-	browser  *rod.Browser
-	launcher *launcher.Launcher
+	browser       *rod.Browser
+	launcher      *launcher.Launcher
+	opts          browserOptions
+	unblock       func() error
+	stopDownloads func()
+	meter         *actions.TrafficMeter
+	stopMetering  func()
 }
 
-func newBrowserWrapper(headless bool) (*browserWrapper, error) {
-	log.Debug().Msg("starting a new browser instance")
+// newBrowserWrapper launches a new browser with the provided options.
+func newBrowserWrapper(opts browserOptions) (*browserWrapper, error) {
+	log.Debug().Str("proxy", opts.proxy).Msg("starting a new browser instance")
 
-	wrapper := new(browserWrapper)
+	wrapper := &browserWrapper{opts: opts}
 	if browserPath, ok := os.LookupEnv("BROWSER"); ok {
 		wrapper.launcher = launcher.New().Bin(browserPath)
 	} else {
 		wrapper.launcher = launcher.New()
 	}
 
-	wrapper.launcher = wrapper.launcher.Headless(headless)
+	wrapper.launcher = opts.display.apply(wrapper.launcher)
+	if opts.proxy != "" {
+		wrapper.launcher = wrapper.launcher.Proxy(opts.proxy)
+	}
+
+	if opts.cacheDir != "" {
+		wrapper.launcher = wrapper.launcher.Set(flags.Flag("disk-cache-dir"), opts.cacheDir)
+	}
+
+	// the additional flags come last, so that they can override those set above.
+	wrapper.launcher = applyBrowserFlags(wrapper.launcher, opts.flags, opts.extensions)
 
 	controlURL, err := wrapper.launcher.Launch()
 	if err != nil {
 		return nil, err
 	}
 	wrapper.browser = rod.New().ControlURL(controlURL)
+	if err := wrapper.browser.Connect(); err != nil {
+		return wrapper, err
+	}
 
-	return wrapper, wrapper.browser.Connect()
+	if opts.traffic != nil {
+		wrapper.meter, wrapper.stopMetering = actions.MeterTraffic(wrapper.browser)
+	}
+
+	if opts.blocker != nil {
+		if wrapper.unblock, err = actions.BlockResources(wrapper.browser, opts.blocker); err != nil {
+			return wrapper, err
+		}
+	}
+
+	if opts.artifacts != nil {
+		if wrapper.stopDownloads, err = opts.artifacts.Watch(wrapper.browser, opts.account, opts.jobID); err != nil {
+			return wrapper, err
+		}
+	}
+
+	return wrapper, nil
 }
 
 func (bw *browserWrapper) close() error {
 	log.Debug().Msg("closing browser instance")
 
-	if err := bw.browser.Close(); err != nil {
+	if bw.unblock != nil {
+		if err := bw.unblock(); err != nil {
+			log.Debug().Err(err).Msg("failed to stop blocking resources")
+		}
+	}
+
+	if bw.stopDownloads != nil {
+		bw.stopDownloads()
+	}
+
+	if bw.stopMetering != nil {
+		bw.stopMetering()
+		bw.opts.traffic(bw.meter.Traffic())
+	}
+
+	// the browser's context may have been cancelled by the watchdog.
+	if err := bw.browser.Context(context.Background()).Close(); err != nil {
 		return err
 	}
 	bw.launcher.Cleanup()
@@ -82,42 +192,181 @@ const (
 	accountCookiesFilename string = "scrapollo-cookies.json"
 )
 
-func (r *Runner) _saveProgress() error {
-	accs := make([]*models.Account, 0, r.jobs.Len())
+func (r *Runner) saveCookies() error {
 	accCookies := make(map[string][]*proto.NetworkCookie, r.jobs.Len())
-
 	for _, job := range r.jobs.iter() {
 		if cookies, ok := job.acc.GetLoginCookies(); ok {
 			accCookies[job.acc.Email] = cookies
 		}
-		accs = append(accs, job.acc)
 	}
 
 	cookiesFile := filepath.Join(r.outputDir, accountCookiesFilename)
 	log.Debug().Str("file", cookiesFile).Msg("saving cookies")
 
-	if err := io.SaveRecords(cookiesFile, accCookies); err != nil {
+	return io.SaveRecords(cookiesFile, accCookies)
+}
+
+func (r *Runner) _saveProgress() error {
+	if err := r.saveCookies(); err != nil {
 		return err
 	}
 
-	progressFile := filepath.Join(r.outputDir, progressFilePrefix+string(r.outputFormat))
+	accs := make([]*models.Account, 0, r.jobs.Len()+len(r.dropped))
+	for _, job := range r.jobs.iter() {
+		accs = append(accs, job.acc)
+	}
+	accs = append(accs, r.dropped...)
+
+	// the run ID keeps the progress files of runs sharing an output directory apart.
+	progressFile := filepath.Join(r.outputDir, progressFilePrefix+"-"+r.runID+string(r.outputFormat))
 	log.Debug().Str("file", progressFile).Msg("saving progress")
 
-	return io.SaveRecords(progressFile, accs)
+	if err := io.SaveRecords(progressFile, accs); err != nil {
+		return err
+	}
+
+	r.snapshotProgress(accs)
+
+	return nil
 }
 
 func (r *Runner) removeAnnoyances(page *rod.Page) error {
-	for _, annoyance := range r.annoyances {
-		if err := actions.RemoveAnnoyance(page, annoyance, r.timeout); err != nil {
-			return err
+	// the background watcher takes care of annoyances as soon as they appear.
+	if r.watchAnnoyances {
+		return nil
+	}
+
+	return actions.RemoveAnnoyances(page, r.annoyances, r.annoyanceTimeout)
+}
+
+// jobContext returns the context passed down to the browser actions of the job, which carries the
+// job's logger and the URL of the Apollo app that its account is served from.
+func (r *Runner) jobContext(job *job) context.Context {
+	ctx := job.log.WithContext(context.Background())
+	if job.apolloURL != "" {
+		ctx = actions.WithApolloURL(ctx, job.apolloURL)
+	} else if r.apolloURL != "" {
+		ctx = actions.WithApolloURL(ctx, r.apolloURL)
+	}
+
+	return ctx
+}
+
+// login logs into Apollo with the job's account, attempting to solve any security challenge
+// encountered along the way if a [actions.CaptchaSolver] is configured. The account's refreshed
+// cookies are saved to the output directory once logged in.
+func (r *Runner) login(bw *browserWrapper, job *job) (*rod.Page, error) {
+	release, err := r.acquireLogin(bw.browser.GetContext(), job)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	page, err := actions.ApolloLogin(bw.browser, job.acc, r.timeouts.Login, r.stealth, job.profile)
+	if errors.Is(err, actions.ErrorSecurityChallenge) && r.captchaSolver != nil {
+		err = actions.SolveSecurityChallenge(page, job.acc, r.captchaSolver, r.timeouts.Login)
+	}
+
+	if page != nil {
+		err = accountStatus(page, err)
+	}
+
+	if err == nil {
+		// persist the refreshed cookies right away so that they survive a crash.
+		if err := r.saveCookies(); err != nil {
+			job.log.Warn().Err(err).Msg("failed to save refreshed cookies")
 		}
+
+		page = r.detectUI(page, job)
 	}
 
-	return nil
+	return page, err
+}
+
+// uiDetectTimeout is how long the runner looks for the markers of the Apollo UI variants after
+// logging in.
+const uiDetectTimeout = 5 * time.Second
+
+// detectUI detects the variant of the Apollo UI that the job's account sees and returns a copy
+// of the page that uses its selectors. The previously detected variant is kept if detection fails.
+func (r *Runner) detectUI(page *rod.Page, job *job) *rod.Page {
+	variant, err := actions.DetectUIVariant(page, uiDetectTimeout)
+	if err != nil {
+		job.log.Warn().Err(err).Str("ui", string(job.ui)).Msg("failed to detect the apollo ui variant")
+		return actions.WithUIVariant(page, job.ui)
+	}
+
+	if job.ui != variant {
+		job.log.Info().Str("ui", string(variant)).Msg("detected apollo ui variant")
+	}
+
+	job.ui = variant
+	return actions.WithUIVariant(page, variant)
 }
 
-func (r *Runner) newScrapingPage(page *rod.Page, bw *browserWrapper, acc *models.Account) error {
-	log.Debug().Str("account", acc.Email).Msg("creating new scraping page")
+// recordFixture saves a snapshot of the job's 'People' page as a test fixture if fixtures are being
+// recorded.
+func (r *Runner) recordFixture(page *rod.Page, job *job) {
+	if r.fixtures == nil {
+		return
+	}
+
+	f, err := r.fixtures.Record(page, "people", string(actions.UIVariantOf(page)))
+	if err != nil {
+		job.log.Warn().Err(err).Msg("failed to record fixture")
+		return
+	}
+
+	job.log.Debug().Str("fixture", f.Name).Msg("recorded fixture")
+}
+
+// warmUp runs a warm-up session for the job's account and returns [ErrorWarmingUp] once it's done,
+// so that the account is rescheduled for its next session.
+func (r *Runner) warmUp(page *rod.Page, job *job) error {
+	opts := r.warmUpOpts
+	opts.Progress = r.status.progress
+
+	if err := actions.WarmUp(page, opts); err != nil {
+		return err
+	}
+
+	job.acc.WarmUp--
+	job.log.Info().Int("remaining", job.acc.WarmUp).Msg("finished warm-up session")
+	r.status.progress()
+	r.record(job, journal.Entry{Action: journal.ActionWarmUp})
+
+	return ErrorWarmingUp
+}
+
+func (r *Runner) startAnnoyanceWatcher(page *rod.Page, job *job) {
+	job.stopWatching()
+
+	if !r.watchAnnoyances || len(r.annoyances) == 0 {
+		return
+	}
+
+	unwatch, err := actions.WatchAnnoyances(page, r.annoyances)
+	if err != nil {
+		job.log.Warn().Err(err).Msg("failed to start annoyance watcher")
+		return
+	}
+
+	job.unwatch = unwatch
+}
+
+// newScrapingPage replaces the job's current page with a new, logged in page that's navigated to the
+// same URL. If restartBrowser is true, the browser itself is also restarted.
+func (r *Runner) newScrapingPage(
+	page *rod.Page,
+	bw *browserWrapper,
+	job *job,
+	restartBrowser bool,
+) error {
+	job.log.Debug().
+		Bool("restart-browser", restartBrowser).
+		Msg("creating new scraping page")
+
+	job.stopWatching()
 
 	info, err := page.Info()
 	if err != nil {
@@ -125,61 +374,307 @@ func (r *Runner) newScrapingPage(page *rod.Page, bw *browserWrapper, acc *models
 	}
 
 	url := info.URL
-	if err := page.Close(); err != nil {
+	if restartBrowser {
+		if err := bw.restart(); err != nil {
+			return err
+		}
+	} else if err := page.Close(); err != nil {
 		return err
 	}
 
-	newPage, err := actions.ApolloLogin(bw.browser, acc, r.timeout, r.stealth)
-	*page = *newPage
+	newPage, err := r.login(bw, job)
+	if newPage != nil {
+		job.replacePage(page, newPage)
+	}
 
 	if err != nil {
 		return err
 	}
 
+	r.startAnnoyanceWatcher(page, job)
+
 	err = page.Navigate(url)
 	if err != nil {
 		return err
 	}
 
-	log.Debug().Str("account", acc.Email).Msg("created and initialised new scraping page")
+	job.log.Debug().Msg("created and initialised new scraping page")
 
 	return nil
 }
 
-func (r *Runner) scrapeLeads(page *rod.Page, bw *browserWrapper, job *job) error {
-	file := filepath.Join(r.outputDir, job.acc.List+string(r.outputFormat))
+// outputName returns the name (without extension) of the file that leads scraped from the job's list are
+// written to, by expanding the placeholders in the [Runner]'s output template.
+func (r *Runner) outputName(job *job) string {
+	return strings.NewReplacer(
+		"{list}", job.acc.List,
+		"{account}", job.acc.Email,
+		"{run-id}", r.runID,
+		"{job-id}", job.id,
+		"{date}", r.now().Format(time.DateOnly),
+	).Replace(r.jobOutputTemplate(job))
+}
+
+// listWriters returns the chain of sinks that leads scraped from the job's list are written to: its
+// output file, followed by the [Runner]'s other sinks. If the [Runner] writes leads asynchronously
+// (see [AsyncWrites]), the chain is written to in the background, and drain must be called once the
+// job is done with it to wait for the queued leads to be written.
+func (r *Runner) listWriters(job *job) (file string, writers io.LeadWriter, drain func()) {
+	file = filepath.Join(r.jobOutputDir(job), r.outputName(job)+string(r.outputFormat))
 
-	var writer io.LeadWriter
+	var sinks []io.Sink
 	switch r.outputFormat {
 	case io.CsvFileFormat:
-		writer = io.NewCsvLeadWriter(file)
+		sinks = append(sinks, io.Sink{Name: "csv", Writer: io.NewCsvLeadWriter(file, r.outputLayout...)})
+		r.results.output(job, file)
 	case io.JsonFileFormat:
-		writer = io.NewJsonLeadWriter(file)
+		sinks = append(sinks, io.Sink{Name: "json", Writer: io.NewJsonLeadWriter(file, r.outputLayout...)})
+		r.results.output(job, file)
+	}
+
+	chain := io.NewSinkChain(append(sinks, r.sinks...)...)
+	if r.asyncWrites <= 0 {
+		return file, chain, func() {}
+	}
+
+	async := io.NewAsyncLeadWriter(chain, r.asyncWrites, func(err error) { logSinkErrors(job, err) })
+	return file, async, func() {
+		if queued := async.Queued(); queued > 0 {
+			job.log.Debug().Int("queued", queued).Msg("waiting for queued leads to be written")
+		}
+		async.Close()
+	}
+}
+
+// logSinkErrors logs the failures of the sinks that leads were written to.
+func logSinkErrors(job *job, err error) {
+	for _, err := range io.SinkErrors(err) {
+		job.log.Error().
+			Err(err.Err).
+			Str("sink", err.Sink).
+			Msg("failed to write leads")
+	}
+}
+
+// writeLeads annotates the leads scraped from a page of the job's list with their source, tags and
+// score, writes them and records the progress.
+func (r *Runner) writeLeads(
+	job *job, writers io.LeadWriter, pageNumber int, leads []*models.Lead, report *actions.ScrapeReport,
+) {
+	source := models.LeadSource{
+		Account:   job.acc.Email,
+		List:      job.acc.List,
+		SearchURL: job.acc.URL,
+		Page:      pageNumber,
+		ScrapedAt: r.now(),
+		RunID:     r.runID,
+		JobID:     job.id,
+	}
+
+	for _, lead := range leads {
+		lead.LeadSource = source
+		tagging.Apply(lead, r.tagRules)
+		r.score(job, lead)
+	}
+
+	r.markCaptured(job, leads)
+	r.scrubLeads(leads)
+
+	// the sinks fail independently, so each failure is logged on its own.
+	if err := writers.WriteLeads(leads); err != nil {
+		logSinkErrors(job, err)
+	}
+	job.pagesScraped++
+	r.results.update(job, func(result *JobResult) { result.Scraped += len(leads) })
+
+	logging.Summary(job.log.Info()).
+		Int("page", pageNumber).
+		Int("num", len(leads)).
+		Int("rows", report.Rows).
+		Int("recovered", report.Recovered).
+		Int("invalid", report.Invalid).
+		Msg("scraped leads")
+
+	if report.Invalid > 0 {
+		job.log.Warn().
+			Int("page", pageNumber).
+			Int("rows", report.Rows).
+			Int("invalid", report.Invalid).
+			Msg("dropped rows without a valid lead")
+	}
+
+	r.status.progress()
+	r.record(job, journal.Entry{
+		Action:    journal.ActionPageScraped,
+		Page:      pageNumber,
+		Leads:     len(leads),
+		Rows:      report.Rows,
+		Recovered: report.Recovered,
+		Invalid:   report.Invalid,
+	})
+}
+
+// score sets the lead's score with the [Runner]'s [scoring.Scorer] (if any). Leads which can't be
+// scored are left with a score of 0.
+func (r *Runner) score(job *job, lead *models.Lead) {
+	if r.scorer == nil {
+		return
+	}
+
+	score, err := r.scorer.Score(lead)
+	if err != nil {
+		job.log.Warn().Err(err).Str("lead", lead.Name).Msg("failed to score lead")
+	}
+	lead.Score = score
+}
+
+// scrapeLeads scrapes the job's list, resuming after the pages that have already been scraped.
+func (r *Runner) scrapeLeads(page *rod.Page, bw *browserWrapper, job *job) (err error) {
+	ctx, span := tracing.Start(page.GetContext(), "runner.scrapeLeads", attribute.String("list", job.acc.List))
+	defer func() { tracing.End(span, err) }()
+	page = page.Context(ctx)
+
+	file, writers, drain := r.listWriters(job)
+	defer drain()
+
+	if job.acc.APIKey != "" && r.apiExport && !r.scrapeOnly() {
+		err := r.exportList(ctx, job, writers)
+		if err == nil {
+			return nil
+		}
+		job.log.Warn().Err(err).Msg("failed to export the list through apollo's api, scraping it instead")
 	}
 
 	if err := r.removeAnnoyances(page); err != nil {
 		return err
 	}
 
-	log.Info().Str("account", job.acc.Email).Msg("scraping leads")
-	if err := actions.LocateList(page, job.acc.List, r.timeout); err != nil {
+	// without saving, the leads are scraped from the search itself rather than from the list.
+	if r.scrapeOnly() {
+		job.log.Info().Str("tab", string(r.tab)).Msg("scraping leads without saving them")
+	} else {
+		job.log.Info().Msg("scraping leads")
+		if err := actions.LocateList(page, job.acc.List, r.timeout); err != nil {
+			return err
+		}
+	}
+
+	if r.splitter != nil {
+		pageData, err := actions.GetPageData(page, r.timeouts.TableLoad)
+		if err != nil {
+			return err
+		}
+
+		if pageData.TotalSize > splitter.MaxResults {
+			info, err := page.Info()
+			if err != nil {
+				return err
+			}
+
+			job.log.Info().
+				Int("results", pageData.TotalSize).
+				Msg("list has more leads than apollo shows, splitting it")
+			return r.scrapePartitions(page, bw, job, file, writers, info.URL)
+		}
+	}
+
+	return r.scrapePages(page, bw, job, file, writers)
+}
+
+// scrapePartitions scrapes a search which has more results than Apollo shows by scraping each of
+// its partitions (see [splitter.Splitter]) in turn, splitting the partitions which are still too
+// large further. Partitions which have already been scraped are skipped.
+func (r *Runner) scrapePartitions(
+	page *rod.Page,
+	bw *browserWrapper,
+	job *job,
+	file string,
+	writers io.LeadWriter,
+	search string,
+) error {
+	partitions, err := r.splitter.Split(search)
+	if err != nil {
 		return err
 	}
 
+	if partitions == nil {
+		job.log.Warn().Str("search", search).Msg("search can't be split any further, scraping the leads apollo shows")
+		if err := page.Navigate(search); err != nil {
+			return err
+		}
+		return r.scrapePages(page, bw, job, file, writers)
+	}
+
+	for _, partition := range partitions {
+		if job.partitionsDone[partition] {
+			continue
+		}
+
+		job.log.Debug().Str("search", partition).Msg("scraping partition")
+		if err := page.Navigate(partition); err != nil {
+			return err
+		}
+
+		pageData, err := actions.GetPageData(page, r.timeouts.TableLoad)
+		switch {
+		case errors.Is(err, actions.ErrorListEnd):
+			// the partition is empty.
+
+		case err != nil:
+			return err
+
+		case pageData.TotalSize > splitter.MaxResults:
+			err = r.scrapePartitions(page, bw, job, file, writers, partition)
+
+		default:
+			err = r.scrapePages(page, bw, job, file, writers)
+		}
+
+		if err != nil && !errors.Is(err, actions.ErrorListEnd) {
+			return err
+		}
+
+		if job.partitionsDone == nil {
+			job.partitionsDone = make(map[string]bool)
+		}
+		job.partitionsDone[partition], job.pagesScraped = true, 0
+	}
+
+	return nil
+}
+
+// scrapePages scrapes the pages of the current search, resuming after the pages that have already
+// been scraped.
+func (r *Runner) scrapePages(
+	page *rod.Page,
+	bw *browserWrapper,
+	job *job,
+	file string,
+	writers io.LeadWriter,
+) error {
+	if job.pagesScraped > 0 {
+		if err := actions.GoToPage(page, job.pagesScraped+1, r.timeouts.TableLoad); err != nil {
+			return err
+		}
+	}
+
 	pageCount := 1
-	total := 0
 	for {
-		if (pageCount-1) > 0 && (pageCount-1)%10 == 0 {
-			if err := r.newScrapingPage(page, bw, job.acc); err != nil {
-				return err
-			}
+		if err := r.waitWhilePaused(page.GetContext()); err != nil {
+			return err
+		}
+
+		if err := r.recycle(page, bw, job, pageCount-1); err != nil {
+			return err
 		}
 
 		if err := r.removeAnnoyances(page); err != nil {
 			return err
 		}
 
-		pageData, err := actions.GetPageData(page, r.timeout)
+		r.recordFixture(page, job)
+
+		pageData, err := actions.GetPageData(page, r.timeouts.TableLoad)
 		if err != nil {
 			return err
 		}
@@ -188,92 +683,387 @@ func (r *Runner) scrapeLeads(page *rod.Page, bw *browserWrapper, job *job) error
 			return nil
 		}
 
-		leads, err := actions.ScrapeLeads(page, r.timeout)
+		leads, report, nextErr, err := r.scrapePage(page, job, pageData)
 		if err != nil {
 			return err
 		}
-		total += len(leads)
-
-		if err := writer.WriteLeads(leads); err != nil {
-			log.Error().
-				Err(err).
-				Str("account", job.acc.Email).
-				Msg("failed to write leads")
-		}
+		r.writeLeads(job, writers, pageData.Number, leads, report)
 
-		log.Info().Str("account", job.acc.Email).Int("num", total).Msg("scraped leads")
-
-		switch err := pageData.NextPage(page); err {
+		switch err := nextErr; err {
 		case nil:
 			pageCount++
 		case actions.ErrorListEnd:
 			return nil
 		default:
-			return errors.Join(err, os.Remove(file))
+			job.pagesScraped, job.partitionsDone = 0, nil
+			io.Flush(writers)
+			return errors.Join(err, io.RemoveLeadFiles(file, r.outputLayout...))
 		}
 	}
 }
 
-func (r *Runner) saveLeads(job *job) (err error) {
-	if r.vpn != nil && job.acc.VpnFile != "" {
-		// calling restart here to make sure any existing openvpn process is stopped.
-		err = r.vpn.Restart(job.acc.VpnFile)
-		if err != nil && !errors.Is(err, openvpn.ErrorNoVpnProcess) {
-			var newConfig string
-			for retries := 0; retries < 10; retries++ {
-				newConfig, err = r.vpn.Backup()
-				if err == nil {
-					job.acc.VpnFile = newConfig
-					break
-				}
-			}
+// bulkSaveLeads saves all of the leads that the job's account has left to save at once, if Apollo
+// offers to (see [actions.BulkSaveLeads]). The leads are left to be saved page by page otherwise, as
+// well as when they're deduplicated or saves are capped across accounts, both of which need every
+// page to be saved separately.
+func (r *Runner) bulkSaveLeads(page *rod.Page, job *job, verifier *saveVerifier) error {
+	if r.dedupe != nil || r.jobLimiter(job) != nil {
+		return nil
+	}
+
+	remaining := min(job.acc.Target-job.acc.Saved, job.acc.Credits, r.dailyLimit(job)-job.savedToday)
+	if remaining <= 0 {
+		return nil
+	}
+
+	saved, teamOwned, list, err := actions.BulkSaveLeads(page, job.acc.List, remaining, r.timeouts.SaveDialog)
+	switch {
+	case errors.Is(err, actions.ErrorBulkSaveUnavailable):
+		job.log.Info().Msg("bulk saving is unavailable, saving leads page by page")
+		return nil
+
+	case err != nil:
+		if blocked := actions.CheckAccountStatus(page); blocked != nil {
+			return blocked
 		}
+		return err
+	}
 
-		if err != nil {
-			return
+	r.reportList(job, list)
+	r.countTeamOwned(job, teamOwned)
+
+	if verifier != nil && saved > 0 {
+		if saved, err = verifier.landed(page.GetContext(), saved); err != nil {
+			return err
+		} else if saved == 0 {
+			job.log.Warn().Msg("bulk saved leads are missing from the list, saving leads page by page")
+			return nil
 		}
 	}
 
-	defer func() {
-		if r.vpn != nil {
-			if err := r.vpn.Stop(); err != nil && !errors.Is(err, openvpn.ErrorNoVpnProcess) {
-				log.Warn().Err(err).Msg("failed to stop vpn")
+	r.incrementSaved(job, saved)
+	logging.Summary(job.log.Info()).
+		Str("list", job.acc.List).
+		Int("leads", saved).
+		Int("team-owned", teamOwned).
+		Msg("bulk saved leads")
+
+	r.status.update(func(status *Status) {
+		status.LastProgress = time.Now()
+	})
+	r.record(job, journal.Entry{Action: journal.ActionBulkSaved, Leads: saved, TeamOwned: teamOwned})
+
+	return nil
+}
+
+// reportList records that the job's list was created by saving leads to it, warning if Apollo
+// already had lists with similar names, as the list's name is then likely to have a typo.
+func (r *Runner) reportList(job *job, list actions.ListPick) {
+	if !list.Created {
+		return
+	}
+
+	if len(list.Similar) > 0 {
+		job.log.Warn().
+			Str("list", job.acc.List).
+			Strs("similar", list.Similar).
+			Msg("created a new list although lists with similar names exist")
+	} else {
+		job.log.Info().Str("list", job.acc.List).Msg("created a new list")
+	}
+
+	r.record(job, journal.Entry{Action: journal.ActionListCreated, List: job.acc.List})
+}
+
+// reloadAfterTimeout reloads the page if err is a timeout waiting for an element and the page hasn't
+// been reloaded since the last successful save, returning true if it was. Such timeouts mostly come
+// from pages that failed to render, which retrying without a reload only repeats, so the page is
+// reloaded before the failure counts as a retry. Failing again after the reload means that the
+// element is truly absent.
+func (r *Runner) reloadAfterTimeout(page *rod.Page, job *job, err error) bool {
+	if job.reloaded || !actions.IsTimeout(err) {
+		return false
+	}
+	job.reloaded = true
+
+	job.log.Warn().Err(unwrapError(err)).Msg("timed out waiting for the page, reloading it")
+	if err := actions.ReloadPage(page, r.timeouts.TableLoad); err != nil {
+		job.log.Warn().Err(err).Msg("failed to reload the page")
+		return false
+	}
+
+	r.record(job, journal.Entry{Action: journal.ActionPageReloaded, Error: unwrapError(err).Error()})
+
+	return true
+}
+
+// scrapePage scrapes the leads of the current page and then moves on to the next page, returning
+// the error of the latter separately. When pipelining, the leads are scraped from a snapshot of the
+// page's table while the next page loads, which hides the latency of navigating.
+func (r *Runner) scrapePage(page *rod.Page, job *job, pageData *actions.PageData) (
+	leads []*models.Lead, report *actions.ScrapeReport, nextErr, err error,
+) {
+	if !r.pipelineScrape {
+		if leads, report, err = r.scrapeTable(page, job, pageData, job.reloaded); err != nil {
+			return nil, nil, nil, err
+		}
+		r.capturePage(page, job, pageData.Number)
+		return leads, report, pageData.NextPage(page, r.timeouts.TableLoad), nil
+	}
+
+	snapshot, err := actions.CaptureTable(page, r.timeouts.TableLoad)
+	if err != nil && r.needsExtraction(pageData, nil, err, job.reloaded) {
+		capture := func() (string, error) { return actions.CaptureResultsHTML(page, r.timeouts.TableLoad) }
+		if leads, report, err = r.extractLeads(page, job, pageData, capture, nil, nil, err); err != nil {
+			return nil, nil, nil, err
+		}
+		r.capturePage(page, job, pageData.Number)
+		return leads, report, pageData.NextPage(page, r.timeouts.TableLoad), nil
+	} else if err != nil {
+		return nil, nil, nil, err
+	}
+	r.capturePage(page, job, pageData.Number)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		defer func() {
+			if err := snapshot.Discard(); err != nil {
+				job.log.Debug().Err(err).Msg("failed to discard the table snapshot")
 			}
+		}()
+
+		// the page has moved on by the time the scrape script's results are known, so the leads are
+		// extracted from the snapshot's html.
+		leads, report, err = snapshot.Leads()
+		if err == nil && r.needsExtraction(pageData, report, nil, false) {
+			leads, report, err = r.extractLeads(page, job, pageData, snapshot.ResultsHTML, leads, report, nil)
 		}
 	}()
 
-	bw, err := newBrowserWrapper(r.headless)
+	nextErr = pageData.NextPage(page, r.timeouts.TableLoad)
+	<-done
+
+	return leads, report, nextErr, err
+}
+
+// connectVpn connects to the VPN configured for the job's account (if any), falling back
+// to a backup config (from the account's region, if it has one) if that fails, and then
+// as permitted by the [Runner]'s [FailoverPolicy].
+func (r *Runner) connectVpn(job *job) (err error) {
+	job.proxy = ""
+	if r.vpn == nil || (job.acc.VpnFile == "" && job.acc.VpnRegion == "") {
+		return nil
+	}
+
+	// accounts without a config of their own are given a backup config from their region.
+	err = openvpn.ErrorConfigNotFound
+	if job.acc.VpnFile != "" {
+		// calling restart here to make sure any existing openvpn process is stopped.
+		err = r.vpn.Restart(job.acc.VpnFile)
+	} else if err := r.vpn.Stop(); err != nil && !errors.Is(err, openvpn.ErrorNoVpnProcess) {
+		return err
+	}
+
+	if err != nil && !errors.Is(err, openvpn.ErrorNoVpnProcess) {
+		var newConfig string
+		for retries := 0; retries < 10; retries++ {
+			newConfig, err = r.vpn.Backup(job.acc.VpnRegion)
+			if err == nil {
+				job.acc.VpnFile = newConfig
+				break
+			} else if errors.Is(err, vpn.ErrorNoUnusedConfigs) {
+				break
+			}
+		}
+	}
+
+	if err != nil {
+		job.log.Debug().Interface("vpn", r.vpn.Status()).Msg("failed to connect to vpn")
+		return r.failover(job, err)
+	}
+
+	// in split-tunnel mode, only the browser's traffic is routed through the vpn by its proxy.
+	job.proxy = r.vpn.Proxy()
+
+	job.vpnSince = time.Now()
+	job.log.Debug().Interface("vpn", r.vpn.Status()).Msg("connected to vpn")
+	r.record(job, journal.Entry{Action: journal.ActionVpnConnected, VpnConfig: job.acc.VpnFile})
+	r.checkVpnRegion(job)
+
+	return nil
+}
+
+// checkVpnRegion flags the job's account if it has a region but is connected through a config
+// on a different continent, since its IP address jumping continents may get it flagged by Apollo.
+func (r *Runner) checkVpnRegion(job *job) {
+	if job.acc.VpnRegion == "" {
+		return
+	}
+
+	region := r.vpn.Region(job.acc.VpnFile)
+	want, got := vpn.Continent(job.acc.VpnRegion), vpn.Continent(region)
+	if want == "" || got == "" || want == got {
+		return
+	}
+
+	msg := fmt.Sprintf(
+		"connected through %q (%s) instead of a config from the account's region %q",
+		job.acc.VpnFile, region, job.acc.VpnRegion,
+	)
+	job.log.Warn().Str("region", job.acc.VpnRegion).Str("vpn-region", region).Msg(msg)
+	r.record(job, journal.Entry{Action: journal.ActionVpnRegion, VpnConfig: job.acc.VpnFile, Error: msg})
+	r.notify(job, EventVpnRegion, msg)
+}
+
+// disconnectVpn stops the running VPN process (if any), adding the time that the job spent connected
+// to it to its result.
+func (r *Runner) disconnectVpn(job *job) {
+	if r.vpn != nil {
+		if err := r.vpn.Stop(); err != nil && !errors.Is(err, openvpn.ErrorNoVpnProcess) {
+			log.Warn().Err(err).Msg("failed to stop vpn")
+		}
+	}
+
+	if !job.vpnSince.IsZero() {
+		elapsed := time.Since(job.vpnSince)
+		r.results.update(job, func(result *JobResult) { result.VpnTime += elapsed })
+		job.vpnSince = time.Time{}
+	}
+}
+
+// closeVpn stops all of the VPN's connections once the run is over, if its provider can be closed
+// (e.g. [*openvpn.Manager], whose concurrent tunnels aren't stopped by [VpnProvider.Stop]).
+func (r *Runner) closeVpn() {
+	closer, ok := r.vpn.(interface{ Close() error })
+	if !ok {
+		return
+	}
+
+	if err := closer.Close(); err != nil {
+		log.Warn().Err(err).Msg("failed to close vpn")
+	}
+}
+
+// selectTab selects the [Runner]'s tab on the search page.
+func (r *Runner) selectTab(page *rod.Page, job *job) error {
+	if err := r.removeAnnoyances(page); err != nil {
+		return err
+	}
+
+	if err := r.tab.Select(page, r.timeouts.TabSelect); err != nil {
+		return err
+	}
+
+	job.log.Debug().Str("tab", string(r.tab)).Msg("selected tab")
+	r.status.progress()
+	r.record(job, journal.Entry{Action: journal.ActionTabSelected, Tab: string(r.tab)})
+
+	return nil
+}
+
+// reselectTab navigates back to the job's search and selects the [Runner]'s tab again.
+func (r *Runner) reselectTab(page *rod.Page, job *job) error {
+	if err := page.Navigate(job.acc.URL); err != nil {
+		return err
+	}
+
+	return r.selectTab(page, job)
+}
+
+func (r *Runner) saveLeads(job *job) (err error) {
+	jobCtx, span := tracing.Start(
+		r.jobContext(job),
+		"runner.job",
+		attribute.String("account", job.acc.Email),
+		attribute.String("job-id", job.id),
+		attribute.String("list", job.acc.List),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	_, vpnSpan := tracing.Start(jobCtx, "runner.connectVpn")
+	err = r.connectVpn(job)
+	tracing.End(vpnSpan, err)
+	if err != nil {
+		return
+	}
+	defer r.disconnectVpn(job)
+
+	_, browserSpan := tracing.Start(jobCtx, "runner.launchBrowser")
+	bw, err := newBrowserWrapper(r.browserOptions(job))
+	tracing.End(browserSpan, err)
 	if err != nil {
 		return err
 	}
 	defer bw.close()
 
-	page, err := actions.ApolloLogin(bw.browser, job.acc, r.timeout, r.stealth)
+	// the browser's actions inherit the job's span through its context.
+	ctx, cancel := context.WithCancelCause(jobCtx)
+	defer cancel(nil)
+	bw.browser = bw.browser.Context(ctx)
+
+	defer func() {
+		cause := context.Cause(ctx)
+		if errors.Is(cause, ErrorJobStalled) || errors.Is(cause, ErrorTimeBudgetExceeded) ||
+			errors.Is(cause, ErrorOutsideWindow) {
+			err = cause
+		}
+	}()
+
+	stopWatchdog := r.startWatchdog(job, cancel)
+	defer stopWatchdog()
+
+	stopBudget := r.startBudget(job, cancel)
+	defer stopBudget()
+
+	page, err := r.login(bw, job)
+	if page != nil {
+		job.setPage(page)
+		defer job.setPage(nil)
+	}
+
 	if err != nil {
 		return err
 	}
 
+	r.status.progress()
+	r.record(job, journal.Entry{Action: journal.ActionLogin, UI: string(job.ui)})
+
+	r.startAnnoyanceWatcher(page, job)
+	defer job.stopWatching()
+
 	defer func() {
 		switch err {
-		case nil, ErrorTargetReached, ErrorDailyLimit:
+		case nil, ErrorTargetReached, ErrorDailyLimit, ErrorJobStalled, ErrorWarmingUp, ErrorOutsideWindow,
+			ErrorAccountBlacklisted:
 		default:
-			if _err := actions.GrabErrorSnapshot(page, job.acc, r.errorDir); _err != nil {
-				log.Warn().Err(err).Msg("failed to grab error snapshot")
-			}
+			err = accountStatus(page, err)
+			state, routed := r.classifyFailure(page, job, err)
+			r.grabErrorSnapshot(page, job, err, state)
+			err = routed
 		}
 	}()
 
-	if r.fetchCredits {
+	// credits fetched by the login prefetch are only fresh for the job's first run.
+	defer func() { job.creditsFetched = false }()
+
+	if job.acc.WarmUp > 0 {
+		return r.warmUp(page, job)
+	}
+
+	if r.fetchCredits && !job.creditsFetched {
 		if err := r.removeAnnoyances(page); err != nil {
 			return err
 		}
 
-		c, r, err := actions.FetchCreditUsage(page, job.acc, r.timeout)
+		credits, err := actions.FetchCreditUsage(page, job.acc, r.creditLocales, r.timeouts.Credits)
 		if err != nil {
 			return err
 		}
 
-		job.acc.Credits, job.acc.CreditRefresh = c, r
+		job.acc.Credits, job.acc.CreditRefresh = credits.Remaining(), credits.Renewal
+		r.recordCredits(job, credits)
+		r.shareCredits(job)
 	}
 
 	if err = page.Navigate(job.acc.URL); err != nil {
@@ -281,40 +1071,88 @@ func (r *Runner) saveLeads(job *job) (err error) {
 	}
 
 	if _, ok := job.startedAt.Get(); !ok {
-		job.start()
+		r.restoreSavedToday(job)
 	}
 
-	if err := r.removeAnnoyances(page); err != nil {
-		return err
+	if _, ok := job.startedAt.Get(); !ok {
+		job.start(r.now())
 	}
 
-	if err := r.tab.Select(page); err != nil {
+	if err := r.selectTab(page, job); err != nil {
 		return err
 	}
 
-	log.Debug().Str("tab", string(r.tab)).Msg("selected tab")
+	// the leads of the saved and total tabs are already saved, so saving them again is pointless.
+	saving := !r.scrapeOnly()
+
+	var verifier *saveVerifier
+	if saving {
+		if verifier, err = r.newSaveVerifier(bw, job); err != nil {
+			return err
+		}
+	}
+
+	if r.bulkSave && saving {
+		if err := r.bulkSaveLeads(page, job, verifier); err != nil {
+			return err
+		}
+	}
+
+	var pipeline *savePipeline
+	stopOverlap := func() {}
+	if r.overlapScrape && saving {
+		pipeline = newSavePipeline(job.acc.Saved)
+		stopOverlap = r.startOverlapScrape(bw, job, pipeline)
+		defer func() { stopOverlap() }()
+	}
 
 	var prevErr error
-	var retries int
+	var retries, pagesSaved int
+	var scraping bool
+	job.reloaded = false
+	meter := newRateMeter()
 	for {
 		if retries >= 5 {
 			return prevErr
 		}
 
-		if job.acc.IsDone() {
-			log.Info().
-				Str("account", job.acc.Email).
-				Str("list", job.acc.List).
-				Msg("finished saving leads")
+		if err := r.waitWhilePaused(page.GetContext()); err != nil {
+			return err
+		}
+
+		if err := r.checkBlacklist(job); err != nil {
+			return err
+		}
+
+		if !saving || job.acc.IsDone() {
+			if saving {
+				job.log.Info().
+					Str("list", job.acc.List).
+					Msg("finished saving leads")
+			}
+
+			stopOverlap()
+
+			// without saving, retries don't locate the list, so they must go back to the selected tab
+			// themselves, since reloading the page or scraping partitions leaves it.
+			if !saving && scraping {
+				if err := r.reselectTab(page, job); err != nil {
+					prevErr, retries = err, retries+1
+					continue
+				}
+			}
+			scraping = true
 
 			if err = r.scrapeLeads(page, bw, job); err == nil {
 				return
+			} else if r.reloadAfterTimeout(page, job, err) {
+				continue
 			}
 			prevErr, retries = err, retries+1
 			continue
 		}
 
-		if job.hitDailyLimit(r.limit) {
+		if job.hitDailyLimit(r.dailyLimit(job), r.now()) {
 			return ErrorDailyLimit
 		}
 
@@ -326,27 +1164,120 @@ func (r *Runner) saveLeads(job *job) (err error) {
 			return err
 		}
 
-		pageData, err := actions.GetPageData(page, r.timeout)
+		r.recordFixture(page, job)
+
+		pageData, err := actions.GetPageData(page, r.timeouts.TableLoad)
 		if err != nil {
+			if r.reloadAfterTimeout(page, job, err) {
+				continue
+			}
+			return err
+		}
+
+		count := pageData.Size
+		var fresh []*models.Lead
+		var rows []int
+		if r.dedupe != nil {
+			if fresh, rows, err = r.uncaptured(page); err != nil {
+				return err
+			}
+
+			if len(fresh) == 0 {
+				if err := r.skipCaptured(page, job, pageData); err != nil {
+					return err
+				}
+				continue
+			}
+			count = len(fresh)
+		}
+
+		if err := r.throttleSaves(page.GetContext(), job, count); err != nil {
 			return err
 		}
 
-		if err = actions.SaveLeads(page, job.acc.List, r.timeout); err != nil {
+		teamOwned, list, err := actions.SaveLeads(page, job.acc.List, r.timeouts.SaveDialog, rows...)
+		if err != nil {
+			if blocked := actions.CheckAccountStatus(page); blocked != nil {
+				return blocked
+			} else if r.reloadAfterTimeout(page, job, err) {
+				continue
+			}
 			prevErr, retries = err, retries+1
 			continue
 		}
+		r.reportList(job, list)
+		job.reloaded = false
+
+		// leads missing from the list aren't counted as saved, and the page is saved again if none of
+		// them landed.
+		if verifier != nil && count > teamOwned {
+			landed, err := verifier.landed(page.GetContext(), count-teamOwned)
+			if err != nil {
+				return err
+			} else if landed == 0 {
+				prevErr, retries = ErrorSaveUnverified, retries+1
+				continue
+			}
+			count = teamOwned + landed
+		}
 
-		log.Info().
-			Str("account", job.acc.Email).
+		// leads owned by the team are never saved, so they're skipped rather than retried.
+		r.markCaptured(job, fresh)
+		r.countTeamOwned(job, teamOwned)
+		captured := pageData.Size - count
+		if count -= teamOwned; count == 0 {
+			if err := r.skipTeamOwned(page, job, pageData, teamOwned); err != nil {
+				return err
+			}
+			continue
+		}
+
+		r.incrementSaved(job, count)
+		meter.add(count)
+
+		progress := r.jobProgress(job, meter, pageData)
+		logging.Summary(job.log.Info()).
 			Str("list", job.acc.List).
-			Int("page", pageData.Size).
+			Int("page", count).
+			Int("team-owned", teamOwned).
+			Int("percent", progress.Percent).
+			Time("eta", progress.ETA).
 			Msg("saved leads")
 
-		job.incrementSaved(pageData.Size)
+		r.status.update(func(status *Status) {
+			status.LastProgress, status.Progress = time.Now(), &progress
+		})
+		r.record(job, journal.Entry{
+			Action:    journal.ActionPageSaved,
+			Page:      pageData.Number,
+			Leads:     count,
+			Captured:  captured,
+			TeamOwned: teamOwned,
+		})
+		pagesSaved++
+
+		if pipeline != nil {
+			pipeline.leadsSaved(job.acc.Saved, pageData.Size)
+		}
+
+		// the overlapping scrape's tab belongs to the browser, which may be restarted, so the scrape is
+		// stopped while the page is recycled and started again afterwards.
+		if due, restart := r.recycling(bw, job, pagesSaved); due {
+			stopOverlap()
+			if err := r.newScrapingPage(page, bw, job, restart); err != nil {
+				return err
+			}
+
+			if pipeline != nil {
+				pipeline = newSavePipeline(job.acc.Saved)
+				pipeline.leadsSaved(job.acc.Saved, pageData.Size)
+				stopOverlap = r.startOverlapScrape(bw, job, pipeline)
+			}
+		}
 
 		if r.saveProgress {
 			if err := r._saveProgress(); err != nil {
-				log.Warn().Err(err).Msg("failed to save progress")
+				job.log.Warn().Err(err).Msg("failed to save progress")
 			}
 		}
 
@@ -356,6 +1287,25 @@ func (r *Runner) saveLeads(job *job) (err error) {
 	}
 }
 
+// byTimeout orders jobs whose accounts aren't timed out before the others, which are ordered by when
+// their timeouts end.
+func byTimeout(a, b *job) int {
+	timeoutA, okA := a.acc.Timeout.Get()
+	timeoutB, okB := b.acc.Timeout.Get()
+
+	switch {
+	case !okA && !okB:
+		return 0
+	case !okA:
+		return -1
+	case !okB:
+		return 1
+	default:
+		return timeoutA.Compare(timeoutB)
+	}
+}
+
+// rearrangeJobs orders the queue by when the jobs' accounts can be used again (see [byTimeout]).
 func (r *Runner) rearrangeJobs() {
 	log.Debug().Msg("rearranging jobs")
 
@@ -364,32 +1314,8 @@ func (r *Runner) rearrangeJobs() {
 		jobs[i] = job
 	}
 
-	slices.SortFunc(jobs, func(a, b *job) int {
-		timeoutA, okA := a.acc.Timeout.Get()
-		timeoutB, okB := a.acc.Timeout.Get()
-
-		if !okA && !okB {
-			return 0
-		}
-
-		if !okA {
-			return -1
-		}
-
-		if !okB {
-			return 1
-		}
-
-		if timeoutA.Before(timeoutB) {
-			return -1
-		}
-
-		if timeoutB.Before(timeoutA) {
-			return 1
-		}
-
-		return 0
-	})
+	// the sort is stable, so that jobs which are equally eligible keep their turns.
+	slices.SortStableFunc(jobs, byTimeout)
 
 	q := list.New()
 	for _, job := range jobs {
@@ -410,6 +1336,41 @@ func unwrapError(err error) error {
 	}
 }
 
+// grabErrorSnapshot saves a snapshot of the page on which the job encountered the provided error,
+// along with the state that the page was classified as (if it was).
+func (r *Runner) grabErrorSnapshot(page *rod.Page, job *job, cause error, state classify.State) {
+	snapshot := &actions.ErrorSnapshot{
+		Account: job.acc.Email,
+		RunID:   r.runID,
+		JobID:   job.id,
+		Error:   unwrapError(cause).Error(),
+		State:   string(state),
+	}
+
+	if err := actions.GrabErrorSnapshot(page, snapshot, r.errorDir, r.snapshots); err != nil {
+		job.log.Warn().Err(err).Msg("failed to grab error snapshot")
+	}
+}
+
+// stopForBudget saves the [Runner]'s progress before it stops for exceeding its time budget.
+func (r *Runner) stopForBudget(err error) error {
+	if _err := r._saveProgress(); _err != nil {
+		log.Error().Err(_err).Msg("failed to save scraping progress")
+	}
+
+	return err
+}
+
+// writeErrorReport aggregates the error snapshots taken during the run into a single report.
+func (r *Runner) writeErrorReport() {
+	file, err := report.WriteErrorReport(r.errorDir)
+	if err != nil {
+		log.Warn().Err(err).Msg("failed to write error report")
+	} else if file != "" {
+		log.Info().Str("file", file).Msg("saved error report")
+	}
+}
+
 func (r *Runner) Start() error {
 	var timeoutSkip int
 
@@ -419,23 +1380,88 @@ func (r *Runner) Start() error {
 		}
 	}
 
+	r.status.update(func(status *Status) {
+		status.State, status.RunID = StateRunning, r.runID
+		status.StartedAt = time.Now()
+		status.LastProgress = status.StartedAt
+	})
+
+	if r.maxRuntime > 0 {
+		r.deadline = r.now().Add(r.maxRuntime)
+	}
+
+	r.purgeExpired()
+
+	if r.sim != nil {
+		r.deadline = r.simulationDeadline()
+		defer r.logSimulation(r.now())
+	}
+
+	defer r.closeVpn()
+	defer r.writeErrorReport()
+	defer r.writeCampaignReports()
+	defer r.writeCostReport()
+
+	stopPauseSignals := r.pause.watchSignals()
+	defer stopPauseSignals()
+
+	stopAccountWatcher := r.accounts.watch()
+	defer stopAccountWatcher()
+
+	defer r.status.update(func(status *Status) {
+		status.State = StateFinished
+		status.CurrentJob, status.CurrentJobID = "", ""
+		status.PendingJobs, status.Progress = r.jobs.Len(), nil
+	})
+
+	r.prefetch()
+
 	for {
+		r.reloadAccounts()
+
 		if r.jobs.isEmpty() {
 			log.Info().Msg("finished all scraping jobs")
 			break
 		}
 
+		if r.runDeadlineExceeded() {
+			return r.stopForBudget(ErrorMaxRuntime)
+		}
+
+		if err := r.waitWhilePaused(context.Background()); err != nil {
+			return err
+		}
+
+		r.schedule()
+
 		_job, _ := r.jobs.Front().Value.(*job)
 		acc := _job.acc
 
-		if _, ok := acc.Timeout.Get(); ok {
+		if err := r.checkBlacklist(_job); err != nil {
+			r.dropJob(_job)
+			if err := r._saveProgress(); err != nil {
+				log.Error().Err(err).Msg("failed to save scraping progress")
+			}
+			continue
+		}
+
+		if _, ok := timedOut(acc, r.now()); ok {
 			if timeoutSkip >= r.jobs.Len() {
 				r.rearrangeJobs()
 				_job, _ = r.jobs.Front().Value.(*job)
 				if t, ok := _job.acc.Timeout.Get(); ok {
-					dur := time.Until(t)
+					dur := t.Sub(r.now())
+					if !r.deadline.IsZero() && t.After(r.deadline) {
+						log.Warn().Dur("duration", dur).Msg("pausing would exceed the maximum runtime")
+						return r.stopForBudget(ErrorMaxRuntime)
+					}
+
 					log.Warn().Dur("duration", dur).Msg("pausing execution")
-					time.Sleep(dur)
+
+					r.status.update(func(status *Status) {
+						status.State, status.WaitingUntil = StateWaiting, t
+					})
+					r.runClock().Sleep(dur)
 				}
 
 				acc = _job.acc
@@ -449,29 +1475,147 @@ func (r *Runner) Start() error {
 			}
 		}
 
-		switch err := r.saveLeads(_job); err {
+		if open, _, ok := r.window(_job); ok && r.now().Before(open) {
+			log.Info().
+				Str("account", acc.Email).
+				Time("opens", open).
+				Msg("waiting for the account's activity window")
+
+			acc.Timeout.Set(open)
+			if err := r.jobs.requeue(); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := _job.begin(); err != nil {
+			return err
+		}
+
+		r.status.update(func(status *Status) {
+			status.State, status.WaitingUntil = StateRunning, time.Time{}
+			status.CurrentJob, status.CurrentJobID = acc.Email, _job.id
+			status.PendingJobs, status.Progress = r.jobs.Len(), nil
+			status.LastProgress = time.Now()
+		})
+
+		r.record(_job, journal.Entry{Action: journal.ActionJobStarted, VpnConfig: acc.VpnFile})
+
+		started := r.now()
+		err := r.runJob(_job)
+		r.results.finish(_job, r.now().Sub(started), err)
+		switch err {
+		case nil, ErrorTargetReached, actions.ErrorListEnd:
+			r.record(_job, journal.Entry{Action: journal.ActionJobFinished})
+		case ErrorWarmingUp, ErrorOutsideWindow, ErrorAccountBlacklisted:
+		default:
+			r.recordError(_job, err)
+		}
+
+		switch err {
+		case nil, ErrorTargetReached, actions.ErrorListEnd:
+			r.notify(_job, EventJobFinished, "scraping completed")
 		case ErrorDailyLimit:
-			log.Warn().Str("account", acc.Email).Msg("hit daily save limit")
-			acc.Timeout.Set(time.Now().Add(24 * time.Hour))
+			r.notify(_job, EventDailyLimit, err.Error())
+		case ErrorNoCredits:
+			r.notify(_job, EventNoCredits, err.Error())
+		case ErrorWarmingUp, ErrorOutsideWindow, ErrorAccountBlacklisted:
+		case actions.ErrorSecurityChallenge:
+			r.notify(_job, EventSecurityChallenge, err.Error())
+		case actions.ErrorAccountSuspended, actions.ErrorPaymentRequired:
+			r.notify(_job, EventAccountBanned, err.Error())
+		default:
+			r.notify(_job, EventJobFailed, unwrapError(err).Error())
+		}
+
+		switch err {
+		case ErrorDailyLimit:
+			_job.log.Warn().Msg("hit daily save limit")
+			acc.Timeout.Set(r.now().Add(24 * time.Hour))
+			if err := r.jobs.requeue(); err != nil {
+				return err
+			}
+
+		case ErrorWarmingUp:
+			// the next warm-up session, or the first real job, happens the following day.
+			acc.Timeout.Set(r.now().Add(24 * time.Hour))
+			if err := r.jobs.requeue(); err != nil {
+				return err
+			}
+
+		case ErrorOutsideWindow:
+			_job.log.Info().Msg("activity window closed, requeueing")
+			if open, _, ok := r.window(_job); ok {
+				acc.Timeout.Set(open)
+			}
 			if err := r.jobs.requeue(); err != nil {
 				return err
 			}
 
 		case ErrorNoCredits:
-			log.Warn().Str("account", acc.Email).Msg("out of credits")
+			_job.log.Warn().Msg("out of credits")
+			r.exhaustPool(_job)
+			if err := r.jobs.requeue(); err != nil {
+				return err
+			}
+
+		case ErrorJobStalled:
+			_job.log.Warn().Msg("job stalled, requeueing")
+			if err := r.jobs.requeue(); err != nil {
+				return err
+			}
+
+		case ErrorApolloMaintenance:
+			_job.log.Warn().Dur("wait", r.maintenanceWait).Msg("apollo is down for maintenance, pausing all jobs")
+			r.waitForMaintenance()
 			if err := r.jobs.requeue(); err != nil {
 				return err
 			}
 
+		case ErrorSessionExpired, ErrorPageObstructed, ErrorNoResults:
+			if r.failedTooOften(_job) {
+				r.deadLetterJob(_job)
+				break
+			}
+
+			_job.log.Warn().Err(err).Msg("recovered from the failure, retrying the job right away")
+			if err := r.jobs.requeue(); err != nil {
+				return err
+			}
+
+		case ErrorMaxRuntime, ErrorMaxJobDuration:
+			_job.log.Warn().Err(err).Msg("time budget exceeded, stopping")
+			if err := r.jobs.requeue(); err != nil {
+				return err
+			}
+			return r.stopForBudget(err)
+
 		case actions.ErrorSecurityChallenge:
-			log.Error().Err(err).Str("account", acc.Email).Msg("")
+			_job.log.Error().Err(err).Msg("")
+
+		case ErrorAccountBlacklisted:
+			r.dropJob(_job)
+
+		case actions.ErrorAccountSuspended, actions.ErrorPaymentRequired:
+			// retrying is pointless, so the account is banned for good.
+			acc.Blacklisted = "banned: " + err.Error()
+			r.dropJob(_job)
 
-		case ErrorTargetReached, actions.ErrorListEnd:
-			log.Info().Str("account", acc.Email).Msg("scraping completed")
+		case apolloapi.ErrorUnauthorized:
+			acc.Blacklisted = "invalid api key"
+			r.dropJob(_job)
+
+		case nil, ErrorTargetReached, actions.ErrorListEnd:
+			_job.log.Info().Msg("scraping completed")
 			r.jobs.Remove(r.jobs.Front())
 
 		default:
-			log.Error().Err(unwrapError(err)).Str("account", acc.Email).Msg("scraping error")
+			if r.failedTooOften(_job) {
+				r.deadLetterJob(_job)
+				break
+			}
+
+			_job.log.Error().Err(unwrapError(err)).Dur("backoff", r.backOff(_job)).Msg("scraping error")
 			if err := r.jobs.requeue(); err != nil {
 				return err
 			}
@@ -15,38 +15,130 @@
 package runner
 
 import (
+	"crypto/rand"
 	"fmt"
+	"iter"
 	"os"
 	"path/filepath"
 	"time"
 
 	"github.com/devsheke/scrapollo/internal/actions"
+	"github.com/devsheke/scrapollo/internal/artifacts"
+	"github.com/devsheke/scrapollo/internal/classify"
+	"github.com/devsheke/scrapollo/internal/credits"
+	"github.com/devsheke/scrapollo/internal/dedupe"
+	"github.com/devsheke/scrapollo/internal/events"
+	"github.com/devsheke/scrapollo/internal/extract"
+	"github.com/devsheke/scrapollo/internal/fixture"
 	"github.com/devsheke/scrapollo/internal/io"
+	"github.com/devsheke/scrapollo/internal/journal"
+	"github.com/devsheke/scrapollo/internal/limiter"
 	"github.com/devsheke/scrapollo/internal/models"
-	"github.com/devsheke/scrapollo/internal/openvpn"
+	"github.com/devsheke/scrapollo/internal/privacy"
+	"github.com/devsheke/scrapollo/internal/scoring"
+	"github.com/devsheke/scrapollo/internal/splitter"
+	"github.com/devsheke/scrapollo/internal/tagging"
 	"github.com/go-rod/rod/lib/proto"
 )
 
 // Runner is a type that manages and orchestrates the process of scraping leads from Apollo.
 type Runner struct {
+	accounts                                             accountWatcher
 	annoyances                                           []*actions.Annoyance
+	artifacts                                            *artifacts.Collector
+	blacklist                                            blacklist
+	blocker                                              *actions.ResourceBlocker
+	campaigns                                            []*Campaign
+	costRates                                            *CostRates
+	progressHistory                                      *progressHistory
+	clock                                                clock
+	sim                                                  *Simulation
+	retention                                            time.Duration
+	scrub                                                privacy.ScrubMode
+	browserCacheDir, apiURL                              string
+	browserFlags, extensions                             []string
+	parsedBrowserFlags                                   []browserFlag
+	splitter                                             *splitter.Splitter
+	captchaSolver                                        actions.CaptchaSolver
+	creditLocales                                        []*actions.CreditLocale
+	creditHistory                                        *credits.History
+	dedupe                                               *dedupe.Store
+	dropped                                              []*models.Account
+	deadLetters                                          []*deadLetter
+	events                                               *events.Stream
+	useCreditHistory                                     bool
 	debug, fetchCredits, headless, saveProgress, stealth bool
+	overlapScrape, pipelineScrape, watchAnnoyances       bool
+	bulkSave, verifySaves, apiExport                     bool
 	jobs                                                 *queue
-	limit                                                int
+	fixtures                                             *fixture.Recorder
+	fixtureDir                                           string
+	journal                                              *journal.Journal
+	useJournal, ignoreTimeouts, deviceProfiles           bool
+	mobileView                                           bool
+	virtualDisplay                                       bool
+	display                                              display
+	sinks                                                []io.Sink
+	limiter                                              *limiter.Limiter
+	limit, recyclePages, asyncWrites, prefetchLogins     int
+	maxFailures                                          int
+	prefetchSpread, errorBackoff, maxErrorBackoff        time.Duration
+	maxBrowserMemory                                     uint64
 	outputFormat                                         io.FileFormat
-	cookieFile, outputDir, errorDir                      string
+	outputLayout                                         []io.LeadWriterOpt
+	apolloURL, cookieFile, outputDir, errorDir, runID    string
+	regions                                              map[string]string
+	outputTemplate                                       string
+	results                                              jobResults
+	notifiers                                            []Notifier
+	pageHooks                                            []PageHook
+	extractor                                            *extract.Extractor
+	classifier                                           classify.Classifier
+	maintenanceWait                                      time.Duration
+	pause                                                pauseSwitch
+	scheduler                                            JobScheduler
+	scorer                                               scoring.Scorer
+	snapshots                                            actions.SnapshotOptions
+	status                                               *statusTracker
+	tagRules                                             []*tagging.Rule
 	tab                                                  actions.ApolloTab
-	timeout                                              time.Duration
-	vpn                                                  *openvpn.Manager
+	annoyanceTimeout, stallTimeout, timeout              time.Duration
+	maxJobDuration, maxRuntime, windowJitter             time.Duration
+	deadline                                             time.Time
+	timeouts                                             ActionTimeouts
+	vpn                                                  VpnProvider
+	warmUpOpts                                           actions.WarmUpOptions
+	failoverPolicy                                       FailoverPolicy
+	proxies                                              []string
 }
 
 const (
-	bannerAnnoyance  string = "new-ui"
-	newUiAnnoyance   string = "pop-up"
-	popUpAnnoyances  string = "banner"
+	bannerAnnoyance  string = "banner"
+	newUiAnnoyance   string = "new-ui"
+	popUpAnnoyances  string = "pop-up"
 	sideNavAnnoyance string = "sidenav"
 )
 
+// ActionTimeouts represents the time limits for specific browser actions. Zero values fall back
+// to the [Runner]'s global timeout.
+type ActionTimeouts struct {
+	Login, TabSelect, SaveDialog, TableLoad, Credits time.Duration
+}
+
+func (t *ActionTimeouts) fill(fallback time.Duration) {
+	for _, timeout := range []*time.Duration{
+		&t.Login,
+		&t.TabSelect,
+		&t.SaveDialog,
+		&t.TableLoad,
+		&t.Credits,
+	} {
+		if *timeout <= 0 {
+			*timeout = fallback
+		}
+	}
+}
+
 // RunnerOpt represents a function that is used to configure an instance of [Runner].
 type RunnerOpt func(r *Runner)
 
@@ -71,6 +163,81 @@ func Annoyances(values []string) RunnerOpt {
 	}
 }
 
+// Scorer is a [RunnerOpt] func that configures the [scoring.Scorer] computing the score of every lead
+// before it's written.
+func Scorer(s scoring.Scorer) RunnerOpt {
+	return func(r *Runner) {
+		r.scorer = s
+	}
+}
+
+// TagRules is a [RunnerOpt] func that configures the [*tagging.Rule]s that tag leads before they're
+// written.
+func TagRules(rules ...*tagging.Rule) RunnerOpt {
+	return func(r *Runner) {
+		r.tagRules = append(r.tagRules, rules...)
+	}
+}
+
+// Timeouts is a [RunnerOpt] func that configures the [Runner]'s time limits for specific browser
+// actions, overriding the global timeout set by [Timeout].
+func Timeouts(t ActionTimeouts) RunnerOpt {
+	return func(r *Runner) {
+		r.timeouts = t
+	}
+}
+
+// AnnoyanceTimeout is a [RunnerOpt] func that configures the time limit shared by all annoyance
+// checks performed at once.
+func AnnoyanceTimeout(t time.Duration) RunnerOpt {
+	return func(r *Runner) {
+		r.annoyanceTimeout = t
+	}
+}
+
+// CaptchaSolver is a [RunnerOpt] func that configures the [Runner] to use the provided
+// [actions.CaptchaSolver] when a security challenge is encountered while logging in.
+func CaptchaSolver(solver actions.CaptchaSolver) RunnerOpt {
+	return func(r *Runner) {
+		r.captchaSolver = solver
+	}
+}
+
+// BlacklistFile is a [RunnerOpt] func that configures a file listing the accounts which must no
+// longer be used (see [Runner.Blacklist]). The file is read again whenever it changes.
+func BlacklistFile(file string) RunnerOpt {
+	return func(r *Runner) {
+		r.blacklist.file = file
+	}
+}
+
+// BlockResources is a [RunnerOpt] func that configures the requests which the [Runner]'s browsers
+// block (see [actions.ResourceBlocker]).
+func BlockResources(blocker *actions.ResourceBlocker) RunnerOpt {
+	return func(r *Runner) {
+		r.blocker = blocker
+	}
+}
+
+// BrowserCacheDir is a [RunnerOpt] func that configures a directory in which the [Runner]'s
+// browsers keep their HTTP cache, so that Apollo's static assets are only downloaded once rather than
+// by each browser launched (e.g. for every job or when recycling a browser). The cache outlives the
+// run, so later runs start with a warm cache too.
+func BrowserCacheDir(dir string) RunnerOpt {
+	return func(r *Runner) {
+		r.browserCacheDir = dir
+	}
+}
+
+// BulkSave is a [RunnerOpt] func that configures the [Runner] to save all of the leads of an account
+// at once with Apollo's 'Select all N people' bulk action when it's offered, instead of saving them
+// page by page (see [actions.BulkSaveLeads]).
+func BulkSave(b bool) RunnerOpt {
+	return func(r *Runner) {
+		r.bulkSave = b
+	}
+}
+
 // CookieFile is a [RunnerOpt] func that specifies the path to a file containing login cookies
 // for the provided Apollo accounts.
 func CookieFile(file string) RunnerOpt {
@@ -86,6 +253,22 @@ func CsvOutput() RunnerOpt {
 	}
 }
 
+// CreditHistory is a [RunnerOpt] func that specifies whether or not the [Runner] keeps a history of
+// every credit usage fetch in the output directory.
+func CreditHistory(b bool) RunnerOpt {
+	return func(r *Runner) {
+		r.useCreditHistory = b
+	}
+}
+
+// CreditLocales is a [RunnerOpt] func that configures additional locales used to parse Apollo's credits
+// page. They are tried before [actions.DefaultCreditLocales].
+func CreditLocales(locales ...*actions.CreditLocale) RunnerOpt {
+	return func(r *Runner) {
+		r.creditLocales = append(r.creditLocales, locales...)
+	}
+}
+
 // Debug is a [RunnerOpt] func that configures the [Runner] to print useful
 // debugging information.
 func Debug(b bool) RunnerOpt {
@@ -94,6 +277,24 @@ func Debug(b bool) RunnerOpt {
 	}
 }
 
+// DeviceProfiles is a [RunnerOpt] func that specifies whether or not the [Runner] gives each account a
+// device profile, which makes its browser look like the same device across runs.
+func DeviceProfiles(b bool) RunnerOpt {
+	return func(r *Runner) {
+		r.deviceProfiles = b
+	}
+}
+
+// MobileView is a [RunnerOpt] func that specifies whether or not the [Runner] emulates phones, whose
+// viewport, touch screen and user agent make Apollo serve its mobile web layout, which is then
+// scraped with [actions.MobileUI]'s selectors. Each account is given one of the
+// [fingerprint.MobileProfiles] like [DeviceProfiles] gives it a desktop one.
+func MobileView(b bool) RunnerOpt {
+	return func(r *Runner) {
+		r.mobileView = b
+	}
+}
+
 // FetchCredits is a [RunnerOpt] func that configures the [Runner] to fetch the
 // credits for each [models.Account] before scraping.
 func FetchCredits(b bool) RunnerOpt {
@@ -103,7 +304,8 @@ func FetchCredits(b bool) RunnerOpt {
 }
 
 // Tab is a [RunnerOpt] func that configures the [Runner] to scrape leads from
-// the specified tab on Apollo.
+// the specified tab on Apollo. Leads are saved to the account's list from the 'new' tab,
+// while the 'saved' and 'total' tabs are scraped without saving anything (see [Runner.scrapeOnly]).
 func Tab(tab string) RunnerOpt {
 	return func(r *Runner) {
 		switch tab {
@@ -119,6 +321,12 @@ func Tab(tab string) RunnerOpt {
 	}
 }
 
+// scrapeOnly returns true if the [Runner]'s tab lists leads which are already saved, whose jobs scrape
+// the tab's leads straight from the account's search rather than saving them to its list first.
+func (r *Runner) scrapeOnly() bool {
+	return r.tab == actions.SavedTab || r.tab == actions.TotalTab
+}
+
 // Headless is a [RunnerOpt] func that configures whether or not the [Runner] launches
 // the browser in headless mode.
 func Headless(b bool) RunnerOpt {
@@ -127,6 +335,16 @@ func Headless(b bool) RunnerOpt {
 	}
 }
 
+// VirtualDisplay is a [RunnerOpt] func that configures the [Runner] to launch headful browsers, which
+// pass Apollo's checks more reliably than headless ones, even on servers without a display. On
+// Linux hosts without one, the browsers run on a virtual display started with Xvfb, or in Chrome's
+// new headless mode if Xvfb isn't installed. It takes precedence over [Headless].
+func VirtualDisplay(b bool) RunnerOpt {
+	return func(r *Runner) {
+		r.virtualDisplay = b
+	}
+}
+
 // JsonOutput is a [RunnerOpt] func that sets the desired output format to CSV.
 func JsonOutput() RunnerOpt {
 	return func(r *Runner) {
@@ -141,6 +359,39 @@ func Dailyimit(l int) RunnerOpt {
 	}
 }
 
+// Notifiers is a [RunnerOpt] func that configures [Notifier]s which are notified of noteworthy
+// events, e.g. a job finishing or failing.
+func Notifiers(notifiers ...Notifier) RunnerOpt {
+	return func(r *Runner) {
+		r.notifiers = append(r.notifiers, notifiers...)
+	}
+}
+
+// PageHooks is a [RunnerOpt] func that configures [PageHook]s which receive the rendered HTML of every
+// page of leads that's scraped.
+func PageHooks(hooks ...PageHook) RunnerOpt {
+	return func(r *Runner) {
+		r.pageHooks = append(r.pageHooks, hooks...)
+	}
+}
+
+// LLMExtraction is a [RunnerOpt] func that configures an [*extract.Extractor] to extract the leads of
+// pages with when the scrape script finds none of the results they show, e.g. after Apollo changed its
+// markup. The extractor's limits cap what this costs.
+func LLMExtraction(extractor *extract.Extractor) RunnerOpt {
+	return func(r *Runner) {
+		r.extractor = extractor
+	}
+}
+
+// WatchAccounts is a [RunnerOpt] func that configures the [Runner] to merge the accounts added to the
+// provided file (the input file) into its queue whenever the file changes or SIGHUP is received.
+func WatchAccounts(file string) RunnerOpt {
+	return func(r *Runner) {
+		r.accounts.file = file
+	}
+}
+
 // OutputDir is a [RunnerOpt] func that specifies the output directory for [Runner]'s output files.
 func OutputDir(outputDir string) RunnerOpt {
 	return func(r *Runner) {
@@ -148,6 +399,172 @@ func OutputDir(outputDir string) RunnerOpt {
 	}
 }
 
+// OutputLayout is a [RunnerOpt] func that configures how the output files are laid out, e.g. whether
+// they're compressed or partitioned by size or date.
+func OutputLayout(opts ...io.LeadWriterOpt) RunnerOpt {
+	return func(r *Runner) {
+		r.outputLayout = append(r.outputLayout, opts...)
+	}
+}
+
+// OutputTemplate is a [RunnerOpt] func that specifies the name of the files that scraped leads are
+// written to. The placeholders '{list}', '{account}', '{run-id}', '{job-id}' and '{date}' are replaced
+// with the list's name, the account's email, the run's ID, the job's ID and the current date.
+func OutputTemplate(t string) RunnerOpt {
+	return func(r *Runner) {
+		r.outputTemplate = t
+	}
+}
+
+// Journal is a [RunnerOpt] func that specifies whether or not the [Runner] keeps an append-only journal
+// (in JSONL format) of every action taken by each account.
+func Journal(b bool) RunnerOpt {
+	return func(r *Runner) {
+		r.useJournal = b
+	}
+}
+
+// ApolloURL is a [RunnerOpt] func that specifies the URL of the Apollo app that the [Runner] scrapes,
+// which defaults to [actions.DefaultApolloURL], e.g. that of a mirrored domain or of a mock of Apollo
+// in tests. Accounts served from another domain can override it with their 'base-url' column (see
+// [Regions]).
+func ApolloURL(url string) RunnerOpt {
+	return func(r *Runner) {
+		r.apolloURL = url
+	}
+}
+
+// RecordFixtures is a [RunnerOpt] func that specifies a directory in which snapshots of the 'People'
+// pages visited by each job are saved as fixtures for testing page actions. Fixtures aren't recorded
+// if dir is empty.
+func RecordFixtures(dir string) RunnerOpt {
+	return func(r *Runner) {
+		r.fixtureDir = dir
+	}
+}
+
+// LeadWriters is a [RunnerOpt] func that configures additional [io.LeadWriter]s that scraped leads
+// are written to, alongside the output file.
+func LeadWriters(writers ...io.LeadWriter) RunnerOpt {
+	return func(r *Runner) {
+		for _, writer := range writers {
+			r.sinks = append(r.sinks, io.Sink{Name: "lead-writer", Writer: writer})
+		}
+	}
+}
+
+// AsyncWrites is a [RunnerOpt] func that configures the [Runner] to write leads to its sinks in the
+// background, so that slow sinks don't hold up scraping, queueing up to the given number of pages of
+// leads per job. Once the queue is full, scraping waits for the sinks to catch up, and the queued
+// leads are always written before a job ends. A zero value writes leads synchronously.
+func AsyncWrites(queue int) RunnerOpt {
+	return func(r *Runner) {
+		r.asyncWrites = queue
+	}
+}
+
+// Sinks is a [RunnerOpt] func that configures additional named sinks that scraped leads are written
+// to, alongside the output file. A sink failing to write leads is logged without affecting the
+// others.
+func Sinks(sinks ...io.Sink) RunnerOpt {
+	return func(r *Runner) {
+		r.sinks = append(r.sinks, sinks...)
+	}
+}
+
+// Dedupe is a [RunnerOpt] func that configures a [*dedupe.Store] of the leads captured by every
+// account, across runs. Leads which are already in it aren't saved again.
+func Dedupe(s *dedupe.Store) RunnerOpt {
+	return func(r *Runner) {
+		r.dedupe = s
+	}
+}
+
+// IgnoreTimeouts is a [RunnerOpt] func that discards the timeouts of the accounts carried over
+// from previous runs, so that every account is eligible right away.
+func IgnoreTimeouts(b bool) RunnerOpt {
+	return func(r *Runner) {
+		r.ignoreTimeouts = b
+	}
+}
+
+// ProgressEvents is a [RunnerOpt] func that publishes the entries of the accounts' journals (e.g.
+// jobs starting, pages being saved and scraped, and errors) on the provided [*events.Stream], whether
+// or not journaling is enabled.
+func ProgressEvents(s *events.Stream) RunnerOpt {
+	return func(r *Runner) {
+		r.events = s
+	}
+}
+
+// Limits is a [RunnerOpt] func that configures a [*limiter.Limiter] which caps the number of leads saved
+// per hour and the number of concurrent logins across all accounts.
+func Limits(l *limiter.Limiter) RunnerOpt {
+	return func(r *Runner) {
+		r.limiter = l
+	}
+}
+
+// MaxJobDuration is a [RunnerOpt] func that configures how long a single job may run for. A job that
+// exceeds it is aborted and requeued, and [Runner.Start] returns [ErrorMaxJobDuration].
+func MaxJobDuration(t time.Duration) RunnerOpt {
+	return func(r *Runner) {
+		r.maxJobDuration = t
+	}
+}
+
+// MaxRuntime is a [RunnerOpt] func that configures how long the [Runner] may run for. Once it is exceeded,
+// the current job is aborted and requeued, and [Runner.Start] returns [ErrorMaxRuntime].
+func MaxRuntime(t time.Duration) RunnerOpt {
+	return func(r *Runner) {
+		r.maxRuntime = t
+	}
+}
+
+// MaxBrowserMemory is a [RunnerOpt] func that configures the [Runner] to restart the browser (restoring
+// the session from cookies) when its resident memory usage exceeds the given number of bytes. A zero value
+// disables this check.
+func MaxBrowserMemory(bytes uint64) RunnerOpt {
+	return func(r *Runner) {
+		r.maxBrowserMemory = bytes
+	}
+}
+
+// OverlapScrape is a [RunnerOpt] func that configures the [Runner] to scrape the pages of a list in a
+// second tab as soon as they have been saved, instead of waiting for all of the leads to be saved.
+func OverlapScrape(b bool) RunnerOpt {
+	return func(r *Runner) {
+		r.overlapScrape = b
+	}
+}
+
+// PipelineScrape is a [RunnerOpt] func that configures the [Runner] to scrape each page of a list from
+// a snapshot of its table while the next page loads, rather than waiting for the page to be scraped
+// before moving on. Emails are never revealed when pipelining, which doesn't matter for the leads of
+// a list, as saving them reveals their emails.
+func PipelineScrape(b bool) RunnerOpt {
+	return func(r *Runner) {
+		r.pipelineScrape = b
+	}
+}
+
+// PauseFile is a [RunnerOpt] func that configures a control file which pauses the [Runner] after the
+// current page for as long as it exists. The [Runner] can also be paused with SIGUSR1 and resumed with
+// SIGUSR2.
+func PauseFile(file string) RunnerOpt {
+	return func(r *Runner) {
+		r.pause.file = file
+	}
+}
+
+// RecyclePages is a [RunnerOpt] func that configures the [Runner] to replace the scraping page with a new one
+// after every n pages. A zero value disables recycling.
+func RecyclePages(n int) RunnerOpt {
+	return func(r *Runner) {
+		r.recyclePages = n
+	}
+}
+
 // SaveProgress is a [RunnerOpt] func that specifies whether or not the [Runner] saves the intermediary state
 // for each of the [models.Account]s.
 func SaveProgress(b bool) RunnerOpt {
@@ -156,6 +573,39 @@ func SaveProgress(b bool) RunnerOpt {
 	}
 }
 
+// StallTimeout is a [RunnerOpt] func that configures how long a job may go without making any progress
+// before it is aborted and requeued. A zero value disables the watchdog.
+func StallTimeout(t time.Duration) RunnerOpt {
+	return func(r *Runner) {
+		r.stallTimeout = t
+	}
+}
+
+// Scheduler is a [RunnerOpt] func that configures a [JobScheduler] which decides the order in which
+// jobs are run.
+func Scheduler(s JobScheduler) RunnerOpt {
+	return func(r *Runner) {
+		r.scheduler = s
+	}
+}
+
+// Snapshots is a [RunnerOpt] func that configures how the [Runner] captures snapshots of the pages
+// on which errors are encountered.
+func Snapshots(opts actions.SnapshotOptions) RunnerOpt {
+	return func(r *Runner) {
+		r.snapshots = opts
+	}
+}
+
+// SplitSearches is a [RunnerOpt] func that configures the [Runner] to split the lists which have more
+// leads than Apollo shows with the provided [*splitter.Splitter], so that all of their leads can be
+// scraped.
+func SplitSearches(s *splitter.Splitter) RunnerOpt {
+	return func(r *Runner) {
+		r.splitter = s
+	}
+}
+
 // Stealth is a [RunnerOpt] func that specifies whether or not the [Runner] launches the browser in stealth mode.
 func Stealth(s bool) RunnerOpt {
 	return func(r *Runner) {
@@ -170,53 +620,199 @@ func Timeout(t time.Duration) RunnerOpt {
 	}
 }
 
-// VpnManager is a [RunnerOpt] func that configures the [Runner] to utilise OpenVPN for scraping leads.
-func VpnManager(v *openvpn.Manager) RunnerOpt {
+// WatchAnnoyances is a [RunnerOpt] func that configures the [Runner] to watch for annoyances in the
+// background and remove them as they appear, instead of checking for them at fixed points.
+func WatchAnnoyances(b bool) RunnerOpt {
+	return func(r *Runner) {
+		r.watchAnnoyances = b
+	}
+}
+
+// WarmUp is a [RunnerOpt] func that configures the warm-up sessions of accounts whose 'warm-up' field is
+// set. Such accounts run one session per day, decrementing the field, instead of saving leads until it
+// reaches zero.
+func WarmUp(opts actions.WarmUpOptions) RunnerOpt {
+	return func(r *Runner) {
+		r.warmUpOpts = opts
+	}
+}
+
+// WindowJitter is a [RunnerOpt] func that specifies by how much the activity windows of accounts are
+// randomly shifted each day, so that accounts don't start and stop at the exact same time every day.
+func WindowJitter(d time.Duration) RunnerOpt {
+	return func(r *Runner) {
+		r.windowJitter = d
+	}
+}
+
+// VpnManager is a [RunnerOpt] func that configures the [Runner] to utilise a VPN (e.g. OpenVPN) for
+// scraping leads.
+func VpnManager(v VpnProvider) RunnerOpt {
 	return func(r *Runner) {
 		r.vpn = v
 	}
 }
 
+// Failover is a [RunnerOpt] func that configures what the [Runner] does when a job's VPN fails
+// to connect and no backup config is left, and the proxies (e.g. 'socks5://127.0.0.1:1080') it
+// may fall back to, in order.
+func Failover(policy FailoverPolicy, proxies ...string) RunnerOpt {
+	return func(r *Runner) {
+		r.failoverPolicy, r.proxies = policy, proxies
+	}
+}
+
+// CreditHistoryDir is the directory (inside the output directory) in which the history of credit usage
+// is kept.
+const CreditHistoryDir string = "credits"
+
+// ArtifactsDir is the directory (inside the output directory) into which the files downloaded by
+// browsers, such as CSV exports and invoices, are collected.
+const ArtifactsDir string = "artifacts"
+
 // New returns a newly insantiated and configured instance of [Runner].
 func New(accounts []*models.Account, opts ...RunnerOpt) (*Runner, error) {
 	r := &Runner{
-		limit:     500,
-		timeout:   60 * time.Second,
-		outputDir: "./apollo-output",
+		limit:            500,
+		recyclePages:     10,
+		annoyanceTimeout: 5 * time.Second,
+		timeout:          60 * time.Second,
+		outputDir:        "./apollo-output",
+		outputTemplate:   "{list}",
+		status:           newStatusTracker(),
+		warmUpOpts:       actions.WarmUpOptions{Duration: 20 * time.Minute, Contacts: 5},
+		errorBackoff:     defaultErrorBackoff,
+		maxErrorBackoff:  defaultMaxErrorBackoff,
 	}
 
 	for _, optFn := range opts {
 		optFn(r)
 	}
+	r.timeouts.fill(r.timeout)
+	if r.sim != nil {
+		r.prepareSimulation()
+	}
+	r.display = r.resolveDisplay()
+	if err := r.prepareBrowser(); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if r.runID, err = newID(); err != nil {
+		return nil, err
+	}
+
+	if err := r.prepareCampaigns(); err != nil {
+		return nil, err
+	}
 
 	r.jobs = newQueue(accounts)
+	r.accounts.known = make(map[string]bool, len(accounts))
 	for _, job := range r.jobs.iter() {
-		if job.acc.CreditRefresh == nil {
-			job.acc.CreditRefresh = &models.Time{}
+		if err := prepareJob(job); err != nil {
+			return nil, err
 		}
-
-		if job.acc.Timeout == nil {
-			job.acc.Timeout = &models.Time{}
+		if err := r.joinCampaign(job); err != nil {
+			return nil, err
+		}
+		if err := r.resolveApolloURL(job); err != nil {
+			return nil, err
 		}
+		r.accounts.known[job.acc.Email] = true
+		r.results.track(job)
 	}
 
-	if r.cookieFile != "" {
-		var accCookies map[string][]*proto.NetworkCookie
-		if err := io.ReadRecords(r.cookieFile, &accCookies); err != nil {
-			return nil, fmt.Errorf("failed to read cookie file: %v", err)
+	if err := r.loadCookies(r.jobs.iter()); err != nil {
+		return nil, err
+	}
+
+	r.restoreTimeouts(r.jobs.iter())
+	r.assignProfiles(r.jobs.iter())
+	r.poolCredits(r.jobs.iter())
+
+	r.errorDir = filepath.Join(r.outputDir, "errors")
+	if err := os.MkdirAll(r.errorDir, 0755); err != nil {
+		return nil, err
+	}
+
+	if r.useCreditHistory {
+		if r.creditHistory, err = credits.New(filepath.Join(r.outputDir, CreditHistoryDir)); err != nil {
+			return nil, err
 		}
+	}
 
-		for _, job := range r.jobs.iter() {
-			if cookies, ok := accCookies[job.acc.Email]; ok {
-				job.acc.SetLoginCookies(cookies)
-			}
+	if r.useJournal {
+		if r.journal, err = journal.New(filepath.Join(r.outputDir, "journal")); err != nil {
+			return nil, err
 		}
 	}
 
-	r.errorDir = filepath.Join(r.outputDir, "errors")
-	if err := os.MkdirAll(r.errorDir, 0755); err != nil {
+	if r.artifacts, err = artifacts.NewCollector(filepath.Join(r.outputDir, ArtifactsDir)); err != nil {
 		return nil, err
 	}
 
+	if r.fixtureDir != "" {
+		if r.fixtures, err = fixture.NewRecorder(r.fixtureDir); err != nil {
+			return nil, err
+		}
+	}
+
 	return r, nil
 }
+
+// prepareJob fills in the defaults of the job's account and parses its activity window.
+func prepareJob(job *job) (err error) {
+	if job.acc.CreditRefresh == nil {
+		job.acc.CreditRefresh = &models.Time{}
+	}
+
+	if job.acc.Timeout == nil {
+		job.acc.Timeout = &models.Time{}
+	}
+
+	if job.acc.Window != "" {
+		if job.window, err = parseWindow(job.acc.Window, job.acc.Timezone); err != nil {
+			return fmt.Errorf("%s: %w", job.acc.Email, err)
+		}
+	}
+
+	return nil
+}
+
+// loadCookies sets the login cookies of the jobs' accounts from the [Runner]'s cookie file (if any).
+func (r *Runner) loadCookies(jobs iter.Seq2[int, *job]) error {
+	if r.cookieFile == "" {
+		return nil
+	}
+
+	var accCookies map[string][]*proto.NetworkCookie
+	if err := io.ReadRecords(r.cookieFile, &accCookies); err != nil {
+		return fmt.Errorf("failed to read cookie file: %v", err)
+	}
+
+	for _, job := range jobs {
+		if cookies, ok := accCookies[job.acc.Email]; ok {
+			job.acc.SetLoginCookies(cookies)
+		}
+	}
+
+	return nil
+}
+
+// RunID returns the unique ID of the [Runner]'s run, which is attached to every scraped lead.
+func (r *Runner) RunID() string {
+	return r.runID
+}
+
+// newID returns a random (version 4) UUID, used for run and job IDs.
+func newID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
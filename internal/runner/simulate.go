@@ -0,0 +1,287 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"path/filepath"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/actions"
+	"github.com/devsheke/scrapollo/internal/journal"
+	"github.com/devsheke/scrapollo/internal/models"
+	"github.com/rs/zerolog/log"
+)
+
+// SimulationDir is the directory (inside the output directory) that the outputs of simulated runs
+// are written to, so that they never mix with those of real runs.
+const SimulationDir string = "simulation"
+
+// ErrorSimulatedFailure is returned by the actions of simulated jobs which fail (see
+// [Simulation.ErrorRate]).
+var ErrorSimulatedFailure = errors.New("simulated failure")
+
+// maxSimulatedRetries is the number of times in a row that a simulated job retries a failed page,
+// like real jobs do, before giving up.
+const maxSimulatedRetries = 5
+
+// creditPeriod is the time after which the credits of simulated accounts are renewed.
+const creditPeriod = 30 * 24 * time.Hour
+
+// Simulation configures a simulated run (see [Simulate]). Unset values fall back to their defaults.
+type Simulation struct {
+	// LoginLatency, PageLatency and ScrapeLatency are the average time that logging in, saving a
+	// page of leads and scraping a page of saved leads take. Every action takes between half and
+	// one and a half times its average. They default to 30, 20 and 5 seconds.
+	LoginLatency, PageLatency, ScrapeLatency time.Duration
+
+	// ErrorRate is the probability (from 0 to 1) that any of the actions fails.
+	ErrorRate float64
+
+	// PageSize is the number of leads per page, which defaults to 25.
+	PageSize int
+
+	// Credits is the number of credits that accounts are given, if the [Runner] fetches credits,
+	// whenever their credits are renewed (every 30 days). It defaults to 10000.
+	Credits int
+
+	// Seed seeds the random latencies and failures, so that simulations can be repeated.
+	Seed uint64
+
+	// Horizon is the longest time that the run is simulated for, which defaults to 90 days. Like
+	// the maximum runtime of real runs, the simulation stops with [ErrorMaxRuntime] once it's over,
+	// e.g. if accounts keep running out of credits.
+	Horizon time.Duration
+
+	rand *rand.Rand
+}
+
+// Simulate is a [RunnerOpt] func that configures the [Runner] to simulate its run instead of running
+// it: the jobs go through the queue, scheduling, activity windows, daily limits, caps, credit pools
+// and time budgets like in a real run, but no browser is launched and every action of the jobs is
+// simulated, with synthetic latencies and failures, by a clock that skips ahead rather than wait.
+// This takes seconds even for a plan spanning weeks, which makes it possible to check how a
+// configuration plays out before running it.
+//
+// Synthetic leads are written to the output files, in the [SimulationDir] of the output directory
+// (campaigns' output directories included), but not to other sinks. Notifiers, VPNs and the dedupe
+// store aren't used, and the caps of limiters aren't shared with real runs.
+func Simulate(sim Simulation) RunnerOpt {
+	return func(r *Runner) {
+		r.sim = &sim
+	}
+}
+
+// prepareSimulation isolates a simulated run from real ones and sets its clock, starting now.
+func (r *Runner) prepareSimulation() {
+	sim := r.sim
+	sim.LoginLatency = orDefault(sim.LoginLatency, 30*time.Second)
+	sim.PageLatency = orDefault(sim.PageLatency, 20*time.Second)
+	sim.ScrapeLatency = orDefault(sim.ScrapeLatency, 5*time.Second)
+	sim.PageSize = orDefault(sim.PageSize, 25)
+	sim.Credits = orDefault(sim.Credits, 10000)
+	sim.Horizon = orDefault(sim.Horizon, 90*24*time.Hour)
+	sim.rand = rand.New(rand.NewPCG(sim.Seed, sim.Seed))
+
+	clock := newVirtualClock(time.Now())
+	r.clock = clock
+
+	r.outputDir = filepath.Join(r.outputDir, SimulationDir)
+	if r.limiter != nil {
+		r.limiter = r.limiter.Simulation(clock.Now)
+	}
+	for _, c := range r.campaigns {
+		c.OutputDir = ""
+		if c.Limiter != nil {
+			c.Limiter = c.Limiter.Simulation(clock.Now)
+		}
+	}
+
+	r.sinks, r.notifiers, r.vpn, r.dedupe = nil, nil, nil, nil
+}
+
+// orDefault returns v, or def if v is the zero value.
+func orDefault[T comparable](v, def T) T {
+	var zero T
+	if v == zero {
+		return def
+	}
+	return v
+}
+
+// wait simulates an action that takes about the provided average time, returning whether it
+// failed.
+func (s *Simulation) wait(c clock, average time.Duration) (failed bool) {
+	c.Sleep(average/2 + time.Duration(s.rand.Int64N(int64(average)+1)))
+	return s.rand.Float64() < s.ErrorRate
+}
+
+// runJob runs the job, or simulates it if the [Runner] simulates its run. Jobs whose account has an
+// API key are run through Apollo's API rather than a browser, unless only their lists are exported
+// through it (see [APIExport]).
+func (r *Runner) runJob(job *job) error {
+	if r.sim != nil {
+		return r.simulateJob(job)
+	}
+
+	if job.acc.APIKey != "" && !r.apiExport {
+		return r.runAPIJob(job)
+	}
+
+	return r.saveLeads(job)
+}
+
+// simulateJob simulates a run of the job, mirroring [Runner.saveLeads].
+func (r *Runner) simulateJob(job *job) error {
+	sim := r.sim
+	deadline, cause := r.budget(job)
+
+	if sim.wait(r.clock, sim.LoginLatency) {
+		return fmt.Errorf("failed to log in: %w", ErrorSimulatedFailure)
+	}
+	r.record(job, journal.Entry{Action: journal.ActionLogin})
+
+	if job.acc.WarmUp > 0 {
+		job.acc.WarmUp--
+		r.record(job, journal.Entry{Action: journal.ActionWarmUp})
+		return ErrorWarmingUp
+	}
+
+	if r.fetchCredits {
+		if refresh, ok := job.acc.CreditRefresh.Get(); !ok || !r.now().Before(refresh) {
+			job.acc.Credits = sim.Credits
+			job.acc.CreditRefresh.Set(r.now().Add(creditPeriod))
+		}
+		r.shareCredits(job)
+	}
+
+	if _, ok := job.startedAt.Get(); !ok {
+		r.restoreSavedToday(job)
+	}
+
+	if _, ok := job.startedAt.Get(); !ok {
+		job.start(r.now())
+	}
+
+	for retries := 0; ; {
+		if retries >= maxSimulatedRetries {
+			return ErrorSimulatedFailure
+		}
+
+		if !deadline.IsZero() && !r.now().Before(deadline) {
+			return cause
+		}
+
+		if err := r.checkBlacklist(job); err != nil {
+			return err
+		}
+
+		if job.acc.IsDone() || r.scrapeOnly() {
+			return r.simulateScrape(job)
+		}
+
+		if job.hitDailyLimit(r.dailyLimit(job), r.now()) {
+			return ErrorDailyLimit
+		}
+
+		if !job.acc.CanScrape() {
+			return ErrorNoCredits
+		}
+
+		count := min(sim.PageSize, job.acc.Target-job.acc.Saved)
+		if err := r.throttleSaves(context.Background(), job, count); err != nil {
+			return err
+		}
+
+		if sim.wait(r.clock, sim.PageLatency) {
+			retries++
+			continue
+		}
+		retries = 0
+
+		r.incrementSaved(job, count)
+		r.record(job, journal.Entry{
+			Action: journal.ActionPageSaved,
+			Page:   (job.acc.Saved + sim.PageSize - 1) / sim.PageSize,
+			Leads:  count,
+		})
+	}
+}
+
+// simulateScrape simulates scraping the leads that the job saved, or as many as its target when the
+// leads are scraped without saving them, writing synthetic leads to its output files.
+func (r *Runner) simulateScrape(job *job) error {
+	sim := r.sim
+	_, writers, drain := r.listWriters(job)
+	defer drain()
+
+	total := job.acc.Saved
+	if r.scrapeOnly() {
+		total = job.acc.Target
+	}
+
+	pages := (total + sim.PageSize - 1) / sim.PageSize
+	for retries := 0; job.pagesScraped < pages; {
+		if retries >= maxSimulatedRetries {
+			return ErrorSimulatedFailure
+		}
+
+		if sim.wait(r.clock, sim.ScrapeLatency) {
+			retries++
+			continue
+		}
+		retries = 0
+
+		number := job.pagesScraped + 1
+		leads := make([]*models.Lead, min(sim.PageSize, total-job.pagesScraped*sim.PageSize))
+		for i := range leads {
+			n := job.pagesScraped*sim.PageSize + i + 1
+			leads[i] = &models.Lead{
+				Name:    fmt.Sprintf("Simulated Lead %d", n),
+				Title:   "Head of Sales",
+				Company: fmt.Sprintf("Simulated Company %d", n),
+				Email:   fmt.Sprintf("lead%d@%s.example.com", n, job.acc.List),
+			}
+		}
+
+		report := &actions.ScrapeReport{Rows: len(leads), Extracted: len(leads)}
+		r.writeLeads(job, writers, number, leads, report)
+	}
+
+	return nil
+}
+
+// simulationDeadline returns the deadline of the simulated run starting now: the end of its horizon,
+// or its maximum runtime if that ends sooner.
+func (r *Runner) simulationDeadline() time.Time {
+	horizon := r.now().Add(r.sim.Horizon)
+	if r.deadline.IsZero() || horizon.Before(r.deadline) {
+		return horizon
+	}
+
+	return r.deadline
+}
+
+// logSimulation logs how long the simulated run would have taken.
+func (r *Runner) logSimulation(started time.Time) {
+	log.Info().
+		Time("started", started).
+		Time("finished", r.now()).
+		Dur("duration", r.now().Sub(started).Round(time.Second)).
+		Msg("finished simulated run")
+}
@@ -0,0 +1,92 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/io"
+	"github.com/devsheke/scrapollo/internal/models"
+)
+
+func TestSimulate(t *testing.T) {
+	dir := t.TempDir()
+	accounts := []*models.Account{
+		{Email: "a@example.com", List: "a", Target: 250, Credits: 1000},
+		{Email: "b@example.com", List: "b", Target: 100, Credits: 1000},
+		{Email: "c@example.com", List: "c", Target: 100, Credits: 50},
+	}
+
+	sim := Simulation{ErrorRate: 0.05, Seed: 42, Horizon: 5 * 24 * time.Hour}
+	r, err := New(accounts, OutputDir(dir), CsvOutput(), Journal(true), Dailyimit(100), Simulate(sim))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// c runs out of credits, so the simulation runs until the end of its horizon.
+	started := time.Now()
+	if err := r.Start(); !errors.Is(err, ErrorMaxRuntime) {
+		t.Fatalf("the simulation stopped with %v; want %v", err, ErrorMaxRuntime)
+	}
+
+	if took := time.Since(started); took > 10*time.Second {
+		t.Errorf("the simulation took %s", took)
+	}
+
+	results := make(map[string]JobResult)
+	for _, result := range r.Results() {
+		results[result.Account] = result
+	}
+
+	// a's daily limit spreads its 250 leads over three days.
+	if a := results["a@example.com"]; a.Runs < 3 {
+		t.Errorf("a was run %d times; want at least 3", a.Runs)
+	}
+
+	if a := results["a@example.com"]; a.Saved != 250 || a.Status != JobFinished || a.Scraped != 250 {
+		t.Errorf("unexpected result of a: %+v", a)
+	}
+	if c := results["c@example.com"]; c.Saved != 50 || c.Status == JobFinished {
+		t.Errorf("unexpected result of c, which runs out of credits: %+v", c)
+	}
+
+	leads, err := io.ReadLeads(filepath.Join(dir, SimulationDir, "a.csv"))
+	if err != nil || len(leads) != 250 {
+		t.Errorf("read %d simulated leads (%v); want 250", len(leads), err)
+	}
+}
+
+func TestSimulateScrapeOnly(t *testing.T) {
+	acc := &models.Account{Email: "a@example.com", List: "a", Target: 60, Credits: 1000}
+
+	sim := Simulation{Seed: 42, Horizon: 24 * time.Hour}
+	r, err := New([]*models.Account{acc}, OutputDir(t.TempDir()), CsvOutput(), Tab("saved"), Simulate(sim))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	// leads are scraped from the saved tab without saving them again or using up any credits.
+	result := r.Results()[0]
+	if result.Status != JobFinished || result.Scraped != 60 || result.Saved != 0 || acc.Credits != 1000 {
+		t.Errorf("unexpected result of a scrape-only job: %+v (credits %d)", result, acc.Credits)
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"sync"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/openvpn"
+)
+
+// RunnerState represents what the [Runner] is currently doing.
+type RunnerState string
+
+// The states a [Runner] can be in.
+const (
+	StateIdle     RunnerState = "idle"
+	StateRunning  RunnerState = "running"
+	StateWaiting  RunnerState = "waiting"
+	StatePaused   RunnerState = "paused"
+	StateFinished RunnerState = "finished"
+)
+
+// Status is a snapshot of the [Runner]'s progress.
+type Status struct {
+	State        RunnerState     `json:"state"`
+	RunID        string          `json:"run-id"`
+	StartedAt    time.Time       `json:"started-at"`
+	CurrentJob   string          `json:"current-job,omitempty"`
+	CurrentJobID string          `json:"current-job-id,omitempty"`
+	LastProgress time.Time       `json:"last-progress"`
+	WaitingUntil time.Time       `json:"waiting-until,omitempty"`
+	PendingJobs  int             `json:"pending-jobs"`
+	Progress     *JobProgress    `json:"progress,omitempty"`
+	Vpn          *openvpn.Status `json:"vpn,omitempty"`
+}
+
+type statusTracker struct {
+	mu     sync.RWMutex
+	status Status
+}
+
+func newStatusTracker() *statusTracker {
+	return &statusTracker{status: Status{State: StateIdle}}
+}
+
+func (s *statusTracker) get() Status {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.status
+}
+
+func (s *statusTracker) update(fn func(*Status)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fn(&s.status)
+}
+
+// progress records that the current job has made progress.
+func (s *statusTracker) progress() {
+	s.update(func(status *Status) {
+		status.LastProgress = time.Now()
+	})
+}
+
+// Status returns a snapshot of the [Runner]'s current progress. It is safe to call
+// concurrently with [Runner.Start].
+func (r *Runner) Status() Status {
+	status := r.status.get()
+	if r.vpn != nil {
+		vpn := r.vpn.Status()
+		status.Vpn = &vpn
+	}
+	return status
+}
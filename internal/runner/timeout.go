@@ -0,0 +1,89 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"iter"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/models"
+	"github.com/rs/zerolog/log"
+)
+
+// maxTimeout is the longest an account can be timed out for, other than the seats of organisations
+// until their pool's credits are renewed (see [untilPoolRefresh]). The runner otherwise times accounts out for a day at most (e.g.
+// after hitting their daily limit) or until their activity window opens, which is less than a week
+// away, so longer timeouts found in an input file are bogus, e.g. written by a host whose clock was
+// off.
+const maxTimeout = 7 * 24 * time.Hour
+
+// untilPoolRefresh reports whether the job's account is a seat of an organisation that is timed out
+// until no later than its credits are renewed, as the seats of organisations whose credit pool is
+// exhausted are (see [Runner.exhaustPool]), which can be weeks.
+func untilPoolRefresh(job *job, timeout time.Time) bool {
+	if orgKey(job) == "" || job.acc.CreditRefresh == nil {
+		return false
+	}
+
+	refresh, ok := job.acc.CreditRefresh.Get()
+	return ok && !timeout.After(refresh)
+}
+
+// restoreTimeouts checks the timeouts of the jobs' accounts, which are carried over from previous
+// runs by progress files. Timeouts which have passed and bogus ones (see [maxTimeout]) are
+// discarded, and the time at which the other accounts will be eligible again is logged. Every
+// timeout is discarded if the [Runner] ignores them.
+func (r *Runner) restoreTimeouts(jobs iter.Seq2[int, *job]) {
+	now := r.now()
+	for _, job := range jobs {
+		t, ok := job.acc.Timeout.Get()
+		if !ok {
+			continue
+		}
+
+		logger := log.With().Str("account", job.acc.Email).Time("until", t).Logger()
+
+		switch {
+		case r.ignoreTimeouts:
+			logger.Debug().Msg("ignoring the account's timeout")
+			job.acc.Timeout.Reset()
+
+		case !now.Before(t):
+			logger.Debug().Msg("the account's timeout has passed")
+			job.acc.Timeout.Reset()
+
+		case t.Sub(now) > maxTimeout && !untilPoolRefresh(job, t):
+			logger.Warn().Msg("discarding the account's timeout, which is too far in the future")
+			job.acc.Timeout.Reset()
+
+		default:
+			logger.Info().
+				Dur("remaining", t.Sub(now).Round(time.Second)).
+				Msg("the account is timed out")
+		}
+	}
+}
+
+// timedOut returns the time until which the account is timed out, and false if it isn't. Timeouts
+// which have passed by now are reset.
+func timedOut(acc *models.Account, now time.Time) (time.Time, bool) {
+	t, ok := acc.Timeout.Get()
+	if ok && !now.Before(t) {
+		acc.Timeout.Reset()
+		return time.Time{}, false
+	}
+
+	return t, ok
+}
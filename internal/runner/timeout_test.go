@@ -0,0 +1,66 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/models"
+)
+
+func TestRestoreTimeouts(t *testing.T) {
+	now := time.Now()
+	accounts := []*models.Account{
+		{Email: "none@example.com", Timeout: models.NewTime()},
+		{Email: "passed@example.com", Timeout: models.NewTimeValid(now.Add(-time.Hour))},
+		{Email: "pending@example.com", Timeout: models.NewTimeValid(now.Add(time.Hour))},
+		{Email: "bogus@example.com", Timeout: models.NewTimeValid(now.AddDate(1, 0, 0))},
+		{
+			Email:         "pooled@example.com",
+			Org:           "Acme",
+			Timeout:       models.NewTimeValid(now.AddDate(0, 0, 20)),
+			CreditRefresh: models.NewTimeValid(now.AddDate(0, 0, 20)),
+		},
+		{
+			Email:         "unpooled@example.com",
+			Timeout:       models.NewTimeValid(now.AddDate(0, 0, 20)),
+			CreditRefresh: models.NewTimeValid(now.AddDate(0, 0, 20)),
+		},
+		{
+			Email:         "past-refresh@example.com",
+			Org:           "Acme",
+			Timeout:       models.NewTimeValid(now.AddDate(0, 0, 30)),
+			CreditRefresh: models.NewTimeValid(now.AddDate(0, 0, 20)),
+		},
+	}
+
+	r := &Runner{jobs: newQueue(accounts)}
+	r.restoreTimeouts(r.jobs.iter())
+
+	for _, acc := range accounts {
+		_, ok := acc.Timeout.Get()
+		if want := acc.Email == "pending@example.com" || acc.Email == "pooled@example.com"; ok != want {
+			t.Errorf("%s: timed out = %t; want %t", acc.Email, ok, want)
+		}
+	}
+
+	r.ignoreTimeouts = true
+	r.restoreTimeouts(r.jobs.iter())
+
+	if accounts[2].Timeout.Valid() {
+		t.Error("timeout wasn't ignored")
+	}
+}
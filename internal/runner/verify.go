@@ -0,0 +1,124 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/actions"
+	"github.com/devsheke/scrapollo/internal/journal"
+)
+
+// ErrorSaveUnverified is returned when none of the leads that Apollo reported as saved can be found in
+// the account's list (see [VerifySaves]).
+var ErrorSaveUnverified = errors.New("saved leads are missing from the list")
+
+// The size of a list is looked up verifyAttempts times, verifyInterval apart, before a save is deemed
+// to have failed, since Apollo's list counts lag behind its saves.
+const (
+	verifyAttempts = 3
+	verifyInterval = 3 * time.Second
+)
+
+// VerifySaves is a [RunnerOpt] func that configures the [Runner] to check that the leads of every save
+// landed in the account's list, by looking up the list's size in a second tab. Only the leads that are
+// found in the list count toward the account's target and daily limit, and saves whose leads are all
+// missing are retried.
+func VerifySaves(b bool) RunnerOpt {
+	return func(r *Runner) {
+		r.verifySaves = b
+	}
+}
+
+// saveVerifier tracks the size of a job's list between its saves.
+type saveVerifier struct {
+	r    *Runner
+	bw   *browserWrapper
+	job  *job
+	size int
+}
+
+// newSaveVerifier returns a [*saveVerifier] for the job's list, which is nil unless the [Runner]
+// verifies saves.
+func (r *Runner) newSaveVerifier(bw *browserWrapper, job *job) (*saveVerifier, error) {
+	if !r.verifySaves {
+		return nil, nil
+	}
+
+	v := &saveVerifier{r: r, bw: bw, job: job}
+
+	var err error
+	if v.size, err = v.listSize(); err != nil {
+		return nil, err
+	}
+
+	job.log.Debug().Str("list", job.acc.List).Int("size", v.size).Msg("verifying saves")
+	return v, nil
+}
+
+// landed returns how many of the expected number of leads have been added to the list since the last
+// save. It's never more than expected, as other accounts may be saving to the same list.
+func (v *saveVerifier) landed(ctx context.Context, expected int) (int, error) {
+	size := v.size
+	for attempt := range verifyAttempts {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return 0, context.Cause(ctx)
+			case <-v.r.runClock().After(verifyInterval):
+			}
+		}
+
+		var err error
+		if size, err = v.listSize(); err != nil {
+			return 0, err
+		}
+
+		if size-v.size >= expected {
+			break
+		}
+	}
+
+	landed := min(max(size-v.size, 0), expected)
+	v.size = size
+
+	if landed < expected {
+		v.job.log.Warn().
+			Str("list", v.job.acc.List).
+			Int("expected", expected).
+			Int("landed", landed).
+			Msg("saved leads are missing from the list")
+		v.r.record(v.job, journal.Entry{
+			Action: journal.ActionSaveUnverified,
+			List:   v.job.acc.List,
+			Leads:  expected - landed,
+		})
+	}
+
+	return landed, nil
+}
+
+// listSize looks up the size of the job's list in a new tab.
+func (v *saveVerifier) listSize() (int, error) {
+	tab, err := actions.NewPage(v.bw.browser, v.r.stealth, v.job.profile)
+	if err != nil {
+		return 0, err
+	}
+	defer tab.Close()
+
+	return actions.ListSize(actions.WithUIVariant(tab, v.job.ui), v.job.acc.List, v.r.timeouts.TableLoad)
+}
@@ -0,0 +1,69 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrorJobStalled is returned when a job is aborted for not making any progress within
+// the [Runner]'s stall timeout.
+var ErrorJobStalled = errors.New("job made no progress within the stall timeout")
+
+const watchdogSnapshotTimeout time.Duration = 30 * time.Second
+
+// startWatchdog starts a watchdog which aborts the job by cancelling its context if no progress is
+// made within the [Runner]'s stall timeout. A snapshot of the job's current page is grabbed before
+// the job is aborted. The returned function stops the watchdog.
+func (r *Runner) startWatchdog(job *job, cancel context.CancelCauseFunc) (stop func()) {
+	if r.stallTimeout <= 0 {
+		return func() {}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(max(r.stallTimeout/4, time.Second))
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+
+			since := time.Since(r.status.get().LastProgress)
+			if since < r.stallTimeout {
+				continue
+			}
+
+			job.log.Error().
+				Dur("since", since).
+				Msg("job has stalled, aborting")
+
+			if page := job.currentPage(); page != nil {
+				page = page.Context(context.Background()).Timeout(watchdogSnapshotTimeout)
+				r.grabErrorSnapshot(page, job, ErrorJobStalled, "")
+			}
+
+			cancel(ErrorJobStalled)
+			return
+		}
+	}()
+
+	return func() { close(done) }
+}
@@ -0,0 +1,130 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"time"
+)
+
+// ErrorOutsideWindow is returned when a job is stopped because its account's activity window has
+// closed. The job is resumed once the window opens again.
+var ErrorOutsideWindow = errors.New("the account's activity window has closed")
+
+// activityWindow is the time of day during which an account is active, e.g. 9:00-17:00 in the
+// account's time zone. Windows that end before they start span midnight.
+type activityWindow struct {
+	start, end time.Duration
+	loc        *time.Location
+}
+
+// parseWindow parses an activity window in the 'HH:MM-HH:MM' format. The window is in the provided
+// IANA time zone, or the local one if it's empty.
+func parseWindow(window, timezone string) (*activityWindow, error) {
+	from, to, ok := strings.Cut(window, "-")
+	if !ok {
+		return nil, fmt.Errorf("invalid activity window %q: expected 'HH:MM-HH:MM'", window)
+	}
+
+	w := &activityWindow{loc: time.Local}
+
+	var err error
+	if w.start, err = parseTimeOfDay(from); err != nil {
+		return nil, err
+	}
+
+	if w.end, err = parseTimeOfDay(to); err != nil {
+		return nil, err
+	}
+
+	if w.start == w.end {
+		return nil, fmt.Errorf("invalid activity window %q: it must not be empty", window)
+	}
+
+	if timezone != "" {
+		if w.loc, err = time.LoadLocation(timezone); err != nil {
+			return nil, err
+		}
+	}
+
+	return w, nil
+}
+
+// ValidateWindow returns an error if the activity window (in the 'HH:MM-HH:MM' format) or the IANA
+// time zone of an account are invalid.
+func ValidateWindow(window, timezone string) error {
+	_, err := parseWindow(window, timezone)
+	return err
+}
+
+func parseTimeOfDay(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q: expected 'HH:MM'", s)
+	}
+
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// next returns the bounds of the window that t falls in or, if it doesn't fall in any, the next
+// one. Each day's window is shifted by up to ±jitter, by an amount derived from the seed and the
+// date, so that an account doesn't start and stop at the exact same time every day.
+func (w *activityWindow) next(t time.Time, jitter time.Duration, seed string) (open, close time.Time) {
+	t = t.In(w.loc)
+
+	length := w.end - w.start
+	if length < 0 {
+		length += 24 * time.Hour
+	}
+
+	// yesterday's window may still be open if the window spans midnight.
+	day := time.Date(t.Year(), t.Month(), t.Day()-1, 0, 0, 0, 0, w.loc)
+	for {
+		open = time.Date(day.Year(), day.Month(), day.Day(), 0, int(w.start/time.Minute), 0, 0, w.loc)
+		open = open.Add(shift(day, jitter, seed))
+		close = open.Add(length)
+		if close.After(t) {
+			return open, close
+		}
+
+		day = day.AddDate(0, 0, 1)
+	}
+}
+
+// shift returns the pseudo-random offset within ±jitter of the window on the given day.
+func shift(day time.Time, jitter time.Duration, seed string) time.Duration {
+	if jitter <= 0 {
+		return 0
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(seed + day.Format(time.DateOnly)))
+
+	return time.Duration(h.Sum64()%uint64(2*jitter+1)) - jitter
+}
+
+// window returns the bounds of the job's current or next activity window. The last return value is
+// false if the job's account doesn't have an activity window.
+func (r *Runner) window(job *job) (open, close time.Time, ok bool) {
+	if job.window == nil {
+		return time.Time{}, time.Time{}, false
+	}
+
+	open, close = job.window.next(r.now(), r.windowJitter, job.acc.Email)
+	return open, close, true
+}
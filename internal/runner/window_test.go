@@ -0,0 +1,87 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package runner
+
+import (
+	"testing"
+	"time"
+)
+
+func TestActivityWindow(t *testing.T) {
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Skip("time zone database unavailable:", err)
+	}
+
+	at := func(day, hour, min int) time.Time {
+		return time.Date(2025, time.March, day, hour, min, 0, 0, berlin)
+	}
+
+	tests := []struct {
+		name, window string
+		now          time.Time
+		open, close  time.Time
+	}{
+		{"before", "09:00-17:00", at(4, 8, 0), at(4, 9, 0), at(4, 17, 0)},
+		{"inside", "09:00-17:00", at(4, 12, 30), at(4, 9, 0), at(4, 17, 0)},
+		{"after", "09:00-17:00", at(4, 17, 0), at(5, 9, 0), at(5, 17, 0)},
+		{"overnight", "22:00-06:00", at(5, 2, 0), at(4, 22, 0), at(5, 6, 0)},
+		{"overnight before", "22:00-06:00", at(5, 12, 0), at(5, 22, 0), at(6, 6, 0)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w, err := parseWindow(tt.window, "Europe/Berlin")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			open, close := w.next(tt.now, 0, "test@example.com")
+			if !open.Equal(tt.open) || !close.Equal(tt.close) {
+				t.Errorf("got %s - %s, want %s - %s", open, close, tt.open, tt.close)
+			}
+		})
+	}
+}
+
+func TestActivityWindowJitter(t *testing.T) {
+	w, err := parseWindow("09:00-17:00", "UTC")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2025, time.March, 4, 0, 0, 0, 0, time.UTC)
+	open, close := w.next(now, 15*time.Minute, "test@example.com")
+
+	if d := open.Sub(now.Add(9 * time.Hour)).Abs(); d > 15*time.Minute {
+		t.Errorf("window shifted by %s, want at most 15m", d)
+	}
+
+	if close.Sub(open) != 8*time.Hour {
+		t.Errorf("got window of %s, want 8h", close.Sub(open))
+	}
+
+	if again, _ := w.next(now, 15*time.Minute, "test@example.com"); !again.Equal(open) {
+		t.Errorf("got a different window for the same day: %s and %s", open, again)
+	}
+}
+
+func TestParseWindowErrors(t *testing.T) {
+	for _, window := range []string{"9-17", "09:00", "09:00-09:00", "25:00-26:00"} {
+		if _, err := parseWindow(window, ""); err == nil {
+			t.Errorf("%q: expected an error", window)
+		}
+	}
+}
@@ -0,0 +1,293 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scoring
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/devsheke/scrapollo/internal/models"
+)
+
+// node is a node of an expression's syntax tree. Values are float64s, strings or bools.
+type node interface {
+	eval(lead *models.Lead) (any, error)
+}
+
+type literal struct{ value any }
+
+func (l literal) eval(*models.Lead) (any, error) {
+	return l.value, nil
+}
+
+type field struct {
+	name  string
+	index []int
+}
+
+func (f field) eval(lead *models.Lead) (any, error) {
+	return fieldValue(lead, f.index)
+}
+
+// compiledPattern is the literal right operand of '=~', compiled while parsing.
+type compiledPattern struct{ re *regexp.Regexp }
+
+func (p *compiledPattern) eval(*models.Lead) (any, error) {
+	return p.re, nil
+}
+
+type unary struct {
+	op      string
+	operand node
+}
+
+func (u *unary) eval(lead *models.Lead) (any, error) {
+	v, err := u.operand.eval(lead)
+	if err != nil {
+		return nil, err
+	}
+
+	if u.op == "!" {
+		b, err := toBool(v)
+		return !b, err
+	}
+
+	n, err := toNumber(v)
+	return -n, err
+}
+
+type binary struct {
+	op          string
+	left, right node
+}
+
+func (b *binary) eval(lead *models.Lead) (any, error) {
+	l, err := b.left.eval(lead)
+	if err != nil {
+		return nil, err
+	}
+
+	// the logical operators short-circuit.
+	switch b.op {
+	case "&&", "||":
+		lb, err := toBool(l)
+		if err != nil || lb == (b.op == "||") {
+			return lb, err
+		}
+
+		r, err := b.right.eval(lead)
+		if err != nil {
+			return nil, err
+		}
+		return toBool(r)
+	}
+
+	r, err := b.right.eval(lead)
+	if err != nil {
+		return nil, err
+	}
+
+	switch b.op {
+	case "=~":
+		s, ok := l.(string)
+		if !ok {
+			return nil, errors.New("the left operand of =~ must be a string")
+		}
+
+		re, ok := r.(*regexp.Regexp)
+		if !ok {
+			pattern, ok := r.(string)
+			if !ok {
+				return nil, errors.New("the right operand of =~ must be a string")
+			}
+
+			if re, err = regexp.Compile(pattern); err != nil {
+				return nil, err
+			}
+		}
+
+		return re.MatchString(s), nil
+
+	case "==", "!=":
+		ls, lok := l.(string)
+		rs, rok := r.(string)
+		if lok != rok {
+			return nil, fmt.Errorf("can't compare a string with a number using %s", b.op)
+		}
+
+		equal := ls == rs
+		if !lok {
+			ln, rn, err := toNumbers(l, r)
+			if err != nil {
+				return nil, err
+			}
+			equal = ln == rn
+		}
+
+		return equal == (b.op == "=="), nil
+	}
+
+	ln, rn, err := toNumbers(l, r)
+	if err != nil {
+		return nil, err
+	}
+
+	switch b.op {
+	case "<":
+		return ln < rn, nil
+	case "<=":
+		return ln <= rn, nil
+	case ">":
+		return ln > rn, nil
+	case ">=":
+		return ln >= rn, nil
+	case "+":
+		return ln + rn, nil
+	case "-":
+		return ln - rn, nil
+	case "*":
+		return ln * rn, nil
+	case "/":
+		if rn == 0 {
+			return nil, errors.New("division by zero")
+		}
+		return ln / rn, nil
+	}
+
+	return nil, fmt.Errorf("unknown operator %s", b.op)
+}
+
+type conditional struct {
+	cond, then, otherwise node
+}
+
+func (c *conditional) eval(lead *models.Lead) (any, error) {
+	v, err := c.cond.eval(lead)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := toBool(v)
+	if err != nil {
+		return nil, err
+	}
+
+	if b {
+		return c.then.eval(lead)
+	}
+	return c.otherwise.eval(lead)
+}
+
+type function struct {
+	arity int
+	fn    func(args []any) (any, error)
+}
+
+// functions are the functions available to expressions.
+var functions = map[string]function{
+	"contains": {2, func(args []any) (any, error) {
+		s, substr, err := toStrings(args[0], args[1])
+		return strings.Contains(strings.ToLower(s), strings.ToLower(substr)), err
+	}},
+	"lower": {1, func(args []any) (any, error) {
+		s, err := toString(args[0])
+		return strings.ToLower(s), err
+	}},
+	"len": {1, func(args []any) (any, error) {
+		s, err := toString(args[0])
+		return float64(len([]rune(s))), err
+	}},
+}
+
+type call struct {
+	name string
+	fn   function
+	args []node
+}
+
+func (c *call) eval(lead *models.Lead) (any, error) {
+	args := make([]any, len(c.args))
+	for i, arg := range c.args {
+		v, err := arg.eval(lead)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = v
+	}
+
+	v, err := c.fn.fn(args)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", c.name, err)
+	}
+
+	return v, nil
+}
+
+func toNumber(v any) (float64, error) {
+	switch v := v.(type) {
+	case float64:
+		return v, nil
+	case bool:
+		if v {
+			return 1, nil
+		}
+		return 0, nil
+	}
+
+	return 0, fmt.Errorf("expected a number, got %q", v)
+}
+
+func toNumbers(a, b any) (float64, float64, error) {
+	an, err := toNumber(a)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	bn, err := toNumber(b)
+	return an, bn, err
+}
+
+func toBool(v any) (bool, error) {
+	switch v := v.(type) {
+	case bool:
+		return v, nil
+	case float64:
+		return v != 0, nil
+	case string:
+		return v != "", nil
+	}
+
+	return false, fmt.Errorf("expected a boolean, got %v", v)
+}
+
+func toString(v any) (string, error) {
+	if s, ok := v.(string); ok {
+		return s, nil
+	}
+
+	return "", fmt.Errorf("expected a string, got %v", v)
+}
+
+func toStrings(a, b any) (string, string, error) {
+	as, err := toString(a)
+	if err != nil {
+		return "", "", err
+	}
+
+	bs, err := toString(b)
+	return as, bs, err
+}
@@ -0,0 +1,88 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scoring
+
+import (
+	"testing"
+
+	"github.com/devsheke/scrapollo/internal/models"
+)
+
+func TestExpression(t *testing.T) {
+	lead := &models.Lead{
+		Title:       "VP of Sales",
+		Keywords:    "SaaS,b2b",
+		LeadDetails: models.LeadDetails{EmployeesMin: 51, EmployeesMax: 200, Country: "Germany"},
+	}
+
+	for _, tc := range []struct {
+		expr string
+		want float64
+	}{
+		{`1 + 2 * 3`, 7},
+		{`(1 + 2) * 3`, 9},
+		{`-2 - -3`, 1},
+		{`10 / 4`, 2.5},
+		{`title =~ "(?i)\\bvp\\b"`, 1},
+		{`(title =~ "(?i)\\b(vp|head)\\b") * 10 + (employees_min >= 50 ? 5 : 0) + contains(keywords, "saas")`, 16},
+		{`country == "Germany" && employees_max <= 200`, 1},
+		{`country != "Germany" || !(employees_min > 10)`, 0},
+		{`lower(title) == "vp of sales" ? len(country) : -1`, 7},
+		{`true + true`, 2},
+		{`email == "" ? 0 : 1`, 0},
+	} {
+		e, err := Compile(tc.expr)
+		if err != nil {
+			t.Errorf("Compile(%q): %v", tc.expr, err)
+			continue
+		}
+
+		got, err := e.Score(lead)
+		if err != nil {
+			t.Errorf("Score(%q): %v", tc.expr, err)
+		} else if got != tc.want {
+			t.Errorf("Score(%q) = %v; want %v", tc.expr, got, tc.want)
+		}
+	}
+}
+
+func TestExpressionErrors(t *testing.T) {
+	for _, expr := range []string{
+		`1 +`,
+		`(1 + 2`,
+		`unknown_field > 1`,
+		`nope(title)`,
+		`contains(title)`,
+		`title =~ "("`,
+		`"unterminated`,
+		`1 ? 2`,
+		`1 $ 2`,
+	} {
+		if _, err := Compile(expr); err == nil {
+			t.Errorf("Compile(%q): expected an error", expr)
+		}
+	}
+
+	for _, expr := range []string{`title`, `title + 1`, `1 / 0`, `title == 1`} {
+		e, err := Compile(expr)
+		if err != nil {
+			t.Fatalf("Compile(%q): %v", expr, err)
+		}
+
+		if _, err := e.Score(&models.Lead{Title: "CEO"}); err == nil {
+			t.Errorf("Score(%q): expected an error", expr)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package scoring computes a score for every lead from its fields, so that leads can be sorted by
+// fit right after a scrape. Scores are computed by a [Scorer], which is either an [Expression] of
+// the built-in expression language or provided by a plugin.
+package scoring
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/models"
+)
+
+// Scorer is implemented by types that compute the score of a lead.
+type Scorer interface {
+	// Score returns the score of the lead.
+	Score(lead *models.Lead) (float64, error)
+}
+
+// leadFields maps the names under which a lead's fields are available to expressions (their CSV
+// names, with hyphens replaced by underscores, e.g. 'employees_min') to their indices.
+var leadFields = func() map[string][]int {
+	fields := make(map[string][]int)
+
+	var walk func(t reflect.Type, index []int)
+	walk = func(t reflect.Type, index []int) {
+		for i := range t.NumField() {
+			field := t.Field(i)
+			path := append(append([]int{}, index...), i)
+
+			if field.Anonymous {
+				walk(field.Type, path)
+				continue
+			}
+
+			name := strings.ReplaceAll(field.Tag.Get("csv"), "-", "_")
+			fields[name] = path
+		}
+	}
+	walk(reflect.TypeFor[models.Lead](), nil)
+
+	return fields
+}()
+
+// fieldValue returns the value of a lead's field as a string, a number or, for times, an RFC 3339
+// string.
+func fieldValue(lead *models.Lead, index []int) (any, error) {
+	v := reflect.ValueOf(lead).Elem().FieldByIndex(index)
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Int, reflect.Int64:
+		return float64(v.Int()), nil
+	case reflect.Float64:
+		return v.Float(), nil
+	}
+
+	if t, ok := v.Interface().(time.Time); ok {
+		return t.Format(time.RFC3339), nil
+	}
+
+	return nil, fmt.Errorf("unsupported field type %s", v.Type())
+}
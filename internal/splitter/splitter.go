@@ -0,0 +1,130 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package splitter partitions Apollo searches which have more results than Apollo shows, so that
+// all of their results can be reached by going through each partition in turn. Searches are split
+// by adding a filter to them, e.g. one search per range of employee counts.
+package splitter
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// MaxResults is the number of results of a search that Apollo shows at most (100 pages of 25).
+const MaxResults = 2500
+
+// Dimension is a filter of Apollo's 'People' page that searches are split by. Each of its values
+// makes a partition, so the values must not overlap.
+type Dimension struct {
+	Name string
+
+	// Param is the name of the filter's query parameter in the page's URL.
+	Param  string
+	Values []string
+}
+
+var (
+	// EmployeeRanges splits searches by the number of employees of the leads' companies.
+	EmployeeRanges = Dimension{
+		Name:  "employees",
+		Param: "organizationNumEmployeesRanges[]",
+		Values: []string{
+			"1,10", "11,20", "21,50", "51,100", "101,200", "201,500", "501,1000", "1001,2000",
+			"2001,5000", "5001,10000", "10001,",
+		},
+	}
+
+	// Seniorities splits searches by the leads' seniority.
+	Seniorities = Dimension{
+		Name:  "seniority",
+		Param: "personSeniorities[]",
+		Values: []string{
+			"owner", "founder", "c_suite", "partner", "vp", "head", "director", "manager", "senior",
+			"entry", "intern",
+		},
+	}
+
+	// DefaultDimensions are the dimensions that searches are split by, in order.
+	DefaultDimensions = []Dimension{EmployeeRanges, Seniorities}
+)
+
+// Splitter splits searches along a set of dimensions.
+type Splitter struct {
+	dimensions []Dimension
+}
+
+// New returns a [*Splitter] which splits searches along the provided dimensions in order, or along
+// [DefaultDimensions] if there are none.
+func New(dimensions ...Dimension) *Splitter {
+	if len(dimensions) == 0 {
+		dimensions = DefaultDimensions
+	}
+
+	return &Splitter{dimensions: dimensions}
+}
+
+// Split partitions the search (the URL of a search on Apollo's 'People' page) along the first
+// dimension it isn't filtered by yet, returning the URL of each partition. No partitions are
+// returned if the search is already filtered by every dimension. Results which have no value for
+// the dimension (e.g. leads whose seniority is unknown) aren't part of any partition.
+func (s *Splitter) Split(search string) ([]string, error) {
+	u, err := url.Parse(search)
+	if err != nil {
+		return nil, fmt.Errorf("invalid search %q: %w", search, err)
+	}
+
+	// the app is routed by the URL's fragment, which holds the search's filters.
+	route, rawQuery, _ := strings.Cut(u.EscapedFragment(), "?")
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return nil, fmt.Errorf("invalid search %q: %w", search, err)
+	}
+
+	for _, dim := range s.dimensions {
+		if query.Has(dim.Param) {
+			continue
+		}
+
+		// results are paged from the start in each partition.
+		query.Del("page")
+
+		partitions := make([]string, len(dim.Values))
+		for i, value := range dim.Values {
+			partition := *u
+			partition.RawFragment = route + "?" + encode(query, dim.Param, value)
+			if partition.Fragment, err = url.PathUnescape(partition.RawFragment); err != nil {
+				return nil, err
+			}
+			partitions[i] = partition.String()
+		}
+
+		return partitions, nil
+	}
+
+	return nil, nil
+}
+
+// encode encodes the query with the parameter added to it, leaving the brackets of parameter names
+// unescaped as Apollo does.
+func encode(query url.Values, param, value string) string {
+	encoded := url.Values{}
+	for k, v := range query {
+		encoded[k] = v
+	}
+	encoded.Add(param, value)
+
+	return strings.NewReplacer("%5B", "[", "%5D", "]").Replace(encoded.Encode())
+}
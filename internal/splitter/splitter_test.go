@@ -0,0 +1,61 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package splitter
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestSplit(t *testing.T) {
+	s := New()
+
+	search := "https://app.apollo.io/#/people?page=3&personTitles[]=cto"
+	partitions, err := s.Split(search)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(partitions) != len(EmployeeRanges.Values) {
+		t.Fatalf("got %d partitions, want %d", len(partitions), len(EmployeeRanges.Values))
+	}
+
+	u, err := url.Parse(partitions[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	route, rawQuery, _ := strings.Cut(u.Fragment, "?")
+	query, _ := url.ParseQuery(rawQuery)
+	if route != "/people" || query.Has("page") || query.Get("personTitles[]") != "cto" ||
+		query.Get(EmployeeRanges.Param) != "1,10" {
+		t.Errorf("unexpected partition: %s", partitions[0])
+	}
+
+	// partitions are split along the next dimension.
+	partitions, err = s.Split(partitions[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(partitions) != len(Seniorities.Values) || !strings.Contains(partitions[1], "personSeniorities[]=founder") {
+		t.Errorf("unexpected partitions: %v", partitions)
+	}
+
+	if partitions, _ := s.Split(partitions[0]); partitions != nil {
+		t.Errorf("expected a search filtered by every dimension not to be split, got %v", partitions)
+	}
+}
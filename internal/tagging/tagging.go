@@ -0,0 +1,95 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package tagging tags leads according to user-defined rules, so that they come out of a scrape
+// already segmented, e.g. for routing them to different campaigns.
+package tagging
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/devsheke/scrapollo/internal/models"
+)
+
+// fieldValues are the lead fields that rules can match, by their CSV names.
+var fieldValues = map[string]func(l *models.Lead) string{
+	"name":     func(l *models.Lead) string { return l.Name },
+	"title":    func(l *models.Lead) string { return l.Title },
+	"company":  func(l *models.Lead) string { return l.Company },
+	"location": func(l *models.Lead) string { return l.Location },
+	"industry": func(l *models.Lead) string { return l.Industry },
+	"keywords": func(l *models.Lead) string { return l.Keywords },
+}
+
+// DefaultFields are the fields matched by rules which don't specify any.
+var DefaultFields = []string{"title", "industry", "keywords"}
+
+// Rule tags the leads which have a field matching its pattern.
+type Rule struct {
+	tag     string
+	pattern *regexp.Regexp
+	fields  []string
+}
+
+// NewRule returns a [*Rule] adding the tag to leads for which any of the fields (by their CSV
+// names, [DefaultFields] if none are provided) matches the pattern.
+func NewRule(tag, pattern string, fields ...string) (*Rule, error) {
+	tag = strings.TrimSpace(tag)
+	if tag == "" || strings.Contains(tag, ",") {
+		return nil, fmt.Errorf("invalid tag %q: tags can't be empty or contain commas", tag)
+	}
+
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern for tag %q: %w", tag, err)
+	}
+
+	if len(fields) == 0 {
+		fields = DefaultFields
+	}
+
+	for _, field := range fields {
+		if _, ok := fieldValues[field]; !ok {
+			return nil, fmt.Errorf("invalid field for tag %q: %q", tag, field)
+		}
+	}
+
+	return &Rule{tag: tag, pattern: re, fields: fields}, nil
+}
+
+// Matches reports whether the rule applies to the lead.
+func (r *Rule) Matches(lead *models.Lead) bool {
+	for _, field := range r.fields {
+		if r.pattern.MatchString(fieldValues[field](lead)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Apply sets the lead's tags to those of the rules matching it, in the order of the rules.
+func Apply(lead *models.Lead, rules []*Rule) {
+	var tags []string
+	for _, rule := range rules {
+		if !slices.Contains(tags, rule.tag) && rule.Matches(lead) {
+			tags = append(tags, rule.tag)
+		}
+	}
+
+	lead.Tags = strings.Join(tags, ",")
+}
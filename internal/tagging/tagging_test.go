@@ -0,0 +1,69 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tagging
+
+import (
+	"testing"
+
+	"github.com/devsheke/scrapollo/internal/models"
+)
+
+func TestApply(t *testing.T) {
+	var rules []*Rule
+	for _, r := range []struct {
+		tag, pattern string
+		fields       []string
+	}{
+		{"engineering", `(?i)\b(engineer|cto)\b`, nil},
+		{"saas", `(?i)saas`, []string{"keywords"}},
+		{"founder", `(?i)founder`, []string{"title"}},
+		{"engineering", `(?i)developer`, nil},
+	} {
+		rule, err := NewRule(r.tag, r.pattern, r.fields...)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rules = append(rules, rule)
+	}
+
+	for _, tc := range []struct {
+		lead models.Lead
+		want string
+	}{
+		{models.Lead{Title: "CTO & Co-Founder", Keywords: "saas,b2b"}, "engineering,saas,founder"},
+		{models.Lead{Title: "Lead Developer", Industry: "Software Engineering"}, "engineering"},
+		{models.Lead{Title: "Head of Sales", Company: "SaaS Inc"}, ""},
+	} {
+		Apply(&tc.lead, rules)
+		if tc.lead.Tags != tc.want {
+			t.Errorf("tags of %q = %q; want %q", tc.lead.Title, tc.lead.Tags, tc.want)
+		}
+	}
+}
+
+func TestNewRule(t *testing.T) {
+	for _, tc := range []struct {
+		tag, pattern, field string
+	}{
+		{"", "x", "title"},
+		{"a,b", "x", "title"},
+		{"tag", "(", "title"},
+		{"tag", "x", "email"},
+	} {
+		if _, err := NewRule(tc.tag, tc.pattern, tc.field); err == nil {
+			t.Errorf("NewRule(%q, %q, %q): expected an error", tc.tag, tc.pattern, tc.field)
+		}
+	}
+}
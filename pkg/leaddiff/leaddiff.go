@@ -0,0 +1,167 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package leaddiff compares two scrapes of the same search, reporting the leads which were added,
+// removed or changed in between. Leads are matched by their LinkedIn profile, or by their name and
+// company if they don't have one.
+package leaddiff
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/devsheke/scrapollo/internal/dedupe"
+	"github.com/devsheke/scrapollo/internal/io"
+	"github.com/devsheke/scrapollo/internal/models"
+)
+
+// Lead is a lead scraped by scrapollo.
+type Lead = models.Lead
+
+// The kinds of changes between two scrapes.
+const (
+	Added   = "added"
+	Removed = "removed"
+	Changed = "changed"
+)
+
+// Change is a lead which is in both scrapes, but whose details differ.
+type Change struct {
+	Old, New *Lead
+
+	// Fields are the names of the fields which differ, as in the CSV header.
+	Fields []string
+}
+
+// Result is the difference between two scrapes.
+type Result struct {
+	Added, Removed []*Lead
+	Changed        []*Change
+}
+
+// Record is an entry of a [Result], flattened for saving to a file.
+type Record struct {
+	Change string `json:"change"         csv:"change"`
+	Fields string `json:"changed-fields" csv:"changed-fields"`
+	Lead
+}
+
+// Records returns the result's entries: added, removed and then changed leads. The new details of
+// changed leads are used.
+func (r *Result) Records() []*Record {
+	var records []*Record
+	for _, lead := range r.Added {
+		records = append(records, &Record{Change: Added, Lead: *lead})
+	}
+
+	for _, lead := range r.Removed {
+		records = append(records, &Record{Change: Removed, Lead: *lead})
+	}
+
+	for _, change := range r.Changed {
+		records = append(records, &Record{
+			Change: Changed,
+			Fields: strings.Join(change.Fields, ","),
+			Lead:   *change.New,
+		})
+	}
+
+	return records
+}
+
+// Diff compares the leads of an old scrape (before) and a new one (after). A lead's provenance (e.g. when and by which
+// account it was scraped) isn't compared. Leads are reported in the order of the scrape they're
+// taken from and, if a scrape holds the same lead more than once, its first occurrence is used.
+func Diff(before, after []*Lead) *Result {
+	oldLeads := index(before)
+	newLeads := index(after)
+
+	result := &Result{}
+	for _, lead := range unique(after) {
+		prev, ok := oldLeads[dedupe.Key(lead)]
+		if !ok {
+			result.Added = append(result.Added, lead)
+		} else if fields := changedFields(prev, lead); len(fields) > 0 {
+			result.Changed = append(result.Changed, &Change{Old: prev, New: lead, Fields: fields})
+		}
+	}
+
+	for _, lead := range unique(before) {
+		if _, ok := newLeads[dedupe.Key(lead)]; !ok {
+			result.Removed = append(result.Removed, lead)
+		}
+	}
+
+	return result
+}
+
+// DiffFiles compares the leads in two output files of scrapollo (CSV or JSON).
+func DiffFiles(oldFile, newFile string) (*Result, error) {
+	before, err := io.ReadLeads(oldFile)
+	if err != nil {
+		return nil, err
+	}
+
+	after, err := io.ReadLeads(newFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return Diff(before, after), nil
+}
+
+// unique returns the first occurrence of each lead.
+func unique(leads []*Lead) []*Lead {
+	seen := make(map[string]bool)
+
+	var unique []*Lead
+	for _, lead := range leads {
+		if key := dedupe.Key(lead); !seen[key] {
+			seen[key] = true
+			unique = append(unique, lead)
+		}
+	}
+
+	return unique
+}
+
+// index maps the leads' keys to their first occurrence.
+func index(leads []*Lead) map[string]*Lead {
+	m := make(map[string]*Lead, len(leads))
+	for _, lead := range unique(leads) {
+		m[dedupe.Key(lead)] = lead
+	}
+
+	return m
+}
+
+// changedFields returns the names of the displayed fields which differ. The embedded details parsed
+// from them and the lead's provenance aren't compared.
+func changedFields(a, b *Lead) []string {
+	va, vb := reflect.ValueOf(a).Elem(), reflect.ValueOf(b).Elem()
+
+	var fields []string
+	for i := range va.NumField() {
+		field := va.Type().Field(i)
+		if field.Anonymous {
+			continue
+		}
+
+		if va.Field(i).Interface() != vb.Field(i).Interface() {
+			fields = append(fields, field.Tag.Get("csv"))
+		}
+	}
+
+	return fields
+}
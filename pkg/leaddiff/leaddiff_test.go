@@ -0,0 +1,89 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package leaddiff
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/devsheke/scrapollo/internal/io"
+	"github.com/devsheke/scrapollo/internal/models"
+)
+
+func lead(name, title string) *Lead {
+	return &Lead{Name: name, Title: title, Company: "Acme"}
+}
+
+func TestDiff(t *testing.T) {
+	before := []*Lead{lead("Jane", "CEO"), lead("John", "CTO"), lead("Jim", "CFO")}
+	after := []*Lead{lead("Jane", "CEO"), lead("John", "VP Engineering"), lead("Joan", "COO"), lead("Joan", "COO")}
+
+	// the provenance of leads isn't compared.
+	after[0].LeadSource = models.LeadSource{Account: "other@example.com", ScrapedAt: time.Now()}
+
+	result := Diff(before, after)
+
+	names := func(leads []*Lead) (names []string) {
+		for _, lead := range leads {
+			names = append(names, lead.Name)
+		}
+		return
+	}
+
+	if got := names(result.Added); !slices.Equal(got, []string{"Joan"}) {
+		t.Errorf("added = %v; want [Joan]", got)
+	}
+
+	if got := names(result.Removed); !slices.Equal(got, []string{"Jim"}) {
+		t.Errorf("removed = %v; want [Jim]", got)
+	}
+
+	if len(result.Changed) != 1 {
+		t.Fatalf("changed = %d leads; want 1", len(result.Changed))
+	}
+
+	change := result.Changed[0]
+	if change.New.Name != "John" || !slices.Equal(change.Fields, []string{"title"}) {
+		t.Errorf("change = %s %v; want John [title]", change.New.Name, change.Fields)
+	}
+
+	if n := len(result.Records()); n != 3 {
+		t.Errorf("records = %d; want 3", n)
+	}
+}
+
+func TestDiffFiles(t *testing.T) {
+	dir := t.TempDir()
+	oldFile, newFile := filepath.Join(dir, "old.csv"), filepath.Join(dir, "new.json")
+
+	if err := io.NewCsvLeadWriter(oldFile).WriteLeads([]*Lead{lead("Jane", "CEO")}); err != nil {
+		t.Fatal(err)
+	}
+	if err := io.NewJsonLeadWriter(newFile).WriteLeads([]*Lead{lead("Jane", "CEO"), lead("John", "CTO")}); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := DiffFiles(oldFile, newFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(result.Added) != 1 || len(result.Removed) != 0 || len(result.Changed) != 0 {
+		t.Errorf("result = %d added, %d removed, %d changed; want 1, 0, 0",
+			len(result.Added), len(result.Removed), len(result.Changed))
+	}
+}
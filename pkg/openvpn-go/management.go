@@ -0,0 +1,310 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openvpn
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// States reported by OpenVPN's management interface.
+const (
+	StateConnecting   = "CONNECTING"
+	StateConnected    = "CONNECTED"
+	StateReconnecting = "RECONNECTING"
+	StateExiting      = "EXITING"
+)
+
+// ErrorManagementClosed is returned when a command is sent over a closed management connection.
+var ErrorManagementClosed = errors.New("openvpn management connection closed")
+
+// Status describes an OpenVPN connection as reported by its management interface.
+type Status struct {
+	// State is OpenVPN's current state (e.g. 'CONNECTED' or 'RECONNECTING').
+	State string `json:"state"`
+
+	// Detail is OpenVPN's reason for the current state (e.g. 'SUCCESS' or 'ping-restart').
+	Detail string `json:"detail,omitempty"`
+
+	// LocalIP is the address assigned to the tunnel interface.
+	LocalIP string `json:"local-ip,omitempty"`
+
+	// RemoteIP is the address of the VPN server.
+	RemoteIP string `json:"remote-ip,omitempty"`
+
+	// Since is when OpenVPN entered its current state.
+	Since time.Time `json:"since"`
+
+	// BytesIn and BytesOut are the number of bytes transferred over the tunnel.
+	BytesIn  uint64 `json:"bytes-in"`
+	BytesOut uint64 `json:"bytes-out"`
+
+	// Reconnects is the number of times OpenVPN has reconnected.
+	Reconnects int `json:"reconnects"`
+}
+
+// monitor tracks the status of an OpenVPN process through its management interface and sends
+// commands to it.
+type monitor struct {
+	conn      net.Conn
+	connected chan struct{}
+	done      chan struct{}
+	replies   chan error
+	cmdMu     sync.Mutex
+	mu        sync.RWMutex
+	once      sync.Once
+	status    Status
+}
+
+// managementAddr returns a free local address for OpenVPN's management interface to listen on.
+func managementAddr() (string, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", fmt.Errorf("failed to find a free port for the openvpn management interface: %w", err)
+	}
+	defer l.Close()
+
+	return l.Addr().String(), nil
+}
+
+// managementPasswordFile writes a random password for OpenVPN's management interface to a file that
+// only the current user can read, so that other local users can't control OpenVPN through it. The file
+// is only needed until OpenVPN has started listening.
+func managementPasswordFile() (file, password string, err error) {
+	secret := make([]byte, 24)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", err
+	}
+	password = hex.EncodeToString(secret)
+
+	// temporary files are created with 0600 permissions.
+	f, err := os.CreateTemp("", "openvpn-management-*")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create the openvpn management password file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := fmt.Fprintln(f, password); err != nil {
+		_ = os.Remove(f.Name())
+		return "", "", err
+	}
+
+	return f.Name(), password, nil
+}
+
+// dialManagement connects to the management interface at the given address, retrying until it is
+// up or the context is done, logs into it with the password (unless it's empty) and starts monitoring
+// the OpenVPN process through it.
+func dialManagement(ctx context.Context, addr, password string) (*monitor, error) {
+	var dialer net.Dialer
+	for {
+		conn, err := dialer.DialContext(ctx, "tcp", addr)
+		if err == nil {
+			reader := bufio.NewReader(conn)
+			if password != "" {
+				if err := authenticate(conn, reader, password); err != nil {
+					_ = conn.Close()
+					return nil, err
+				}
+			}
+			return newMonitor(conn, reader)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("failed to connect to the openvpn management interface: %w", err)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+// authenticate logs into the management interface with its password. OpenVPN prompts for the password
+// with 'ENTER PASSWORD:' (without a line break), and confirms it with 'SUCCESS: password is correct'.
+func authenticate(conn net.Conn, reader *bufio.Reader, password string) error {
+	if err := conn.SetDeadline(time.Now().Add(5 * time.Second)); err != nil {
+		return err
+	}
+	defer conn.SetDeadline(time.Time{})
+
+	if _, err := fmt.Fprintf(conn, "%s\n", password); err != nil {
+		return err
+	}
+
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return fmt.Errorf("failed to log into the openvpn management interface: %w", err)
+	}
+
+	if line = strings.TrimSpace(strings.TrimPrefix(line, "ENTER PASSWORD:")); !strings.HasPrefix(line, "SUCCESS:") {
+		return fmt.Errorf("openvpn management: %s", line)
+	}
+
+	return nil
+}
+
+func newMonitor(conn net.Conn, reader io.Reader) (*monitor, error) {
+	m := &monitor{
+		conn:      conn,
+		connected: make(chan struct{}),
+		done:      make(chan struct{}),
+		replies:   make(chan error, 1),
+	}
+	go m.read(reader)
+
+	for _, command := range []string{"state on all", "bytecount 5"} {
+		if err := m.command(command, 5*time.Second); err != nil {
+			_ = conn.Close()
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+// read handles the messages sent over the management interface until it is closed.
+func (m *monitor) read(reader io.Reader) {
+	defer close(m.done)
+
+	scanner := bufio.NewScanner(reader)
+	for scanner.Scan() {
+		m.handle(strings.TrimSpace(scanner.Text()))
+	}
+}
+
+func (m *monitor) handle(line string) {
+	switch {
+	case strings.HasPrefix(line, ">STATE:"):
+		m.setState(strings.TrimPrefix(line, ">STATE:"))
+
+	case strings.HasPrefix(line, ">BYTECOUNT:"):
+		in, out, _ := strings.Cut(strings.TrimPrefix(line, ">BYTECOUNT:"), ",")
+		m.mu.Lock()
+		m.status.BytesIn, _ = strconv.ParseUint(in, 10, 64)
+		m.status.BytesOut, _ = strconv.ParseUint(out, 10, 64)
+		m.mu.Unlock()
+
+	case strings.HasPrefix(line, "SUCCESS:"):
+		m.reply(nil)
+
+	case strings.HasPrefix(line, "ERROR:"):
+		m.reply(fmt.Errorf("openvpn management: %s", strings.TrimSpace(strings.TrimPrefix(line, "ERROR:"))))
+
+	case strings.HasPrefix(line, ">"), line == "END":
+
+	default:
+		// lines of the state history printed in response to 'state on all'.
+		if timestamp, _, ok := strings.Cut(line, ","); ok && isNumber(timestamp) {
+			m.setState(line)
+		}
+	}
+}
+
+func isNumber(s string) bool {
+	_, err := strconv.ParseInt(s, 10, 64)
+	return err == nil
+}
+
+// setState updates the monitored status from a state message, e.g.
+// '1700000000,CONNECTED,SUCCESS,10.8.0.2,203.0.113.1,1194,,'.
+func (m *monitor) setState(message string) {
+	fields := strings.Split(message, ",")
+	if len(fields) < 2 {
+		return
+	}
+
+	field := func(i int) string {
+		if i < len(fields) {
+			return fields[i]
+		}
+		return ""
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if timestamp, err := strconv.ParseInt(fields[0], 10, 64); err == nil {
+		m.status.Since = time.Unix(timestamp, 0)
+	}
+	m.status.State, m.status.Detail = fields[1], field(2)
+	m.status.LocalIP, m.status.RemoteIP = field(3), field(4)
+
+	switch m.status.State {
+	case StateReconnecting:
+		m.status.Reconnects++
+	case StateConnected:
+		m.once.Do(func() { close(m.connected) })
+	}
+}
+
+func (m *monitor) reply(err error) {
+	select {
+	case m.replies <- err:
+	default:
+	}
+}
+
+// command sends a command over the management interface and waits for it to be acknowledged.
+func (m *monitor) command(command string, timeout time.Duration) error {
+	m.cmdMu.Lock()
+	defer m.cmdMu.Unlock()
+
+	// discard stale replies of commands that timed out.
+	select {
+	case <-m.replies:
+	default:
+	}
+
+	if err := m.conn.SetWriteDeadline(time.Now().Add(timeout)); err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(m.conn, "%s\n", command); err != nil {
+		return err
+	}
+
+	select {
+	case err := <-m.replies:
+		return err
+	case <-m.done:
+		return ErrorManagementClosed
+	case <-time.After(timeout):
+		return fmt.Errorf("openvpn management: timed out waiting for a reply to %q", command)
+	}
+}
+
+// signal sends the given signal (e.g. 'SIGTERM') to OpenVPN.
+func (m *monitor) signal(sig string, timeout time.Duration) error {
+	return m.command("signal "+sig, timeout)
+}
+
+func (m *monitor) get() Status {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.status
+}
+
+func (m *monitor) close() error {
+	return m.conn.Close()
+}
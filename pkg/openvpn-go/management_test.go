@@ -0,0 +1,187 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package openvpn
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testManagementServer emulates OpenVPN's management interface: it asks for the password (unless it's
+// empty), acknowledges every command, prints the state history in response to 'state on all' and then
+// sends the given messages.
+func testManagementServer(t *testing.T, password string, messages ...string) (string, <-chan string) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { l.Close() })
+
+	commands := make(chan string, 8)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		scanner := bufio.NewScanner(conn)
+		if password != "" {
+			fmt.Fprint(conn, "ENTER PASSWORD:")
+			if !scanner.Scan() || scanner.Text() != password {
+				fmt.Fprint(conn, "ERROR: bad password\r\n")
+				return
+			}
+			fmt.Fprint(conn, "SUCCESS: password is correct\r\n")
+		}
+
+		fmt.Fprint(conn, ">INFO:OpenVPN Management Interface Version 5 -- type 'help' for more info\r\n")
+
+		for scanner.Scan() {
+			command := scanner.Text()
+			commands <- command
+
+			switch {
+			case command == "state on all":
+				fmt.Fprint(conn, "SUCCESS: real-time state notification set to ON\r\n")
+				fmt.Fprint(conn, "1700000000,CONNECTING,,,,,,\r\n")
+				fmt.Fprint(conn, "END\r\n")
+
+			case command == "signal SIGFOO":
+				fmt.Fprint(conn, "ERROR: signal 'SIGFOO' is not supported\r\n")
+
+			case strings.HasPrefix(command, "signal"):
+				fmt.Fprintf(conn, "SUCCESS: %s thrown\r\n", command)
+				return
+
+			case command == "bytecount 5":
+				fmt.Fprint(conn, "SUCCESS: bytecount interval changed\r\n")
+				for _, message := range messages {
+					fmt.Fprintf(conn, "%s\r\n", message)
+				}
+			}
+		}
+	}()
+
+	return l.Addr().String(), commands
+}
+
+func testDialManagement(t *testing.T, addr, password string) *monitor {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	m, err := dialManagement(ctx, addr, password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { m.close() })
+
+	return m
+}
+
+func TestManagementStatus(t *testing.T) {
+	addr, _ := testManagementServer(t, "",
+		">STATE:1700000010,CONNECTED,SUCCESS,10.8.0.2,203.0.113.1,1194,,",
+		">STATE:1700000020,RECONNECTING,ping-restart,,,,,",
+		">STATE:1700000030,CONNECTED,SUCCESS,10.8.0.3,203.0.113.1,1194,,",
+		">BYTECOUNT:1024,2048",
+	)
+	m := testDialManagement(t, addr, "")
+
+	select {
+	case <-m.connected:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the connection")
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for m.get().BytesOut == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	want := Status{
+		State:      StateConnected,
+		Detail:     "SUCCESS",
+		LocalIP:    "10.8.0.3",
+		RemoteIP:   "203.0.113.1",
+		Since:      time.Unix(1700000030, 0),
+		BytesIn:    1024,
+		BytesOut:   2048,
+		Reconnects: 1,
+	}
+	if got := m.get(); got != want {
+		t.Fatalf("unexpected status: got %+v, want %+v", got, want)
+	}
+}
+
+func TestManagementSignal(t *testing.T) {
+	addr, commands := testManagementServer(t, "")
+	m := testDialManagement(t, addr, "")
+
+	if err := m.signal("SIGFOO", time.Second); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if err := m.signal("SIGTERM", time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	var sent []string
+	for len(commands) > 0 {
+		sent = append(sent, <-commands)
+	}
+	if got := strings.Join(sent, "; "); got != "state on all; bytecount 5; signal SIGFOO; signal SIGTERM" {
+		t.Fatalf("unexpected commands: %q", got)
+	}
+}
+
+func TestManagementPassword(t *testing.T) {
+	file, password, err := managementPasswordFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Remove(file)
+
+	info, err := os.Stat(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("password file has permissions %v, want 0600", perm)
+	}
+
+	if content, _ := os.ReadFile(file); strings.TrimSpace(string(content)) != password {
+		t.Errorf("password file holds %q, want %q", content, password)
+	}
+
+	addr, commands := testManagementServer(t, password)
+	testDialManagement(t, addr, password)
+	if got := <-commands; got != "state on all" {
+		t.Errorf("first command = %q, want %q", got, "state on all")
+	}
+
+	addr, _ = testManagementServer(t, password)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := dialManagement(ctx, addr, "wrong"); err == nil || !strings.Contains(err.Error(), "bad password") {
+		t.Errorf("dialManagement() with a wrong password = %v, want a bad password error", err)
+	}
+}
@@ -18,7 +18,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
+	"os"
 	"os/exec"
+	"runtime"
 	"strings"
 	"time"
 
@@ -52,99 +55,238 @@ func (e ErrorVpnFailure) Error() string {
 	return fmt.Sprintf("openvpn failed to run: stdout: %q; stderr: %q", e.stdout, e.stderr)
 }
 
+// stopTimeout is how long [Stop] waits for OpenVPN to exit after each attempt at stopping it.
+const stopTimeout = 5 * time.Second
+
+// Process is a running instance of OpenVPN.
+type Process struct {
+	*cmd.Cmd
+
+	// Management is the address of the process' management interface.
+	Management string
+
+	monitor *monitor
+
+	// sudo is true if the process was spawned through sudo.
+	sudo bool
+}
+
+// Connection returns the status of the process' connection as reported by its management interface.
+func (p *Process) Connection() Status {
+	return p.monitor.get()
+}
+
+// openvpnCommand returns the command and arguments used to spawn OpenVPN. When the current process is not
+// elevated, OpenVPN is spawned through (non-interactive) sudo if it is available, since it needs
+// administrative privileges to create the tunnel interface.
+func openvpnCommand(args ...string) (name string, _ []string, sudo bool) {
+	if runtime.GOOS == "windows" || IsElevated() {
+		return "openvpn", args, false
+	}
+
+	if _, err := exec.LookPath("sudo"); err != nil {
+		log.Warn().Msg("not running with elevated privileges and sudo was not found; openvpn may fail to start")
+		return "openvpn", args, false
+	}
+
+	return "sudo", append([]string{"-n", "openvpn"}, args...), true
+}
+
 // Start spawns an OpenVPN process with the provided configuration, credentials and arguments and returns
-// [*cmd.Cmd] and [<-chan cmd.Status] for controlling and monitoring the spawned process.
-func Start(config, auth, args string, timeout time.Duration) (*cmd.Cmd, <-chan cmd.Status, error) {
+// [*Process] and [<-chan cmd.Status] for controlling and monitoring the spawned process.
+func Start(config, auth, args string, timeout time.Duration) (*Process, <-chan cmd.Status, error) {
 	log.Debug().Str("config", config).Msg("starting openvpn")
 
-	process := cmd.NewCmdOptions(
-		cmd.Options{Streaming: true},
-		"openvpn",
+	management, err := managementAddr()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	passwordFile, password, err := managementPasswordFile()
+	if err != nil {
+		return nil, nil, err
+	}
+	defer os.Remove(passwordFile)
+
+	host, port, _ := net.SplitHostPort(management)
+	name, cmdArgs, sudo := openvpnCommand(append([]string{
 		"--config",
 		config,
 		"--auth-user-pass",
 		auth,
-		args,
-	)
+		"--management",
+		host,
+		port,
+		passwordFile,
+	}, strings.Fields(args)...)...)
+
+	process := cmd.NewCmdOptions(cmd.Options{Streaming: true}, name, cmdArgs...)
 
 	status := process.Start()
 
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
-	var stdoutStack []string
+	type dialed struct {
+		monitor *monitor
+		err     error
+	}
+
+	dialing := make(chan dialed, 1)
+	go func() {
+		m, err := dialManagement(ctx, management, password)
+		dialing <- dialed{m, err}
+	}()
+
+	var (
+		m           *monitor
+		connected   <-chan struct{}
+		stdoutStack []string
+	)
+
+	// fail stops the process when it fails to start up.
+	fail := func(err error) (*Process, <-chan cmd.Status, error) {
+		if m != nil {
+			_ = m.close()
+		}
+		if _err := kill(process, sudo); _err != nil && !errors.Is(_err, ErrorNoVpnProcess) {
+			log.Warn().Err(_err).Msg("failed to stop openvpn after it failed to start")
+		}
+		return nil, nil, err
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
-			return nil, nil, ErrorVpnTimedOut{Msg: strings.Join(stdoutStack, "\n")}
+			return fail(ErrorVpnTimedOut{Msg: strings.Join(stdoutStack, "\n")})
 
-		case stdout := <-process.Stdout:
-			if strings.Contains(stdout, "Initialization Sequence Completed") {
-				return process, status, nil
+		case <-connected:
+			go drain(process)
+			return &Process{Cmd: process, Management: management, monitor: m, sudo: sudo}, status, nil
+
+		case d := <-dialing:
+			if d.err != nil {
+				return fail(ErrorVpnTimedOut{Msg: strings.Join(append(stdoutStack, d.err.Error()), "\n")})
 			}
+			m, connected = d.monitor, d.monitor.connected
+
+		case stdout := <-process.Stdout:
 			if stdout != "" {
 				stdoutStack = append(stdoutStack, stdout)
 			}
 
 		case stderr := <-process.Stderr:
-			return nil, nil, ErrorVpnFailure{
+			return fail(ErrorVpnFailure{
 				stdout: strings.Join(stdoutStack, "\n"),
 				stderr: stderr,
-			}
+			})
 
 		case status := <-status:
 			if err := status.Error; err != nil {
-				return nil, nil, err
+				return fail(err)
 			}
 
 			stderr := status.Stderr
 			if len(stderr) > 0 {
-				return nil, nil, ErrorVpnFailure{
+				return fail(ErrorVpnFailure{
 					stdout: strings.Join(stdoutStack, "\n"),
 					stderr: strings.Join(stderr, "\n"),
-				}
+				})
 			}
 
-			return nil, nil, ErrorVpnFailure{stdout: strings.Join(stdoutStack, "\n")}
+			return fail(ErrorVpnFailure{stdout: strings.Join(stdoutStack, "\n")})
+		}
+	}
+}
+
+// drain logs the output of a running OpenVPN process until it exits, so that it never blocks
+// writing to it.
+func drain(process *cmd.Cmd) {
+	for {
+		select {
+		case stdout := <-process.Stdout:
+			log.Trace().Str("stdout", stdout).Msg("openvpn")
+		case stderr := <-process.Stderr:
+			log.Debug().Str("stderr", stderr).Msg("openvpn")
+		case <-process.Done():
+			return
 		}
 	}
 }
 
 // Stop is a function which attempts to stop the provided OpenVPN process. ErrorNoVpnProcess is
 // returned if there is no process found matching the details of the provided process.
-func Stop(process *cmd.Cmd) (err error) {
+//
+// OpenVPN is first asked to shut down gracefully through its management interface. If it doesn't
+// exit in time, it is signalled directly and, as a last resort, forcefully terminated.
+func Stop(process *Process) error {
 	log.Debug().Msg("stopping openvpn")
 
-	defer func() {
-		if process != nil {
-			err = errors.Join(
-				err,
-				exec.Command("kill", fmt.Sprintf("%d", process.Status().PID)).Run(),
-			)
+	if process == nil || process.Cmd == nil {
+		return ErrorNoVpnProcess
+	}
+
+	select {
+	case <-process.Done():
+		return nil
+	default:
+	}
+
+	if process.monitor != nil {
+		defer process.monitor.close()
+
+		if err := process.monitor.signal("SIGTERM", stopTimeout); err != nil {
+			log.Debug().Err(err).Msg("failed to stop openvpn through its management interface")
+		} else if waitDone(process.Cmd, stopTimeout) {
+			return nil
 		}
-	}()
+	}
 
-	if process == nil {
-		return ErrorNoVpnProcess
+	return kill(process.Cmd, process.sudo)
+}
+
+// kill signals the process (and its process group) to exit and, if it doesn't exit in time,
+// forcefully terminates it.
+func kill(process *cmd.Cmd, sudo bool) error {
+	pid := process.Status().PID
+
+	// go-cmd signals the process group itself, which it isn't allowed to do if sudo spawned it.
+	var err error
+	if sudo {
+		err = interrupt(pid, sudo)
+	} else {
+		err = process.Stop()
 	}
 
-	if err := process.Stop(); err != nil {
-		if errors.Is(err, cmd.ErrNotStarted) {
+	if err != nil {
+		if errors.Is(err, cmd.ErrNotStarted) || errors.Is(err, ErrorNoVpnProcess) {
 			return ErrorNoVpnProcess
 		}
-		return err
+		log.Debug().Err(err).Msg("failed to signal openvpn")
+	} else if waitDone(process, stopTimeout) {
+		return nil
 	}
 
-	return
+	return terminate(pid, sudo)
+}
+
+// waitDone waits for the process to exit and returns false if it does not do so within the timeout.
+func waitDone(process *cmd.Cmd, timeout time.Duration) bool {
+	select {
+	case <-process.Done():
+		return true
+	case <-time.After(timeout):
+		return false
+	}
 }
 
 // Restart is a function which attempts to restart the OpenVPN process with the provided configuration,
 // credentials and arguments.
 func Restart(
-	process *cmd.Cmd,
+	process *Process,
 	config, auth, args string,
 	timeout time.Duration,
-) (*cmd.Cmd, <-chan cmd.Status, error) {
+) (*Process, <-chan cmd.Status, error) {
 	if err := Stop(process); err != nil && err != ErrorNoVpnProcess {
 		return nil, nil, err
 	}
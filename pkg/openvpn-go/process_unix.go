@@ -0,0 +1,77 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !windows
+
+package openvpn
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"strconv"
+	"syscall"
+)
+
+// IsElevated returns true if the current process is running as root.
+func IsElevated() bool {
+	return os.Geteuid() == 0
+}
+
+// signalNames are the names that kill and pkill know the signals sent to OpenVPN by.
+var signalNames = map[syscall.Signal]string{syscall.SIGTERM: "TERM", syscall.SIGKILL: "KILL"}
+
+// signalGroup sends the signal to the process group led by the process with the given PID, which is
+// how go-cmd spawns processes. Processes spawned through sudo are owned by root, so unless the current
+// process is elevated, they are signalled through sudo as well: signalling sudo itself isn't enough,
+// since it can't relay SIGKILL to OpenVPN, and an unprivileged process isn't allowed to signal the
+// group of a root process.
+func signalGroup(pid int, sig syscall.Signal, sudo bool) error {
+	// a non-positive PID would signal the current process group, or every process.
+	if pid <= 0 {
+		return ErrorNoVpnProcess
+	}
+
+	if !sudo || IsElevated() {
+		if err := syscall.Kill(-pid, sig); err != nil && !errors.Is(err, syscall.ESRCH) {
+			return err
+		}
+		return nil
+	}
+
+	name := signalNames[sig]
+
+	// sudo may run OpenVPN in a process group of its own (e.g. with use_pty), so its children are
+	// signalled first, while they can still be found by their parent. pkill exits with 1 when none
+	// are found.
+	err := exec.Command("sudo", "-n", "pkill", "-"+name, "-P", strconv.Itoa(pid)).Run()
+	if exitErr := (*exec.ExitError)(nil); errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		err = nil
+	}
+
+	return errors.Join(err, exec.Command("sudo", "-n", "kill", "-"+name, "--", "-"+strconv.Itoa(pid)).Run())
+}
+
+// sendSignal is the function that interrupt and terminate signal process groups with.
+var sendSignal = signalGroup
+
+// interrupt asks the process with the given PID (and its process group) to exit.
+func interrupt(pid int, sudo bool) error {
+	return sendSignal(pid, syscall.SIGTERM, sudo)
+}
+
+// terminate forcefully kills the process with the given PID (and its process group).
+func terminate(pid int, sudo bool) error {
+	return sendSignal(pid, syscall.SIGKILL, sudo)
+}
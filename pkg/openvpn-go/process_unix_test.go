@@ -0,0 +1,107 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build !windows
+
+package openvpn
+
+import (
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/go-cmd/cmd"
+)
+
+// alive reports whether the process with the given PID is running, i.e. exists and isn't a zombie
+// waiting to be reaped.
+func alive(pid int) bool {
+	out, err := exec.Command("ps", "-o", "stat=", "-p", strconv.Itoa(pid)).Output()
+	return err == nil && !strings.HasPrefix(strings.TrimSpace(string(out)), "Z")
+}
+
+func TestTerminateKillsProcessGroup(t *testing.T) {
+	// like sudo, the shell runs the actual process as its child, in the process group it leads.
+	sh := exec.Command("sh", "-c", "sleep 60 & echo $!; wait")
+	sh.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	stdout, err := sh.StdoutPipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := sh.Start(); err != nil {
+		t.Fatal(err)
+	}
+
+	buf := make([]byte, 32)
+	n, _ := stdout.Read(buf)
+	child, err := strconv.Atoi(strings.TrimSpace(string(buf[:n])))
+	if err != nil {
+		t.Fatalf("failed to read the child's pid: %v", err)
+	}
+
+	if err := terminate(sh.Process.Pid, false); err != nil {
+		t.Fatal(err)
+	}
+	_ = sh.Wait()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for alive(child) {
+		if time.Now().After(deadline) {
+			_ = syscall.Kill(child, syscall.SIGKILL)
+			t.Fatal("the child outlived its process group's leader")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	if err := terminate(0, false); err != ErrorNoVpnProcess {
+		t.Errorf("terminate(0) = %v, want %v", err, ErrorNoVpnProcess)
+	}
+}
+
+func TestKillSignalsGroupThroughSudo(t *testing.T) {
+	type signal struct {
+		pid  int
+		sig  syscall.Signal
+		sudo bool
+	}
+
+	// the signals are recorded and then sent without sudo, which isn't available in tests.
+	var signals []signal
+	sendSignal = func(pid int, sig syscall.Signal, sudo bool) error {
+		signals = append(signals, signal{pid, sig, sudo})
+		return signalGroup(pid, sig, false)
+	}
+	t.Cleanup(func() { sendSignal = signalGroup })
+
+	process := cmd.NewCmdOptions(cmd.Options{Streaming: true}, "sleep", "60")
+	process.Start()
+	for process.Status().PID == 0 {
+		time.Sleep(10 * time.Millisecond)
+	}
+	pid := process.Status().PID
+
+	if err := kill(process, true); err != nil {
+		t.Fatal(err)
+	}
+	if !waitDone(process, 5*time.Second) {
+		t.Fatal("the process outlived being killed")
+	}
+
+	if len(signals) == 0 || signals[0] != (signal{pid, syscall.SIGTERM, true}) {
+		t.Errorf("expected the process group to be interrupted through sudo, got %+v", signals)
+	}
+}
@@ -0,0 +1,54 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build windows
+
+package openvpn
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"strconv"
+)
+
+// IsElevated returns true if the current process is running with administrative privileges.
+func IsElevated() bool {
+	// only elevated processes are allowed to open physical drives.
+	f, err := os.Open(`\\.\PHYSICALDRIVE0`)
+	if err != nil {
+		return false
+	}
+	_ = f.Close()
+
+	return true
+}
+
+// interrupt asks the process with the given PID (and its children) to exit.
+func interrupt(pid int, _ bool) error {
+	return exec.Command("taskkill", "/PID", strconv.Itoa(pid), "/T").Run()
+}
+
+// terminate forcefully kills the process with the given PID, falling back to taskkill (which
+// also kills the process' children) if that fails.
+func terminate(pid int, _ bool) error {
+	process, err := os.FindProcess(pid)
+	if err == nil {
+		if err = process.Kill(); err == nil || errors.Is(err, os.ErrProcessDone) {
+			return nil
+		}
+	}
+
+	return errors.Join(err, exec.Command("taskkill", "/PID", strconv.Itoa(pid), "/T", "/F").Run())
+}
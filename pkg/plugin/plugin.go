@@ -0,0 +1,182 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package plugin provides the means to extend scrapollo with external binaries. A plugin is
+// an executable which implements one or more of scrapollo's extension points and serves them
+// using [Serve]. Plugins are loaded by scrapollo using [Load].
+package plugin
+
+import (
+	"errors"
+	"os/exec"
+
+	"github.com/devsheke/scrapollo/internal/actions"
+	"github.com/devsheke/scrapollo/internal/io"
+	"github.com/devsheke/scrapollo/internal/models"
+	"github.com/devsheke/scrapollo/internal/runner"
+	"github.com/devsheke/scrapollo/internal/scoring"
+	"github.com/hashicorp/go-hclog"
+	goplugin "github.com/hashicorp/go-plugin"
+)
+
+// Handshake is the handshake configuration shared by scrapollo and its plugins.
+var Handshake = goplugin.HandshakeConfig{
+	ProtocolVersion:  1,
+	MagicCookieKey:   "SCRAPOLLO_PLUGIN",
+	MagicCookieValue: "scrapollo",
+}
+
+// The names under which scrapollo's extension points are served.
+const (
+	LeadWriterName    string = "lead-writer"
+	SchedulerName     string = "scheduler"
+	CaptchaSolverName string = "captcha-solver"
+	NotifierName      string = "notifier"
+	LeadScorerName    string = "lead-scorer"
+	PageHookName      string = "page-hook"
+)
+
+// The types used by scrapollo's extension points.
+type (
+	Lead             = models.Lead
+	LeadWriter       = io.LeadWriter
+	Candidate        = runner.Candidate
+	Scheduler        = runner.JobScheduler
+	CaptchaChallenge = actions.CaptchaChallenge
+	CaptchaSolver    = actions.CaptchaSolver
+	Notification     = runner.Notification
+	Notifier         = runner.Notifier
+	LeadScorer       = scoring.Scorer
+	PageCapture      = runner.PageCapture
+	PageHook         = runner.PageHook
+)
+
+// ServeOpts specifies the extension points implemented by a plugin. Extension points
+// left unset are not served.
+type ServeOpts struct {
+	LeadWriter    LeadWriter
+	Scheduler     Scheduler
+	CaptchaSolver CaptchaSolver
+	Notifier      Notifier
+	LeadScorer    LeadScorer
+	PageHook      PageHook
+}
+
+// Serve serves the provided extension points to scrapollo. This function should be called
+// from a plugin's main function and blocks until scrapollo exits.
+func Serve(opts *ServeOpts) {
+	plugins := goplugin.PluginSet{}
+
+	if opts.LeadWriter != nil {
+		plugins[LeadWriterName] = &leadWriterPlugin{impl: opts.LeadWriter}
+	}
+
+	if opts.Scheduler != nil {
+		plugins[SchedulerName] = &schedulerPlugin{impl: opts.Scheduler}
+	}
+
+	if opts.CaptchaSolver != nil {
+		plugins[CaptchaSolverName] = &captchaSolverPlugin{impl: opts.CaptchaSolver}
+	}
+
+	if opts.Notifier != nil {
+		plugins[NotifierName] = &notifierPlugin{impl: opts.Notifier}
+	}
+
+	if opts.LeadScorer != nil {
+		plugins[LeadScorerName] = &leadScorerPlugin{impl: opts.LeadScorer}
+	}
+
+	if opts.PageHook != nil {
+		plugins[PageHookName] = &pageHookPlugin{impl: opts.PageHook}
+	}
+
+	goplugin.Serve(&goplugin.ServeConfig{HandshakeConfig: Handshake, Plugins: plugins})
+}
+
+// Plugin is a running plugin along with the extension points it implements. Extension
+// points which aren't implemented by the plugin are nil.
+type Plugin struct {
+	client *goplugin.Client
+
+	LeadWriter    LeadWriter
+	Scheduler     Scheduler
+	CaptchaSolver CaptchaSolver
+	Notifier      Notifier
+	LeadScorer    LeadScorer
+	PageHook      PageHook
+}
+
+// Load starts the plugin executable at the provided path and connects to the
+// extension points it implements.
+func Load(path string) (*Plugin, error) {
+	client := goplugin.NewClient(&goplugin.ClientConfig{
+		HandshakeConfig: Handshake,
+		Plugins: goplugin.PluginSet{
+			LeadWriterName:    &leadWriterPlugin{},
+			SchedulerName:     &schedulerPlugin{},
+			CaptchaSolverName: &captchaSolverPlugin{},
+			NotifierName:      &notifierPlugin{},
+			LeadScorerName:    &leadScorerPlugin{},
+			PageHookName:      &pageHookPlugin{},
+		},
+		Cmd:              exec.Command(path),
+		AllowedProtocols: []goplugin.Protocol{goplugin.ProtocolNetRPC},
+		Logger:           hclog.New(&hclog.LoggerOptions{Name: "plugin", Level: hclog.Warn}),
+	})
+
+	rpcClient, err := client.Client()
+	if err != nil {
+		client.Kill()
+		return nil, err
+	}
+
+	p := &Plugin{client: client}
+	if raw, err := rpcClient.Dispense(LeadWriterName); err == nil {
+		p.LeadWriter, _ = raw.(LeadWriter)
+	}
+
+	if raw, err := rpcClient.Dispense(SchedulerName); err == nil {
+		p.Scheduler, _ = raw.(Scheduler)
+	}
+
+	if raw, err := rpcClient.Dispense(CaptchaSolverName); err == nil {
+		p.CaptchaSolver, _ = raw.(CaptchaSolver)
+	}
+
+	if raw, err := rpcClient.Dispense(NotifierName); err == nil {
+		p.Notifier, _ = raw.(Notifier)
+	}
+
+	if raw, err := rpcClient.Dispense(LeadScorerName); err == nil {
+		p.LeadScorer, _ = raw.(LeadScorer)
+	}
+
+	if raw, err := rpcClient.Dispense(PageHookName); err == nil {
+		p.PageHook, _ = raw.(PageHook)
+	}
+
+	if p.LeadWriter == nil && p.Scheduler == nil && p.CaptchaSolver == nil && p.Notifier == nil &&
+		p.LeadScorer == nil && p.PageHook == nil {
+		client.Kill()
+		return nil, errors.New("plugin does not implement any extension points")
+	}
+
+	return p, nil
+}
+
+// Close stops the plugin's process.
+func (p *Plugin) Close() {
+	p.client.Kill()
+}
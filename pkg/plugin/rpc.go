@@ -0,0 +1,170 @@
+// Copyright 2025 Abhisheke Acharya
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//    http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package plugin
+
+import (
+	"net/rpc"
+
+	goplugin "github.com/hashicorp/go-plugin"
+)
+
+type leadWriterPlugin struct{ impl LeadWriter }
+
+func (p *leadWriterPlugin) Server(*goplugin.MuxBroker) (interface{}, error) {
+	return &leadWriterServer{impl: p.impl}, nil
+}
+
+func (p *leadWriterPlugin) Client(_ *goplugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	return &leadWriterClient{client: c}, nil
+}
+
+type leadWriterServer struct{ impl LeadWriter }
+
+func (s *leadWriterServer) WriteLead(lead *Lead, _ *bool) error {
+	return s.impl.WriteLead(lead)
+}
+
+func (s *leadWriterServer) WriteLeads(leads []*Lead, _ *bool) error {
+	return s.impl.WriteLeads(leads)
+}
+
+type leadWriterClient struct{ client *rpc.Client }
+
+func (c *leadWriterClient) WriteLead(lead *Lead) error {
+	return c.client.Call("Plugin.WriteLead", lead, new(bool))
+}
+
+func (c *leadWriterClient) WriteLeads(leads []*Lead) error {
+	return c.client.Call("Plugin.WriteLeads", leads, new(bool))
+}
+
+type schedulerPlugin struct{ impl Scheduler }
+
+func (p *schedulerPlugin) Server(*goplugin.MuxBroker) (interface{}, error) {
+	return &schedulerServer{impl: p.impl}, nil
+}
+
+func (p *schedulerPlugin) Client(_ *goplugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	return &schedulerClient{client: c}, nil
+}
+
+type schedulerServer struct{ impl Scheduler }
+
+func (s *schedulerServer) Next(candidates []Candidate, idx *int) (err error) {
+	*idx, err = s.impl.Next(candidates)
+	return
+}
+
+type schedulerClient struct{ client *rpc.Client }
+
+func (c *schedulerClient) Next(candidates []Candidate) (int, error) {
+	var idx int
+	err := c.client.Call("Plugin.Next", candidates, &idx)
+	return idx, err
+}
+
+type captchaSolverPlugin struct{ impl CaptchaSolver }
+
+func (p *captchaSolverPlugin) Server(*goplugin.MuxBroker) (interface{}, error) {
+	return &captchaSolverServer{impl: p.impl}, nil
+}
+
+func (p *captchaSolverPlugin) Client(_ *goplugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	return &captchaSolverClient{client: c}, nil
+}
+
+type captchaSolverServer struct{ impl CaptchaSolver }
+
+func (s *captchaSolverServer) Solve(challenge *CaptchaChallenge, token *string) (err error) {
+	*token, err = s.impl.Solve(challenge)
+	return
+}
+
+type captchaSolverClient struct{ client *rpc.Client }
+
+func (c *captchaSolverClient) Solve(challenge *CaptchaChallenge) (string, error) {
+	var token string
+	err := c.client.Call("Plugin.Solve", challenge, &token)
+	return token, err
+}
+
+type notifierPlugin struct{ impl Notifier }
+
+func (p *notifierPlugin) Server(*goplugin.MuxBroker) (interface{}, error) {
+	return &notifierServer{impl: p.impl}, nil
+}
+
+func (p *notifierPlugin) Client(_ *goplugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	return &notifierClient{client: c}, nil
+}
+
+type notifierServer struct{ impl Notifier }
+
+func (s *notifierServer) Notify(notification Notification, _ *bool) error {
+	return s.impl.Notify(notification)
+}
+
+type notifierClient struct{ client *rpc.Client }
+
+func (c *notifierClient) Notify(notification Notification) error {
+	return c.client.Call("Plugin.Notify", notification, new(bool))
+}
+
+type leadScorerPlugin struct{ impl LeadScorer }
+
+func (p *leadScorerPlugin) Server(*goplugin.MuxBroker) (interface{}, error) {
+	return &leadScorerServer{impl: p.impl}, nil
+}
+
+func (p *leadScorerPlugin) Client(_ *goplugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	return &leadScorerClient{client: c}, nil
+}
+
+type leadScorerServer struct{ impl LeadScorer }
+
+func (s *leadScorerServer) Score(lead *Lead, score *float64) (err error) {
+	*score, err = s.impl.Score(lead)
+	return err
+}
+
+type leadScorerClient struct{ client *rpc.Client }
+
+func (c *leadScorerClient) Score(lead *Lead) (float64, error) {
+	var score float64
+	err := c.client.Call("Plugin.Score", lead, &score)
+	return score, err
+}
+
+type pageHookPlugin struct{ impl PageHook }
+
+func (p *pageHookPlugin) Server(*goplugin.MuxBroker) (interface{}, error) {
+	return &pageHookServer{impl: p.impl}, nil
+}
+
+func (p *pageHookPlugin) Client(_ *goplugin.MuxBroker, c *rpc.Client) (interface{}, error) {
+	return &pageHookClient{client: c}, nil
+}
+
+type pageHookServer struct{ impl PageHook }
+
+func (s *pageHookServer) OnPage(capture PageCapture, _ *bool) error {
+	return s.impl.OnPage(capture)
+}
+
+type pageHookClient struct{ client *rpc.Client }
+
+func (c *pageHookClient) OnPage(capture PageCapture) error {
+	return c.client.Call("Plugin.OnPage", capture, new(bool))
+}